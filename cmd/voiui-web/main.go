@@ -0,0 +1,186 @@
+//go:build js
+
+// Command voiui-web runs the voiui window in a browser against the local
+// API of a voiui on the node box:
+//
+//	gogio -target js -o web ./cmd/voiui-web
+//	voiui -api-listen 0.0.0.0:8787 -api-secret <secret> -web-dir web
+//
+// and open http://<node>:8787/?token=<secret>.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"log/slog"
+	"net/http"
+	"syscall/js"
+	"time"
+
+	"gioui.org/app"
+	"github.com/pkg/errors"
+
+	"voiui/internal/state"
+	"voiui/internal/ui"
+)
+
+var errBrowser = errors.New("not available in the browser")
+
+// remote reaches the voiui that served the page.
+type remote struct {
+	origin string
+	token  string
+	c      *http.Client
+}
+
+func newRemote() remote {
+	location := js.Global().Get("location")
+
+	r := remote{
+		origin: location.Get("origin").String(),
+		c:      &http.Client{Timeout: 10 * time.Second},
+	}
+
+	token := js.Global().Get("URLSearchParams").New(location.Get("search")).Call("get", "token")
+	if !token.IsNull() {
+		r.token = token.String()
+	}
+
+	return r
+}
+
+func (r remote) do(method string, path string) (*http.Response, error) {
+	req, err := http.NewRequest(method, r.origin+path, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create request")
+	}
+
+	if r.token != "" {
+		req.Header.Set("Authorization", "Bearer "+r.token)
+	}
+
+	resp, err := r.c.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to reach voiui")
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, errors.Errorf("voiui answered %s", resp.Status)
+	}
+
+	return resp, nil
+}
+
+func (r remote) fetch() (state.State, error) {
+	var s state.State
+
+	resp, err := r.do(http.MethodGet, "/v1/state")
+	if err != nil {
+		return s, err
+	}
+	defer resp.Body.Close()
+
+	err = json.NewDecoder(resp.Body).Decode(&s)
+	if err != nil {
+		return s, errors.Wrap(err, "failed to decode state")
+	}
+
+	return s, nil
+}
+
+// poll mirrors the remote state, the window keeps its own lock and notes.
+func (r remote) poll(ctx context.Context, updates chan<- state.Update) {
+	t := time.NewTicker(time.Second)
+	defer t.Stop()
+
+	var seen uint64
+	for {
+		s, err := r.fetch()
+
+		// the remote version moves with every change over there, an error
+		// resets it so that the next answer goes through
+		if err != nil || s.Version != seen {
+			seen = s.Version
+
+			updates <- func(local *state.State) error {
+				if err != nil {
+					local.Running = false
+					local.TokenNote = err.Error()
+					return nil
+				}
+
+				note, locked := local.TokenNote, local.Locked
+				*local = s
+				local.TokenNote, local.Locked = note, locked
+				return nil
+			}
+		}
+
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (r remote) URL() string                  { return r.origin }
+func (r remote) DataDir() string              { return "" }
+func (r remote) Tokens() (string, string)     { return "", "" }
+func (r remote) ReloadToken() (string, error) { return "", errBrowser }
+func (r remote) RotateToken() (string, error) { return "", errBrowser }
+
+func (r remote) SealAdmin(passphrase string) (string, error)   { return "", errBrowser }
+func (r remote) UnlockAdmin(passphrase string) (string, error) { return "", errBrowser }
+func (r remote) LockAdmin() error                              { return errBrowser }
+
+func (r remote) StoreTokens() (string, error)  { return "", errBrowser }
+func (r remote) ForgetTokens() (string, error) { return "", errBrowser }
+
+func (r remote) RetryNow() {
+	go func() {
+		resp, err := r.do(http.MethodPost, "/v1/refresh")
+		if err != nil {
+			slog.Error("failed to refresh", "err", err)
+			return
+		}
+		resp.Body.Close()
+	}()
+}
+
+// noLock leaves locking to the browser session.
+type noLock struct{}
+
+func (noLock) Enabled() bool                      { return false }
+func (noLock) Verify(pin string) bool             { return false }
+func (noLock) SetPIN(pin string) error            { return errBrowser }
+func (noLock) Disable() error                     { return errBrowser }
+func (noLock) OSAvailable() bool                  { return false }
+func (noLock) OSAuthenticate(reason string) error { return errBrowser }
+
+func main() {
+	ctx := context.Background()
+
+	r := newRemote()
+
+	updates := make(chan state.Update)
+	store := state.NewStore(state.State{})
+	go store.Run(updates)
+
+	go r.poll(ctx, updates)
+
+	u := ui.New(ui.Config{Controller: r, Lock: noLock{}}, store, updates)
+
+	go func() {
+		w := app.NewWindow(app.Title("Voi Node Monitor"))
+
+		err := u.Run(ctx, w)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}()
+
+	app.Main()
+}
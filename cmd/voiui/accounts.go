@@ -0,0 +1,213 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/algorand/go-algorand-sdk/v2/types"
+	"github.com/pkg/errors"
+
+	"voiui/internal/config"
+	"voiui/internal/state"
+)
+
+// watchList keeps the watched accounts in the config file shared with the
+// profiles.
+type watchList struct {
+	p *profiles
+}
+
+func watchedState(f *config.File) ([]state.Account, []string) {
+	accounts := make([]state.Account, 0, len(f.Accounts))
+	for _, a := range f.Accounts {
+		accounts = append(accounts, state.Account{Address: a.Address, Label: a.Label})
+	}
+
+	return accounts, append([]string(nil), f.Dismissed...)
+}
+
+// publish hands the list to the node and keeps the last readings of
+// accounts that stay on it.
+func (w watchList) publish() {
+	accounts, dismissed := watchedState(w.p.f)
+
+	w.p.n.Watch(accounts)
+
+	w.p.updates <- func(s *state.State) error {
+		for i, a := range accounts {
+			for _, old := range s.Watched {
+				if old.Address == a.Address {
+					old.Label = a.Label
+					accounts[i] = old
+				}
+			}
+		}
+
+		s.Watched = accounts
+		s.Dismissed = dismissed
+		return nil
+	}
+}
+
+func hasLabel(accounts []config.Account, label string) bool {
+	for _, a := range accounts {
+		if a.Label == label {
+			return true
+		}
+	}
+	return false
+}
+
+// label numbers base when the name is taken.
+func (w watchList) label(base string) string {
+	label := base
+	for i := 2; hasLabel(w.p.f.Accounts, label); i++ {
+		label = fmt.Sprintf("%s %d", base, i)
+	}
+
+	return label
+}
+
+// Watch adds addresses that are not on the list yet.
+func (w watchList) Watch(addresses []string) (string, error) {
+	w.p.mu.Lock()
+	defer w.p.mu.Unlock()
+
+	// discovered accounts are named after the profile they were found on
+	base := w.p.active
+	if base == "" {
+		base = "Node"
+	}
+
+	added := 0
+
+	for _, address := range addresses {
+		if contains(w.p.f.Dismissed, address) {
+			w.p.f.Dismissed = remove(w.p.f.Dismissed, address)
+		}
+
+		if w.p.f.Watching(address) {
+			continue
+		}
+
+		w.p.f.Accounts = append(w.p.f.Accounts, config.Account{Address: address, Label: w.label(base)})
+		added++
+	}
+
+	err := w.p.f.Save(w.p.dir)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to save the watch list")
+	}
+
+	w.publish()
+
+	return fmt.Sprintf("Added %d account(s) to the watch list", added), nil
+}
+
+// Add puts a single address on the list, with label or a generated one.
+func (w watchList) Add(address string, label string) (string, error) {
+	address = strings.TrimSpace(address)
+	label = strings.TrimSpace(label)
+
+	_, err := types.DecodeAddress(address)
+	if err != nil {
+		return "", errors.Errorf("invalid address: %s", address)
+	}
+
+	w.p.mu.Lock()
+	defer w.p.mu.Unlock()
+
+	if w.p.f.Watching(address) {
+		return "", errors.New("the address is already watched")
+	}
+
+	if label == "" {
+		label = w.label("Account")
+	}
+
+	w.p.f.Accounts = append(w.p.f.Accounts, config.Account{Address: address, Label: label})
+
+	err = w.p.f.Save(w.p.dir)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to save the watch list")
+	}
+
+	w.publish()
+
+	return "Watching " + label, nil
+}
+
+func (w watchList) Remove(address string) (string, error) {
+	w.p.mu.Lock()
+	defer w.p.mu.Unlock()
+
+	accounts := w.p.f.Accounts[:0]
+	for _, a := range w.p.f.Accounts {
+		if a.Address != address {
+			accounts = append(accounts, a)
+		}
+	}
+	w.p.f.Accounts = accounts
+
+	err := w.p.f.Save(w.p.dir)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to save the watch list")
+	}
+
+	w.publish()
+
+	return "Stopped watching " + address, nil
+}
+
+// Dismiss stops proposing addresses for the watch list.
+func (w watchList) Dismiss(addresses []string) (string, error) {
+	w.p.mu.Lock()
+	defer w.p.mu.Unlock()
+
+	for _, address := range addresses {
+		if !contains(w.p.f.Dismissed, address) {
+			w.p.f.Dismissed = append(w.p.f.Dismissed, address)
+		}
+	}
+
+	err := w.p.f.Save(w.p.dir)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to save the watch list")
+	}
+
+	w.publish()
+
+	return "Accounts will not be proposed again", nil
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func remove(list []string, s string) []string {
+	out := list[:0]
+	for _, v := range list {
+		if v != s {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// label names address after its watch list entry.
+func (p *profiles) label(address string) string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, a := range p.f.Accounts {
+		if a.Address == address {
+			return a.Label
+		}
+	}
+	return ""
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"voiui/internal/node"
+	"voiui/internal/nodeconfig"
+	"voiui/internal/state"
+)
+
+// algodConfig edits config.json and the telemetry in logging.config in
+// the data directory of a local node.
+type algodConfig struct {
+	n *node.Node
+}
+
+func (c algodConfig) Settings() ([]state.NodeSetting, error) {
+	return nodeconfig.Settings(c.n.DataDir())
+}
+
+func (c algodConfig) Set(name string, value string) (string, error) {
+	err := nodeconfig.Set(c.n.DataDir(), name, value)
+	if err != nil {
+		return "", err
+	}
+
+	return "Saved " + name + ", restart the node to apply it", nil
+}
+
+func (c algodConfig) Telemetry() (state.Telemetry, error) {
+	return nodeconfig.Telemetry(c.n.DataDir())
+}
+
+func (c algodConfig) SetTelemetry(enabled bool, name string) (string, error) {
+	err := nodeconfig.SetTelemetry(c.n.DataDir(), enabled, name)
+	if err != nil {
+		return "", err
+	}
+
+	if !enabled {
+		return "Telemetry disabled, restart the node to apply it", nil
+	}
+	return "Telemetry enabled as " + name + ", restart the node to apply it", nil
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"github.com/pkg/errors"
+)
+
+// appearance saves the window's scale and layout.
+type appearance struct {
+	p *profiles
+}
+
+func (a appearance) Scale() float32 {
+	a.p.mu.Lock()
+	defer a.p.mu.Unlock()
+
+	if a.p.f.Window.Scale <= 0 {
+		return 1
+	}
+	return a.p.f.Window.Scale
+}
+
+func (a appearance) Compact() bool {
+	a.p.mu.Lock()
+	defer a.p.mu.Unlock()
+
+	return a.p.f.Window.Compact
+}
+
+func (a appearance) SetScale(scale float32) (string, error) {
+	a.p.mu.Lock()
+	defer a.p.mu.Unlock()
+
+	a.p.f.Window.Scale = scale
+
+	err := a.p.f.Save(a.p.dir)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to save the window scale")
+	}
+
+	return "", nil
+}
+
+func (a appearance) SetCompact(on bool) (string, error) {
+	a.p.mu.Lock()
+	defer a.p.mu.Unlock()
+
+	a.p.f.Window.Compact = on
+
+	err := a.p.f.Save(a.p.dir)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to save the window layout")
+	}
+
+	return "", nil
+}
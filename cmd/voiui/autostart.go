@@ -0,0 +1,25 @@
+package main
+
+import (
+	"voiui/internal/autostart"
+)
+
+// loginStart turns starting at login on and off from the window.
+type loginStart struct{}
+
+func (loginStart) Enabled() bool {
+	return autostart.Enabled()
+}
+
+func (loginStart) Set(on bool) (string, error) {
+	if !on {
+		return "voiui will not start at login", autostart.Disable()
+	}
+
+	err := autostart.Enable()
+	if err != nil {
+		return "", err
+	}
+
+	return "voiui will start at login in the tray", nil
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"voiui/internal/node"
+)
+
+// keyBackup implements ui.KeyBackup on top of the node.
+type keyBackup struct {
+	ctx context.Context
+	n   *node.Node
+}
+
+func (b keyBackup) Backup(passphrase string) (string, error) {
+	data, count, err := b.n.BackupKeys(passphrase)
+	if err != nil {
+		return "", err
+	}
+
+	path := filepath.Join(exportDir(), fmt.Sprintf("voiui-partkeys-%s.bin", time.Now().Format("20060102")))
+
+	err = os.WriteFile(path, data, 0600)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to save key backup")
+	}
+
+	return fmt.Sprintf("Backed up %d key(s) to %s", count, path), nil
+}
+
+func (b keyBackup) Restore(path string, passphrase string) (string, error) {
+	data, err := os.ReadFile(strings.TrimSpace(path))
+	if err != nil {
+		return "", errors.Wrap(err, "failed to read key backup")
+	}
+
+	ctx, cancel := context.WithTimeout(b.ctx, 5*time.Minute)
+	defer cancel()
+
+	installed, skipped, err := b.n.RestoreKeys(ctx, data, passphrase)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("Restored %d key(s), %d were already installed", installed, skipped), nil
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"github.com/pkg/errors"
+)
+
+const (
+	daemonName  = "voiui"
+	daemonTitle = "Voi Node Monitor"
+)
+
+// runDaemon implements `voiui service install [watch flags]` and `voiui
+// service uninstall`, which register the headless monitor with the system
+// so it keeps running across logouts and reboots.
+func runDaemon(argv []string) error {
+	if len(argv) == 0 {
+		return errors.New("usage: voiui service install [watch flags] | voiui service uninstall")
+	}
+
+	switch argv[0] {
+	case "install":
+		// the flags end up in the unit, fail now rather than on every start
+		a := parseArgs("watch", argv[1:])
+		if a.StatusBar != "" {
+			return errors.New("-statusbar is for desktop bars, not the service")
+		}
+
+		return installDaemon(argv[1:])
+	case "uninstall":
+		return uninstallDaemon()
+	default:
+		return errors.Errorf("unknown service command: %s, use install or uninstall", argv[0])
+	}
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const unitTemplate = `[Unit]
+Description=%s
+After=network-online.target
+Wants=network-online.target
+
+[Service]
+ExecStart=%s
+Restart=on-failure
+RestartSec=10
+StandardOutput=journal
+StandardError=journal
+
+[Install]
+WantedBy=default.target
+`
+
+func unitPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get user config dir")
+	}
+
+	return filepath.Join(dir, "systemd", "user", daemonName+".service"), nil
+}
+
+// systemdQuote quotes a word of an ExecStart line.
+func systemdQuote(s string) string {
+	s = strings.ReplaceAll(s, "%", "%%")
+	if s != "" && !strings.ContainsAny(s, " \t\"'\\;$") {
+		return s
+	}
+
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	s = strings.ReplaceAll(s, "$", "$$")
+
+	return `"` + s + `"`
+}
+
+func systemctl(args ...string) error {
+	out, err := exec.Command("systemctl", append([]string{"--user"}, args...)...).CombinedOutput()
+	if err != nil {
+		return errors.Wrapf(err, "systemctl %s: %s", strings.Join(args, " "), strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// installDaemon writes a systemd user unit running `voiui watch` with
+// flags, its output goes to the journal.
+func installDaemon(flags []string) error {
+	exe, err := os.Executable()
+	if err != nil {
+		return errors.Wrap(err, "failed to find the voiui binary")
+	}
+
+	words := []string{systemdQuote(exe), "watch"}
+	for _, f := range flags {
+		words = append(words, systemdQuote(f))
+	}
+
+	path, err := unitPath()
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(filepath.Dir(path), 0755)
+	if err != nil {
+		return errors.Wrap(err, "failed to create the systemd user dir")
+	}
+
+	err = os.WriteFile(path, []byte(fmt.Sprintf(unitTemplate, daemonTitle, strings.Join(words, " "))), 0644)
+	if err != nil {
+		return errors.Wrap(err, "failed to write the unit")
+	}
+
+	err = systemctl("daemon-reload")
+	if err != nil {
+		return err
+	}
+
+	err = systemctl("enable", "--now", daemonName+".service")
+	if err != nil {
+		return err
+	}
+
+	// user units stop at logout unless the user lingers
+	out, err := exec.Command("loginctl", "enable-linger").CombinedOutput()
+	if err != nil {
+		slog.Warn("failed to keep the service running after logout, run loginctl enable-linger", "err", err, "output", strings.TrimSpace(string(out)))
+	}
+
+	fmt.Printf("installed %s, follow it with journalctl --user -u %s -f\n", path, daemonName)
+
+	return nil
+}
+
+func uninstallDaemon() error {
+	path, err := unitPath()
+	if err != nil {
+		return err
+	}
+
+	_, err = os.Stat(path)
+	if os.IsNotExist(err) {
+		return errors.New("the service is not installed")
+	}
+
+	err = systemctl("disable", "--now", daemonName+".service")
+	if err != nil {
+		return err
+	}
+
+	err = os.Remove(path)
+	if err != nil {
+		return errors.Wrap(err, "failed to remove the unit")
+	}
+
+	err = systemctl("daemon-reload")
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("uninstalled", path)
+
+	return nil
+}
+
+// inService reports false, systemd runs watch like any other process.
+func inService(watch func(ctx context.Context) error) (bool, error) {
+	return false, nil
+}
@@ -0,0 +1,21 @@
+//go:build !windows && !linux
+
+package main
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+)
+
+func installDaemon(flags []string) error {
+	return errors.New("voiui service is available on Linux with systemd and on Windows")
+}
+
+func uninstallDaemon() error {
+	return errors.New("voiui service is available on Linux with systemd and on Windows")
+}
+
+func inService(watch func(ctx context.Context) error) (bool, error) {
+	return false, nil
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/sys/windows/registry"
+	"golang.org/x/sys/windows/svc"
+	"golang.org/x/sys/windows/svc/eventlog"
+	"golang.org/x/sys/windows/svc/mgr"
+
+	"voiui/internal/config"
+)
+
+// installDaemon registers `voiui watch` with flags as a service started at
+// boot. It runs as LocalSystem but keeps using the installing user's
+// config dir, its log goes to the Application event log.
+func installDaemon(flags []string) error {
+	exe, err := os.Executable()
+	if err != nil {
+		return errors.Wrap(err, "failed to find the voiui binary")
+	}
+
+	dir, err := config.Dir()
+	if err != nil {
+		return err
+	}
+
+	m, err := mgr.Connect()
+	if err != nil {
+		return errors.Wrap(err, "failed to reach the service manager, run as administrator")
+	}
+	defer m.Disconnect()
+
+	s, err := m.OpenService(daemonName)
+	if err == nil {
+		s.Close()
+		return errors.New("the service is already installed")
+	}
+
+	s, err = m.CreateService(daemonName, exe, mgr.Config{
+		DisplayName: daemonTitle,
+		Description: "Monitors the Voi node and sends alerts without a window.",
+		StartType:   mgr.StartAutomatic,
+	}, append([]string{"watch"}, flags...)...)
+	if err != nil {
+		return errors.Wrap(err, "failed to create the service")
+	}
+	defer s.Close()
+
+	k, err := registry.OpenKey(registry.LOCAL_MACHINE, `SYSTEM\CurrentControlSet\Services\`+daemonName, registry.SET_VALUE)
+	if err != nil {
+		s.Delete()
+		return errors.Wrap(err, "failed to open the service key")
+	}
+	defer k.Close()
+
+	err = k.SetStringsValue("Environment", []string{config.DirEnv + "=" + dir})
+	if err != nil {
+		s.Delete()
+		return errors.Wrap(err, "failed to set the service environment")
+	}
+
+	err = eventlog.InstallAsEventCreate(daemonName, eventlog.Error|eventlog.Warning|eventlog.Info)
+	if err != nil && !strings.Contains(err.Error(), "exists") {
+		s.Delete()
+		return errors.Wrap(err, "failed to register the event log source")
+	}
+
+	err = s.Start()
+	if err != nil {
+		return errors.Wrap(err, "failed to start the service")
+	}
+
+	fmt.Printf("installed the %s service, its log is in the Application event log\n", daemonName)
+
+	return nil
+}
+
+func uninstallDaemon() error {
+	m, err := mgr.Connect()
+	if err != nil {
+		return errors.Wrap(err, "failed to reach the service manager, run as administrator")
+	}
+	defer m.Disconnect()
+
+	s, err := m.OpenService(daemonName)
+	if err != nil {
+		return errors.New("the service is not installed")
+	}
+	defer s.Close()
+
+	_, err = s.Control(svc.Stop)
+	if err != nil {
+		slog.Error("failed to stop the service", "err", err)
+	}
+
+	err = s.Delete()
+	if err != nil {
+		return errors.Wrap(err, "failed to delete the service")
+	}
+
+	err = eventlog.Remove(daemonName)
+	if err != nil {
+		slog.Error("failed to remove the event log source", "err", err)
+	}
+
+	fmt.Printf("uninstalled the %s service\n", daemonName)
+
+	return nil
+}
+
+type eventWriter struct {
+	l *eventlog.Log
+}
+
+func (w eventWriter) Write(p []byte) (int, error) {
+	return len(p), w.l.Info(1, strings.TrimSpace(string(p)))
+}
+
+type daemon struct {
+	watch func(ctx context.Context) error
+}
+
+func (d daemon) Execute(args []string, requests <-chan svc.ChangeRequest, status chan<- svc.Status) (bool, uint32) {
+	status <- svc.Status{State: svc.StartPending}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- d.watch(ctx)
+	}()
+
+	status <- svc.Status{State: svc.Running, Accepts: svc.AcceptStop | svc.AcceptShutdown}
+
+	for {
+		select {
+		case err := <-done:
+			if err != nil {
+				slog.Error("watch failed", "err", err)
+				return false, 1
+			}
+			return false, 0
+		case r := <-requests:
+			switch r.Cmd {
+			case svc.Interrogate:
+				status <- r.CurrentStatus
+			case svc.Stop, svc.Shutdown:
+				status <- svc.Status{State: svc.StopPending}
+				cancel()
+				<-done
+				return false, 0
+			}
+		}
+	}
+}
+
+// inService runs watch under the service manager when it started the
+// process and reports whether it did.
+func inService(watch func(ctx context.Context) error) (bool, error) {
+	ok, err := svc.IsWindowsService()
+	if err != nil || !ok {
+		return false, err
+	}
+
+	l, err := eventlog.Open(daemonName)
+	if err == nil {
+		defer l.Close()
+		log.SetOutput(eventWriter{l})
+	}
+
+	return true, svc.Run(daemonName, daemon{watch})
+}
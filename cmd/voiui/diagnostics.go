@@ -0,0 +1,41 @@
+package main
+
+import (
+	"voiui/internal/config"
+	"voiui/internal/logs"
+	"voiui/internal/state"
+	"voiui/internal/support"
+)
+
+// diagnostics shows what voiui logged in the window and bundles it into
+// reports for support requests.
+type diagnostics struct {
+	dir     string
+	profile string
+	f       *config.File
+	store   *state.Store
+}
+
+func (d diagnostics) Recent() []string {
+	return logs.Recent()
+}
+
+func (d diagnostics) LogPath() string {
+	return logs.Path(d.dir)
+}
+
+func (d diagnostics) Report() (string, error) {
+	path, err := support.Write(exportDir(), support.Report{
+		Version: version,
+		Profile: d.profile,
+		Config:  d.f,
+		State:   d.store.Snapshot(),
+		LogPath: logs.Path(d.dir),
+		Recent:  logs.Recent(),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return "Saved the diagnostic report to " + path, nil
+}
@@ -0,0 +1,209 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"voiui/internal/api"
+	"voiui/internal/config"
+	"voiui/internal/history"
+)
+
+func parseTime(s string) (time.Time, error) {
+	t, err := time.ParseInLocation("2006-01-02", s, time.Local)
+	if err == nil {
+		return t, nil
+	}
+
+	t, err = time.Parse(time.RFC3339, s)
+	if err != nil {
+		return t, errors.Errorf("invalid time %q, use YYYY-MM-DD or RFC 3339", s)
+	}
+
+	return t, nil
+}
+
+// runExport implements `voiui export`.
+func runExport(argv []string) error {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+
+	format := fs.String("format", "csv", "csv, json or report (this week against the last, without rewards)")
+	from := fs.String("from", "", "start date, YYYY-MM-DD or RFC 3339, defaults to 7 days ago")
+	to := fs.String("to", "", "end date, YYYY-MM-DD or RFC 3339, defaults to now")
+	dir := fs.String("dir", ".", "folder to write the export to")
+
+	fs.Parse(argv)
+
+	end := time.Now()
+	if *to != "" {
+		t, err := parseTime(*to)
+		if err != nil {
+			return err
+		}
+		end = t
+	}
+
+	start := end.AddDate(0, 0, -7)
+	if *from != "" {
+		t, err := parseTime(*from)
+		if err != nil {
+			return err
+		}
+		start = t
+	}
+
+	cdir, err := config.Dir()
+	if err != nil {
+		return err
+	}
+
+	// the running instance holds the history open, so it writes the files
+	out, err := filepath.Abs(*dir)
+	if err != nil {
+		return errors.Wrap(err, "invalid folder")
+	}
+
+	req := api.ExportRequest{Format: *format, From: start, To: end, Dir: out}
+
+	paths, running, err := controlExport(filepath.Join(cdir, controlSocket), req)
+	if !running {
+		paths, err = exportDB(cdir, req)
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, p := range paths {
+		fmt.Println(p)
+	}
+
+	return nil
+}
+
+// controlExport asks the running instance for the export, running is
+// false when none answers on the control socket.
+func controlExport(path string, req api.ExportRequest) (paths []string, running bool, err error) {
+	conn, err := net.DialTimeout("unix", path, time.Second)
+	if err != nil {
+		return nil, false, nil
+	}
+	conn.Close()
+
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, true, errors.Wrap(err, "failed to encode export request")
+	}
+
+	resp, err := controlClient(path, 5*time.Minute).Post("http://voiui/v1/export", "application/json", bytes.NewReader(body))
+	if err != nil {
+		return nil, true, errors.Wrap(err, "failed to reach the running voiui")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, true, errors.New("the running voiui keeps no history to export")
+	}
+
+	var reply api.ExportReply
+
+	err = json.NewDecoder(resp.Body).Decode(&reply)
+	if err != nil {
+		return nil, true, errors.Wrap(err, "failed to decode export reply")
+	}
+
+	if reply.Error != "" {
+		return nil, true, errors.New(reply.Error)
+	}
+
+	return reply.Paths, true, nil
+}
+
+// exportDB exports from the history database when no voiui is running.
+func exportDB(dir string, req api.ExportRequest) ([]string, error) {
+	h, err := history.Open(dir, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "close voiui or use Export in its window")
+	}
+	defer h.Close()
+
+	return exportFiles(h, req.Format, req.From, req.To, req.Dir)
+}
+
+// exportFiles writes the history from start to end to dir, the report
+// compares the week before end with the one before it instead.
+func exportFiles(h *history.DB, format string, start time.Time, end time.Time, dir string) ([]string, error) {
+	if format == "report" {
+		c, err := h.Compare(end)
+		if err != nil {
+			return nil, err
+		}
+
+		path, err := history.WriteReport(dir, c)
+		if err != nil {
+			return nil, err
+		}
+
+		return []string{path}, nil
+	}
+
+	e, err := h.Export(start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	return e.WriteFiles(dir, format)
+}
+
+// exporter exports history from the running instance for the UI.
+type exporter struct {
+	h *history.DB
+}
+
+func exportDir() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "."
+	}
+
+	downloads := filepath.Join(home, "Downloads")
+	if _, err := os.Stat(downloads); err == nil {
+		return downloads
+	}
+
+	return home
+}
+
+// Files serves voiui export through the control socket.
+func (x exporter) Files(format string, start time.Time, end time.Time, dir string) ([]string, error) {
+	return exportFiles(x.h, format, start, end, dir)
+}
+
+func (x exporter) Export(format string, days int) (string, error) {
+	end := time.Now()
+
+	paths, err := exportFiles(x.h, format, end.AddDate(0, 0, -days), end, exportDir())
+	if err != nil {
+		return "", err
+	}
+
+	if format == "report" {
+		return "Saved the weekly report to " + paths[0], nil
+	}
+
+	names := make([]string, len(paths))
+	for i, p := range paths {
+		names[i] = filepath.Base(p)
+	}
+
+	return fmt.Sprintf("Exported %s to %s", strings.Join(names, ", "), filepath.Dir(paths[0])), nil
+}
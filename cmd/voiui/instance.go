@@ -0,0 +1,56 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"voiui/internal/config"
+	"voiui/internal/instance"
+	"voiui/internal/state"
+)
+
+// runCommand implements `voiui open` by asking the running instance. It
+// reports false when none is running.
+func runCommand(cmd string) (bool, error) {
+	dir, err := config.Dir()
+	if err != nil {
+		return false, err
+	}
+
+	reply, err := instance.Send(dir, cmd)
+	if err != nil {
+		return false, nil
+	}
+
+	fmt.Print(reply)
+
+	return true, nil
+}
+
+// instanceHandler answers commands from later invocations, open is nil
+// when running without a window.
+func instanceHandler(open func(), store *state.Store) func(cmd string) string {
+	return func(cmd string) string {
+		fields := strings.Fields(cmd)
+		if len(fields) == 0 {
+			return "empty command\n"
+		}
+
+		asJSON := len(fields) > 1 && fields[1] == "-json"
+
+		switch fields[0] {
+		case "open":
+			if open == nil {
+				return "voiui runs without a window\n"
+			}
+			open()
+			return "opened the running voiui\n"
+		case "status":
+			return statusText(store.Snapshot(), asJSON)
+		case "keys":
+			return keysText(store.Snapshot().Keys, asJSON)
+		default:
+			return "unknown command: " + cmd + "\n"
+		}
+	}
+}
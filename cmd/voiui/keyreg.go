@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/algorand/go-algorand-sdk/v2/crypto"
+	"github.com/algorand/go-algorand-sdk/v2/encoding/msgpack"
+	"github.com/algorand/go-algorand-sdk/v2/types"
+	"github.com/pkg/errors"
+
+	"voiui/internal/node"
+)
+
+// keyreg implements ui.Keyreg on top of the node.
+type keyreg struct {
+	ctx context.Context
+	n   *node.Node
+}
+
+func keyregName(tx types.Transaction, online bool) string {
+	status := "offline"
+	if online {
+		status = "online"
+	}
+
+	return fmt.Sprintf("keyreg-%s-%s.txn", status, tx.Sender.String()[:8])
+}
+
+// Export writes the unsigned transaction in the format goal clerk sign
+// reads.
+func (k keyreg) Export(keyID string, online bool) (string, error) {
+	ctx, cancel := context.WithTimeout(k.ctx, 30*time.Second)
+	defer cancel()
+
+	tx, err := k.n.Keyreg(ctx, keyID, online)
+	if err != nil {
+		return "", err
+	}
+
+	path := filepath.Join(exportDir(), keyregName(tx, online))
+
+	err = os.WriteFile(path, msgpack.Encode(types.SignedTxn{Txn: tx}), 0600)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to save keyreg transaction")
+	}
+
+	return fmt.Sprintf("Saved %s, sign it with goal clerk sign or a wallet and submit it", path), nil
+}
+
+func (k keyreg) Wallets() ([]string, error) {
+	return k.n.KMDWallets()
+}
+
+func (k keyreg) Unlock(wallet string, password string) ([]string, error) {
+	return k.n.KMDAccounts(wallet, password)
+}
+
+func (k keyreg) Submit(keyID string, online bool, wallet string, password string) (string, error) {
+	ctx, cancel := context.WithTimeout(k.ctx, 30*time.Second)
+	defer cancel()
+
+	tx, err := k.n.Keyreg(ctx, keyID, online)
+	if err != nil {
+		return "", err
+	}
+
+	signed, err := k.n.SignKMD(tx, wallet, password)
+	if err != nil {
+		return "", err
+	}
+
+	return k.submit(signed)
+}
+
+// Unsigned encodes the transaction to be signed elsewhere.
+func (k keyreg) Unsigned(keyID string, online bool) (string, string, error) {
+	ctx, cancel := context.WithTimeout(k.ctx, 30*time.Second)
+	defer cancel()
+
+	tx, err := k.n.Keyreg(ctx, keyID, online)
+	if err != nil {
+		return "", "", err
+	}
+
+	return base64.StdEncoding.EncodeToString(msgpack.Encode(types.SignedTxn{Txn: tx})), crypto.GetTxID(tx), nil
+}
+
+// SubmitSigned takes the transaction back from the signer, base64 encoded.
+func (k keyreg) SubmitSigned(encoded string, txid string) (string, error) {
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return "", errors.New("the signed transaction is not valid base64")
+	}
+
+	var stx types.SignedTxn
+
+	err = msgpack.Decode(raw, &stx)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to decode the signed transaction")
+	}
+
+	if stx.Txn.Type != types.KeyRegistrationTx {
+		return "", errors.Errorf("expected a key registration, got %s", stx.Txn.Type)
+	}
+
+	if id := crypto.GetTxID(stx.Txn); id != txid {
+		return "", errors.Errorf("signed transaction %s is not the one shown, %s", id, txid)
+	}
+
+	if stx.Sig == (types.Signature{}) && stx.Msig.Version == 0 && len(stx.Lsig.Logic) == 0 {
+		return "", errors.New("the transaction is not signed")
+	}
+
+	return k.submit(raw)
+}
+
+// submit sends signed and waits for it to be confirmed.
+func (k keyreg) submit(signed []byte) (string, error) {
+	ctx, cancel := context.WithTimeout(k.ctx, 2*time.Minute)
+	defer cancel()
+
+	id, err := k.n.SubmitRaw(ctx, signed)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to submit keyreg transaction")
+	}
+
+	round, err := k.n.WaitConfirmed(ctx, id)
+	if err != nil {
+		return "", errors.Wrapf(err, "submitted %s", id)
+	}
+
+	return fmt.Sprintf("Key registration %s confirmed in round %d", id, round), nil
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"github.com/pkg/errors"
+
+	"voiui/internal/i18n"
+)
+
+// language saves the window's language choice and switches to it.
+type language struct {
+	p *profiles
+}
+
+func (l language) Get() string {
+	l.p.mu.Lock()
+	defer l.p.mu.Unlock()
+
+	return l.p.f.Language
+}
+
+func (l language) Set(code string) (string, error) {
+	l.p.mu.Lock()
+	defer l.p.mu.Unlock()
+
+	l.p.f.Language = code
+
+	err := l.p.f.Save(l.p.dir)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to save the language")
+	}
+
+	i18n.Set(code)
+
+	return "", nil
+}
@@ -2,383 +2,866 @@ package main
 
 import (
 	"context"
-	_ "embed"
-	"encoding/json"
 	"flag"
 	"fmt"
-	"image/color"
 	"log"
+	"log/slog"
+	"net"
 	"net/http"
 	"os"
+	"os/signal"
 	"path/filepath"
 	"strings"
+	"sync"
+	"syscall"
 	"time"
 
 	"gioui.org/app"
-	"gioui.org/font/gofont"
 	"gioui.org/io/system"
-	"gioui.org/layout"
-	"gioui.org/op"
 	"gioui.org/unit"
-	"gioui.org/widget/material"
-	"github.com/algorand/go-algorand-sdk/v2/client/v2/algod"
-	"github.com/getlantern/systray"
 	"github.com/pkg/errors"
+
+	"voiui/internal/actions"
+	"voiui/internal/alert"
+	"voiui/internal/api"
+	"voiui/internal/applock"
+	"voiui/internal/clock"
+	"voiui/internal/config"
+	"voiui/internal/explorer"
+	"voiui/internal/health"
+	"voiui/internal/history"
+	"voiui/internal/hooks"
+	"voiui/internal/hw"
+	"voiui/internal/i18n"
+	"voiui/internal/ical"
+	"voiui/internal/instance"
+	"voiui/internal/logs"
+	"voiui/internal/mqtt"
+	"voiui/internal/netcheck"
+	"voiui/internal/node"
+	"voiui/internal/notify"
+	"voiui/internal/panels"
+	"voiui/internal/plugins"
+	"voiui/internal/portcheck"
+	"voiui/internal/relay"
+	"voiui/internal/release"
+	"voiui/internal/rules"
+	"voiui/internal/selfupdate"
+	"voiui/internal/sound"
+	"voiui/internal/state"
+	"voiui/internal/statuspage"
+	"voiui/internal/sysmon"
+	"voiui/internal/templates"
+	"voiui/internal/tray"
+	"voiui/internal/txwatch"
+	"voiui/internal/ui"
+	"voiui/internal/ups"
 )
 
-//go:embed voi.ico
-var voiIcon []byte
+const controlSocket = "control.sock"
 
-type state struct {
-	running bool
+// version is set at build time with -ldflags "-X main.version=x.y.z".
+var version = "dev"
 
-	round         uint64
-	participating bool
-	progress      float32
+// control is what the control socket steers.
+type control struct {
+	n *node.Node
+}
 
-	prevBlockDuration time.Duration
-	currBlockAt       time.Time
+func (c control) RetryNow() {
+	c.n.RetryNow()
 }
 
-type updateCb func(*state) error
+func (c control) Silence(until time.Time) {
+	c.n.Alerts().Silence(until)
+}
 
-type program struct {
-	url   string
-	token string
+func newAPI(addr string, secret string) (*api.Server, error) {
+	if secret == "" {
+		secret = os.Getenv("VOIUI_API_SECRET")
+	}
 
-	ac *algod.Client
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid -api-listen address")
+	}
 
-	updates chan updateCb
+	ip := net.ParseIP(host)
+	if secret == "" && host != "localhost" && (ip == nil || !ip.IsLoopback()) {
+		return nil, errors.New("-api-secret is required when the API listens beyond localhost")
+	}
 
-	s state
+	return api.New(addr, secret), nil
 }
 
-func (p *program) runFrontend(ctx context.Context, w *app.Window) error {
-	th := material.NewTheme(gofont.Collection())
+// monitor is the core every subcommand shares: the node of the selected
+// profile, the state it feeds and the history.
+type monitor struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	dir   string
+	f     *config.File
+	name  string
+	prof  config.Profile
+	n     *node.Node
+	hist  *history.DB
+	profs *profiles
+
+	updates chan state.Update
+	store   *state.Store
+	ui      ui.Config
+}
 
-	t := time.NewTicker(time.Millisecond * 20)
-	defer t.Stop()
+func (m *monitor) close() {
+	m.cancel()
+	if m.hist != nil {
+		m.hist.Close()
+	}
+}
 
-	var ops op.Ops
-	for {
-		select {
-		case <-t.C:
-			if p.s.prevBlockDuration != 0 {
-				diff := time.Since(p.s.currBlockAt)
-				p.s.progress = 1 - float32(diff)/float32(p.s.prevBlockDuration)
-			}
-			w.Invalidate()
-		case <-ctx.Done():
-			log.Println("context done")
-			return ctx.Err()
-		case e := <-p.updates:
-			err := e(&p.s)
-			if err != nil {
-				return errors.Wrap(err, "failed to update state")
-			}
-			w.Invalidate()
-		case e := <-w.Events():
-			switch e := e.(type) {
-			case system.DestroyEvent:
-				return e.Err
-			case system.FrameEvent:
-				type (
-					C = layout.Context
-					D = layout.Dimensions
-				)
-
-				gtx := layout.NewContext(&ops, e)
-
-				layout.Flex{Axis: layout.Vertical}.Layout(gtx,
-					layout.Rigid(func(gtx C) D {
-						in := layout.UniformInset(unit.Dp(8))
-						return in.Layout(gtx, func(gtx C) D {
-							return layout.Flex{Axis: layout.Vertical}.Layout(
-								gtx,
-								layout.Rigid(func(gtx C) D {
-									title := material.Caption(th, "Address:")
-									return title.Layout(gtx)
-								}),
-								layout.Rigid(func(gtx C) D {
-									running := material.Body1(th, p.url)
-									return running.Layout(gtx)
-								}),
-							)
-						})
-					}),
-					layout.Rigid(func(gtx C) D {
-						in := layout.UniformInset(unit.Dp(8))
-
-						var text string
-						if p.s.running {
-							text = "Running"
-						} else {
-							text = "Not Running"
-						}
-
-						title := material.Subtitle1(th, text)
-						if p.s.running {
-							title.Color = color.NRGBA{R: 0x00, G: 0xaa, B: 0x00, A: 0xff}
-						} else {
-							title.Color = color.NRGBA{R: 0xaa, G: 0x00, B: 0x00, A: 0xff}
-						}
-
-						return in.Layout(gtx, func(gtx C) D { return title.Layout(gtx) })
-					}),
-					layout.Rigid(func(gtx C) D {
-						in := layout.UniformInset(unit.Dp(8))
-						return in.Layout(gtx, func(gtx C) D {
-							return layout.Flex{Axis: layout.Vertical}.Layout(
-								gtx,
-								layout.Rigid(func(gtx C) D {
-									title := material.Caption(th, "Last round:")
-									return title.Layout(gtx)
-								}),
-								layout.Rigid(func(gtx C) D {
-									running := material.Body1(th, fmt.Sprintf("%d", p.s.round))
-									return running.Layout(gtx)
-								}),
-							)
-						})
-					}),
-					layout.Rigid(func(gtx C) D {
-						in := layout.UniformInset(unit.Dp(8))
-
-						var text string
-						if p.s.participating {
-							text = "Participating"
-						} else {
-							text = "Not participating"
-						}
-
-						title := material.Subtitle1(th, text)
-						if p.s.participating {
-							title.Color = color.NRGBA{R: 0x00, G: 0xaa, B: 0x00, A: 0xff}
-						} else {
-							title.Color = color.NRGBA{R: 0xaa, G: 0x00, B: 0x00, A: 0xff}
-						}
-
-						return in.Layout(gtx, func(gtx C) D { return title.Layout(gtx) })
-					}),
-					layout.Rigid(func(gtx C) D {
-						bar := material.ProgressBar(th, p.s.progress)
-						return bar.Layout(gtx)
-					}),
-				)
-
-				e.Frame(gtx.Ops)
-			}
+// start sets up the node of the profile a selects until parent is done,
+// the node itself is started by the caller.
+func start(parent context.Context, a args) (_ *monitor, err error) {
+	if a.Path != "" && (a.Algod != "" || a.Token != "" || a.APIToken != "") {
+		return nil, errors.New("cannot specify -path with -algod, -token or -api-token")
+	}
+
+	dir, err := config.Dir()
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := config.Load(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	i18n.Set(f.Language)
+
+	if a.SSH != "" && a.Demo {
+		return nil, errors.New("cannot specify -ssh with -demo")
+	}
+
+	if a.AccountsOnly && (a.Path != "" || a.SSH != "" || a.Demo) {
+		return nil, errors.New("cannot specify -accounts-only with -path, -ssh or -demo")
+	}
+
+	adhoc := a.adhoc()
+
+	if a.Profile != "" && adhoc {
+		return nil, errors.New("cannot specify -profile with -path, -algod, -ssh, -demo, -accounts-only or -template")
+	}
+
+	if a.SaveProfile != "" && !adhoc {
+		return nil, errors.New("-save-profile requires -path, -algod, -ssh, -demo, -accounts-only or -template")
+	}
+
+	prof := config.Profile{
+		Name:           a.SaveProfile,
+		Path:           a.Path,
+		Algod:          a.Algod,
+		Demo:           a.Demo,
+		AccountsOnly:   a.AccountsOnly,
+		Network:        a.Network,
+		Reference:      a.Reference,
+		ReferenceToken: a.ReferenceToken,
+		Indexer:        a.Indexer,
+		IndexerToken:   a.IndexerToken,
+		TLSCA:          a.TLS.CAFile,
+		TLSCert:        a.TLS.CertFile,
+		TLSKey:         a.TLS.KeyFile,
+		TLSInsecure:    a.TLS.Insecure,
+		SSH:            a.SSH,
+		SSHKey:         a.SSHKey,
+		SSHKnownHosts:  a.SSHKnownHosts,
+	}
+
+	if a.Template != "" {
+		t, ok := templates.Get(a.Template)
+		if !ok {
+			return nil, errors.Errorf("unknown template: %s, see voiui templates", a.Template)
 		}
+		prof = t.Apply(prof)
 	}
-}
 
-type Participation struct {
-	Address             string  `json:"address"`
-	EffectiveFirstValid *uint64 `json:"effective-first-valid"`
-	EffectiveLastValid  *uint64 `json:"effective-last-valid"`
-	Id                  string  `json:"id"`
-}
+	name := a.Profile
+	if name == "" && !adhoc {
+		name = f.LastProfile
+	}
 
-func (p *program) runBackend() error {
-	status, err := p.ac.Status().Do(context.Background())
+	if name != "" {
+		p, ok := f.Profile(name)
+		if !ok {
+			return nil, errors.Errorf("unknown profile: %s", name)
+		}
+		prof = p
+	}
+
+	apiToken := a.APIToken
+	adminToken := a.Token
+
+	if prof.Algod != "" && name == "" {
+		if apiToken == "" {
+			apiToken = os.Getenv("VOIUI_ALGOD_API_TOKEN")
+		}
+
+		if adminToken == "" {
+			adminToken = os.Getenv("VOIUI_ALGOD_TOKEN")
+		}
+	}
+
+	e, err := resolve(prof, dir, apiToken, adminToken)
 	if err != nil {
-		return errors.Wrap(err, "failed to get status")
+		return nil, err
 	}
 
-	round := status.LastRound
+	save := a.SaveProfile != ""
+	if save {
+		f.SetProfile(prof)
+		name = a.SaveProfile
+	}
 
-	p.updates <- func(s *state) error {
-		s.round = round
-		s.running = true
-		return nil
+	if name != "" && name != f.LastProfile && !a.Once {
+		f.LastProfile = name
+		save = true
 	}
 
-	for {
-		status, err = p.ac.StatusAfterBlock(status.LastRound).Do(context.Background())
+	if save {
+		err = f.Save(dir)
 		if err != nil {
-			p.updates <- func(s *state) error {
-				s.running = false
-				return nil
-			}
-			return errors.Wrap(err, "failed to get status")
+			return nil, err
 		}
+	}
 
-		round := status.LastRound
-		currBlockAt := time.Now()
-
-		p.updates <- func(s *state) error {
-			s.round = round
-			s.running = true
+	updates := make(chan state.Update)
 
-			s.prevBlockDuration = currBlockAt.Sub(s.currBlockAt)
-			s.currBlockAt = currBlockAt
-			return nil
+	ctx, cancel := context.WithCancel(parent)
+	defer func() {
+		if err != nil {
+			cancel()
 		}
+	}()
 
-		err = func() error {
-			req, err := http.NewRequest("GET", fmt.Sprintf("%s/v2/participation", p.url), nil)
+	ncfg := nodeConfig(prof, e)
+	ncfg.ConfigDir = dir
+	ncfg.ElevateFor = a.ElevateFor
+	ncfg.StakeChange = a.StakeChange
+	ncfg.Grace = a.Grace
+	ncfg.Renew = node.RenewConfig{
+		For:      time.Duration(a.RenewDays) * 24 * time.Hour,
+		Wallet:   a.RenewWallet,
+		Password: os.Getenv("VOIUI_KMD_PASSWORD"),
+	}
+	ncfg.RetryMin = a.RetryMin
+	ncfg.RetryMax = a.RetryMax
+	ncfg.MaxRetries = a.MaxRetries
+	ncfg.Timeout = a.Timeout
+
+	hist, err := history.Open(dir, updates)
+	if err != nil {
+		slog.Warn("history disabled", "err", err)
+	} else {
+		defer func() {
 			if err != nil {
-				return errors.Wrap(err, "failed to create participation request")
+				hist.Close()
 			}
+		}()
+		ncfg.History = hist
+	}
+
+	profs := &profiles{
+		dir:     dir,
+		updates: updates,
+		f:       f,
+		active:  name,
+		service: prof.Service,
+	}
 
-			req.Header.Set("X-Algo-API-Token", p.token)
+	var proposals fanout
+
+	if len(f.OnProposal) > 0 {
+		proposals = append(proposals, actions.New(actions.Config{
+			Actions: f.OnProposal,
+			Label:   profs.label,
+			Tray:    tray.Flash,
+		}))
+	}
+
+	notifiers := alert.Notifiers{alert.LogNotifier{}}
+
+	if player := sound.New(f.Sounds); player.Enabled() {
+		notifiers = append(notifiers, player)
+	}
+
+	hks, err := hooks.New(f.Hooks)
+	if err != nil {
+		return nil, err
+	}
+	if hks.Enabled() {
+		hks.Label = profs.label
+		notifiers = append(notifiers, hks)
+		proposals = append(proposals, hks)
+	}
+
+	var mailer *notify.SMTP
+
+	if f.Email != nil {
+		mailer, err = notify.NewSMTP(*f.Email)
+		if err != nil {
+			return nil, err
+		}
+
+		notifiers = append(notifiers, mailer)
+	}
+
+	for _, c := range f.Push {
+		push, err := notify.NewPush(c)
+		if err != nil {
+			return nil, err
+		}
+
+		notifiers = append(notifiers, push)
+	}
+
+	plugs, err := plugins.New(filepath.Join(dir, "plugins"), updates)
+	if err != nil {
+		return nil, err
+	}
+	if plugs.Len() > 0 {
+		notifiers = append(notifiers, plugs)
+	}
+
+	if len(notifiers) > 1 {
+		ncfg.Notifier = notifiers
+	}
+
+	if len(proposals) > 0 {
+		ncfg.Proposals = proposals
+	}
+
+	n, err := node.New(ncfg, updates)
+	if err != nil {
+		return nil, err
+	}
+
+	applyAlerts(n.Alerts(), prof.Alerts)
+
+	profs.n = n
+
+	lock, err := applock.Load(dir)
+	if err != nil {
+		return nil, err
+	}
 
-			resp, err := http.DefaultClient.Do(req)
+	watched, dismissed := watchedState(f)
+	n.Watch(watched)
+
+	initial := state.State{
+		AdminSealed:  e.sealed,
+		AdminLocked:  e.sealed,
+		Profile:      name,
+		AlertsMuted:  prof.Alerts.Muted,
+		AccountsOnly: prof.AccountsOnly,
+		Service:      serviceState(prof.Service),
+		Watched:      watched,
+		Dismissed:    dismissed,
+	}
+
+	if e.found != nil {
+		initial.DataDir = e.path
+		initial.DataDirs = e.found
+	}
+
+	store := state.NewStore(initial)
+
+	go store.Run(updates)
+
+	if hist != nil {
+		n.Alerts().Record(hist.Event)
+		hist.SetEarnings(n.Earned)
+
+		go func() {
+			now := time.Now()
+
+			events, err := hist.Events(now.Add(-7*24*time.Hour), now)
 			if err != nil {
-				return errors.Wrap(err, "failed to do participation request")
+				slog.Error("failed to load event history", "err", err)
+				return
 			}
 
-			defer resp.Body.Close()
+			n.Alerts().Restore(events)
+		}()
 
-			if resp.StatusCode >= 400 {
-				return errors.Errorf("failed to check participation: %s", resp.Status)
-			}
+		go hist.Run(ctx)
+	}
 
-			var items []Participation
+	cfg := ui.Config{
+		Controller:  n,
+		Lock:        lock,
+		Profiles:    profs,
+		Accounts:    watchList{profs},
+		Explorer:    explorer.New(f.Explorer),
+		Keyreg:      keyreg{ctx, n},
+		Backup:      keyBackup{ctx, n},
+		Service:     nodeService{profs},
+		Autostart:   loginStart{},
+		Language:    language{profs},
+		Appearance:  appearance{profs},
+		Diagnostics: diagnostics{dir, name, f, store},
+		Alerts:      n.Alerts(),
+	}
 
-			err = json.NewDecoder(resp.Body).Decode(&items)
+	if hist != nil {
+		cfg.Exporter = exporter{hist}
+	}
+
+	if !prof.AccountsOnly {
+		cfg.NodeConfig = algodConfig{n}
+	}
+
+	if len(initial.DataDirs) > 1 {
+		cfg.DataDirs = dataDirs{profs}
+	}
+
+	if mailer != nil {
+		cfg.Email = mailer
+		go mailer.Run(ctx)
+	}
+
+	if plugs.Len() > 0 {
+		go plugs.Run(ctx, n.Alerts())
+	}
+
+	return &monitor{
+		ctx:     ctx,
+		cancel:  cancel,
+		dir:     dir,
+		f:       f,
+		name:    name,
+		prof:    prof,
+		n:       n,
+		hist:    hist,
+		profs:   profs,
+		updates: updates,
+		store:   store,
+		ui:      cfg,
+	}, nil
+}
+
+// fanout tells several receivers about proposals.
+type fanout []node.Proposals
+
+func (f fanout) Proposed(round uint64, address string, payout uint64) {
+	for _, p := range f {
+		p.Proposed(round, address, payout)
+	}
+}
+
+// services starts what a enables around the node: the API, exporters and
+// host monitors.
+func (m *monitor) services(a args) error {
+	ctx, dir, f, prof, n, store, updates := m.ctx, m.dir, m.f, m.prof, m.n, m.store, m.updates
+	cfg := &m.ui
+
+	calendar := ical.New(store, f.Calendar)
+
+	if a.Automation && a.APIListen == "" {
+		return errors.New("-automation requires -api-listen")
+	}
+
+	if a.WebDir != "" && a.APIListen == "" {
+		return errors.New("-web-dir requires -api-listen")
+	}
+
+	if a.APIListen != "" {
+		srv, err := newAPI(a.APIListen, a.APISecret)
+		if err != nil {
+			return err
+		}
+
+		srv.Handle("/v1/events", api.Webhook(n.Alerts()))
+		srv.Handle("/v1/calendar.ics", calendar)
+		srv.Handle("/v1/health", health.Handler(store))
+
+		if m.hist != nil {
+			srv.Handle("/v1/grafana/", api.Grafana(m.hist))
+		}
+
+		if a.WebDir != "" {
+			ctl := api.Control(store, control{n})
+			srv.Handle("/v1/state", ctl)
+			srv.Handle("/v1/refresh", ctl)
+			srv.Handle("/v1/alerts/silence", ctl)
+			srv.Public(http.FileServer(http.Dir(a.WebDir)))
+		}
+
+		if a.Automation {
+			drv := ui.NewDriver()
+			cfg.Driver = drv
+			srv.Handle("/v1/ui/", api.Automation(drv))
+		}
+
+		go func() {
+			err := srv.Run(ctx)
 			if err != nil {
-				return errors.Wrap(err, "failed to decode participation response")
+				slog.Error("api server failed", "err", err)
 			}
+		}()
+	}
 
-			participating := false
+	if a.Control {
+		srv := api.New("", "")
+		srv.Handle("/v1/", api.Control(store, control{n}))
+		srv.Handle("/v1/health", health.Handler(store))
 
-			for _, item := range items {
-				if item.EffectiveFirstValid != nil && *item.EffectiveFirstValid >= status.LastRound && item.EffectiveLastValid != nil && *item.EffectiveLastValid <= status.LastRound {
-					participating = true
-					break
-				}
-			}
+		if m.hist != nil {
+			srv.Handle("/v1/export", api.Export(exporter{m.hist}))
+		}
 
-			p.updates <- func(s *state) error {
-				s.participating = participating
-				return nil
+		go func() {
+			err := srv.RunSocket(ctx, filepath.Join(dir, controlSocket))
+			if err != nil {
+				slog.Error("control api failed", "err", err)
 			}
-
-			return nil
 		}()
+	}
+
+	if a.ICalFile != "" {
+		go calendar.Run(ctx, a.ICalFile, 15*time.Minute)
+	}
 
+	if a.UPS != "" {
+		src, err := ups.Parse(a.UPS)
 		if err != nil {
 			return err
 		}
+
+		go ups.NewMonitor(src, 10*time.Second, n.Alerts(), updates).Run(ctx)
 	}
-}
 
-func run(a args) error {
-	if a.Path != "" && (a.Algod != "" || a.Token != "") {
-		return errors.New("cannot specify -path with -algod or -token")
+	// without a node of our own, host and release checks have nothing to watch
+	local := !prof.AccountsOnly
+
+	if a.ReleaseFeed != "" && local {
+		c := release.NewChecker(a.ReleaseFeed, 6*time.Hour, n.Version, n.Alerts(), updates, tray.SetNotice)
+		go c.Run(ctx)
+	}
+
+	if !a.NoSelfUpdate && version != "dev" {
+		go selfupdate.New(version, updates).Run(ctx)
 	}
 
-	var url string
-	var token string
+	if a.TempWarn > 0 && local {
+		go hw.NewMonitor(time.Minute, a.TempWarn, n.Alerts(), updates).Run(ctx)
+	}
 
-	if a.Algod != "" {
-		url = a.Algod
-		token = a.Token
-	} else {
-		if a.Path == "" {
-			a.Path = "data"
+	if a.Probe > 0 {
+		go n.Probe(ctx, a.Probe, a.SlowRTT)
+	}
+
+	if a.ClockDrift > 0 && local {
+		go clock.New(a.NTPServer, 15*time.Minute, a.ClockDrift, n.Alerts(), updates).Run(ctx)
+	}
+
+	if a.IndexerLag > 0 {
+		go n.WatchIndexer(ctx, time.Minute, a.IndexerLag)
+	}
+
+	if a.Follower {
+		go n.WatchFollower(ctx, 15*time.Second, a.PipelineStall)
+	}
+
+	if a.Relay {
+		go relay.New(n, a.RelayName, a.DNSBootstrap, time.Minute, n.Alerts(), updates).Run(ctx)
+	}
+
+	if a.PortCheck != "" && a.PortCheckEvery > 0 && local {
+		go portcheck.New(a.PortCheck, a.GossipPort, n.DataDir, a.PortCheckEvery, n.Alerts(), updates).Run(ctx)
+	}
+
+	if a.Heartbeat != "" && a.HeartbeatEvery > 0 {
+		hb, err := health.NewHeartbeat(a.Heartbeat, a.HeartbeatEvery, store)
+		if err != nil {
+			return err
+		}
+
+		go hb.Run(ctx)
+	}
+
+	if a.NetCheck > 0 && local {
+		var relays []string
+		if a.Relays != "" {
+			relays = strings.Split(a.Relays, ",")
 		}
 
-		addrBytes, err := os.ReadFile(filepath.Join(a.Path, "algod.net"))
+		go netcheck.New(relays, a.DNSBootstrap, n.Network, a.NetCheck, updates).Run(ctx)
+	}
+
+	if a.Resources > 0 && local {
+		go sysmon.NewMonitor(sysmon.Config{
+			DataDir:   n.DataDir,
+			ConfigDir: dir,
+			Interval:  a.Resources,
+			DiskWarn:  a.DiskWarn,
+			FullDays:  a.DiskFullDays,
+		}, n.Alerts(), updates).Run(ctx)
+	}
+
+	if len(f.Panels) > 0 {
+		panels.New(f.Panels, updates).Run(ctx)
+	}
+
+	go n.Alerts().Run(ctx)
+
+	if len(f.Rules) > 0 {
+		e, err := rules.New(f.Rules, store, n.Alerts())
+		if err != nil {
+			return err
+		}
+
+		go e.Run(ctx)
+	}
+
+	if f.MQTT != nil {
+		p, err := mqtt.New(*f.MQTT, m.name, store)
 		if err != nil {
-			return errors.Wrap(err, "failed to read algod.net")
+			return err
 		}
 
-		addr := strings.TrimSpace(string(addrBytes))
+		go p.Run(ctx)
+	}
+
+	if a.TxnDir != "" {
+		w := txwatch.New(a.TxnDir, 3*time.Second, n, updates)
+		go w.Run(ctx)
 
-		tokenBytes, err := os.ReadFile(filepath.Join(a.Path, "algod.admin.token"))
+		cfg.Txns = w
+	}
+
+	if (a.StatusPageDir != "" || a.StatusPageS3 != "") && a.StatusPageEvery > 0 {
+		g := statuspage.New(statuspage.Config{
+			Dir:      a.StatusPageDir,
+			S3:       a.StatusPageS3,
+			Interval: a.StatusPageEvery,
+		}, store)
+		go g.Run(ctx)
+	}
+
+	if a.KumaPush != "" && a.KumaEvery > 0 {
+		k, err := health.NewKuma(a.KumaPush, a.KumaEvery, store)
 		if err != nil {
-			return errors.Wrap(err, "failed to read algod.admin.token")
+			return err
 		}
 
-		token = strings.TrimSpace(string(tokenBytes))
-		url = fmt.Sprintf("http://%s", addr)
+		go k.Run(ctx)
 	}
 
-	ac, err := algod.MakeClient(url, token)
+	return nil
+}
+
+func run(a args) error {
+	if a.NoWindow && a.Minimized {
+		return errors.New("cannot specify -no-window with -minimized")
+	}
+
+	dir, err := config.Dir()
 	if err != nil {
-		return errors.Wrap(err, "failed to make algod client")
+		return err
+	}
+
+	if firstRun(a, dir) {
+		setup(dir)
 	}
 
-	updates := make(chan updateCb)
+	inst, err := instance.Listen(dir)
+	if err == instance.ErrRunning {
+		running, err := runCommand("open")
+		if err == nil && !running {
+			err = errors.New("another voiui holds the instance socket but does not answer")
+		}
+		return err
+	}
+	if err != nil {
+		return err
+	}
 
-	ctx, cancel := context.WithCancel(context.Background())
+	// only the instance writes the log file, the others would fight over it
+	logFile, err := logs.Setup(dir, a.Verbose)
+	if err != nil {
+		return err
+	}
+	defer logFile.Close()
 
-	p := &program{
-		url:     url,
-		token:   token,
-		ac:      ac,
-		updates: updates,
-		s: state{
-			progress: 1.0,
-		},
+	m, err := start(context.Background(), a)
+	if err != nil {
+		return err
 	}
+	defer m.close()
+
+	err = m.services(a)
+	if err != nil {
+		return err
+	}
+
+	ctx, f, name, n, profs, store, updates := m.ctx, m.f, m.name, m.n, m.profs, m.store, m.updates
+	cancel := m.cancel
+
+	go func() {
+		var last string
+		lastUnacked := -1
+
+		store.Watch(ctx, func(s state.State) {
+			status := fmt.Sprintf("round %d, %s", s.Round, health.Report(s, time.Now()).Status)
+			if status != last {
+				last = status
+				tray.SetStatus(status)
+			}
+
+			unacked := 0
+			for _, inc := range s.Alerts {
+				if inc.Acked.IsZero() {
+					unacked++
+				}
+			}
+			if unacked != lastUnacked {
+				lastUnacked = unacked
+				tray.SetAlerts(unacked)
+			}
+		})
+	}()
+
+	u := ui.New(m.ui, store, updates)
+
+	var (
+		winMu sync.Mutex
+		win   *app.Window
+	)
+
+	// openWindow raises the open window instead of stacking another one on
+	// top of it, opts only apply to a new one.
+	openWindow := func(opts ...app.Option) {
+		winMu.Lock()
+		defer winMu.Unlock()
+
+		if win != nil {
+			win.Perform(system.ActionRaise)
+			return
+		}
 
-	runWindow := func() {
 		w := app.NewWindow()
-		w.Option(
+		w.Option(append([]app.Option{
 			app.Title("Voi Node Monitor"),
 			app.Size(unit.Dp(300), unit.Dp(200)),
 			app.MinSize(unit.Dp(300), unit.Dp(200)),
-		)
+		}, opts...)...)
+		win = w
 
-		err := p.runFrontend(ctx, w)
-		fmt.Println("run exited", err)
-		if err != nil {
-			log.Fatal(err)
-		}
-	}
+		go func() {
+			err := u.Run(ctx, w)
+			slog.Debug("window closed", "err", err)
 
-	go func() {
-		for {
-			err := p.runBackend()
-			if err != nil {
-				log.Printf("error: %v", err)
+			winMu.Lock()
+			win = nil
+			winMu.Unlock()
+
+			if err != nil && ctx.Err() == nil {
+				log.Fatal(err)
 			}
+		}()
+	}
+
+	var mini *app.Window
+
+	// toggleMini opens the mini widget or closes it when it is open.
+	toggleMini := func() {
+		winMu.Lock()
+		defer winMu.Unlock()
+
+		if mini != nil {
+			mini.Perform(system.ActionClose)
+			return
 		}
-	}()
 
-	systray.Run(func() {
-		// TODO: set icon
-		systray.SetIcon(voiIcon)
-		systray.SetTitle("Voi Node Monitor")
+		w := app.NewWindow(
+			app.Title("Voi Node Monitor"),
+			app.Decorated(false),
+			app.Size(unit.Dp(160), unit.Dp(56)),
+			app.MinSize(unit.Dp(120), unit.Dp(48)),
+		)
+		mini = w
+		tray.SetMini(true)
+
+		go func() {
+			err := u.RunMini(ctx, w)
+
+			winMu.Lock()
+			mini = nil
+			winMu.Unlock()
 
-		mOpen := systray.AddMenuItem("Open", "Open monitor")
-		mQuit := systray.AddMenuItem("Quit", "Quit monitor")
+			tray.SetMini(false)
+
+			if err != nil && ctx.Err() == nil {
+				slog.Error("mini widget failed", "err", err)
+			}
+		}()
+	}
+
+	go inst.Serve(ctx, instanceHandler(func() { openWindow() }, store))
+
+	go n.Run(ctx)
+
+	tray.Run("Voi Node Monitor", f.Names(), func(m tray.Menu) {
+		tray.SetProfile(name)
 
 		go func() {
-			runWindow()
+			for name := range m.Profile {
+				_, err := profs.Switch(name)
+				if err != nil {
+					slog.Error("failed to switch profile", "err", err)
+				}
+			}
+		}()
+
+		go func() {
+			switch {
+			case a.NoWindow:
+			case a.Minimized:
+				openWindow(app.Minimized.Option())
+			default:
+				openWindow()
+			}
 
 		loop:
 			for {
 				select {
-				case <-mOpen.ClickedCh:
-					runWindow()
+				case <-m.Open:
+					openWindow()
+				case <-m.Mini:
+					toggleMini()
 				case <-ctx.Done():
 					break loop
 				}
 			}
 
-			fmt.Println("open done")
+			slog.Debug("tray open loop done")
 		}()
 
 		go func() {
-			<-mQuit.ClickedCh
+			<-m.Quit
 			// TODO: Quit probably must be called for alt+f4 too
-			systray.Quit()
+			tray.Quit()
 			cancel()
 
-			fmt.Println("quit done")
+			slog.Debug("quit")
 
 			os.Exit(0)
 		}()
-
-	}, nil)
+	})
 
 	app.Main()
 
-	fmt.Println("main done")
+	slog.Debug("main loop done")
 
 	return nil
 }
@@ -386,22 +869,365 @@ func run(a args) error {
 type args struct {
 	Path string
 
-	Algod string
-	Token string
+	// JSON and Wait belong to the status, watch and keys list commands.
+	JSON bool
+	Wait time.Duration
+	// Once is set for one-shot queries, they leave the active profile be.
+	Once bool
+
+	StatusBar string
+
+	NoWindow  bool
+	Minimized bool
+	Verbose   bool
+
+	Profile     string
+	SaveProfile string
+	Template    string
+
+	Algod    string
+	Token    string
+	APIToken string
+
+	RetryMin   time.Duration
+	RetryMax   time.Duration
+	MaxRetries int
+	Timeout    time.Duration
+
+	ElevateFor time.Duration
+
+	StakeChange float64
+	Grace       uint64
+
+	RenewDays   int
+	RenewWallet string
+
+	Indexer      string
+	IndexerToken string
+	IndexerLag   uint64
+
+	TLS node.TLSConfig
+
+	SSH           string
+	SSHKey        string
+	SSHKnownHosts string
+
+	Demo bool
+
+	AccountsOnly bool
+
+	Network string
+
+	TxnDir string
+
+	APIListen  string
+	APISecret  string
+	Automation bool
+	WebDir     string
+	Control    bool
+
+	UPS string
+
+	ReleaseFeed string
+
+	NoSelfUpdate bool
+
+	TempWarn float64
+
+	Probe   time.Duration
+	SlowRTT time.Duration
+
+	NTPServer  string
+	ClockDrift time.Duration
+
+	Relay     bool
+	RelayName string
+
+	Follower      bool
+	PipelineStall time.Duration
+
+	PortCheck      string
+	GossipPort     int
+	PortCheckEvery time.Duration
+
+	Heartbeat      string
+	HeartbeatEvery time.Duration
+
+	NetCheck     time.Duration
+	Relays       string
+	DNSBootstrap string
+
+	Resources time.Duration
+	DiskWarn  float64
+
+	DiskFullDays float64
+
+	StatusPageDir   string
+	StatusPageS3    string
+	StatusPageEvery time.Duration
+
+	ICalFile string
+
+	KumaPush  string
+	KumaEvery time.Duration
+
+	Reference      string
+	ReferenceToken string
 }
 
-func main() {
+// adhoc tells whether a describes a node of its own instead of naming a
+// profile.
+func (a args) adhoc() bool {
+	return a.Path != "" || a.Algod != "" || a.SSH != "" || a.Demo || a.AccountsOnly || a.Template != ""
+}
+
+// parseArgs reads the flags of cmd, every subcommand takes the ones that
+// select and configure the node.
+func parseArgs(cmd string, argv []string) args {
 	var a args
 
-	flag.StringVar(&a.Path, "path", "", "path to node data")
+	fs := flag.NewFlagSet("voiui "+cmd, flag.ExitOnError)
+
+	fs.BoolVar(&a.Verbose, "verbose", false, "log debug messages")
+	fs.StringVar(&a.Profile, "profile", "", "named profile from the config file, defaults to the last active one")
+	fs.StringVar(&a.SaveProfile, "save-profile", "", "save the endpoint flags as a named profile and make it active")
+	fs.StringVar(&a.Template, "template", "", "preset data path, service commands and log of a hosting setup, list them with voiui templates")
+	// or
+	fs.StringVar(&a.Path, "path", "", "path to node data, found from $ALGORAND_DATA, $VOI_DATA, running algod processes or the usual install locations when empty")
 	// or
-	flag.StringVar(&a.Algod, "algod", "", "algod address")
-	flag.StringVar(&a.Token, "token", "", "algod admin token")
+	fs.StringVar(&a.Algod, "algod", "", "algod address")
+	fs.StringVar(&a.Token, "token", "", "algod admin token (participation and key actions), prefer $VOIUI_ALGOD_TOKEN or the keychain")
+	fs.StringVar(&a.APIToken, "api-token", "", "algod non-admin token (status polling), falls back to -token, prefer $VOIUI_ALGOD_API_TOKEN or the keychain")
+
+	fs.StringVar(&a.Network, "network", "", "expected network, e.g. voimain, warns when the node is on another one")
+	fs.StringVar(&a.Reference, "reference", "", "independent algod URL to compare block hashes with, alerts when the node is on a different fork")
+	fs.StringVar(&a.ReferenceToken, "reference-token", "", "API token for -reference")
+
+	fs.StringVar(&a.Indexer, "indexer", "", "indexer address used for historical analysis")
+	fs.StringVar(&a.IndexerToken, "indexer-token", "", "indexer token")
+	fs.Uint64Var(&a.IndexerLag, "indexer-lag", 100, "alert when the indexer falls this many rounds behind the node (0 disables indexer monitoring)")
+
+	fs.StringVar(&a.TLS.CAFile, "tls-ca", "", "CA bundle (PEM) used to verify algod over HTTPS")
+	fs.StringVar(&a.TLS.CertFile, "tls-cert", "", "client certificate (PEM) for algod over HTTPS")
+	fs.StringVar(&a.TLS.KeyFile, "tls-key", "", "client certificate key (PEM) for algod over HTTPS")
+	fs.BoolVar(&a.TLS.Insecure, "tls-insecure", false, "skip algod certificate verification")
+	fs.StringVar(&a.SSH, "ssh", "", "user@host[:port] to reach algod through; -path and -algod then refer to the remote host")
+	fs.StringVar(&a.SSHKey, "ssh-key", "", "SSH private key, defaults to ssh-agent and ~/.ssh/id_*")
+	fs.StringVar(&a.SSHKnownHosts, "ssh-known-hosts", "", "known_hosts file used to verify the SSH host, defaults to ~/.ssh/known_hosts")
+
+	fs.StringVar(&a.TxnDir, "txn-dir", "", "folder watched for signed transaction files to submit")
+
+	fs.StringVar(&a.APIListen, "api-listen", "", "address of the local API, e.g. 127.0.0.1:8787 (disabled when empty)")
+	fs.StringVar(&a.APISecret, "api-secret", "", "bearer token required by the local API, prefer $VOIUI_API_SECRET")
+	fs.BoolVar(&a.Automation, "automation", false, "let scripts operate the UI through /v1/ui/ on the local API, for tests and screenshots")
+	fs.StringVar(&a.WebDir, "web-dir", "", "serve the browser build of the window from this folder on the local API, see cmd/voiui-web")
+	fs.BoolVar(&a.Control, "control", true, "serve the control API on "+controlSocket+" in the config dir, e.g. curl --unix-socket <path> http://voiui/v1/state")
+
+	fs.StringVar(&a.UPS, "ups", "", "UPS to monitor: nut://host/ups or apcupsd://host (or post to the /v1/events webhook)")
+
+	fs.StringVar(&a.ReleaseFeed, "release-feed", "https://api.github.com/repos/algorand/go-algorand/releases/latest", "GitHub releases API URL checked for node updates (disabled when empty)")
+
+	fs.BoolVar(&a.NoSelfUpdate, "no-self-update", false, "never download or install voiui updates (for packaged builds)")
+
+	fs.Float64Var(&a.TempWarn, "temp-warn", 85, "warn when a host temperature sensor reaches this many °C (0 disables hardware monitoring)")
 
-	flag.Parse()
+	fs.DurationVar(&a.Probe, "probe", 30*time.Second, "how often to measure the round trip to algod (0 disables)")
+	fs.DurationVar(&a.SlowRTT, "slow-rtt", time.Second, "alert when algod takes longer than this to answer a probe (0 never alerts)")
+
+	fs.StringVar(&a.NTPServer, "ntp-server", "pool.ntp.org", "NTP server the host clock is compared with")
+	fs.DurationVar(&a.ClockDrift, "clock-drift", 2*time.Second, "alert when the host clock is off by more than this (0 disables the check)")
+
+	fs.BoolVar(&a.Relay, "relay", false, "monitor the node as a relay: peers, traffic and bootstrap records, needs EnableMetricReporting in algod's config.json")
+	fs.StringVar(&a.RelayName, "relay-name", "", "host:port the relay is published as in the bootstrap records, e.g. r1.example.com:5011")
+
+	fs.BoolVar(&a.Follower, "follower", false, "monitor a follower node's sync round and state deltas, as advanced by a pipeline like Conduit")
+	fs.DurationVar(&a.PipelineStall, "pipeline-stall", 5*time.Minute, "alert when the follower's sync round has not advanced for this long")
+
+	fs.StringVar(&a.PortCheck, "port-check", "", "URL of a service that tests whether the gossip port is reachable from the internet, {port} is replaced with the port and it answers {\"open\": true|false}")
+	fs.IntVar(&a.GossipPort, "gossip-port", 0, "gossip port to check, defaults to the NetAddress port in algod's config.json")
+	fs.DurationVar(&a.PortCheckEvery, "port-check-every", time.Hour, "how often to check the gossip port")
+
+	fs.StringVar(&a.Heartbeat, "heartbeat", "", "URL to ping while the node is healthy and participating, e.g. a healthchecks.io check or an Uptime Kuma push monitor")
+	fs.DurationVar(&a.HeartbeatEvery, "heartbeat-every", time.Minute, "how often to ping the heartbeat URL")
+
+	fs.DurationVar(&a.NetCheck, "net-check", 0, "how often to measure latency to relays, e.g. 5m (0 disables)")
+	fs.StringVar(&a.Relays, "relays", "", "comma separated relay host:port list, defaults to the network's SRV bootstrap records")
+	fs.StringVar(&a.DNSBootstrap, "dns-bootstrap", "voi.network", "DNS bootstrap domain used to find relays")
+
+	fs.DurationVar(&a.Resources, "resources", 30*time.Second, "how often to sample CPU, memory and disk usage (0 disables)")
+	fs.Float64Var(&a.DiskWarn, "disk-warn", 10, "warn when free space on the data directory's disk drops below this percentage")
+
+	fs.Float64Var(&a.DiskFullDays, "disk-full-days", 14, "warn when ledger growth would fill the disk within this many days")
+
+	fs.StringVar(&a.StatusPageDir, "status-page-dir", "", "folder to write a public index.html status page to")
+	fs.StringVar(&a.StatusPageS3, "status-page-s3", "", "S3-compatible URL to upload the status page to, e.g. https://s3.example.com/bucket/status.html, credentials from $AWS_ACCESS_KEY_ID and $AWS_SECRET_ACCESS_KEY")
+	fs.DurationVar(&a.StatusPageEvery, "status-page-every", 5*time.Minute, "how often to render the status page")
+	fs.StringVar(&a.KumaPush, "kuma-push", "", "Uptime Kuma push monitor URL, e.g. https://kuma.example.com/api/push/abc123, heartbeats are sent while nothing is down")
+	fs.DurationVar(&a.KumaEvery, "kuma-every", time.Minute, "how often to push to Uptime Kuma, keep it below the monitor's heartbeat interval")
+	fs.StringVar(&a.ICalFile, "ical-file", "", "write an iCal feed of key expiries, upgrades and calendar entries from the config file to this path, also served at /v1/calendar.ics with -api-listen")
+
+	fs.BoolVar(&a.Demo, "demo", false, "use a simulated node instead of algod")
+	fs.BoolVar(&a.AccountsOnly, "accounts-only", false, "watch accounts through public Voi endpoints without a node of your own, -algod and -indexer override the endpoints")
+
+	fs.DurationVar(&a.RetryMin, "retry-min", time.Second, "initial reconnect delay")
+	fs.DurationVar(&a.RetryMax, "retry-max", time.Minute, "maximum reconnect delay")
+	fs.DurationVar(&a.ElevateFor, "elevate-for", 5*time.Minute, "how long the admin token stays unlocked")
+	fs.Float64Var(&a.StakeChange, "stake-change", 10, "notify when a watched account's online stake changes by this many percent (0 disables)")
+	fs.Uint64Var(&a.Grace, "participation-grace", 0, "rounds past the current one a registered key must cover to count as participating")
+	fs.IntVar(&a.RenewDays, "renew-days", 0, "generate a replacement participation key valid for this many days when the current one has a week left (0 disables)")
+	fs.StringVar(&a.RenewWallet, "renew-wallet", "", "kmd wallet that registers renewed keys online, password from $VOIUI_KMD_PASSWORD; without it you are asked to register them")
+	fs.DurationVar(&a.Timeout, "timeout", 10*time.Second, "how long a request to algod may take before it is abandoned, waiting for the next block gets another minute on top")
+	fs.IntVar(&a.MaxRetries, "max-retries", 0, "reconnect attempts before giving up until retried manually (0 = unlimited)")
+
+	if cmd == "status" || cmd == "watch" || cmd == "keys list" {
+		fs.BoolVar(&a.JSON, "json", false, "print JSON instead of text")
+	}
+
+	if cmd == "gui" {
+		fs.BoolVar(&a.NoWindow, "no-window", false, "start in the tray without opening the window, e.g. at login")
+		fs.BoolVar(&a.Minimized, "minimized", false, "open the window minimized to the taskbar or dock")
+	}
+
+	if cmd == "watch" {
+		fs.StringVar(&a.StatusBar, "statusbar", "", "print a line every second for desktop bars: text (polybar, tmux), i3blocks or waybar JSON, follows a running voiui through its control socket")
+	}
+
+	if cmd == "status" || cmd == "keys list" {
+		fs.DurationVar(&a.Wait, "wait", 30*time.Second, "how long to wait for the node when voiui is not running")
+	}
+
+	fs.Parse(argv)
+
+	return a
+}
+
+// selfUpdate installs a staged update and restarts into it, it reports
+// true when the new version took over.
+func selfUpdate(a args) bool {
+	if a.NoSelfUpdate {
+		return false
+	}
 
-	err := run(a)
+	restart, err := selfupdate.Apply()
 	if err != nil {
-		panic(err)
+		slog.Error("failed to apply update", "err", err)
+	}
+
+	if !restart {
+		return false
+	}
+
+	err = selfupdate.Restart()
+	if err != nil {
+		slog.Error("failed to restart after update", "err", err)
+		return false
+	}
+
+	return true
+}
+
+const usage = `usage: voiui [command] [flags]
+
+commands:
+  gui        monitor the node in a window and the tray (default)
+  watch      monitor without a window, printing status changes
+  status     print the status of the running voiui or the node once
+  keys list  print the participation keys
+  open       raise the window of the running voiui, or start it
+  export     export the history
+  service    install or uninstall watch as a systemd user unit or Windows service
+  templates  list the hosting templates for -template
+
+run voiui <command> -h for its flags
+`
+
+func main() {
+	cmd, argv := "gui", os.Args[1:]
+	if len(argv) > 0 && !strings.HasPrefix(argv[0], "-") {
+		cmd, argv = argv[0], argv[1:]
+	}
+
+	switch cmd {
+	case "open":
+		running, err := runCommand("open")
+		if err != nil {
+			log.Fatal(err)
+		}
+		if running {
+			return
+		}
+
+		// nothing to open yet, start normally
+		cmd = "gui"
+	case "templates":
+		runTemplates()
+		return
+	case "service":
+		err := runDaemon(argv)
+		if err != nil {
+			log.Fatal(err)
+		}
+		return
+	case "export":
+		err := runExport(argv)
+		if err != nil {
+			log.Fatal(err)
+		}
+		return
+	case "keys":
+		if len(argv) == 0 || argv[0] != "list" {
+			log.Fatal("usage: voiui keys list [flags]")
+		}
+		cmd, argv = "keys list", argv[1:]
+	case "gui", "status", "watch":
+	case "help":
+		fmt.Print(usage)
+		return
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	a := parseArgs(cmd, argv)
+
+	switch cmd {
+	case "status":
+		err := runStatus(a)
+		if err != nil {
+			log.Fatal(err)
+		}
+	case "keys list":
+		err := runKeys(a)
+		if err != nil {
+			log.Fatal(err)
+		}
+	case "watch":
+		logs.Setup("", a.Verbose)
+
+		watch := func(ctx context.Context) error { return runWatch(ctx, a) }
+
+		// the service manager owns the process, updates wait for a restart
+		ok, err := inService(watch)
+		if !ok && err == nil {
+			if selfUpdate(a) {
+				return
+			}
+
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			err = watch(ctx)
+			stop()
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+	default:
+		if selfUpdate(a) {
+			return
+		}
+
+		err := run(a)
+		if err != nil {
+			panic(err)
+		}
 	}
 }
@@ -0,0 +1,301 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"voiui/internal/alert"
+	"voiui/internal/config"
+	"voiui/internal/datadir"
+	"voiui/internal/node"
+	"voiui/internal/state"
+	"voiui/internal/tray"
+)
+
+// used by accounts-only profiles without their own endpoints
+const (
+	publicAlgod   = "https://mainnet-api.voi.nodely.dev"
+	publicIndexer = "https://mainnet-idx.voi.nodely.dev"
+	publicNetwork = "voimain"
+)
+
+type endpoint struct {
+	url        string
+	path       string
+	apiToken   string
+	adminToken string
+	sealed     bool
+	// found are the data directories discovered when the profile names
+	// none.
+	found []string
+}
+
+// resolve finds the algod address and tokens for p. Tokens that are not
+// passed in are read from the data directory or the OS keychain.
+func resolve(p config.Profile, dir string, apiToken string, adminToken string) (endpoint, error) {
+	e := endpoint{
+		path:       p.Path,
+		apiToken:   apiToken,
+		adminToken: adminToken,
+	}
+
+	if p.Demo {
+		e.url = "demo"
+		e.apiToken = "demo"
+	} else if p.AccountsOnly {
+		e.url = p.Algod
+		if e.url == "" {
+			e.url = publicAlgod
+		}
+		e.path = ""
+		e.adminToken = ""
+
+		return e, nil
+	} else if p.Algod != "" {
+		e.url = p.Algod
+
+		if e.apiToken == "" || e.adminToken == "" {
+			kcAPI, kcAdmin, err := node.KeychainTokens(e.url)
+			if err != nil {
+				slog.Error("failed to read tokens from keychain", "err", err)
+			}
+
+			if e.apiToken == "" {
+				e.apiToken = kcAPI
+			}
+
+			if e.adminToken == "" {
+				e.adminToken = kcAdmin
+			}
+		}
+	} else if p.SSH != "" {
+		if e.path == "" {
+			e.path = "data"
+		}
+
+		addr, api, admin, err := node.ReadRemote(sshConfig(p), e.path)
+		if err != nil {
+			return e, err
+		}
+
+		e.url = fmt.Sprintf("http://%s", addr)
+		e.apiToken = api
+		e.adminToken = admin
+		e.path = ""
+	} else {
+		if e.path == "" {
+			e.path = "data"
+			e.found = datadir.Find()
+			if len(e.found) > 0 {
+				e.path = e.found[0]
+				slog.Info("found the data directory", "path", e.path, "candidates", len(e.found))
+			}
+		}
+
+		addrBytes, err := os.ReadFile(filepath.Join(e.path, "algod.net"))
+		if err != nil {
+			return e, errors.Wrap(err, "failed to read algod.net")
+		}
+
+		addr := strings.TrimSpace(string(addrBytes))
+
+		e.apiToken, e.adminToken, err = node.ReadTokens(e.path)
+		if err != nil {
+			return e, err
+		}
+
+		e.url = fmt.Sprintf("http://%s", addr)
+	}
+
+	if node.Sealed(dir, node.Endpoint(e.url, e.path, sshConfig(p))) {
+		if e.apiToken == "" {
+			return e, errors.New("the admin token is locked, a non-admin token is required for polling")
+		}
+
+		e.sealed = true
+		e.adminToken = ""
+	}
+
+	if e.apiToken == "" {
+		slog.Warn("no non-admin token configured, polling with the admin token; pass -api-token or keep algod.token in the data directory")
+	}
+
+	return e, nil
+}
+
+func sshConfig(p config.Profile) node.SSHConfig {
+	return node.SSHConfig{
+		Target:     p.SSH,
+		KeyFile:    p.SSHKey,
+		KnownHosts: p.SSHKnownHosts,
+	}
+}
+
+// applyAlerts sets the profile's notification settings on c.
+func applyAlerts(c *alert.Correlator, a config.Alerts) {
+	c.SetMuted(a.Muted)
+
+	critical := time.Duration(a.QuietCritical)
+	if critical == 0 {
+		critical = 15 * time.Minute
+	}
+
+	q, err := alert.ParseQuiet(a.QuietFrom, a.QuietTo, critical)
+	if err != nil {
+		slog.Error("quiet hours disabled", "err", err)
+	}
+	c.SetQuiet(q)
+}
+
+func nodeConfig(p config.Profile, e endpoint) node.Config {
+	if p.AccountsOnly && p.Algod == "" {
+		if p.Indexer == "" {
+			p.Indexer = publicIndexer
+		}
+		if p.Network == "" {
+			p.Network = publicNetwork
+		}
+	}
+
+	return node.Config{
+		URL:          e.url,
+		DataDir:      e.path,
+		APIToken:     e.apiToken,
+		AdminToken:   e.adminToken,
+		IndexerURL:   p.Indexer,
+		IndexerToken: p.IndexerToken,
+		TLS: node.TLSConfig{
+			CAFile:   p.TLSCA,
+			CertFile: p.TLSCert,
+			KeyFile:  p.TLSKey,
+			Insecure: p.TLSInsecure,
+		},
+		SSH:            sshConfig(p),
+		Demo:           p.Demo,
+		AccountsOnly:   p.AccountsOnly,
+		Network:        p.Network,
+		Reference:      p.Reference,
+		ReferenceToken: p.ReferenceToken,
+	}
+}
+
+type profiles struct {
+	dir     string
+	n       *node.Node
+	updates chan<- state.Update
+
+	mu     sync.Mutex
+	f      *config.File
+	active string
+	// service is the active profile's, adhoc profiles are not in f.
+	service config.Service
+}
+
+func (p *profiles) Names() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.f.Names()
+}
+
+func (p *profiles) Active() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.active
+}
+
+func (p *profiles) Switch(name string) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if name == p.active {
+		return "Profile " + name + " is already active", nil
+	}
+
+	prof, ok := p.f.Profile(name)
+	if !ok {
+		return "", errors.Errorf("unknown profile: %s", name)
+	}
+
+	e, err := resolve(prof, p.dir, "", "")
+	if err != nil {
+		return "", err
+	}
+
+	err = p.n.Switch(nodeConfig(prof, e))
+	if err != nil {
+		return "", err
+	}
+
+	applyAlerts(p.n.Alerts(), prof.Alerts)
+
+	p.active = name
+	p.service = prof.Service
+	p.f.LastProfile = name
+
+	err = p.f.Save(p.dir)
+	if err != nil {
+		slog.Error("failed to remember profile", "err", err)
+	}
+
+	tray.SetProfile(name)
+
+	p.updates <- func(s *state.State) error {
+		s.Profile = name
+		s.AlertsMuted = prof.Alerts.Muted
+		s.AccountsOnly = prof.AccountsOnly
+		s.Service = serviceState(prof.Service)
+		s.DataDir = e.path
+		s.DataDirs = e.found
+		return nil
+	}
+
+	return "Switched to profile " + name, nil
+}
+
+// UseDataDir switches to another of the data directories found on this
+// machine.
+func (p *profiles) UseDataDir(path string) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	prof := config.Profile{Path: path}
+
+	e, err := resolve(prof, p.dir, "", "")
+	if err != nil {
+		return "", err
+	}
+
+	err = p.n.Switch(nodeConfig(prof, e))
+	if err != nil {
+		return "", err
+	}
+
+	p.active = ""
+	p.service = config.Service{}
+
+	p.updates <- func(s *state.State) error {
+		s.Profile = ""
+		s.DataDir = path
+		s.Service = state.Service{}
+		return nil
+	}
+
+	return "Switched to " + path, nil
+}
+
+type dataDirs struct {
+	p *profiles
+}
+
+func (d dataDirs) Use(path string) (string, error) {
+	return d.p.UseDataDir(path)
+}
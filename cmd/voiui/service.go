@@ -0,0 +1,85 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/pkg/errors"
+
+	"voiui/internal/config"
+	"voiui/internal/explorer"
+	"voiui/internal/state"
+	"voiui/internal/templates"
+)
+
+func serviceState(c config.Service) state.Service {
+	return state.Service{
+		Start:   c.Start != "",
+		Stop:    c.Stop != "",
+		Restart: c.Restart != "",
+		Log:     c.Log != "",
+	}
+}
+
+// nodeService runs the service commands of the active profile.
+type nodeService struct {
+	p *profiles
+}
+
+func (s nodeService) Control(action string) (string, error) {
+	s.p.mu.Lock()
+	c := s.p.service
+	s.p.mu.Unlock()
+
+	var command, done string
+
+	switch action {
+	case "start":
+		command, done = c.Start, "Node started"
+	case "stop":
+		command, done = c.Stop, "Node stopped"
+	case "restart":
+		command, done = c.Restart, "Node restarted"
+	default:
+		return "", errors.Errorf("unknown service action: %s", action)
+	}
+
+	if command == "" {
+		return "", errors.Errorf("the profile has no %s command", action)
+	}
+
+	out, err := shell(command).CombinedOutput()
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to %s the node: %s", action, strings.TrimSpace(string(out)))
+	}
+
+	return done, nil
+}
+
+func (s nodeService) OpenLog() error {
+	s.p.mu.Lock()
+	path := s.p.service.Log
+	s.p.mu.Unlock()
+
+	if path == "" {
+		return errors.New("the profile has no log file")
+	}
+
+	_, err := os.Stat(path)
+	if err != nil {
+		return errors.Wrap(err, "failed to find the node log")
+	}
+
+	return explorer.Open(path)
+}
+
+// runTemplates implements `voiui templates`.
+func runTemplates() {
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	for _, t := range templates.All() {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", t.Name, t.Path, t.Description)
+	}
+	w.Flush()
+}
@@ -0,0 +1,179 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"gioui.org/app"
+	"gioui.org/unit"
+	"github.com/pkg/errors"
+
+	"voiui/internal/autostart"
+	"voiui/internal/config"
+	"voiui/internal/datadir"
+	"voiui/internal/node"
+	"voiui/internal/selfupdate"
+	"voiui/internal/sound"
+	"voiui/internal/templates"
+	"voiui/internal/ui"
+)
+
+// firstRun tells whether to show the setup instead of the monitor: nothing
+// was configured yet and no flags pick the node.
+func firstRun(a args, dir string) bool {
+	return !a.NoWindow && !a.adhoc() && a.Profile == "" && !config.Exists(dir)
+}
+
+// setup walks a new node runner through writing the config file, then
+// starts voiui again with it. It never returns.
+func setup(dir string) {
+	w := app.NewWindow(
+		app.Title("Voi Node Monitor setup"),
+		app.Size(unit.Dp(480), unit.Dp(420)),
+		app.MinSize(unit.Dp(360), unit.Dp(300)),
+	)
+
+	go func() {
+		saved, err := ui.RunSetup(context.Background(), w, wizard{dir: dir})
+		if err != nil {
+			slog.Error("setup failed", "err", err)
+		}
+
+		if saved {
+			err = selfupdate.Restart()
+			if err != nil {
+				slog.Error("failed to start after setup", "err", err)
+			}
+		}
+
+		os.Exit(0)
+	}()
+
+	app.Main()
+}
+
+// wizard tests and writes what the first-run setup collects.
+type wizard struct {
+	dir string
+}
+
+func (wizard) DataDirs() []string {
+	return datadir.Find()
+}
+
+func (wizard) Templates() []ui.Template {
+	var ts []ui.Template
+	for _, t := range templates.All() {
+		ts = append(ts, ui.Template{Name: t.Name, Title: t.Title, Description: t.Description, Path: t.Path})
+	}
+	return ts
+}
+
+func (wz wizard) endpoint(c ui.Choices) (endpoint, error) {
+	if c.Remote {
+		if c.Algod == "" {
+			return endpoint{}, errors.New("enter the algod URL")
+		}
+		return endpoint{url: c.Algod, apiToken: c.Token}, nil
+	}
+
+	if c.Path == "" {
+		return endpoint{}, errors.New("enter the data directory")
+	}
+
+	return resolve(config.Profile{Path: c.Path}, wz.dir, "", "")
+}
+
+func (wz wizard) Test(c ui.Choices) (string, error) {
+	e, err := wz.endpoint(c)
+	if err != nil {
+		return "", err
+	}
+
+	token := e.apiToken
+	if token == "" {
+		token = e.adminToken
+	}
+
+	g, err := node.Check(context.Background(), e.url, token, 10*time.Second)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("Connected to %s (%s)", g.Network, g.ID), nil
+}
+
+func (wz wizard) Save(c ui.Choices) error {
+	f, err := config.Load(wz.dir)
+	if err != nil {
+		return err
+	}
+
+	p := config.Profile{Name: "default"}
+	if c.Remote {
+		p.Algod = c.Algod
+
+		err = node.SetKeychainTokens(c.Algod, c.Token, "")
+		if err != nil {
+			return errors.Wrap(err, "failed to store the token")
+		}
+	} else {
+		p.Path = c.Path
+
+		if c.Template != "" {
+			t, ok := templates.Get(c.Template)
+			if !ok {
+				return errors.Errorf("unknown template: %s", c.Template)
+			}
+			p = t.Apply(p)
+		}
+	}
+
+	f.SetProfile(p)
+	f.LastProfile = p.Name
+
+	if c.Sounds {
+		f.Sounds.Down.Enabled = true
+		f.Sounds.Participation.Enabled = true
+		f.OnProposal = append(f.OnProposal, config.ProposalAction{Sound: sound.ProposalTone})
+	}
+
+	if c.Ntfy != "" {
+		push, err := ntfy(c.Ntfy)
+		if err != nil {
+			return err
+		}
+		f.Push = append(f.Push, push)
+	}
+
+	if c.Autostart {
+		err = autostart.Enable()
+		if err != nil {
+			return err
+		}
+	}
+
+	return f.Save(wz.dir)
+}
+
+// ntfy splits an ntfy topic URL into the server and the topic.
+func ntfy(raw string) (config.Push, error) {
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return config.Push{}, errors.New("the ntfy URL should look like https://ntfy.sh/my-node")
+	}
+
+	topic := strings.Trim(u.Path, "/")
+	if topic == "" || strings.Contains(topic, "/") {
+		return config.Push{}, errors.New("the ntfy URL should end with the topic")
+	}
+
+	u.Path = ""
+
+	return config.Push{Service: "ntfy", URL: u.String(), Topic: topic}, nil
+}
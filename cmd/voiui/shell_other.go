@@ -0,0 +1,9 @@
+//go:build !windows
+
+package main
+
+import "os/exec"
+
+func shell(command string) *exec.Cmd {
+	return exec.Command("sh", "-c", command)
+}
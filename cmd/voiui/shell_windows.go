@@ -0,0 +1,7 @@
+package main
+
+import "os/exec"
+
+func shell(command string) *exec.Cmd {
+	return exec.Command("cmd", "/C", command)
+}
@@ -0,0 +1,237 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"voiui/internal/config"
+	"voiui/internal/health"
+	"voiui/internal/instance"
+	"voiui/internal/state"
+)
+
+// statusReport is what `voiui status -json` prints.
+type statusReport struct {
+	Profile       string `json:"profile"`
+	Network       string `json:"network,omitempty"`
+	Participation string `json:"participation,omitempty"`
+
+	health.Health
+}
+
+func statusText(s state.State, asJSON bool) string {
+	h := health.Report(s, time.Now())
+
+	if !asJSON {
+		return fmt.Sprintf("%s: %s, round %d, %d open alert(s)\n", s.Profile, h.Status, h.Round, h.OpenAlerts)
+	}
+
+	data, err := json.Marshal(statusReport{
+		Profile:       s.Profile,
+		Network:       s.Network,
+		Participation: string(s.Participation),
+		Health:        h,
+	})
+	if err != nil {
+		return fmt.Sprintf("{\"error\":%q}\n", err.Error())
+	}
+
+	return string(data) + "\n"
+}
+
+type keyReport struct {
+	ID           string `json:"id"`
+	Address      string `json:"address"`
+	FirstValid   uint64 `json:"first_valid"`
+	LastValid    uint64 `json:"last_valid"`
+	LastVote     uint64 `json:"last_vote,omitempty"`
+	LastProposal uint64 `json:"last_proposal,omitempty"`
+}
+
+func keysText(keys []state.Key, asJSON bool) string {
+	if asJSON {
+		list := make([]keyReport, 0, len(keys))
+		for _, k := range keys {
+			list = append(list, keyReport(k))
+		}
+
+		data, err := json.Marshal(list)
+		if err != nil {
+			return fmt.Sprintf("{\"error\":%q}\n", err.Error())
+		}
+
+		return string(data) + "\n"
+	}
+
+	var b strings.Builder
+
+	w := tabwriter.NewWriter(&b, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tADDRESS\tFIRST\tLAST\tLAST VOTE\tLAST PROPOSAL")
+	for _, k := range keys {
+		fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%d\t%d\n", k.ID, k.Address, k.FirstValid, k.LastValid, k.LastVote, k.LastProposal)
+	}
+	w.Flush()
+
+	return b.String()
+}
+
+// settled waits until a whole round of checks went through: the keys and
+// participation of a block are in once the next block is seen.
+func settled(ctx context.Context, store *state.Store) (state.State, error) {
+	changed, unsubscribe := store.Subscribe()
+	defer unsubscribe()
+
+	var first uint64
+
+	for {
+		s := store.Snapshot()
+
+		switch {
+		case !s.RetryAt.IsZero() || s.RetryStopped:
+			return s, errors.New("the node is not reachable")
+		case first == 0 && !s.CurrBlockAt.IsZero():
+			first = s.Round
+		case first != 0 && s.Round > first:
+			return s, nil
+		}
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return s, errors.New("timed out waiting for the node")
+		}
+	}
+}
+
+// query prints the answer of the running instance to cmd or, when none
+// runs or a asks for another node, report of a node started just long
+// enough to see it through a round.
+func query(a args, cmd string, report func(s state.State) string) error {
+	if a.Profile == "" && !a.adhoc() {
+		dir, err := config.Dir()
+		if err != nil {
+			return err
+		}
+
+		reply, err := instance.Send(dir, cmd)
+		if err == nil {
+			fmt.Print(reply)
+			return nil
+		}
+	}
+
+	a.Once = true
+
+	m, err := start(context.Background(), a)
+	if err != nil {
+		return err
+	}
+	defer m.close()
+
+	go m.n.Run(m.ctx)
+
+	ctx, cancel := context.WithTimeout(m.ctx, a.Wait)
+	defer cancel()
+
+	s, err := settled(ctx, m.store)
+	if err != nil {
+		return err
+	}
+
+	fmt.Print(report(s))
+
+	return nil
+}
+
+// runStatus implements `voiui status`.
+func runStatus(a args) error {
+	cmd := "status"
+	if a.JSON {
+		cmd += " -json"
+	}
+
+	return query(a, cmd, func(s state.State) string { return statusText(s, a.JSON) })
+}
+
+// runKeys implements `voiui keys list`.
+func runKeys(a args) error {
+	cmd := "keys"
+	if a.JSON {
+		cmd += " -json"
+	}
+
+	return query(a, cmd, func(s state.State) string { return keysText(s.Keys, a.JSON) })
+}
+
+// runWatch implements `voiui watch`: the monitor without a window, printing
+// the status whenever the round or the health changes. With -statusbar it
+// prints a bar line every second instead and follows a running voiui
+// rather than refusing to start.
+func runWatch(ctx context.Context, a args) error {
+	if a.StatusBar != "" {
+		_, err := barLine(a.StatusBar, state.State{}, time.Now())
+		if err != nil {
+			return err
+		}
+	}
+
+	dir, err := config.Dir()
+	if err != nil {
+		return err
+	}
+
+	inst, err := instance.Listen(dir)
+	if err == instance.ErrRunning && a.StatusBar != "" {
+		return printBar(ctx, a.StatusBar, controlState(filepath.Join(dir, controlSocket)))
+	}
+	if err == instance.ErrRunning {
+		return errors.New("voiui is already running, ask it with voiui status")
+	}
+	if err != nil {
+		return err
+	}
+
+	m, err := start(ctx, a)
+	if err != nil {
+		return err
+	}
+	defer m.close()
+
+	err = m.services(a)
+	if err != nil {
+		return err
+	}
+
+	ctx = m.ctx
+
+	go inst.Serve(ctx, instanceHandler(nil, m.store))
+
+	go m.n.Run(ctx)
+
+	if a.StatusBar != "" {
+		return printBar(ctx, a.StatusBar, func() (state.State, error) { return m.store.Snapshot(), nil })
+	}
+
+	var last string
+
+	m.store.Watch(ctx, func(s state.State) {
+		h := health.Report(s, time.Now())
+
+		seen := fmt.Sprintf("%d %s %d", s.Round, h.Status, h.OpenAlerts)
+		if seen == last {
+			return
+		}
+		last = seen
+
+		fmt.Print(statusText(s, a.JSON))
+	})
+
+	return nil
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"voiui/internal/state"
+)
+
+// glyph sums participation up in a character that fits a desktop bar,
+// class names the same for bar styling.
+func glyph(s state.State) (string, string) {
+	switch {
+	case !s.Running:
+		return "✖", "down"
+	case s.AccountsOnly:
+		return "◇", "watching"
+	case s.Participation == state.ParticipationActive:
+		return "●", "participating"
+	case s.Participation == state.ParticipationOffline:
+		return "◐", "offline"
+	default:
+		return "○", "idle"
+	}
+}
+
+// barLine renders s for a desktop bar: text for polybar and tmux, or one
+// JSON object per line for i3blocks and waybar.
+func barLine(format string, s state.State, now time.Time) (string, error) {
+	g, class := glyph(s)
+
+	text := g + " voiui down"
+	if s.Running {
+		text = fmt.Sprintf("%s %d", g, s.Round)
+		if !s.CurrBlockAt.IsZero() {
+			text += fmt.Sprintf(" %.1fs", now.Sub(s.CurrBlockAt).Seconds())
+		}
+	}
+
+	tooltip := s.Profile
+	if s.Participation != "" {
+		tooltip += ": " + string(s.Participation)
+	}
+
+	var v interface{}
+
+	switch format {
+	case "text":
+		return text, nil
+	case "i3blocks":
+		color := "#FFFFFF"
+		switch class {
+		case "down":
+			color = "#FF5555"
+		case "offline":
+			color = "#FFB86C"
+		}
+		v = map[string]string{"full_text": text, "short_text": g, "color": color}
+	case "waybar":
+		v = map[string]string{"text": text, "tooltip": tooltip, "class": class, "alt": class}
+	default:
+		return "", errors.Errorf("unknown status bar format: %s, use text, i3blocks or waybar", format)
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to encode status line")
+	}
+
+	return string(data), nil
+}
+
+// printBar prints the line of the state next returns every second, the
+// block timer moves even when nothing else does. format was checked by
+// barLine before.
+func printBar(ctx context.Context, format string, next func() (state.State, error)) error {
+	t := time.NewTicker(time.Second)
+	defer t.Stop()
+
+	for {
+		s, err := next()
+		if err != nil {
+			s = state.State{}
+		}
+
+		line, _ := barLine(format, s, time.Now())
+		fmt.Println(line)
+
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// controlClient talks HTTP to the control socket at path.
+func controlClient(path string, timeout time.Duration) *http.Client {
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, "unix", path)
+			},
+		},
+	}
+}
+
+// controlState reads the state of the running instance from its control
+// socket.
+func controlState(path string) func() (state.State, error) {
+	c := controlClient(path, 2*time.Second)
+
+	return func() (state.State, error) {
+		var s state.State
+
+		resp, err := c.Get("http://voiui/v1/state")
+		if err != nil {
+			return s, errors.Wrap(err, "failed to reach the running voiui")
+		}
+		defer resp.Body.Close()
+
+		err = json.NewDecoder(resp.Body).Decode(&s)
+		if err != nil {
+			return s, errors.Wrap(err, "failed to decode state")
+		}
+
+		return s, nil
+	}
+}
@@ -1,12 +1,16 @@
 module voiui
 
-go 1.20
+go 1.21
 
 require (
 	gioui.org v0.1.0
 	github.com/algorand/go-algorand-sdk/v2 v2.2.0
 	github.com/getlantern/systray v1.2.2
 	github.com/pkg/errors v0.9.1
+	github.com/skip2/go-qrcode v0.0.0-20200617195104-da1b6568686e
+	go.etcd.io/bbolt v1.3.7
+	golang.org/x/crypto v0.0.0-20210921155107-089bfa567519
+	golang.org/x/sys v0.4.0
 )
 
 require (
@@ -23,10 +27,8 @@ require (
 	github.com/go-text/typesetting v0.0.0-20230602202114-9797aefac433 // indirect
 	github.com/google/go-querystring v1.0.0 // indirect
 	github.com/oxtoacart/bpool v0.0.0-20190530202638-03653db5a59c // indirect
-	golang.org/x/crypto v0.0.0-20210921155107-089bfa567519 // indirect
 	golang.org/x/exp v0.0.0-20221012211006-4de253d81b95 // indirect
 	golang.org/x/exp/shiny v0.0.0-20220827204233-334a2380cb91 // indirect
 	golang.org/x/image v0.5.0 // indirect
-	golang.org/x/sys v0.1.0 // indirect
 	golang.org/x/text v0.7.0 // indirect
 )
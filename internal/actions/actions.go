@@ -0,0 +1,136 @@
+package actions
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"voiui/internal/config"
+	"voiui/internal/hooks"
+	"voiui/internal/sound"
+)
+
+const timeout = 30 * time.Second
+
+// Proposal is what actions are told about a block won by an account.
+type Proposal struct {
+	Event   string `json:"event"`
+	Round   uint64 `json:"round"`
+	Address string `json:"address"`
+	Label   string `json:"label,omitempty"`
+	Payout  uint64 `json:"payout"`
+}
+
+type Config struct {
+	Actions []config.ProposalAction
+	// Label names an address, e.g. from the watch list.
+	Label func(address string) string
+	// Tray animates the tray icon with text.
+	Tray func(text string)
+}
+
+// Runner carries out the configured actions when a tracked account
+// proposes a block.
+type Runner struct {
+	cfg Config
+}
+
+func New(cfg Config) *Runner {
+	return &Runner{cfg: cfg}
+}
+
+func (r *Runner) matches(a config.ProposalAction, p Proposal) bool {
+	return a.Account == "" || a.Account == p.Address || (p.Label != "" && a.Account == p.Label)
+}
+
+// Proposed runs the matching actions in the background so the node's
+// polling is never held up by them.
+func (r *Runner) Proposed(round uint64, address string, payout uint64) {
+	p := Proposal{Event: "proposal", Round: round, Address: address, Payout: payout}
+	if r.cfg.Label != nil {
+		p.Label = r.cfg.Label(address)
+	}
+
+	for _, a := range r.cfg.Actions {
+		if !r.matches(a, p) {
+			continue
+		}
+
+		go r.run(a, p)
+	}
+}
+
+func (r *Runner) run(a config.ProposalAction, p Proposal) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	if a.Tray && r.cfg.Tray != nil {
+		name := p.Label
+		if name == "" {
+			name = p.Address
+		}
+		r.cfg.Tray(fmt.Sprintf("%s proposed block %d", name, p.Round))
+	}
+
+	if a.Sound != "" {
+		var err error
+		if a.Sound == sound.ProposalTone {
+			err = sound.PlayProposal(ctx)
+		} else {
+			err = sound.Play(ctx, a.Sound, 1)
+		}
+		if err != nil {
+			slog.Error("failed to play sound", "sound", a.Sound, "err", err)
+		}
+	}
+
+	if a.Webhook != "" {
+		err := post(ctx, a.Webhook, p)
+		if err != nil {
+			slog.Error("proposal action failed", "err", err)
+		}
+	}
+
+	if a.Script != "" {
+		hooks.Exec(a.Script, nil, 0, hooks.Event{
+			Event:   p.Event,
+			Title:   fmt.Sprintf("Proposed block %d", p.Round),
+			Round:   p.Round,
+			Address: p.Address,
+			Label:   p.Label,
+			Payout:  p.Payout,
+		})
+	}
+}
+
+func post(ctx context.Context, url string, p Proposal) error {
+	data, err := json.Marshal(p)
+	if err != nil {
+		return errors.Wrap(err, "failed to encode proposal")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return errors.Wrap(err, "failed to create webhook request")
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "failed to call webhook")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		return errors.Errorf("webhook failed: %s", resp.Status)
+	}
+
+	return nil
+}
@@ -0,0 +1,545 @@
+package alert
+
+import (
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"voiui/internal/state"
+)
+
+type Kind string
+
+const (
+	OnBattery        Kind = "ups:on-battery"
+	LowBattery       Kind = "ups:low-battery"
+	Down             Kind = "down"
+	Unauthorized     Kind = "unauthorized"
+	Lag              Kind = "lag"
+	Diverged         Kind = "diverged"
+	NotParticipating Kind = "not-participating"
+	NodeOutdated     Kind = "node-outdated"
+	Underperforming  Kind = "underperforming"
+	Overheating      Kind = "overheating"
+	DiskFailing      Kind = "disk-failing"
+	LowDisk          Kind = "low-disk"
+	SlowNode         Kind = "slow-node"
+	ClockDrift       Kind = "clock-drift"
+	PortClosed       Kind = "port-closed"
+	NoInbound        Kind = "no-inbound"
+	IndexerBehind    Kind = "indexer-behind"
+	PipelineStalled  Kind = "pipeline-stalled"
+)
+
+// rank orders kinds from the most likely root cause to the most likely symptom.
+var rank = map[Kind]int{
+	OnBattery:        0,
+	LowBattery:       1,
+	Overheating:      2,
+	DiskFailing:      3,
+	LowDisk:          4,
+	ClockDrift:       5,
+	Down:             6,
+	Unauthorized:     7,
+	Lag:              8,
+	Diverged:         9,
+	NotParticipating: 10,
+	NodeOutdated:     11,
+	Underperforming:  12,
+	SlowNode:         13,
+	PortClosed:       14,
+	NoInbound:        15,
+	IndexerBehind:    16,
+	PipelineStalled:  17,
+}
+
+var titles = map[Kind]string{
+	OnBattery:        "Node host is on battery",
+	LowBattery:       "UPS battery is low",
+	Down:             "Node is down",
+	Unauthorized:     "Node rejects the token",
+	Lag:              "Node is catching up",
+	Diverged:         "Node is on a different fork than the reference",
+	NotParticipating: "Not participating",
+	NodeOutdated:     "Node software is behind the latest release",
+	Underperforming:  "Proposing fewer blocks than stake implies",
+	Overheating:      "Node host is overheating",
+	DiskFailing:      "Node host disk is failing",
+	LowDisk:          "Node host is running out of disk space",
+	SlowNode:         "Node responds slowly",
+	ClockDrift:       "Node host clock is off",
+	PortClosed:       "Gossip port is closed",
+	NoInbound:        "Relay has no inbound peers",
+	IndexerBehind:    "Indexer is unhealthy or behind the node",
+	PipelineStalled:  "Data pipeline stopped advancing",
+}
+
+type Severity string
+
+const (
+	Info     Severity = "info"
+	Warning  Severity = "warning"
+	Critical Severity = "critical"
+)
+
+// critical kinds cost rewards or need the operator right away, the others
+// are warnings unless set otherwise.
+var critical = map[Kind]bool{
+	Down:             true,
+	Unauthorized:     true,
+	Diverged:         true,
+	NotParticipating: true,
+	LowBattery:       true,
+}
+
+func (s Severity) rank() int {
+	switch s {
+	case Info:
+		return 0
+	case Critical:
+		return 2
+	default:
+		return 1
+	}
+}
+
+const (
+	maxIncidents = 20
+	maxEvents    = 100
+
+	// external kinds rank after the built-in ones
+	externalRank = 100
+)
+
+func rankOf(kind Kind) int {
+	if r, ok := rank[kind]; ok {
+		return r
+	}
+	return externalRank
+}
+
+type Notification struct {
+	// Kind is what the notification is about, empty for summaries.
+	Kind     Kind
+	Severity Severity
+	Thread   string
+	Title    string
+	Body     string
+	Resolved bool
+}
+
+type Notifier interface {
+	Notify(n Notification) error
+}
+
+// Notifiers sends each notification to all of them.
+type Notifiers []Notifier
+
+func (ns Notifiers) Notify(n Notification) error {
+	var first error
+	for _, notifier := range ns {
+		err := notifier.Notify(n)
+		if err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+type LogNotifier struct{}
+
+func (LogNotifier) Notify(n Notification) error {
+	slog.Info("alert", "thread", n.Thread, "title", n.Title, "body", n.Body)
+	return nil
+}
+
+// Correlator groups conditions that are active at the same time into a
+// single incident, notifying when it opens, when a more severe or critical
+// condition joins it and when it resolves.
+type Correlator struct {
+	notifier Notifier
+	updates  chan<- state.Update
+
+	mu         sync.Mutex
+	muted      bool
+	silenced   time.Time
+	snoozed    map[Kind]time.Time
+	severities map[Kind]Severity
+	quietHours QuietHours
+	held       []Notification
+	active     map[Kind]string
+	incidents  []state.AlertIncident
+	events     []state.Event
+	open       bool
+	nextID     int
+	record     func(state.Event)
+}
+
+func NewCorrelator(notifier Notifier, updates chan<- state.Update) *Correlator {
+	if notifier == nil {
+		notifier = LogNotifier{}
+	}
+
+	return &Correlator{
+		notifier:   notifier,
+		updates:    updates,
+		snoozed:    map[Kind]time.Time{},
+		severities: map[Kind]Severity{},
+		active:     map[Kind]string{},
+	}
+}
+
+// Record passes every new event to fn, e.g. to persist it.
+func (c *Correlator) Record(fn func(state.Event)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.record = fn
+}
+
+// Restore seeds the event log, e.g. from a previous run.
+func (c *Correlator) Restore(events []state.Event) {
+	c.mu.Lock()
+
+	c.events = append(append([]state.Event(nil), events...), c.events...)
+	if len(c.events) > maxEvents {
+		c.events = c.events[len(c.events)-maxEvents:]
+	}
+
+	incidents, events := c.snapshot()
+
+	c.mu.Unlock()
+
+	c.publish(incidents, events)
+}
+
+func (c *Correlator) SetMuted(muted bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.muted = muted
+}
+
+// Silence holds notifications back until the given time, a zero time
+// lifts it. Incidents and events are still recorded.
+func (c *Correlator) Silence(until time.Time) {
+	c.mu.Lock()
+	c.silenced = until
+	c.mu.Unlock()
+
+	c.updates <- func(s *state.State) error {
+		s.AlertsSilenced = until
+		return nil
+	}
+}
+
+// quiet tells whether notifications are held back, c.mu must be held.
+func (c *Correlator) quiet(now time.Time) bool {
+	return c.muted || now.Before(c.silenced)
+}
+
+// SetSeverity overrides the severity of kind, e.g. for a user-defined rule.
+func (c *Correlator) SetSeverity(kind Kind, severity Severity) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.severities[kind] = severity
+}
+
+func (c *Correlator) severity(kind Kind) Severity {
+	if s, ok := c.severities[kind]; ok {
+		return s
+	}
+	if critical[kind] {
+		return Critical
+	}
+	return Warning
+}
+
+// Snooze holds notifications for kind back until the given time, a zero
+// time lifts it.
+func (c *Correlator) Snooze(kind string, until time.Time) {
+	c.mu.Lock()
+
+	if until.IsZero() {
+		delete(c.snoozed, Kind(kind))
+	} else {
+		c.snoozed[Kind(kind)] = until
+	}
+
+	snoozed := make([]state.Snooze, 0, len(c.snoozed))
+	for k, t := range c.snoozed {
+		snoozed = append(snoozed, state.Snooze{Kind: string(k), Until: t})
+	}
+
+	c.mu.Unlock()
+
+	c.updates <- func(s *state.State) error {
+		s.Snoozed = snoozed
+		return nil
+	}
+}
+
+// Acknowledge marks the incident as seen by the operator.
+func (c *Correlator) Acknowledge(id int) {
+	c.mu.Lock()
+
+	for i := range c.incidents {
+		if c.incidents[i].ID == id && c.incidents[i].Acked.IsZero() {
+			c.incidents[i].Acked = time.Now()
+		}
+	}
+
+	incidents, events := c.snapshot()
+
+	c.mu.Unlock()
+
+	c.publish(incidents, events)
+}
+
+func (c *Correlator) title(kind Kind) string {
+	if title, ok := titles[kind]; ok {
+		return title
+	}
+	return c.active[kind]
+}
+
+func (c *Correlator) root() Kind {
+	var root Kind
+	for kind := range c.active {
+		if root == "" || rankOf(kind) < rankOf(root) {
+			root = kind
+		}
+	}
+	return root
+}
+
+// escalates tells whether kind joining an open incident of the given
+// severity is worth another notification, e.g. the node going down while
+// it is outdated.
+func (c *Correlator) escalates(kind Kind, severity Severity) bool {
+	s := c.severity(kind)
+	return s == Critical || s.rank() > severity.rank()
+}
+
+// Set raises or clears a condition.
+func (c *Correlator) Set(kind Kind, active bool, message string) {
+	c.mu.Lock()
+
+	_, was := c.active[kind]
+	if active == was {
+		c.mu.Unlock()
+		return
+	}
+
+	now := time.Now()
+
+	var notes []Notification
+
+	snoozed := now.Before(c.snoozed[kind])
+
+	c.logEvent(now, "voiui", string(kind), message)
+
+	if active {
+		c.active[kind] = message
+
+		if !c.open {
+			c.nextID++
+			c.open = true
+			c.incidents = append(c.incidents, state.AlertIncident{
+				ID:     c.nextID,
+				Opened: now,
+			})
+			if len(c.incidents) > maxIncidents {
+				c.incidents = c.incidents[len(c.incidents)-maxIncidents:]
+			}
+
+			if !snoozed {
+				notes = append(notes, Notification{
+					Kind:     kind,
+					Severity: c.severity(kind),
+					Thread:   fmt.Sprintf("incident-%d", c.nextID),
+					Title:    c.title(kind),
+					Body:     message,
+				})
+			}
+		} else if inc := c.incidents[len(c.incidents)-1]; !snoozed && c.escalates(kind, Severity(inc.Severity)) {
+			notes = append(notes, Notification{
+				Kind:     kind,
+				Severity: c.severity(kind),
+				Thread:   fmt.Sprintf("incident-%d", inc.ID),
+				Title:    c.title(kind),
+				Body:     message,
+			})
+		}
+	} else {
+		delete(c.active, kind)
+	}
+
+	inc := &c.incidents[len(c.incidents)-1]
+	inc.Timeline = append(inc.Timeline, state.AlertEvent{
+		At:      now,
+		Kind:    string(kind),
+		Message: message,
+		Cleared: !active,
+	})
+
+	if root := c.root(); root != "" {
+		inc.Title = c.title(root)
+		inc.Kind = string(root)
+	}
+
+	if active && (inc.Severity == "" || c.severity(kind).rank() > Severity(inc.Severity).rank()) {
+		inc.Severity = string(c.severity(kind))
+	}
+
+	if len(c.active) == 0 {
+		c.open = false
+		inc.Resolved = now
+
+		if !snoozed {
+			notes = append(notes, Notification{
+				Kind:     kind,
+				Severity: Info,
+				Thread:   fmt.Sprintf("incident-%d", inc.ID),
+				Title:    "Resolved: " + inc.Title,
+				Body:     fmt.Sprintf("after %s, %d events", now.Sub(inc.Opened).Round(time.Second), len(inc.Timeline)),
+				Resolved: true,
+			})
+		}
+	}
+
+	muted := c.quiet(now)
+	incidents, events := c.snapshot()
+
+	c.mu.Unlock()
+
+	if !muted {
+		for _, n := range notes {
+			c.deliver(kind, n)
+		}
+	}
+
+	c.publish(incidents, events)
+}
+
+// Note adds an informational event to the latest incident.
+func (c *Correlator) Note(message string) {
+	c.mu.Lock()
+
+	if len(c.incidents) == 0 {
+		c.mu.Unlock()
+		return
+	}
+
+	inc := &c.incidents[len(c.incidents)-1]
+	inc.Timeline = append(inc.Timeline, state.AlertEvent{
+		At:      time.Now(),
+		Message: message,
+	})
+
+	incidents, events := c.snapshot()
+
+	c.mu.Unlock()
+
+	c.publish(incidents, events)
+}
+
+// Reset drops all conditions without notifying, e.g. after switching nodes.
+func (c *Correlator) Reset() {
+	c.mu.Lock()
+
+	if c.open {
+		inc := &c.incidents[len(c.incidents)-1]
+		inc.Resolved = time.Now()
+		c.open = false
+	}
+	c.active = map[Kind]string{}
+
+	incidents, events := c.snapshot()
+
+	c.mu.Unlock()
+
+	c.publish(incidents, events)
+}
+
+// Event records an informational event in the event log.
+func (c *Correlator) Event(source string, kind string, message string) {
+	c.mu.Lock()
+
+	c.logEvent(time.Now(), source, kind, message)
+
+	incidents, events := c.snapshot()
+
+	c.mu.Unlock()
+
+	c.publish(incidents, events)
+}
+
+// Digest sends a one-off summary, e.g. of what happened while voiui was
+// not running. It does not open an incident.
+func (c *Correlator) Digest(title string, body string) {
+	c.Notice("digest", title, body)
+}
+
+// Notice sends a one-off notification of kind, e.g. a stake change, that
+// needs no resolution and does not open an incident.
+func (c *Correlator) Notice(kind string, title string, body string) {
+	c.mu.Lock()
+
+	now := time.Now()
+	c.logEvent(now, "voiui", kind, title+": "+body)
+
+	muted := c.quiet(now)
+	incidents, events := c.snapshot()
+
+	c.mu.Unlock()
+
+	if !muted {
+		c.deliver("", Notification{
+			Kind:     Kind(kind),
+			Severity: Info,
+			Thread:   fmt.Sprintf("%s-%d", kind, now.UnixNano()),
+			Title:    title,
+			Body:     body,
+		})
+	}
+
+	c.publish(incidents, events)
+}
+
+func (c *Correlator) logEvent(at time.Time, source string, kind string, message string) {
+	e := state.Event{
+		At:      at,
+		Source:  source,
+		Kind:    kind,
+		Message: message,
+	}
+
+	c.events = append(c.events, e)
+	if len(c.events) > maxEvents {
+		c.events = c.events[len(c.events)-maxEvents:]
+	}
+
+	if c.record != nil {
+		c.record(e)
+	}
+}
+
+func (c *Correlator) snapshot() ([]state.AlertIncident, []state.Event) {
+	incidents := make([]state.AlertIncident, len(c.incidents))
+	for i, inc := range c.incidents {
+		inc.Timeline = append([]state.AlertEvent(nil), inc.Timeline...)
+		incidents[i] = inc
+	}
+	return incidents, append([]state.Event(nil), c.events...)
+}
+
+func (c *Correlator) publish(incidents []state.AlertIncident, events []state.Event) {
+	c.updates <- func(s *state.State) error {
+		s.Alerts = incidents
+		s.Events = events
+		return nil
+	}
+}
@@ -0,0 +1,228 @@
+package alert
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"voiui/internal/state"
+)
+
+type recorder struct {
+	mu    sync.Mutex
+	notes []Notification
+}
+
+func (r *recorder) Notify(n Notification) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.notes = append(r.notes, n)
+	return nil
+}
+
+func (r *recorder) sent() []Notification {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]Notification(nil), r.notes...)
+}
+
+// newTest returns a correlator and the state its updates have left so far.
+func newTest(t *testing.T) (*Correlator, *recorder, func() state.State) {
+	t.Helper()
+
+	updates := make(chan state.Update)
+	store := state.NewStore(state.State{})
+	done := make(chan struct{})
+
+	go func() {
+		for {
+			select {
+			case u := <-updates:
+				store.Apply(u)
+			case <-done:
+				return
+			}
+		}
+	}()
+	t.Cleanup(func() { close(done) })
+
+	snapshot := func() state.State {
+		// every update sent before this one is applied
+		applied := make(chan struct{})
+		updates <- func(s *state.State) error {
+			close(applied)
+			return nil
+		}
+		<-applied
+
+		return store.Snapshot()
+	}
+
+	r := &recorder{}
+	return NewCorrelator(r, updates), r, snapshot
+}
+
+type step struct {
+	kind   Kind
+	active bool
+}
+
+func TestIncidents(t *testing.T) {
+	tests := []struct {
+		name      string
+		steps     []step
+		incidents int
+		open      bool
+		root      Kind
+		severity  Severity
+		// sent is the title of each notification, all in one thread per
+		// incident
+		sent []string
+	}{
+		{
+			name:      "one condition",
+			steps:     []step{{Down, true}, {Down, false}},
+			incidents: 1,
+			root:      Down,
+			severity:  Critical,
+			sent:      []string{titles[Down], "Resolved: " + titles[Down]},
+		},
+		{
+			name:      "root cause titles the incident",
+			steps:     []step{{Lag, true}, {LowDisk, true}},
+			incidents: 1,
+			open:      true,
+			root:      LowDisk,
+			severity:  Warning,
+			sent:      []string{titles[Lag]},
+		},
+		{
+			name:      "critical joins a warning",
+			steps:     []step{{NodeOutdated, true}, {Down, true}},
+			incidents: 1,
+			open:      true,
+			root:      Down,
+			severity:  Critical,
+			sent:      []string{titles[NodeOutdated], titles[Down]},
+		},
+		{
+			name:      "critical joins a critical",
+			steps:     []step{{Down, true}, {NotParticipating, true}},
+			incidents: 1,
+			open:      true,
+			root:      Down,
+			severity:  Critical,
+			sent:      []string{titles[Down], titles[NotParticipating]},
+		},
+		{
+			name:      "warning joins a critical",
+			steps:     []step{{Down, true}, {SlowNode, true}},
+			incidents: 1,
+			open:      true,
+			root:      Down,
+			severity:  Critical,
+			sent:      []string{titles[Down]},
+		},
+		{
+			name:      "warning joins a warning",
+			steps:     []step{{ClockDrift, true}, {PortClosed, true}},
+			incidents: 1,
+			open:      true,
+			root:      ClockDrift,
+			severity:  Warning,
+			sent:      []string{titles[ClockDrift]},
+		},
+		{
+			name:      "repeated state",
+			steps:     []step{{Down, true}, {Down, true}, {Lag, false}},
+			incidents: 1,
+			open:      true,
+			root:      Down,
+			severity:  Critical,
+			sent:      []string{titles[Down]},
+		},
+		{
+			name:      "resolves once all clear",
+			steps:     []step{{SlowNode, true}, {Down, true}, {SlowNode, false}, {Down, false}},
+			incidents: 1,
+			root:      Down,
+			severity:  Critical,
+			sent:      []string{titles[SlowNode], titles[Down], "Resolved: " + titles[Down]},
+		},
+		{
+			name:      "new incident after resolving",
+			steps:     []step{{Lag, true}, {Lag, false}, {Down, true}},
+			incidents: 2,
+			open:      true,
+			root:      Down,
+			severity:  Critical,
+			sent:      []string{titles[Lag], "Resolved: " + titles[Lag], titles[Down]},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, r, snapshot := newTest(t)
+
+			for _, s := range tt.steps {
+				c.Set(s.kind, s.active, string(s.kind))
+			}
+
+			incidents := snapshot().Alerts
+			if len(incidents) != tt.incidents {
+				t.Fatalf("%d incidents, want %d", len(incidents), tt.incidents)
+			}
+
+			inc := incidents[len(incidents)-1]
+			if open := inc.Resolved.IsZero(); open != tt.open {
+				t.Errorf("open = %v, want %v", open, tt.open)
+			}
+			if Kind(inc.Kind) != tt.root {
+				t.Errorf("root = %s, want %s", inc.Kind, tt.root)
+			}
+			if Severity(inc.Severity) != tt.severity {
+				t.Errorf("severity = %s, want %s", inc.Severity, tt.severity)
+			}
+
+			sent := r.sent()
+			if len(sent) != len(tt.sent) {
+				t.Fatalf("sent %v, want %v", sent, tt.sent)
+			}
+
+			threads := map[string]bool{}
+			for i, n := range sent {
+				if n.Title != tt.sent[i] {
+					t.Errorf("notification %d = %q, want %q", i, n.Title, tt.sent[i])
+				}
+				threads[n.Thread] = true
+			}
+			if len(threads) != tt.incidents {
+				t.Errorf("%d threads for %d incidents", len(threads), tt.incidents)
+			}
+		})
+	}
+}
+
+func TestSnoozeAndMute(t *testing.T) {
+	c, r, _ := newTest(t)
+
+	c.SetMuted(true)
+	c.Set(Down, true, "")
+	c.Set(Down, false, "")
+	c.SetMuted(false)
+
+	if sent := r.sent(); len(sent) != 0 {
+		t.Errorf("muted correlator sent %v", sent)
+	}
+
+	c.Snooze(string(NodeOutdated), time.Now().Add(time.Hour))
+	c.Set(NodeOutdated, true, "")
+	c.Set(Down, true, "")
+
+	sent := r.sent()
+	if len(sent) != 1 || sent[0].Kind != Down {
+		t.Errorf("sent %v, want only %s", sent, Down)
+	}
+}
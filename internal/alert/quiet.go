@@ -0,0 +1,152 @@
+package alert
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// QuietHours hold non-critical notifications back overnight and send them
+// as one summary when they end. Critical ones still get through once they
+// have lasted Critical.
+type QuietHours struct {
+	// From and To are minutes after midnight, equal ones disable it.
+	From     int
+	To       int
+	Critical time.Duration
+}
+
+// ParseQuiet reads quiet hours given as "23:00" and "07:00".
+func ParseQuiet(from string, to string, critical time.Duration) (QuietHours, error) {
+	q := QuietHours{Critical: critical}
+
+	if from == "" && to == "" {
+		return q, nil
+	}
+
+	for _, p := range []struct {
+		s string
+		m *int
+	}{{from, &q.From}, {to, &q.To}} {
+		t, err := time.Parse("15:04", p.s)
+		if err != nil {
+			return QuietHours{}, errors.Errorf("invalid quiet hours time %q, use e.g. 23:00", p.s)
+		}
+		*p.m = t.Hour()*60 + t.Minute()
+	}
+
+	return q, nil
+}
+
+// in tells whether t falls in the quiet hours, which may span midnight.
+func (q QuietHours) in(t time.Time) bool {
+	m := t.Hour()*60 + t.Minute()
+
+	switch {
+	case q.From == q.To:
+		return false
+	case q.From < q.To:
+		return m >= q.From && m < q.To
+	default:
+		return m >= q.From || m < q.To
+	}
+}
+
+func (c *Correlator) SetQuiet(q QuietHours) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.quietHours = q
+}
+
+// deliver sends n now, later or in the summary depending on the quiet
+// hours and the severity of kind.
+func (c *Correlator) deliver(kind Kind, n Notification) {
+	c.mu.Lock()
+
+	if !c.quietHours.in(time.Now()) {
+		c.mu.Unlock()
+		c.notify(n)
+		return
+	}
+
+	if kind == "" || c.severity(kind) != Critical || n.Resolved {
+		c.held = append(c.held, n)
+		c.mu.Unlock()
+		return
+	}
+
+	wait := c.quietHours.Critical
+
+	c.mu.Unlock()
+
+	time.AfterFunc(wait, func() {
+		c.mu.Lock()
+		_, still := c.active[kind]
+		if !still {
+			c.held = append(c.held, n)
+		}
+		c.mu.Unlock()
+
+		if still {
+			c.notify(n)
+		}
+	})
+}
+
+func (c *Correlator) notify(n Notification) {
+	err := c.notifier.Notify(n)
+	if err != nil {
+		slog.Error("failed to send notification", "err", err)
+	}
+}
+
+// flush sends what the quiet hours held back as one summary once they are
+// over.
+func (c *Correlator) flush(now time.Time) {
+	c.mu.Lock()
+
+	if len(c.held) == 0 || c.quietHours.in(now) {
+		c.mu.Unlock()
+		return
+	}
+
+	held := c.held
+	c.held = nil
+
+	c.mu.Unlock()
+
+	lines := make([]string, len(held))
+	for i, n := range held {
+		lines[i] = n.Title
+		if n.Body != "" {
+			lines[i] += ": " + n.Body
+		}
+	}
+
+	c.notify(Notification{
+		Severity: Info,
+		Thread:   fmt.Sprintf("summary-%d", now.Unix()),
+		Title:    fmt.Sprintf("%d notifications during quiet hours", len(held)),
+		Body:     strings.Join(lines, "\n"),
+	})
+}
+
+// Run sends the summary of the quiet hours when they end.
+func (c *Correlator) Run(ctx context.Context) {
+	t := time.NewTicker(time.Minute)
+	defer t.Stop()
+
+	for {
+		select {
+		case now := <-t.C:
+			c.flush(now)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package alert
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseQuiet(t *testing.T) {
+	tests := []struct {
+		from, to string
+		want     QuietHours
+		err      bool
+	}{
+		{"", "", QuietHours{}, false},
+		{"23:00", "07:00", QuietHours{From: 23 * 60, To: 7 * 60}, false},
+		{"12:30", "13:45", QuietHours{From: 12*60 + 30, To: 13*60 + 45}, false},
+		{"23:00", "", QuietHours{}, true},
+		{"11pm", "07:00", QuietHours{}, true},
+		{"24:00", "07:00", QuietHours{}, true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseQuiet(tt.from, tt.to, 0)
+		if (err != nil) != tt.err {
+			t.Errorf("ParseQuiet(%q, %q) error = %v, want error %v", tt.from, tt.to, err, tt.err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseQuiet(%q, %q) = %+v, want %+v", tt.from, tt.to, got, tt.want)
+		}
+	}
+}
+
+func TestQuietIn(t *testing.T) {
+	at := func(h, m int) time.Time {
+		return time.Date(2024, 1, 1, h, m, 0, 0, time.Local)
+	}
+
+	tests := []struct {
+		name string
+		q    QuietHours
+		t    time.Time
+		want bool
+	}{
+		{"disabled", QuietHours{}, at(3, 0), false},
+		{"same day inside", QuietHours{From: 12 * 60, To: 14 * 60}, at(13, 0), true},
+		{"same day start", QuietHours{From: 12 * 60, To: 14 * 60}, at(12, 0), true},
+		{"same day end", QuietHours{From: 12 * 60, To: 14 * 60}, at(14, 0), false},
+		{"same day outside", QuietHours{From: 12 * 60, To: 14 * 60}, at(11, 59), false},
+		{"overnight before midnight", QuietHours{From: 23 * 60, To: 7 * 60}, at(23, 30), true},
+		{"overnight after midnight", QuietHours{From: 23 * 60, To: 7 * 60}, at(6, 59), true},
+		{"overnight end", QuietHours{From: 23 * 60, To: 7 * 60}, at(7, 0), false},
+		{"overnight daytime", QuietHours{From: 23 * 60, To: 7 * 60}, at(12, 0), false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.q.in(tt.t); got != tt.want {
+			t.Errorf("%s: in(%s) = %v, want %v", tt.name, tt.t.Format("15:04"), got, tt.want)
+		}
+	}
+}
+
+// around returns quiet hours that last from an hour ago to an hour from now.
+func around(critical time.Duration) QuietHours {
+	t := time.Now()
+	m := t.Hour()*60 + t.Minute()
+	return QuietHours{From: (m + 23*60) % (24 * 60), To: (m + 60) % (24 * 60), Critical: critical}
+}
+
+func TestQuietHours(t *testing.T) {
+	tests := []struct {
+		name  string
+		steps []step
+		// wait lets critical notifications that outlast Critical through
+		wait time.Duration
+		// during is what goes out in the quiet hours, held is what the
+		// summary afterwards lists
+		during []string
+		held   []string
+	}{
+		{
+			name:   "warnings wait for the summary",
+			steps:  []step{{Lag, true}, {Lag, false}},
+			during: nil,
+			held:   []string{titles[Lag], "Resolved: " + titles[Lag]},
+		},
+		{
+			name:   "lasting critical gets through",
+			steps:  []step{{Down, true}},
+			wait:   200 * time.Millisecond,
+			during: []string{titles[Down]},
+			held:   nil,
+		},
+		{
+			name:   "short critical waits for the summary",
+			steps:  []step{{Down, true}, {Down, false}},
+			wait:   200 * time.Millisecond,
+			during: nil,
+			held:   []string{"Resolved: " + titles[Down], titles[Down]},
+		},
+		{
+			name:   "critical escalation gets through",
+			steps:  []step{{NodeOutdated, true}, {NotParticipating, true}},
+			wait:   200 * time.Millisecond,
+			during: []string{titles[NotParticipating]},
+			held:   []string{titles[NodeOutdated]},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, r, _ := newTest(t)
+			c.SetQuiet(around(50 * time.Millisecond))
+
+			for _, s := range tt.steps {
+				c.Set(s.kind, s.active, "")
+			}
+			time.Sleep(tt.wait)
+
+			sent := r.sent()
+			if len(sent) != len(tt.during) {
+				t.Fatalf("sent %v during quiet hours, want %v", sent, tt.during)
+			}
+			for i, n := range sent {
+				if n.Title != tt.during[i] {
+					t.Errorf("notification %d = %q, want %q", i, n.Title, tt.during[i])
+				}
+			}
+
+			c.flush(time.Now())
+			if got := r.sent(); len(got) != len(sent) {
+				t.Fatalf("summary sent during quiet hours: %v", got[len(sent):])
+			}
+
+			c.SetQuiet(QuietHours{})
+			c.flush(time.Now())
+
+			got := r.sent()[len(sent):]
+			if len(tt.held) == 0 {
+				if len(got) != 0 {
+					t.Errorf("summary %v with nothing held", got)
+				}
+				return
+			}
+			if len(got) != 1 {
+				t.Fatalf("sent %v after quiet hours, want one summary", got)
+			}
+
+			lines := strings.Split(got[0].Body, "\n")
+			if len(lines) != len(tt.held) {
+				t.Fatalf("summary %q, want %v", got[0].Body, tt.held)
+			}
+			for i, line := range lines {
+				if !strings.HasPrefix(line, tt.held[i]) {
+					t.Errorf("summary line %d = %q, want %q", i, line, tt.held[i])
+				}
+			}
+		})
+	}
+}
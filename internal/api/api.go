@@ -0,0 +1,127 @@
+package api
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Server is the local HTTP API. Every request must carry the secret as a
+// bearer token when one is configured; clients that cannot set headers,
+// like calendar apps, may pass it as the token query parameter.
+type Server struct {
+	addr   string
+	secret string
+	mux    *http.ServeMux
+	public http.Handler
+}
+
+func New(addr string, secret string) *Server {
+	return &Server{
+		addr:   addr,
+		secret: secret,
+		mux:    http.NewServeMux(),
+	}
+}
+
+func (s *Server) Handle(pattern string, h http.Handler) {
+	s.mux.Handle(pattern, h)
+}
+
+// Public serves h without the secret for paths outside /v1/, e.g. the
+// files of the web UI, which then calls the API with the secret.
+func (s *Server) Public(h http.Handler) {
+	s.public = h
+}
+
+func (s *Server) authorized(r *http.Request) bool {
+	if s.secret == "" {
+		return true
+	}
+
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if token == "" {
+		token = r.URL.Query().Get("token")
+	}
+
+	return subtle.ConstantTimeCompare([]byte(token), []byte(s.secret)) == 1
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if s.public != nil && !strings.HasPrefix(r.URL.Path, "/v1/") {
+		s.public.ServeHTTP(w, r)
+		return
+	}
+
+	if !s.authorized(r) {
+		writeError(w, http.StatusUnauthorized, errors.New("unauthorized"))
+		return
+	}
+
+	s.mux.ServeHTTP(w, r)
+}
+
+func (s *Server) Run(ctx context.Context) error {
+	l, err := net.Listen("tcp", s.addr)
+	if err != nil {
+		return errors.Wrapf(err, "failed to listen on %s", s.addr)
+	}
+
+	return s.serve(ctx, l)
+}
+
+// RunSocket serves on a Unix socket at path that only the current user
+// can connect to, replacing one left behind by an earlier run.
+func (s *Server) RunSocket(ctx context.Context, path string) error {
+	os.Remove(path)
+
+	l, err := net.Listen("unix", path)
+	if err != nil {
+		return errors.Wrapf(err, "failed to listen on %s", path)
+	}
+	defer os.Remove(path)
+
+	err = os.Chmod(path, 0600)
+	if err != nil {
+		l.Close()
+		return errors.Wrap(err, "failed to restrict the socket")
+	}
+
+	return s.serve(ctx, l)
+}
+
+func (s *Server) serve(ctx context.Context, l net.Listener) error {
+	srv := &http.Server{
+		Handler:           s,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		<-ctx.Done()
+		srv.Close()
+	}()
+
+	err := srv.Serve(l)
+	if err == http.ErrServerClosed {
+		return nil
+	}
+
+	return errors.Wrap(err, "api server failed")
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
@@ -0,0 +1,105 @@
+package api
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+const maxAutomationBody = 64 << 10
+
+type UIDriver interface {
+	Elements() (map[string]string, error)
+	Click(id string) error
+	Type(id string, text string) error
+	Screenshot(width int, height int) ([]byte, error)
+}
+
+type automationAction struct {
+	ID   string `json:"id"`
+	Text string `json:"text"`
+}
+
+// Automation lets scripts walk through the UI:
+//
+//	GET  /v1/ui/elements
+//	POST /v1/ui/click      {"id": "token.reload"}
+//	POST /v1/ui/type       {"id": "lock.pin", "text": "1234"}
+//	GET  /v1/ui/screenshot?w=480&h=800
+func Automation(d UIDriver) http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/v1/ui/elements", func(w http.ResponseWriter, r *http.Request) {
+		els, err := d.Elements()
+		if err != nil {
+			writeError(w, http.StatusServiceUnavailable, err)
+			return
+		}
+
+		writeJSON(w, http.StatusOK, els)
+	})
+
+	action := func(f func(a automationAction) error) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodPost {
+				writeError(w, http.StatusMethodNotAllowed, errors.New("use POST"))
+				return
+			}
+
+			var a automationAction
+
+			err := json.NewDecoder(io.LimitReader(r.Body, maxAutomationBody)).Decode(&a)
+			if err != nil {
+				writeError(w, http.StatusBadRequest, errors.Wrap(err, "failed to decode action"))
+				return
+			}
+
+			err = f(a)
+			if err != nil {
+				writeError(w, http.StatusBadRequest, err)
+				return
+			}
+
+			writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+		}
+	}
+
+	mux.HandleFunc("/v1/ui/click", action(func(a automationAction) error { return d.Click(a.ID) }))
+	mux.HandleFunc("/v1/ui/type", action(func(a automationAction) error { return d.Type(a.ID, a.Text) }))
+
+	mux.HandleFunc("/v1/ui/screenshot", func(w http.ResponseWriter, r *http.Request) {
+		width, height := 480, 800
+
+		if v := r.URL.Query().Get("w"); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil || n <= 0 || n > 4096 {
+				writeError(w, http.StatusBadRequest, errors.New("invalid width"))
+				return
+			}
+			width = n
+		}
+
+		if v := r.URL.Query().Get("h"); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil || n <= 0 || n > 4096 {
+				writeError(w, http.StatusBadRequest, errors.New("invalid height"))
+				return
+			}
+			height = n
+		}
+
+		data, err := d.Screenshot(width, height)
+		if err != nil {
+			writeError(w, http.StatusServiceUnavailable, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "image/png")
+		w.Write(data)
+	})
+
+	return mux
+}
@@ -0,0 +1,86 @@
+package api
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"voiui/internal/state"
+)
+
+const maxControlBody = 4 << 10
+
+type Snapshotter interface {
+	Snapshot() state.State
+}
+
+type Controller interface {
+	RetryNow()
+	Silence(until time.Time)
+}
+
+type silenceRequest struct {
+	// For is a duration like "30m", empty or "0" lifts the silence.
+	For string `json:"for"`
+}
+
+// Control lets local scripts, e.g. status bars, follow and steer the
+// running instance:
+//
+//	GET  /v1/state
+//	POST /v1/refresh                              retries the node right away
+//	POST /v1/alerts/silence {"for": "30m"}        holds notifications back
+//	POST /v1/alerts/silence {"for": "0"}          lifts the silence
+func Control(store Snapshotter, c Controller) http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/v1/state", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, store.Snapshot())
+	})
+
+	mux.HandleFunc("/v1/refresh", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			writeError(w, http.StatusMethodNotAllowed, errors.New("use POST"))
+			return
+		}
+
+		c.RetryNow()
+
+		writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+	})
+
+	mux.HandleFunc("/v1/alerts/silence", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			writeError(w, http.StatusMethodNotAllowed, errors.New("use POST"))
+			return
+		}
+
+		var req silenceRequest
+
+		err := json.NewDecoder(io.LimitReader(r.Body, maxControlBody)).Decode(&req)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, errors.Wrap(err, "failed to decode request"))
+			return
+		}
+
+		var until time.Time
+
+		if req.For != "" && req.For != "0" {
+			d, err := time.ParseDuration(req.For)
+			if err != nil || d < 0 {
+				writeError(w, http.StatusBadRequest, errors.Errorf("invalid duration: %s", req.For))
+				return
+			}
+			until = time.Now().Add(d)
+		}
+
+		c.Silence(until)
+
+		writeJSON(w, http.StatusOK, map[string]time.Time{"until": until})
+	})
+
+	return mux
+}
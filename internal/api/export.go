@@ -0,0 +1,54 @@
+package api
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+type Exporter interface {
+	Files(format string, from time.Time, to time.Time, dir string) ([]string, error)
+}
+
+// ExportRequest asks the running instance to export its history, which
+// it holds open, to Dir.
+type ExportRequest struct {
+	Format string    `json:"format"`
+	From   time.Time `json:"from"`
+	To     time.Time `json:"to"`
+	Dir    string    `json:"dir"`
+}
+
+type ExportReply struct {
+	Paths []string `json:"paths"`
+	Error string   `json:"error,omitempty"`
+}
+
+// Export serves POST /v1/export for voiui export.
+func Export(x Exporter) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			writeError(w, http.StatusMethodNotAllowed, errors.New("use POST"))
+			return
+		}
+
+		var req ExportRequest
+
+		err := json.NewDecoder(io.LimitReader(r.Body, maxControlBody)).Decode(&req)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, errors.Wrap(err, "failed to decode request"))
+			return
+		}
+
+		paths, err := x.Files(req.Format, req.From, req.To, req.Dir)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+
+		writeJSON(w, http.StatusOK, ExportReply{Paths: paths})
+	})
+}
@@ -0,0 +1,261 @@
+package api
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"voiui/internal/history"
+	"voiui/internal/state"
+)
+
+const maxGrafanaBody = 64 << 10
+
+type History interface {
+	Blocks(from time.Time, to time.Time) ([]history.Block, error)
+	Proposals(from time.Time, to time.Time) ([]history.Proposal, error)
+	Outages(from time.Time, to time.Time) ([]state.Outage, error)
+}
+
+var grafanaMetrics = []string{"block_time", "txns_per_block", "proposals", "uptime_percent"}
+
+type grafanaQuery struct {
+	Range struct {
+		From time.Time `json:"from"`
+		To   time.Time `json:"to"`
+	} `json:"range"`
+	IntervalMs    int64 `json:"intervalMs"`
+	MaxDataPoints int   `json:"maxDataPoints"`
+	Targets       []struct {
+		Target string `json:"target"`
+	} `json:"targets"`
+}
+
+type series struct {
+	Target     string       `json:"target"`
+	Datapoints [][2]float64 `json:"datapoints"`
+}
+
+type annotation struct {
+	Time    int64    `json:"time"`
+	TimeEnd int64    `json:"timeEnd,omitempty"`
+	Title   string   `json:"title"`
+	Text    string   `json:"text"`
+	Tags    []string `json:"tags"`
+}
+
+func ms(t time.Time) float64 {
+	return float64(t.UnixMilli())
+}
+
+// step is Grafana's interval, or the range spread over the data points.
+func (q grafanaQuery) step() time.Duration {
+	step := time.Duration(q.IntervalMs) * time.Millisecond
+	if step <= 0 && q.MaxDataPoints > 0 {
+		step = q.Range.To.Sub(q.Range.From) / time.Duration(q.MaxDataPoints)
+	}
+	if step < time.Second {
+		step = time.Minute
+	}
+	return step
+}
+
+func query(h History, q grafanaQuery, target string) (series, error) {
+	from, to, step := q.Range.From, q.Range.To, q.step()
+	s := series{Target: target, Datapoints: [][2]float64{}}
+
+	n := int(to.Sub(from)/step) + 1
+	index := func(t time.Time) int {
+		return int(t.Sub(from) / step)
+	}
+	at := func(i int) float64 {
+		return ms(from.Add(time.Duration(i) * step))
+	}
+
+	switch target {
+	case "block_time", "txns_per_block":
+		blocks, err := h.Blocks(from, to)
+		if err != nil {
+			return s, err
+		}
+
+		sums := make([]float64, n)
+		counts := make([]int, n)
+		for _, b := range blocks {
+			i := index(b.At)
+			if i < 0 || i >= n {
+				continue
+			}
+			if target == "block_time" {
+				sums[i] += b.Duration.Seconds()
+			} else {
+				sums[i] += float64(b.Txns)
+			}
+			counts[i]++
+		}
+
+		for i := range sums {
+			if counts[i] > 0 {
+				s.Datapoints = append(s.Datapoints, [2]float64{sums[i] / float64(counts[i]), at(i)})
+			}
+		}
+	case "proposals":
+		proposals, err := h.Proposals(from, to)
+		if err != nil {
+			return s, err
+		}
+
+		counts := make([]int, n)
+		for _, p := range proposals {
+			if i := index(p.At); i >= 0 && i < n {
+				counts[i]++
+			}
+		}
+
+		for i, c := range counts {
+			s.Datapoints = append(s.Datapoints, [2]float64{float64(c), at(i)})
+		}
+	case "uptime_percent":
+		outages, err := h.Outages(from, to)
+		if err != nil {
+			return s, err
+		}
+
+		down := make([]time.Duration, n)
+		for _, o := range outages {
+			end := o.End
+			if end.IsZero() {
+				end = time.Now()
+			}
+
+			for i := 0; i < n; i++ {
+				start, stop := from.Add(time.Duration(i)*step), from.Add(time.Duration(i+1)*step)
+				if o.Start.After(start) {
+					start = o.Start
+				}
+				if end.Before(stop) {
+					stop = end
+				}
+				if stop.After(start) {
+					down[i] += stop.Sub(start)
+				}
+			}
+		}
+
+		for i, d := range down {
+			if from.Add(time.Duration(i) * step).After(time.Now()) {
+				break
+			}
+			up := 100 * (1 - float64(d)/float64(step))
+			if up < 0 {
+				up = 0
+			}
+			s.Datapoints = append(s.Datapoints, [2]float64{up, at(i)})
+		}
+	default:
+		return s, errors.Errorf("unknown target %q, use one of %s", target, strings.Join(grafanaMetrics, ", "))
+	}
+
+	return s, nil
+}
+
+func annotations(h History, q grafanaQuery) ([]annotation, error) {
+	outages, err := h.Outages(q.Range.From, q.Range.To)
+	if err != nil {
+		return nil, err
+	}
+
+	proposals, err := h.Proposals(q.Range.From, q.Range.To)
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]annotation, 0, len(outages)+len(proposals))
+
+	for _, o := range outages {
+		a := annotation{
+			Time:  o.Start.UnixMilli(),
+			Title: "Outage",
+			Text:  o.Kind,
+			Tags:  []string{"outage", o.Kind},
+		}
+		if !o.End.IsZero() {
+			a.TimeEnd = o.End.UnixMilli()
+		}
+		list = append(list, a)
+	}
+
+	for _, p := range proposals {
+		list = append(list, annotation{
+			Time:  p.At.UnixMilli(),
+			Title: "Proposal",
+			Text:  p.Address,
+			Tags:  []string{"proposal"},
+		})
+	}
+
+	return list, nil
+}
+
+// Grafana serves the history to Grafana's JSON (SimpleJSON) datasource
+// and the Infinity plugin: POST search lists the metrics, query returns
+// their time series averaged over Grafana's interval and annotations marks
+// outages and proposals.
+func Grafana(h History) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+
+		if path == "" {
+			writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+			return
+		}
+
+		var q grafanaQuery
+
+		if r.Method == http.MethodPost {
+			err := json.NewDecoder(io.LimitReader(r.Body, maxGrafanaBody)).Decode(&q)
+			if err != nil && err != io.EOF {
+				writeError(w, http.StatusBadRequest, errors.Wrap(err, "failed to decode query"))
+				return
+			}
+		}
+
+		if q.Range.To.IsZero() {
+			q.Range.To = time.Now()
+		}
+		if q.Range.From.IsZero() {
+			q.Range.From = q.Range.To.Add(-24 * time.Hour)
+		}
+
+		switch path {
+		case "search", "metrics":
+			writeJSON(w, http.StatusOK, grafanaMetrics)
+		case "query":
+			result := make([]series, 0, len(q.Targets))
+			for _, t := range q.Targets {
+				s, err := query(h, q, t.Target)
+				if err != nil {
+					writeError(w, http.StatusBadRequest, err)
+					return
+				}
+				result = append(result, s)
+			}
+
+			writeJSON(w, http.StatusOK, result)
+		case "annotations":
+			list, err := annotations(h, q)
+			if err != nil {
+				writeError(w, http.StatusInternalServerError, err)
+				return
+			}
+
+			writeJSON(w, http.StatusOK, list)
+		default:
+			writeError(w, http.StatusNotFound, errors.Errorf("unknown endpoint %s", path))
+		}
+	})
+}
@@ -0,0 +1,66 @@
+package api
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/pkg/errors"
+
+	"voiui/internal/alert"
+)
+
+const maxWebhookBody = 64 << 10
+
+type EventSink interface {
+	Set(kind alert.Kind, active bool, message string)
+	Event(source string, kind string, message string)
+}
+
+// WebhookEvent is posted by external systems, e.g. a UPS monitor:
+//
+//	{"source": "ups", "event": "on-battery", "message": "UPS on battery", "alert": true}
+//
+// Alerts stay open until the same source and event are posted with
+// "resolved": true.
+type WebhookEvent struct {
+	Source   string `json:"source"`
+	Event    string `json:"event"`
+	Message  string `json:"message"`
+	Alert    bool   `json:"alert"`
+	Resolved bool   `json:"resolved"`
+}
+
+func Webhook(sink EventSink) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			writeError(w, http.StatusMethodNotAllowed, errors.New("use POST"))
+			return
+		}
+
+		var e WebhookEvent
+
+		err := json.NewDecoder(io.LimitReader(r.Body, maxWebhookBody)).Decode(&e)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, errors.Wrap(err, "failed to decode event"))
+			return
+		}
+
+		if e.Source == "" || e.Event == "" {
+			writeError(w, http.StatusBadRequest, errors.New("source and event are required"))
+			return
+		}
+
+		if e.Message == "" {
+			e.Message = e.Source + ": " + e.Event
+		}
+
+		if e.Alert {
+			sink.Set(alert.Kind(e.Source+":"+e.Event), !e.Resolved, e.Message)
+		} else {
+			sink.Event(e.Source, e.Event, e.Message)
+		}
+
+		writeJSON(w, http.StatusAccepted, map[string]string{"status": "accepted"})
+	})
+}
@@ -0,0 +1,153 @@
+package applock
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/scrypt"
+)
+
+const lockFile = "applock.json"
+
+var ErrUnsupported = errors.New("OS authentication is not supported on this platform")
+
+type settings struct {
+	Salt []byte `json:"salt"`
+	Hash []byte `json:"hash"`
+}
+
+type Lock struct {
+	path string
+
+	mu sync.Mutex
+	s  *settings
+
+	osOnce sync.Once
+	osOK   bool
+}
+
+func Load(dir string) (*Lock, error) {
+	l := &Lock{
+		path: filepath.Join(dir, lockFile),
+	}
+
+	data, err := os.ReadFile(l.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return l, nil
+		}
+		return nil, errors.Wrap(err, "failed to read app lock settings")
+	}
+
+	var s settings
+
+	err = json.Unmarshal(data, &s)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode app lock settings")
+	}
+
+	l.s = &s
+
+	return l, nil
+}
+
+func hashPIN(pin string, salt []byte) ([]byte, error) {
+	hash, err := scrypt.Key([]byte(pin), salt, 1<<15, 8, 1, 32)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to hash pin")
+	}
+
+	return hash, nil
+}
+
+func (l *Lock) Enabled() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.s != nil
+}
+
+func (l *Lock) SetPIN(pin string) error {
+	if len(pin) < 4 {
+		return errors.New("PIN must be at least 4 characters")
+	}
+
+	salt := make([]byte, 16)
+
+	_, err := rand.Read(salt)
+	if err != nil {
+		return errors.Wrap(err, "failed to generate salt")
+	}
+
+	hash, err := hashPIN(pin, salt)
+	if err != nil {
+		return err
+	}
+
+	s := &settings{
+		Salt: salt,
+		Hash: hash,
+	}
+
+	data, err := json.Marshal(s)
+	if err != nil {
+		return errors.Wrap(err, "failed to encode app lock settings")
+	}
+
+	err = os.WriteFile(l.path, data, 0600)
+	if err != nil {
+		return errors.Wrap(err, "failed to write app lock settings")
+	}
+
+	l.mu.Lock()
+	l.s = s
+	l.mu.Unlock()
+
+	return nil
+}
+
+func (l *Lock) Disable() error {
+	err := os.Remove(l.path)
+	if err != nil && !os.IsNotExist(err) {
+		return errors.Wrap(err, "failed to remove app lock settings")
+	}
+
+	l.mu.Lock()
+	l.s = nil
+	l.mu.Unlock()
+
+	return nil
+}
+
+func (l *Lock) Verify(pin string) bool {
+	l.mu.Lock()
+	s := l.s
+	l.mu.Unlock()
+
+	if s == nil {
+		return true
+	}
+
+	hash, err := hashPIN(pin, s.Salt)
+	if err != nil {
+		return false
+	}
+
+	return subtle.ConstantTimeCompare(hash, s.Hash) == 1
+}
+
+// OSAvailable tells whether OS authentication can be offered, it is checked
+// once as it may run a program.
+func (l *Lock) OSAvailable() bool {
+	l.osOnce.Do(func() { l.osOK = osAvailable() })
+	return l.osOK
+}
+
+func (l *Lock) OSAuthenticate(reason string) error {
+	return osAuthenticate(reason)
+}
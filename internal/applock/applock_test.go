@@ -0,0 +1,105 @@
+package applock
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestVerify(t *testing.T) {
+	dir := t.TempDir()
+
+	l, err := Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l.Enabled() || !l.Verify("") {
+		t.Fatal("a new lock is enabled")
+	}
+
+	if err := l.SetPIN("123"); err == nil {
+		t.Error("accepted a 3 character PIN")
+	}
+
+	err = l.SetPIN("1234")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, lockFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "1234") {
+		t.Error("the PIN is stored in the clear")
+	}
+
+	loaded, err := Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		pin  string
+		want bool
+	}{
+		{"1234", true},
+		{"1235", false},
+		{"12345", false},
+		{"", false},
+	}
+
+	for _, lock := range []*Lock{l, loaded} {
+		if !lock.Enabled() {
+			t.Fatal("the lock is not enabled")
+		}
+		for _, tt := range tests {
+			if got := lock.Verify(tt.pin); got != tt.want {
+				t.Errorf("Verify(%q) = %v, want %v", tt.pin, got, tt.want)
+			}
+		}
+	}
+
+	err = l.Disable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l.Enabled() || !l.Verify("") {
+		t.Error("the disabled lock is enabled")
+	}
+	if _, err := os.Stat(filepath.Join(dir, lockFile)); !os.IsNotExist(err) {
+		t.Errorf("settings left behind: %v", err)
+	}
+}
+
+func TestHashPIN(t *testing.T) {
+	tests := []struct {
+		name  string
+		pin   string
+		salt  string
+		equal bool
+	}{
+		{name: "same pin and salt", pin: "1234", salt: "salt", equal: true},
+		{name: "other pin", pin: "4321", salt: "salt"},
+		{name: "other salt", pin: "1234", salt: "pepper"},
+	}
+
+	want, err := hashPIN("1234", []byte("salt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hash, err := hashPIN(tt.pin, []byte(tt.salt))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if bytes.Equal(hash, want) != tt.equal {
+				t.Errorf("hash equal = %v, want %v", !tt.equal, tt.equal)
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+//go:build cgo
+
+package applock
+
+/*
+#cgo CFLAGS: -x objective-c -fobjc-arc
+#cgo LDFLAGS: -framework Foundation -framework LocalAuthentication
+
+#include <stdlib.h>
+#import <Foundation/Foundation.h>
+#import <LocalAuthentication/LocalAuthentication.h>
+
+static int canAuthenticate(void) {
+	LAContext *ctx = [[LAContext alloc] init];
+	return [ctx canEvaluatePolicy:LAPolicyDeviceOwnerAuthentication error:nil] ? 1 : 0;
+}
+
+// authenticate asks for Touch ID or the user's own password and waits for
+// the answer, it returns 1 when verified.
+static int authenticate(const char *reason) {
+	LAContext *ctx = [[LAContext alloc] init];
+	dispatch_semaphore_t done = dispatch_semaphore_create(0);
+	__block int ok = 0;
+
+	[ctx evaluatePolicy:LAPolicyDeviceOwnerAuthentication
+	    localizedReason:[NSString stringWithUTF8String:reason]
+	              reply:^(BOOL success, NSError *error) {
+		ok = success ? 1 : 0;
+		dispatch_semaphore_signal(done);
+	}];
+
+	dispatch_semaphore_wait(done, DISPATCH_TIME_FOREVER);
+	return ok;
+}
+*/
+import "C"
+
+import (
+	"unsafe"
+
+	"github.com/pkg/errors"
+)
+
+func osAvailable() bool {
+	return C.canAuthenticate() == 1
+}
+
+func osAuthenticate(reason string) error {
+	r := C.CString(reason)
+	defer C.free(unsafe.Pointer(r))
+
+	if C.authenticate(r) != 1 {
+		return errors.New("authentication failed")
+	}
+
+	return nil
+}
@@ -0,0 +1,39 @@
+package applock
+
+import (
+	"os/exec"
+	"os/user"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Linux has no desktop-neutral way to ask the user, not an administrator,
+// who they are, so the lock verifies an enrolled fingerprint with fprintd
+// and otherwise leaves only the PIN.
+func osAvailable() bool {
+	u, err := user.Current()
+	if err != nil {
+		return false
+	}
+
+	out, err := exec.Command("fprintd-list", u.Username).Output()
+	if err != nil {
+		return false
+	}
+
+	return strings.Contains(string(out), " - #")
+}
+
+func osAuthenticate(reason string) error {
+	out, err := exec.Command("fprintd-verify").CombinedOutput()
+	if err != nil && len(out) == 0 {
+		return errors.Wrap(err, "failed to run fprintd-verify")
+	}
+
+	if !strings.Contains(string(out), "verify-match") {
+		return errors.New("fingerprint did not match")
+	}
+
+	return nil
+}
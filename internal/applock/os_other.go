@@ -0,0 +1,11 @@
+//go:build !windows && !linux && !(darwin && cgo)
+
+package applock
+
+func osAvailable() bool {
+	return false
+}
+
+func osAuthenticate(reason string) error {
+	return ErrUnsupported
+}
@@ -0,0 +1,62 @@
+package applock
+
+import (
+	"encoding/base64"
+	"os/exec"
+	"strings"
+	"syscall"
+	"unicode/utf16"
+
+	"github.com/pkg/errors"
+)
+
+const helloScript = `
+Add-Type -AssemblyName System.Runtime.WindowsRuntime
+$asTask = ([System.WindowsRuntimeSystemExtensions].GetMethods() | ? { $_.Name -eq 'AsTask' -and $_.GetParameters().Count -eq 1 -and $_.GetParameters()[0].ParameterType.Name -eq 'IAsyncOperation` + "`" + `1' })[0]
+[Windows.Security.Credentials.UI.UserConsentVerifier,Windows.Security.Credentials.UI,ContentType=WindowsRuntime] | Out-Null
+$op = [Windows.Security.Credentials.UI.UserConsentVerifier]::RequestVerificationAsync($reason)
+$task = $asTask.MakeGenericMethod([Windows.Security.Credentials.UI.UserConsentVerificationResult]).Invoke($null, @($op))
+$task.Wait() | Out-Null
+$task.Result
+`
+
+func osAvailable() bool {
+	_, err := exec.LookPath("powershell")
+	return err == nil
+}
+
+// quote doubles what PowerShell takes as a single quote, the curly ones
+// included, to embed text in a single-quoted string.
+var quote = strings.NewReplacer("'", "''", "\u2018", "\u2018\u2018", "\u2019", "\u2019\u2019", "\u201a", "\u201a\u201a", "\u201b", "\u201b\u201b")
+
+// encodeCommand encodes script for -EncodedCommand, which takes base64 of
+// UTF-16LE and sidesteps quoting the command line.
+func encodeCommand(script string) string {
+	u := utf16.Encode([]rune(script))
+
+	b := make([]byte, 0, len(u)*2)
+	for _, c := range u {
+		b = append(b, byte(c), byte(c>>8))
+	}
+
+	return base64.StdEncoding.EncodeToString(b)
+}
+
+func osAuthenticate(reason string) error {
+	script := "$reason = '" + quote.Replace(reason) + "'\n" + helloScript
+
+	cmd := exec.Command("powershell", "-NoProfile", "-NonInteractive", "-EncodedCommand", encodeCommand(script))
+	cmd.SysProcAttr = &syscall.SysProcAttr{HideWindow: true}
+
+	out, err := cmd.Output()
+	if err != nil {
+		return errors.Wrap(err, "failed to run Windows Hello verification")
+	}
+
+	result := strings.TrimSpace(string(out))
+	if result != "Verified" {
+		return errors.Errorf("Windows Hello verification failed: %s", result)
+	}
+
+	return nil
+}
@@ -0,0 +1,52 @@
+package autostart
+
+import (
+	"os"
+
+	"github.com/pkg/errors"
+)
+
+// Args are passed to voiui when it starts at login, the window stays
+// closed until opened from the tray.
+var Args = []string{"gui", "-no-window"}
+
+// Enabled tells whether voiui starts at login.
+func Enabled() bool {
+	p, err := path()
+	if err != nil {
+		return false
+	}
+
+	_, err = os.Stat(p)
+	return err == nil
+}
+
+// Enable creates the login entry for the running binary.
+func Enable() error {
+	exe, err := os.Executable()
+	if err != nil {
+		return errors.Wrap(err, "failed to find the voiui binary")
+	}
+
+	p, err := path()
+	if err != nil {
+		return err
+	}
+
+	return create(p, exe)
+}
+
+// Disable removes the login entry.
+func Disable() error {
+	p, err := path()
+	if err != nil {
+		return err
+	}
+
+	err = os.Remove(p)
+	if err != nil && !os.IsNotExist(err) {
+		return errors.Wrap(err, "failed to remove the login entry")
+	}
+
+	return nil
+}
@@ -0,0 +1,58 @@
+package autostart
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+)
+
+const label = "network.voi.voiui"
+
+const plist = `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+	<key>Label</key>
+	<string>%s</string>
+	<key>ProgramArguments</key>
+	<array>
+%s	</array>
+	<key>RunAtLoad</key>
+	<true/>
+</dict>
+</plist>
+`
+
+func path() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get home dir")
+	}
+
+	return filepath.Join(home, "Library", "LaunchAgents", label+".plist"), nil
+}
+
+func create(p string, exe string) error {
+	var b bytes.Buffer
+	for _, a := range append([]string{exe}, Args...) {
+		b.WriteString("\t\t<string>")
+		xml.EscapeText(&b, []byte(a))
+		b.WriteString("</string>\n")
+	}
+
+	err := os.MkdirAll(filepath.Dir(p), 0755)
+	if err != nil {
+		return errors.Wrap(err, "failed to create the LaunchAgents dir")
+	}
+
+	err = os.WriteFile(p, []byte(fmt.Sprintf(plist, label, b.String())), 0644)
+	if err != nil {
+		return errors.Wrap(err, "failed to write the launch agent")
+	}
+
+	return nil
+}
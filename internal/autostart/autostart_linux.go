@@ -0,0 +1,57 @@
+package autostart
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const desktopEntry = `[Desktop Entry]
+Type=Application
+Name=Voi Node Monitor
+Exec=%s
+Terminal=false
+X-GNOME-Autostart-enabled=true
+`
+
+func path() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get user config dir")
+	}
+
+	return filepath.Join(dir, "autostart", "voiui.desktop"), nil
+}
+
+// quote follows the quoting rules of the Exec key.
+func quote(s string) string {
+	s = strings.ReplaceAll(s, "%", "%%")
+	if !strings.ContainsAny(s, " \t\"'\\`$;&|<>()*?#~=") {
+		return s
+	}
+
+	r := strings.NewReplacer(`\`, `\\\\`, `"`, `\\"`, "`", "\\\\`", "$", `\\$`)
+	return `"` + r.Replace(s) + `"`
+}
+
+func create(p string, exe string) error {
+	words := []string{quote(exe)}
+	for _, a := range Args {
+		words = append(words, quote(a))
+	}
+
+	err := os.MkdirAll(filepath.Dir(p), 0755)
+	if err != nil {
+		return errors.Wrap(err, "failed to create the autostart dir")
+	}
+
+	err = os.WriteFile(p, []byte(fmt.Sprintf(desktopEntry, strings.Join(words, " "))), 0644)
+	if err != nil {
+		return errors.Wrap(err, "failed to write the autostart entry")
+	}
+
+	return nil
+}
@@ -0,0 +1,15 @@
+//go:build !windows && !darwin && !linux
+
+package autostart
+
+import (
+	"github.com/pkg/errors"
+)
+
+func path() (string, error) {
+	return "", errors.New("starting at login is not supported on this platform")
+}
+
+func create(p string, exe string) error {
+	return errors.New("starting at login is not supported on this platform")
+}
@@ -0,0 +1,39 @@
+package autostart
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+func path() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get user config dir")
+	}
+
+	return filepath.Join(dir, "Microsoft", "Windows", "Start Menu", "Programs", "Startup", "voiui.lnk"), nil
+}
+
+func psQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// create makes a Startup folder shortcut through the shell's COM object.
+func create(p string, exe string) error {
+	script := "$s = (New-Object -ComObject WScript.Shell).CreateShortcut(" + psQuote(p) + "); " +
+		"$s.TargetPath = " + psQuote(exe) + "; " +
+		"$s.Arguments = " + psQuote(strings.Join(Args, " ")) + "; " +
+		"$s.WorkingDirectory = " + psQuote(filepath.Dir(exe)) + "; " +
+		"$s.Save()"
+
+	out, err := exec.Command("powershell", "-NoProfile", "-Command", script).CombinedOutput()
+	if err != nil {
+		return errors.Wrapf(err, "failed to create the startup shortcut: %s", strings.TrimSpace(string(out)))
+	}
+
+	return nil
+}
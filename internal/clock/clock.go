@@ -0,0 +1,94 @@
+package clock
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"runtime"
+	"time"
+
+	"voiui/internal/alert"
+	"voiui/internal/state"
+)
+
+type Sink interface {
+	Set(kind alert.Kind, active bool, message string)
+}
+
+// Checker compares the local clock with an NTP server, a node with a
+// skewed clock proposes blocks with timestamps the network rejects.
+type Checker struct {
+	server   string
+	interval time.Duration
+	max      time.Duration
+	alerts   Sink
+	updates  chan<- state.Update
+}
+
+func New(server string, interval time.Duration, max time.Duration, alerts Sink, updates chan<- state.Update) *Checker {
+	return &Checker{
+		server:   server,
+		interval: interval,
+		max:      max,
+		alerts:   alerts,
+		updates:  updates,
+	}
+}
+
+// hint is how to turn time sync on for the host.
+func hint() string {
+	switch runtime.GOOS {
+	case "windows":
+		return "run w32tm /resync as administrator"
+	case "darwin":
+		return "turn on \"Set time and date automatically\" in System Settings"
+	default:
+		return "enable time sync with timedatectl set-ntp true or install chrony"
+	}
+}
+
+func (c *Checker) check(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	d, err := offset(ctx, c.server)
+	if err != nil {
+		slog.Warn("clock check failed", "err", err)
+		return
+	}
+
+	drift := d
+	if drift < 0 {
+		drift = -drift
+	}
+	off := drift > c.max
+
+	c.alerts.Set(alert.ClockDrift, off, fmt.Sprintf("the clock is %s off %s, %s", d.Round(time.Millisecond), c.server, hint()))
+
+	clock := state.Clock{
+		Offset:    d,
+		Server:    c.server,
+		Off:       off,
+		CheckedAt: time.Now(),
+	}
+
+	c.updates <- func(s *state.State) error {
+		s.Clock = clock
+		return nil
+	}
+}
+
+func (c *Checker) Run(ctx context.Context) {
+	t := time.NewTicker(c.interval)
+	defer t.Stop()
+
+	for {
+		c.check(ctx)
+
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package clock
+
+import (
+	"context"
+	"encoding/binary"
+	"net"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// seconds between the NTP epoch (1900) and the Unix epoch
+const ntpEpoch = 2208988800
+
+func ntpTime(b []byte) time.Time {
+	secs := binary.BigEndian.Uint32(b[0:4])
+	frac := binary.BigEndian.Uint32(b[4:8])
+	return time.Unix(int64(secs)-ntpEpoch, int64(frac)*1e9>>32)
+}
+
+// offset asks an NTP server how far the local clock is off, positive when
+// it is behind.
+func offset(ctx context.Context, server string) (time.Duration, error) {
+	if _, _, err := net.SplitHostPort(server); err != nil {
+		server = net.JoinHostPort(server, "123")
+	}
+
+	var d net.Dialer
+
+	conn, err := d.DialContext(ctx, "udp", server)
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to reach %s", server)
+	}
+	defer conn.Close()
+
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(5 * time.Second)
+	}
+	conn.SetDeadline(deadline)
+
+	req := make([]byte, 48)
+	// no leap warning, version 4, client mode
+	req[0] = 0x23
+
+	sent := time.Now()
+
+	_, err = conn.Write(req)
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to query %s", server)
+	}
+
+	resp := make([]byte, 48)
+
+	n, err := conn.Read(resp)
+	if err != nil {
+		return 0, errors.Wrapf(err, "no answer from %s", server)
+	}
+
+	received := time.Now()
+
+	if n < 48 || resp[0]&0x7 != 4 || resp[1] == 0 {
+		return 0, errors.Errorf("invalid answer from %s", server)
+	}
+
+	rx := ntpTime(resp[32:40])
+	tx := ntpTime(resp[40:48])
+
+	return (rx.Sub(sent) + tx.Sub(received)) / 2, nil
+}
@@ -0,0 +1,392 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// DirEnv replaces the config dir, e.g. for a service that runs as another
+// user than the one who set it up.
+const DirEnv = "VOIUI_CONFIG_DIR"
+
+func Dir() (string, error) {
+	dir := os.Getenv(DirEnv)
+	if dir == "" {
+		base, err := os.UserConfigDir()
+		if err != nil {
+			return "", errors.Wrap(err, "failed to get user config dir")
+		}
+
+		dir = filepath.Join(base, "voiui")
+	}
+
+	err := os.MkdirAll(dir, 0700)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to create config dir")
+	}
+
+	return dir, nil
+}
+
+const fileName = "config.json"
+
+type Alerts struct {
+	Muted bool `json:"muted,omitempty"`
+
+	// QuietFrom and QuietTo are the daily quiet hours, e.g. "23:00" and
+	// "07:00", with notifications sent as a summary afterwards.
+	QuietFrom string `json:"quiet_from,omitempty"`
+	QuietTo   string `json:"quiet_to,omitempty"`
+	// QuietCritical is how long a critical alert lasts before it gets
+	// through the quiet hours, 15 minutes when empty.
+	QuietCritical Duration `json:"quiet_critical,omitempty"`
+}
+
+// Service holds shell commands that control the node process on this
+// machine and the path of its log.
+type Service struct {
+	Start   string `json:"start,omitempty"`
+	Stop    string `json:"stop,omitempty"`
+	Restart string `json:"restart,omitempty"`
+	Log     string `json:"log,omitempty"`
+}
+
+type Profile struct {
+	Name string `json:"name"`
+
+	// Template is the hosting template the profile was created from.
+	Template string `json:"template,omitempty"`
+
+	Path  string `json:"path,omitempty"`
+	Algod string `json:"algod,omitempty"`
+	Demo  bool   `json:"demo,omitempty"`
+
+	// AccountsOnly watches accounts through public endpoints, Algod and
+	// Indexer override them.
+	AccountsOnly bool `json:"accounts_only,omitempty"`
+
+	Network string `json:"network,omitempty"`
+
+	Reference      string `json:"reference,omitempty"`
+	ReferenceToken string `json:"reference_token,omitempty"`
+
+	Indexer      string `json:"indexer,omitempty"`
+	IndexerToken string `json:"indexer_token,omitempty"`
+
+	TLSCA       string `json:"tls_ca,omitempty"`
+	TLSCert     string `json:"tls_cert,omitempty"`
+	TLSKey      string `json:"tls_key,omitempty"`
+	TLSInsecure bool   `json:"tls_insecure,omitempty"`
+
+	SSH           string `json:"ssh,omitempty"`
+	SSHKey        string `json:"ssh_key,omitempty"`
+	SSHKnownHosts string `json:"ssh_known_hosts,omitempty"`
+
+	Service Service `json:"service"`
+
+	Alerts Alerts `json:"alerts"`
+}
+
+// Duration is a time.Duration written as a string, e.g. "30s".
+type Duration time.Duration
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var s string
+
+	err := json.Unmarshal(data, &s)
+	if err != nil {
+		return err
+	}
+
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return errors.Wrapf(err, "invalid duration %q", s)
+	}
+
+	*d = Duration(v)
+
+	return nil
+}
+
+type PanelField struct {
+	Label string `json:"label"`
+	// Path selects the value, e.g. $.data.items[0].name
+	Path string `json:"path"`
+}
+
+type Panel struct {
+	Title    string            `json:"title"`
+	URL      string            `json:"url"`
+	Interval Duration          `json:"interval,omitempty"`
+	Headers  map[string]string `json:"headers,omitempty"`
+	Fields   []PanelField      `json:"fields"`
+}
+
+// CalendarEvent is an operator-entered date, e.g. planned maintenance or a
+// staking deadline, published in the calendar feed.
+type CalendarEvent struct {
+	Title    string    `json:"title"`
+	Start    time.Time `json:"start"`
+	Duration Duration  `json:"duration,omitempty"`
+	Notes    string    `json:"notes,omitempty"`
+}
+
+// Explorer holds block explorer URL templates, {round} and {address} are
+// replaced.
+type Explorer struct {
+	Block   string `json:"block,omitempty"`
+	Account string `json:"account,omitempty"`
+}
+
+// Window holds how the window is drawn.
+type Window struct {
+	// Scale multiplies the size of text and widgets, 0 means 1.
+	Scale   float32 `json:"scale,omitempty"`
+	Compact bool    `json:"compact,omitempty"`
+}
+
+// ProposalAction runs when Account, an address or watch list label, wins a
+// block proposal; an empty Account matches every tracked account.
+type ProposalAction struct {
+	Account string `json:"account,omitempty"`
+	// Sound is an audio file to play, or "tone" for the built-in tone.
+	Sound string `json:"sound,omitempty"`
+	// Webhook receives the proposal as a JSON POST.
+	Webhook string `json:"webhook,omitempty"`
+	// Script is run like an on_proposal hook, with VOIUI_ROUND,
+	// VOIUI_ADDRESS, VOIUI_LABEL and VOIUI_PAYOUT set.
+	Script string `json:"script,omitempty"`
+	// Tray animates the tray icon.
+	Tray bool `json:"tray,omitempty"`
+}
+
+// Rule is a user-defined alert, raised while Metric compares to Threshold
+// with Op for at least For. Channels are "alert" to open an incident,
+// "event" to only log it, or a URL the state changes are POSTed to; they
+// default to "event" for info rules and "alert" otherwise.
+type Rule struct {
+	Name      string   `json:"name"`
+	Metric    string   `json:"metric"`
+	Op        string   `json:"op"`
+	Threshold float64  `json:"threshold"`
+	For       Duration `json:"for,omitempty"`
+	// Severity is info, warning or critical, warning when empty.
+	Severity string   `json:"severity,omitempty"`
+	Channels []string `json:"channels,omitempty"`
+}
+
+// Sound is an audible alert, the built-in tone unless File is set.
+type Sound struct {
+	Enabled bool   `json:"enabled,omitempty"`
+	File    string `json:"file,omitempty"`
+	// Volume is from 0 to 1, 0 means full.
+	Volume float64 `json:"volume,omitempty"`
+}
+
+type Sounds struct {
+	Down          Sound `json:"down"`
+	Participation Sound `json:"participation"`
+}
+
+// Email sends notifications over SMTP. Subject and Body are text/template
+// templates over the notification's Title, Body, Resolved and Time.
+type Email struct {
+	Host string `json:"host"`
+	// Port defaults to 465 with TLS "tls" and to 587 otherwise.
+	Port int `json:"port,omitempty"`
+	// TLS is "starttls" when empty, "tls" to connect over TLS or "none".
+	TLS      string `json:"tls,omitempty"`
+	Username string `json:"username,omitempty"`
+	// Password is taken from $VOIUI_SMTP_PASSWORD when empty.
+	Password string   `json:"password,omitempty"`
+	From     string   `json:"from"`
+	To       []string `json:"to"`
+	Subject  string   `json:"subject,omitempty"`
+	Body     string   `json:"body,omitempty"`
+	// Digest collects notifications into one email this often, empty
+	// sends each right away.
+	Digest Duration `json:"digest,omitempty"`
+}
+
+// Push sends notifications to a self-hosted ntfy or Gotify server.
+type Push struct {
+	// Service is "ntfy" or "gotify".
+	Service string `json:"service"`
+	URL     string `json:"url"`
+	// Topic is the ntfy topic, Gotify posts to the app of the token.
+	Topic string `json:"topic,omitempty"`
+	// Token is the ntfy access token or the Gotify app token, taken from
+	// $VOIUI_PUSH_TOKEN when empty.
+	Token string `json:"token,omitempty"`
+	// Priorities maps the info, warning and critical severities to the
+	// service's priorities.
+	Priorities map[string]int `json:"priorities,omitempty"`
+}
+
+// MQTT publishes the node state to a broker and announces it to Home
+// Assistant.
+type MQTT struct {
+	// Broker is e.g. tcp://homeassistant.local:1883 or tls://broker:8883.
+	Broker   string `json:"broker"`
+	Username string `json:"username,omitempty"`
+	// Password is taken from $VOIUI_MQTT_PASSWORD when empty.
+	Password string `json:"password,omitempty"`
+	// Topic prefixes the state topics, "voiui" when empty.
+	Topic string `json:"topic,omitempty"`
+	// Discovery is the Home Assistant discovery prefix, "homeassistant"
+	// when empty, "-" sends no discovery messages.
+	Discovery string   `json:"discovery,omitempty"`
+	Interval  Duration `json:"interval,omitempty"`
+}
+
+// Hook is a command run on an event with VOIUI_EVENT, VOIUI_TITLE,
+// VOIUI_MESSAGE, VOIUI_RESOLVED, VOIUI_ROUND, VOIUI_ADDRESS, VOIUI_LABEL
+// and VOIUI_PAYOUT set. Args are text/template templates over the same
+// fields, e.g. {{.Round}}.
+type Hook struct {
+	Command string   `json:"command"`
+	Args    []string `json:"args,omitempty"`
+	// Timeout kills the command after this long, 30s when empty.
+	Timeout Duration `json:"timeout,omitempty"`
+}
+
+type Hooks struct {
+	ParticipationLost []Hook `json:"on_participation_lost,omitempty"`
+	Proposal          []Hook `json:"on_proposal,omitempty"`
+	NodeDown          []Hook `json:"on_node_down,omitempty"`
+}
+
+// Account is an address on the watch list.
+type Account struct {
+	Address string `json:"address"`
+	Label   string `json:"label,omitempty"`
+}
+
+type File struct {
+	LastProfile string    `json:"last_profile,omitempty"`
+	Profiles    []Profile `json:"profiles,omitempty"`
+
+	Panels []Panel `json:"panels,omitempty"`
+
+	Calendar []CalendarEvent `json:"calendar,omitempty"`
+
+	Explorer Explorer `json:"explorer"`
+
+	OnProposal []ProposalAction `json:"on_proposal,omitempty"`
+
+	Rules []Rule `json:"rules,omitempty"`
+
+	Sounds Sounds `json:"sounds"`
+
+	Email *Email `json:"email,omitempty"`
+	Push  []Push `json:"push,omitempty"`
+	MQTT  *MQTT  `json:"mqtt,omitempty"`
+
+	Hooks Hooks `json:"hooks"`
+
+	Accounts []Account `json:"accounts,omitempty"`
+	// Dismissed are discovered addresses the operator chose not to watch.
+	Dismissed []string `json:"dismissed,omitempty"`
+
+	// Language is the UI language code, empty to follow the system.
+	Language string `json:"language,omitempty"`
+
+	Window Window `json:"window"`
+}
+
+// Exists tells whether the config file was written, it is not on the
+// first run.
+func Exists(dir string) bool {
+	_, err := os.Stat(filepath.Join(dir, fileName))
+	return err == nil
+}
+
+func Load(dir string) (*File, error) {
+	data, err := os.ReadFile(filepath.Join(dir, fileName))
+	if os.IsNotExist(err) {
+		return &File{}, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read config")
+	}
+
+	var f File
+	err = json.Unmarshal(data, &f)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse config")
+	}
+
+	return &f, nil
+}
+
+func (f *File) Save(dir string) error {
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "failed to encode config")
+	}
+
+	tmp, err := os.CreateTemp(dir, fileName+".*")
+	if err != nil {
+		return errors.Wrap(err, "failed to create temporary config")
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = tmp.Write(data)
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return errors.Wrap(err, "failed to write temporary config")
+	}
+
+	err = os.Rename(tmp.Name(), filepath.Join(dir, fileName))
+	if err != nil {
+		return errors.Wrap(err, "failed to replace config")
+	}
+
+	return nil
+}
+
+func (f *File) Profile(name string) (Profile, bool) {
+	for _, p := range f.Profiles {
+		if p.Name == name {
+			return p, true
+		}
+	}
+	return Profile{}, false
+}
+
+func (f *File) SetProfile(p Profile) {
+	for i := range f.Profiles {
+		if f.Profiles[i].Name == p.Name {
+			f.Profiles[i] = p
+			return
+		}
+	}
+	f.Profiles = append(f.Profiles, p)
+}
+
+func (f *File) Names() []string {
+	names := make([]string, 0, len(f.Profiles))
+	for _, p := range f.Profiles {
+		names = append(names, p.Name)
+	}
+	return names
+}
+
+func (f *File) Watching(address string) bool {
+	for _, a := range f.Accounts {
+		if a.Address == address {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,66 @@
+package datadir
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// common are where install scripts and packages put the data directory.
+func common() []string {
+	dirs := []string{}
+
+	if home, err := os.UserHomeDir(); err == nil {
+		dirs = append(dirs,
+			filepath.Join(home, "voi", "node", "data"),
+			filepath.Join(home, "voi", "data"),
+			filepath.Join(home, "node", "data"),
+		)
+	}
+
+	return append(dirs, "/var/lib/voi", "/var/lib/algorand", "data")
+}
+
+// valid tells whether dir looks like an algod data directory.
+func valid(dir string) bool {
+	for _, name := range []string{"algod.net", "genesis.json"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// Find lists the data directories on this machine like goal would look for
+// them: $ALGORAND_DATA and $VOI_DATA first, then those of running algod
+// processes and then the usual install locations.
+func Find() []string {
+	candidates := []string{os.Getenv("ALGORAND_DATA"), os.Getenv("VOI_DATA")}
+	candidates = append(candidates, running()...)
+	candidates = append(candidates, common()...)
+
+	seen := map[string]bool{}
+	found := []string{}
+
+	for _, dir := range candidates {
+		if dir == "" || !valid(dir) {
+			continue
+		}
+
+		abs, err := filepath.Abs(dir)
+		if err != nil {
+			continue
+		}
+		if real, err := filepath.EvalSymlinks(abs); err == nil {
+			abs = real
+		}
+
+		if seen[abs] {
+			continue
+		}
+		seen[abs] = true
+
+		found = append(found, abs)
+	}
+
+	return found
+}
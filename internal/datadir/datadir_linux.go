@@ -0,0 +1,93 @@
+package datadir
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// running finds the data directories of algod processes from their -d
+// argument, their ALGORAND_DATA or the node.log they hold open.
+func running() []string {
+	pids, _ := filepath.Glob("/proc/[0-9]*")
+
+	dirs := []string{}
+
+	for _, proc := range pids {
+		comm, err := os.ReadFile(filepath.Join(proc, "comm"))
+		if err != nil || strings.TrimSpace(string(comm)) != "algod" {
+			continue
+		}
+
+		if dir := fromArgs(proc); dir != "" {
+			dirs = append(dirs, dir)
+			continue
+		}
+
+		if dir := fromEnv(proc); dir != "" {
+			dirs = append(dirs, dir)
+			continue
+		}
+
+		fds, _ := filepath.Glob(filepath.Join(proc, "fd", "*"))
+		for _, fd := range fds {
+			target, err := os.Readlink(fd)
+			if err == nil && filepath.Base(target) == "node.log" {
+				dirs = append(dirs, filepath.Dir(target))
+				break
+			}
+		}
+	}
+
+	return dirs
+}
+
+func fromArgs(proc string) string {
+	data, err := os.ReadFile(filepath.Join(proc, "cmdline"))
+	if err != nil {
+		return ""
+	}
+
+	args := strings.Split(string(bytes.TrimRight(data, "\x00")), "\x00")
+
+	for i, a := range args {
+		var dir string
+
+		switch {
+		case a == "-d" && i+1 < len(args):
+			dir = args[i+1]
+		case strings.HasPrefix(a, "-d="):
+			dir = strings.TrimPrefix(a, "-d=")
+		default:
+			continue
+		}
+
+		if !filepath.IsAbs(dir) {
+			cwd, err := os.Readlink(filepath.Join(proc, "cwd"))
+			if err != nil {
+				return ""
+			}
+			dir = filepath.Join(cwd, dir)
+		}
+
+		return dir
+	}
+
+	return ""
+}
+
+func fromEnv(proc string) string {
+	data, err := os.ReadFile(filepath.Join(proc, "environ"))
+	if err != nil {
+		return ""
+	}
+
+	for _, kv := range strings.Split(string(data), "\x00") {
+		if dir, ok := strings.CutPrefix(kv, "ALGORAND_DATA="); ok {
+			return dir
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,7 @@
+//go:build !linux
+
+package datadir
+
+func running() []string {
+	return nil
+}
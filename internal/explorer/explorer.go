@@ -0,0 +1,68 @@
+package explorer
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"voiui/internal/config"
+)
+
+// Default points at Voi Observer.
+var Default = config.Explorer{
+	Block:   "https://voi.observer/explorer/block/{round}",
+	Account: "https://voi.observer/explorer/account/{address}",
+}
+
+// Explorer opens rounds and accounts in a block explorer. Templates
+// replace {round} and {address}.
+type Explorer struct {
+	templates config.Explorer
+}
+
+func New(templates config.Explorer) *Explorer {
+	if templates.Block == "" {
+		templates.Block = Default.Block
+	}
+
+	if templates.Account == "" {
+		templates.Account = Default.Account
+	}
+
+	return &Explorer{templates: templates}
+}
+
+func (e *Explorer) BlockURL(round uint64) string {
+	return strings.ReplaceAll(e.templates.Block, "{round}", strconv.FormatUint(round, 10))
+}
+
+func (e *Explorer) AccountURL(address string) string {
+	return strings.ReplaceAll(e.templates.Account, "{address}", address)
+}
+
+func (e *Explorer) Block(round uint64) error {
+	if round == 0 {
+		return errors.New("no round to show yet")
+	}
+
+	return Open(e.BlockURL(round))
+}
+
+func (e *Explorer) Account(address string) error {
+	return Open(e.AccountURL(address))
+}
+
+// Open shows url in the default browser.
+func Open(url string) error {
+	if !strings.HasPrefix(url, "https://") && !strings.HasPrefix(url, "http://") {
+		return errors.Errorf("not a web address: %s", url)
+	}
+
+	err := open(url)
+	if err != nil {
+		return errors.Wrap(err, "failed to open the browser")
+	}
+
+	return nil
+}
@@ -0,0 +1,9 @@
+//go:build !windows && !darwin && !linux
+
+package explorer
+
+import "github.com/pkg/errors"
+
+func open(url string) error {
+	return errors.New("opening links is not supported on this platform")
+}
@@ -0,0 +1,7 @@
+package explorer
+
+import "os/exec"
+
+func open(url string) error {
+	return exec.Command("rundll32", "url.dll,FileProtocolHandler", url).Run()
+}
@@ -0,0 +1,169 @@
+package health
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"voiui/internal/state"
+)
+
+// used until enough blocks were seen to measure the network
+const defaultBlockTime = 2800 * time.Millisecond
+
+const keyWarn = 7 * 24 * time.Hour
+
+type Status int
+
+const (
+	OK Status = iota
+	Degraded
+	Down
+)
+
+func (s Status) String() string {
+	switch s {
+	case OK:
+		return "ok"
+	case Degraded:
+		return "degraded"
+	default:
+		return "down"
+	}
+}
+
+func (s Status) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.String())
+}
+
+type Check struct {
+	Name    string `json:"name"`
+	Status  Status `json:"status"`
+	Message string `json:"message,omitempty"`
+}
+
+// Health rolls the node and the watched accounts up into one status so a
+// single external probe covers everything voiui monitors.
+type Health struct {
+	Status Status  `json:"status"`
+	Checks []Check `json:"checks"`
+
+	Round          uint64  `json:"round"`
+	LastBlockAge   float64 `json:"last_block_age_s"`
+	OpenAlerts     int     `json:"open_alerts"`
+	ExpiringKeys   int     `json:"expiring_keys"`
+	OfflineWatched int     `json:"offline_watched"`
+}
+
+func (h *Health) add(name string, status Status, message string) {
+	h.Checks = append(h.Checks, Check{Name: name, Status: status, Message: message})
+	if status > h.Status {
+		h.Status = status
+	}
+}
+
+func Report(s state.State, now time.Time) Health {
+	h := Health{Round: s.Round, Checks: []Check{}}
+
+	if !s.CurrBlockAt.IsZero() {
+		h.LastBlockAge = now.Sub(s.CurrBlockAt).Seconds()
+	}
+
+	if s.Running {
+		h.add("node", OK, "")
+	} else {
+		h.add("node", Down, "not reachable")
+	}
+
+	for _, inc := range s.Alerts {
+		if inc.Resolved.IsZero() {
+			h.OpenAlerts++
+			h.add("alert", Degraded, inc.Title)
+		}
+	}
+
+	bt := s.AvgBlockDuration
+	if bt <= 0 {
+		bt = defaultBlockTime
+	}
+	warn := uint64(keyWarn / bt)
+
+	expiring := func(lastValid uint64) bool {
+		return s.Round > 0 && lastValid > s.Round && lastValid-s.Round < warn
+	}
+
+	for _, k := range s.Keys {
+		if expiring(k.LastValid) {
+			h.ExpiringKeys++
+			h.add("key", Degraded, fmt.Sprintf("key %s for %s expires at round %d", k.ID, k.Address, k.LastValid))
+		}
+	}
+
+	if s.Running && !s.AccountsOnly && len(s.Keys) > 0 && !s.AdminLocked {
+		switch s.Participation {
+		case state.ParticipationNoKey:
+			h.add("participation", Degraded, "no registered key covers the coming rounds")
+		case state.ParticipationOffline:
+			h.add("participation", Degraded, "a key is installed but its account is offline")
+		}
+	}
+
+	for _, a := range s.Watched {
+		name := a.Label
+		if name == "" {
+			name = a.Address
+		}
+
+		switch {
+		case a.Err != "":
+			h.add("account", Degraded, name+": "+a.Err)
+		case a.Round > 0 && !a.Online:
+			h.OfflineWatched++
+			h.add("account", Degraded, name+" is offline")
+		case a.Online && expiring(a.KeyLastValid):
+			h.ExpiringKeys++
+			h.add("account", Degraded, fmt.Sprintf("the key of %s expires at round %d", name, a.KeyLastValid))
+		}
+	}
+
+	return h
+}
+
+type Snapshotter interface {
+	Snapshot() state.State
+}
+
+// Handler serves the report as JSON, as one word with ?format=text or as
+// Prometheus metrics with ?format=prometheus. Anything down answers 503 so
+// probes that only look at the status code still work.
+func Handler(store Snapshotter) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h := Report(store.Snapshot(), time.Now())
+
+		code := http.StatusOK
+		if h.Status == Down {
+			code = http.StatusServiceUnavailable
+		}
+
+		switch r.URL.Query().Get("format") {
+		case "text":
+			w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+			w.WriteHeader(code)
+			fmt.Fprintln(w, h.Status)
+		case "prometheus":
+			w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+			w.WriteHeader(code)
+			fmt.Fprintf(w, "# HELP voiui_health 0 ok, 1 degraded, 2 down\n# TYPE voiui_health gauge\nvoiui_health %d\n", h.Status)
+			fmt.Fprintf(w, "# TYPE voiui_round gauge\nvoiui_round %d\n", h.Round)
+			fmt.Fprintf(w, "# TYPE voiui_last_block_age_seconds gauge\nvoiui_last_block_age_seconds %g\n", h.LastBlockAge)
+			fmt.Fprintf(w, "# TYPE voiui_open_alerts gauge\nvoiui_open_alerts %d\n", h.OpenAlerts)
+			fmt.Fprintf(w, "# TYPE voiui_expiring_keys gauge\nvoiui_expiring_keys %d\n", h.ExpiringKeys)
+			fmt.Fprintf(w, "# TYPE voiui_offline_watched gauge\nvoiui_offline_watched %d\n", h.OfflineWatched)
+		default:
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(code)
+			json.NewEncoder(w).Encode(h)
+		}
+	})
+}
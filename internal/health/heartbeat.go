@@ -0,0 +1,34 @@
+package health
+
+import (
+	"net/url"
+	"time"
+
+	"voiui/internal/state"
+)
+
+// stale is how long the round may stand still before no heartbeat goes
+// out.
+const stale = 2 * time.Minute
+
+// Heartbeat pings a dead man's switch, e.g. a healthchecks.io check, while
+// the node is up, advancing and participating, so the service alerts once
+// the pings stop because voiui, the node or the host is gone.
+type Heartbeat struct {
+	*pusher
+}
+
+func NewHeartbeat(pingURL string, interval time.Duration, store Snapshotter) (*Heartbeat, error) {
+	p, err := newPusher("heartbeat", pingURL, interval, store, participating, func(u url.URL, _ Health) string {
+		return u.String()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &Heartbeat{p}, nil
+}
+
+func participating(s state.State, h Health) bool {
+	return h.Status != Down && h.Round > 0 && h.LastBlockAge < stale.Seconds() && s.Participation == state.ParticipationActive
+}
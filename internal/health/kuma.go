@@ -0,0 +1,51 @@
+package health
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+
+	"voiui/internal/state"
+)
+
+// Kuma feeds an Uptime Kuma push monitor. Heartbeats are only sent while
+// nothing is down, so Kuma raises its own alert once the heartbeat
+// interval configured there passes without one.
+type Kuma struct {
+	*pusher
+}
+
+func NewKuma(pushURL string, interval time.Duration, store Snapshotter) (*Kuma, error) {
+	p, err := newPusher("Uptime Kuma push", pushURL, interval, store, up, kumaURL)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Kuma{p}, nil
+}
+
+func up(_ state.State, h Health) bool {
+	return h.Status != Down
+}
+
+// kumaURL reports the status as the message and the age of the last block
+// as the ping, which Kuma charts.
+func kumaURL(u url.URL, h Health) string {
+	msg := h.Status.String()
+	if h.Status == Degraded {
+		for _, c := range h.Checks {
+			if c.Status == Degraded {
+				msg += ": " + c.Message
+				break
+			}
+		}
+	}
+
+	q := u.Query()
+	q.Set("status", "up")
+	q.Set("msg", msg)
+	q.Set("ping", fmt.Sprintf("%.0f", h.LastBlockAge*1000))
+	u.RawQuery = q.Encode()
+
+	return u.String()
+}
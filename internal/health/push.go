@@ -0,0 +1,88 @@
+package health
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"voiui/internal/state"
+)
+
+// pusher requests a URL every interval while healthy holds, for monitors
+// that alert once the requests stop.
+type pusher struct {
+	name     string
+	url      *url.URL
+	interval time.Duration
+	store    Snapshotter
+	client   *http.Client
+
+	healthy func(s state.State, h Health) bool
+	target  func(u url.URL, h Health) string
+}
+
+func newPusher(name string, raw string, interval time.Duration, store Snapshotter, healthy func(state.State, Health) bool, target func(url.URL, Health) string) (*pusher, error) {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return nil, errors.Errorf("invalid %s URL: %s", name, raw)
+	}
+
+	return &pusher{
+		name:     name,
+		url:      u,
+		interval: interval,
+		store:    store,
+		client:   &http.Client{Timeout: 10 * time.Second},
+		healthy:  healthy,
+		target:   target,
+	}, nil
+}
+
+func (p *pusher) push(ctx context.Context, h Health) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.target(*p.url, h), nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to create push request")
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "failed to push")
+	}
+	defer resp.Body.Close()
+
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 4<<10))
+
+	if resp.StatusCode/100 != 2 {
+		return errors.Errorf("push rejected: %s", resp.Status)
+	}
+
+	return nil
+}
+
+func (p *pusher) Run(ctx context.Context) {
+	t := time.NewTicker(p.interval)
+	defer t.Stop()
+
+	for {
+		s := p.store.Snapshot()
+		h := Report(s, time.Now())
+
+		if p.healthy(s, h) {
+			err := p.push(ctx, h)
+			if err != nil {
+				slog.Error("failed to push", "to", p.name, "err", err)
+			}
+		}
+
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
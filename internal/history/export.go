@@ -0,0 +1,163 @@
+package history
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"voiui/internal/state"
+)
+
+type Export struct {
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+
+	Blocks    []Block        `json:"blocks"`
+	Proposals []Proposal     `json:"proposals"`
+	Outages   []state.Outage `json:"outages"`
+	Events    []state.Event  `json:"events"`
+}
+
+func (h *DB) Export(from time.Time, to time.Time) (Export, error) {
+	e := Export{From: from, To: to}
+
+	var err error
+
+	e.Blocks, err = h.Blocks(from, to)
+	if err != nil {
+		return e, err
+	}
+
+	e.Proposals, err = h.Proposals(from, to)
+	if err != nil {
+		return e, err
+	}
+
+	e.Outages, err = h.Outages(from, to)
+	if err != nil {
+		return e, err
+	}
+
+	e.Events, err = h.Events(from, to)
+	if err != nil {
+		return e, err
+	}
+
+	return e, nil
+}
+
+func (e Export) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return errors.Wrap(enc.Encode(e), "failed to encode export")
+}
+
+func stamp(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+type table struct {
+	name string
+	rows [][]string
+}
+
+func (e Export) tables() []table {
+	blocks := [][]string{{"round", "time", "duration_ms", "txns"}}
+	for _, b := range e.Blocks {
+		blocks = append(blocks, []string{
+			strconv.FormatUint(b.Round, 10),
+			stamp(b.At),
+			strconv.FormatInt(b.Duration.Milliseconds(), 10),
+			strconv.Itoa(b.Txns),
+		})
+	}
+
+	proposals := [][]string{{"round", "time", "address"}}
+	for _, p := range e.Proposals {
+		proposals = append(proposals, []string{strconv.FormatUint(p.Round, 10), stamp(p.At), p.Address})
+	}
+
+	outages := [][]string{{"kind", "start", "end", "duration_s"}}
+	for _, o := range e.Outages {
+		d := ""
+		if !o.End.IsZero() {
+			d = strconv.FormatInt(int64(o.End.Sub(o.Start).Seconds()), 10)
+		}
+		outages = append(outages, []string{o.Kind, stamp(o.Start), stamp(o.End), d})
+	}
+
+	events := [][]string{{"time", "source", "kind", "message"}}
+	for _, ev := range e.Events {
+		events = append(events, []string{stamp(ev.At), ev.Source, ev.Kind, ev.Message})
+	}
+
+	return []table{
+		{"blocks", blocks},
+		{"proposals", proposals},
+		{"outages", outages},
+		{"events", events},
+	}
+}
+
+// WriteFiles writes the export to dir as one JSON file or one CSV file per
+// table and returns the paths written.
+func (e Export) WriteFiles(dir string, format string) ([]string, error) {
+	name := fmt.Sprintf("voiui-%s-%s", e.From.Format("20060102"), e.To.Format("20060102"))
+
+	switch format {
+	case "json":
+		path := filepath.Join(dir, name+".json")
+
+		f, err := os.Create(path)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create export")
+		}
+
+		err = e.WriteJSON(f)
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		return []string{path}, nil
+	case "csv":
+		var paths []string
+
+		for _, t := range e.tables() {
+			path := filepath.Join(dir, name+"-"+t.name+".csv")
+
+			f, err := os.Create(path)
+			if err != nil {
+				return paths, errors.Wrap(err, "failed to create export")
+			}
+
+			w := csv.NewWriter(f)
+			err = w.WriteAll(t.rows)
+			if cerr := f.Close(); err == nil {
+				err = cerr
+			}
+			if err != nil {
+				return paths, errors.Wrapf(err, "failed to write %s", path)
+			}
+
+			paths = append(paths, path)
+		}
+
+		return paths, nil
+	default:
+		return nil, errors.Errorf("unknown export format: %s", format)
+	}
+}
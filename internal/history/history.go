@@ -0,0 +1,373 @@
+package history
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"log/slog"
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/errors"
+	bolt "go.etcd.io/bbolt"
+
+	"voiui/internal/state"
+)
+
+const (
+	fileName = "history.db"
+
+	// Retention bounds the database to roughly 30 MB of block records.
+	Retention = 30 * 24 * time.Hour
+)
+
+var (
+	blocksBucket    = []byte("blocks")
+	proposalsBucket = []byte("proposals")
+	outagesBucket   = []byte("outages")
+	eventsBucket    = []byte("events")
+)
+
+type Block struct {
+	Round    uint64        `json:"round"`
+	At       time.Time     `json:"at"`
+	Duration time.Duration `json:"duration"`
+	Txns     int           `json:"txns"`
+}
+
+type Proposal struct {
+	Round   uint64    `json:"round"`
+	At      time.Time `json:"at"`
+	Address string    `json:"address"`
+}
+
+// DB records what voiui observed so charts and statistics survive restarts.
+type DB struct {
+	db       *bolt.DB
+	updates  chan<- state.Update
+	earnings Earnings
+}
+
+func Open(dir string, updates chan<- state.Update) (*DB, error) {
+	db, err := bolt.Open(filepath.Join(dir, fileName), 0600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open history database, is another voiui running?")
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, name := range [][]byte{blocksBucket, proposalsBucket, outagesBucket, eventsBucket} {
+			_, err := tx.CreateBucketIfNotExists(name)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, errors.Wrap(err, "failed to initialize history database")
+	}
+
+	return &DB{db: db, updates: updates}, nil
+}
+
+func (h *DB) Close() error {
+	return h.db.Close()
+}
+
+func u64(v uint64) []byte {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, v)
+	return b
+}
+
+func timeKey(t time.Time) []byte {
+	return u64(uint64(t.UnixNano()))
+}
+
+func (h *DB) put(bucket []byte, key []byte, value []byte) {
+	err := h.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucket).Put(key, value)
+	})
+	if err != nil {
+		slog.Error("failed to record history", "err", err)
+	}
+}
+
+func (h *DB) putJSON(bucket []byte, key []byte, v interface{}) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		slog.Error("failed to encode history", "err", err)
+		return
+	}
+
+	h.put(bucket, key, data)
+}
+
+// Block records when round was first seen and how many transactions it
+// has. Block durations are derived from the previous round.
+func (h *DB) Block(round uint64, at time.Time, txns int) {
+	h.put(blocksBucket, u64(round), append(u64(uint64(at.UnixMilli())), u64(uint64(txns))...))
+}
+
+func (h *DB) Proposal(round uint64, address string, at time.Time) {
+	h.putJSON(proposalsBucket, u64(round), Proposal{Round: round, At: at, Address: address})
+}
+
+// Outage stores o keyed by its start, so reporting the end later updates it.
+func (h *DB) Outage(o state.Outage) {
+	h.putJSON(outagesBucket, append(timeKey(o.Start), o.Kind...), o)
+}
+
+func (h *DB) Event(e state.Event) {
+	h.putJSON(eventsBucket, timeKey(e.At), e)
+}
+
+// Blocks walks back from the newest round, rounds are recorded in time order.
+func (h *DB) Blocks(from time.Time, to time.Time) ([]Block, error) {
+	var blocks []Block
+
+	err := h.db.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(blocksBucket).Cursor()
+
+		for k, v := c.Last(); k != nil; k, v = c.Prev() {
+			// records from before transaction counts are only the time
+			if len(k) != 8 || (len(v) != 8 && len(v) != 16) {
+				continue
+			}
+
+			b := Block{
+				Round: binary.BigEndian.Uint64(k),
+				At:    time.UnixMilli(int64(binary.BigEndian.Uint64(v))),
+			}
+			if len(v) == 16 {
+				b.Txns = int(binary.BigEndian.Uint64(v[8:]))
+			}
+
+			if n := len(blocks); n > 0 && blocks[n-1].Round == b.Round+1 {
+				blocks[n-1].Duration = blocks[n-1].At.Sub(b.At)
+			}
+
+			if b.At.Before(from) {
+				break
+			}
+
+			blocks = append(blocks, b)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read block history")
+	}
+
+	for i, j := 0, len(blocks)-1; i < j; i, j = i+1, j-1 {
+		blocks[i], blocks[j] = blocks[j], blocks[i]
+	}
+
+	for len(blocks) > 0 && blocks[len(blocks)-1].At.After(to) {
+		blocks = blocks[:len(blocks)-1]
+	}
+
+	return blocks, nil
+}
+
+func (h *DB) Proposals(from time.Time, to time.Time) ([]Proposal, error) {
+	var proposals []Proposal
+
+	err := h.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(proposalsBucket).ForEach(func(k []byte, v []byte) error {
+			var p Proposal
+			if json.Unmarshal(v, &p) != nil || p.At.Before(from) || p.At.After(to) {
+				return nil
+			}
+
+			proposals = append(proposals, p)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read proposal history")
+	}
+
+	return proposals, nil
+}
+
+func (h *DB) Outages(from time.Time, to time.Time) ([]state.Outage, error) {
+	var outages []state.Outage
+
+	err := h.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(outagesBucket).ForEach(func(k []byte, v []byte) error {
+			var o state.Outage
+			if json.Unmarshal(v, &o) != nil || o.Start.After(to) || (!o.End.IsZero() && o.End.Before(from)) {
+				return nil
+			}
+
+			outages = append(outages, o)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read outage history")
+	}
+
+	return outages, nil
+}
+
+func (h *DB) Events(from time.Time, to time.Time) ([]state.Event, error) {
+	var events []state.Event
+
+	err := h.db.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(eventsBucket).Cursor()
+
+		for k, v := c.Seek(timeKey(from)); k != nil; k, v = c.Next() {
+			var e state.Event
+			if json.Unmarshal(v, &e) != nil {
+				continue
+			}
+			if e.At.After(to) {
+				break
+			}
+
+			events = append(events, e)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read event history")
+	}
+
+	return events, nil
+}
+
+func (h *DB) prune(now time.Time) error {
+	cutoff := now.Add(-Retention)
+
+	return h.db.Update(func(tx *bolt.Tx) error {
+		c := tx.Bucket(blocksBucket).Cursor()
+		for k, v := c.First(); k != nil; k, v = c.First() {
+			if len(v) >= 8 && time.UnixMilli(int64(binary.BigEndian.Uint64(v))).After(cutoff) {
+				break
+			}
+			if err := c.Delete(); err != nil {
+				return err
+			}
+		}
+
+		c = tx.Bucket(proposalsBucket).Cursor()
+		for k, v := c.First(); k != nil; k, v = c.First() {
+			var p Proposal
+			if json.Unmarshal(v, &p) == nil && p.At.After(cutoff) {
+				break
+			}
+			if err := c.Delete(); err != nil {
+				return err
+			}
+		}
+
+		for _, name := range [][]byte{outagesBucket, eventsBucket} {
+			c = tx.Bucket(name).Cursor()
+			for k, _ := c.First(); k != nil && len(k) >= 8; k, _ = c.First() {
+				if time.Unix(0, int64(binary.BigEndian.Uint64(k[:8]))).After(cutoff) {
+					break
+				}
+				if err := c.Delete(); err != nil {
+					return err
+				}
+			}
+		}
+
+		return nil
+	})
+}
+
+func (h *DB) summary(now time.Time) (state.History, error) {
+	var s state.History
+
+	blocks, err := h.Blocks(now.Add(-24*time.Hour), now)
+	if err != nil {
+		return s, err
+	}
+
+	var sums [24]time.Duration
+	var counts [24]int
+	var txns [24]int
+	var blocksIn [24]int
+
+	for _, b := range blocks {
+		i := 23 - int(now.Sub(b.At)/time.Hour)
+		if i < 0 || i > 23 {
+			continue
+		}
+
+		txns[i] += b.Txns
+		blocksIn[i]++
+
+		if b.Duration <= 0 {
+			continue
+		}
+
+		sums[i] += b.Duration
+		counts[i]++
+	}
+
+	s.BlockTimes = make([]time.Duration, 24)
+	s.TxnsPerBlock = make([]float64, 24)
+	for i := range sums {
+		if counts[i] > 0 {
+			s.BlockTimes[i] = sums[i] / time.Duration(counts[i])
+		}
+		if blocksIn[i] > 0 {
+			s.TxnsPerBlock[i] = float64(txns[i]) / float64(blocksIn[i])
+		}
+	}
+
+	proposals, err := h.Proposals(now.Add(-Retention), now)
+	if err != nil {
+		return s, err
+	}
+
+	s.Proposals = len(proposals)
+	if len(proposals) > 0 {
+		s.LastProposal = proposals[len(proposals)-1].At
+	}
+
+	s.Weekly, err = h.Compare(now)
+	if err != nil {
+		return s, err
+	}
+
+	return s, nil
+}
+
+// Run prunes old records and publishes a summary for the UI.
+func (h *DB) Run(ctx context.Context) {
+	t := time.NewTicker(5 * time.Minute)
+	defer t.Stop()
+
+	for {
+		now := time.Now()
+
+		err := h.prune(now)
+		if err != nil {
+			slog.Error("failed to prune history", "err", err)
+		}
+
+		s, err := h.summary(now)
+		if err != nil {
+			slog.Error("failed to summarize history", "err", err)
+		} else {
+			h.updates <- func(st *state.State) error {
+				st.History = s
+				return nil
+			}
+		}
+
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
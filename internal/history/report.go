@@ -0,0 +1,190 @@
+package history
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"voiui/internal/state"
+)
+
+const week = 7 * 24 * time.Hour
+
+// Earnings sums proposer payouts and rewards earned between from and to,
+// in microVoi.
+type Earnings func(from time.Time, to time.Time) uint64
+
+// SetEarnings lets weekly reports include rewards, which the node keeps.
+func (h *DB) SetEarnings(f Earnings) {
+	h.earnings = f
+}
+
+func (h *DB) week(from time.Time, to time.Time) (state.Week, error) {
+	w := state.Week{From: from, To: to, Uptime: 1}
+
+	blocks, err := h.Blocks(from, to)
+	if err != nil {
+		return w, err
+	}
+
+	var sum time.Duration
+	var count int
+
+	for _, b := range blocks {
+		if b.Duration > 0 {
+			sum += b.Duration
+			count++
+		}
+	}
+
+	if count > 0 {
+		w.AvgBlock = sum / time.Duration(count)
+	}
+
+	proposals, err := h.Proposals(from, to)
+	if err != nil {
+		return w, err
+	}
+
+	w.Proposals = len(proposals)
+
+	outages, err := h.Outages(from, to)
+	if err != nil {
+		return w, err
+	}
+
+	var down time.Duration
+
+	for _, o := range outages {
+		if o.Kind != state.OutageConnection {
+			continue
+		}
+
+		start, end := o.Start, o.End
+		if end.IsZero() || end.After(to) {
+			end = to
+		}
+		if start.Before(from) {
+			start = from
+		}
+		if end.After(start) {
+			down += end.Sub(start)
+		}
+	}
+
+	w.Uptime = 1 - float64(down)/float64(to.Sub(from))
+
+	if h.earnings != nil {
+		w.Rewards = h.earnings(from, to)
+		w.HasRewards = true
+	}
+
+	return w, nil
+}
+
+func percent(a float64, b float64) float64 {
+	if b == 0 {
+		return 0
+	}
+	return (a - b) / b * 100
+}
+
+// commentary points out what changed enough to look into.
+func commentary(this state.Week, last state.Week) []string {
+	var notes []string
+
+	switch d := (this.Uptime - last.Uptime) * 100; {
+	case d <= -1:
+		notes = append(notes, fmt.Sprintf("Uptime fell by %.1f points, check the outages in the events.", -d))
+	case d >= 1:
+		notes = append(notes, fmt.Sprintf("Uptime improved by %.1f points.", d))
+	}
+
+	switch {
+	case last.Proposals > 0 && this.Proposals == 0:
+		notes = append(notes, "No proposals this week after some last week, make sure the key is still online.")
+	case this.Proposals < last.Proposals:
+		notes = append(notes, "Fewer proposals than last week, week to week swings are normal as proposers are drawn at random.")
+	case this.Proposals > last.Proposals:
+		notes = append(notes, "More proposals than last week.")
+	}
+
+	if this.HasRewards && last.Rewards > 0 {
+		if d := percent(float64(this.Rewards), float64(last.Rewards)); d <= -20 {
+			notes = append(notes, fmt.Sprintf("Rewards dropped %.0f%%.", -d))
+		} else if d >= 20 {
+			notes = append(notes, fmt.Sprintf("Rewards grew %.0f%%.", d))
+		}
+	}
+
+	if this.AvgBlock > 0 && last.AvgBlock > 0 && percent(float64(this.AvgBlock), float64(last.AvgBlock)) >= 10 {
+		notes = append(notes, "Blocks were seen more slowly than last week, the node or the network may be lagging.")
+	}
+
+	if len(notes) == 0 {
+		notes = append(notes, "A steady week, nothing changed notably.")
+	}
+
+	return notes
+}
+
+// Compare puts the last seven days next to the seven before.
+func (h *DB) Compare(now time.Time) (state.Comparison, error) {
+	var c state.Comparison
+	var err error
+
+	c.This, err = h.week(now.Add(-week), now)
+	if err != nil {
+		return c, err
+	}
+
+	c.Last, err = h.week(now.Add(-2*week), now.Add(-week))
+	if err != nil {
+		return c, err
+	}
+
+	c.Notes = commentary(c.This, c.Last)
+
+	return c, nil
+}
+
+func voi(micro uint64) string {
+	return fmt.Sprintf("%.6f VOI", float64(micro)/1e6)
+}
+
+// Markdown renders c as a table fit for pasting into chats and issues.
+func Markdown(c state.Comparison) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# Week %s – %s vs. previous week\n\n", c.This.From.Format("2006-01-02"), c.This.To.Format("2006-01-02"))
+	fmt.Fprintf(&b, "| | This week | Last week | Change |\n|---|---|---|---|\n")
+	fmt.Fprintf(&b, "| Uptime | %.2f%% | %.2f%% | %+.2f pts |\n", c.This.Uptime*100, c.Last.Uptime*100, (c.This.Uptime-c.Last.Uptime)*100)
+	fmt.Fprintf(&b, "| Proposals | %d | %d | %+d |\n", c.This.Proposals, c.Last.Proposals, c.This.Proposals-c.Last.Proposals)
+	if c.This.HasRewards {
+		fmt.Fprintf(&b, "| Rewards | %s | %s | %+.1f%% |\n", voi(c.This.Rewards), voi(c.Last.Rewards), percent(float64(c.This.Rewards), float64(c.Last.Rewards)))
+	}
+	fmt.Fprintf(&b, "| Avg block time | %s | %s | %+.1f%% |\n", c.This.AvgBlock.Round(time.Millisecond), c.Last.AvgBlock.Round(time.Millisecond), percent(float64(c.This.AvgBlock), float64(c.Last.AvgBlock)))
+
+	b.WriteString("\n")
+	for _, n := range c.Notes {
+		fmt.Fprintf(&b, "- %s\n", n)
+	}
+
+	return b.String()
+}
+
+// WriteReport saves c as Markdown to dir and returns the path.
+func WriteReport(dir string, c state.Comparison) (string, error) {
+	path := filepath.Join(dir, fmt.Sprintf("voiui-week-%s.md", c.This.To.Format("20060102")))
+
+	err := os.WriteFile(path, []byte(Markdown(c)), 0644)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to write report")
+	}
+
+	return path, nil
+}
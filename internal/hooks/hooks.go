@@ -0,0 +1,183 @@
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"voiui/internal/alert"
+	"voiui/internal/config"
+)
+
+const defaultTimeout = 30 * time.Second
+
+// Event is what a hook is told, both as VOIUI_* environment variables and
+// as the data of its argument templates.
+type Event struct {
+	Event    string
+	Title    string
+	Message  string
+	Resolved bool
+	Round    uint64
+	Address  string
+	Label    string
+	Payout   uint64
+}
+
+func (e Event) env() []string {
+	resolved := "0"
+	if e.Resolved {
+		resolved = "1"
+	}
+
+	return []string{
+		"VOIUI_EVENT=" + e.Event,
+		"VOIUI_TITLE=" + e.Title,
+		"VOIUI_MESSAGE=" + e.Message,
+		"VOIUI_RESOLVED=" + resolved,
+		fmt.Sprintf("VOIUI_ROUND=%d", e.Round),
+		"VOIUI_ADDRESS=" + e.Address,
+		"VOIUI_LABEL=" + e.Label,
+		fmt.Sprintf("VOIUI_PAYOUT=%d", e.Payout),
+	}
+}
+
+type hook struct {
+	command string
+	args    []*template.Template
+	timeout time.Duration
+}
+
+// Runner runs the configured commands when the node goes down, loses
+// participation or proposes a block. Their output goes to the log, so it
+// shows in the diagnostics pane.
+type Runner struct {
+	hooks map[string][]hook
+	// Label names an address, e.g. from the watch list.
+	Label func(address string) string
+}
+
+func New(cfg config.Hooks) (*Runner, error) {
+	r := &Runner{hooks: map[string][]hook{}}
+
+	for event, hs := range map[string][]config.Hook{
+		"node_down":          cfg.NodeDown,
+		"participation_lost": cfg.ParticipationLost,
+		"proposal":           cfg.Proposal,
+	} {
+		for _, h := range hs {
+			if h.Command == "" {
+				return nil, errors.Errorf("on_%s hook has no command", event)
+			}
+
+			parsed := hook{command: h.Command, timeout: time.Duration(h.Timeout)}
+
+			for i, a := range h.Args {
+				t, err := template.New(fmt.Sprintf("%s arg %d", h.Command, i)).Parse(a)
+				if err != nil {
+					return nil, errors.Wrapf(err, "invalid on_%s hook argument", event)
+				}
+				parsed.args = append(parsed.args, t)
+			}
+
+			r.hooks[event] = append(r.hooks[event], parsed)
+		}
+	}
+
+	return r, nil
+}
+
+func (r *Runner) Enabled() bool {
+	return len(r.hooks) > 0
+}
+
+func (r *Runner) fire(e Event) {
+	for _, h := range r.hooks[e.Event] {
+		go h.run(e)
+	}
+}
+
+func (h hook) run(e Event) {
+	args := make([]string, len(h.args))
+	for i, t := range h.args {
+		var b bytes.Buffer
+
+		err := t.Execute(&b, e)
+		if err != nil {
+			slog.Error("hook argument failed", "event", e.Event, "command", h.command, "err", err)
+			return
+		}
+		args[i] = b.String()
+	}
+
+	Exec(h.command, args, h.timeout, e)
+}
+
+// Exec runs command with e in the VOIUI_* environment variables and logs
+// its output, killing it after timeout or 30s when that is zero.
+func Exec(command string, args []string, timeout time.Duration, e Event) {
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, command, args...)
+	cmd.Env = append(os.Environ(), e.env()...)
+
+	start := time.Now()
+	out, err := cmd.CombinedOutput()
+
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		if line != "" {
+			slog.Info("hook: "+line, "event", e.Event, "command", command)
+		}
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		err = errors.Errorf("timed out after %s", timeout)
+	}
+	if err != nil {
+		slog.Error("hook failed", "event", e.Event, "command", command, "err", err)
+		return
+	}
+
+	slog.Info("hook done", "event", e.Event, "command", command, "took", time.Since(start).Round(time.Millisecond))
+}
+
+// Notify fires the node_down and participation_lost hooks, also when the
+// incident resolves with Resolved set.
+func (r *Runner) Notify(n alert.Notification) error {
+	e := Event{Title: n.Title, Message: n.Body, Resolved: n.Resolved}
+
+	switch n.Kind {
+	case alert.Down:
+		e.Event = "node_down"
+	case alert.NotParticipating:
+		e.Event = "participation_lost"
+	default:
+		return nil
+	}
+
+	r.fire(e)
+	return nil
+}
+
+func (r *Runner) Proposed(round uint64, address string, payout uint64) {
+	e := Event{Event: "proposal", Round: round, Address: address, Payout: payout}
+	if r.Label != nil {
+		e.Label = r.Label(address)
+	}
+	e.Title = fmt.Sprintf("Proposed block %d", round)
+
+	r.fire(e)
+}
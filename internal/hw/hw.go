@@ -0,0 +1,89 @@
+package hw
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"voiui/internal/alert"
+	"voiui/internal/state"
+)
+
+type Sink interface {
+	Set(kind alert.Kind, active bool, message string)
+}
+
+type Monitor struct {
+	interval time.Duration
+	tempWarn float64
+	alerts   Sink
+	updates  chan<- state.Update
+}
+
+func NewMonitor(interval time.Duration, tempWarn float64, alerts Sink, updates chan<- state.Update) *Monitor {
+	return &Monitor{
+		interval: interval,
+		tempWarn: tempWarn,
+		alerts:   alerts,
+		updates:  updates,
+	}
+}
+
+func (m *Monitor) check(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, m.interval)
+	defer cancel()
+
+	h := state.Hardware{
+		UpdatedAt: time.Now(),
+	}
+
+	temps, err := temperatures(ctx)
+	if err != nil {
+		slog.Error("failed to read temperatures", "err", err)
+	}
+	h.Temps = temps
+
+	disks, err := smart(ctx)
+	if err != nil {
+		slog.Error("failed to read SMART status", "err", err)
+	}
+	h.Disks = disks
+
+	var hot []string
+	for _, t := range temps {
+		if t.Celsius >= m.tempWarn || (t.Critical > 0 && t.Celsius >= t.Critical) {
+			hot = append(hot, fmt.Sprintf("%s %.0f°C", t.Name, t.Celsius))
+		}
+	}
+
+	var failing []string
+	for _, d := range disks {
+		if !d.Healthy {
+			failing = append(failing, d.Device+" "+d.Status)
+		}
+	}
+
+	m.alerts.Set(alert.Overheating, len(hot) > 0, fmt.Sprintf("overheating: %v", hot))
+	m.alerts.Set(alert.DiskFailing, len(failing) > 0, fmt.Sprintf("disk failing: %v", failing))
+
+	m.updates <- func(s *state.State) error {
+		s.Hardware = h
+		return nil
+	}
+}
+
+func (m *Monitor) Run(ctx context.Context) {
+	t := time.NewTicker(m.interval)
+	defer t.Stop()
+
+	for {
+		m.check(ctx)
+
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package hw
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"voiui/internal/state"
+)
+
+func readMilli(path string) (float64, bool) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, false
+	}
+
+	v, err := strconv.ParseFloat(strings.TrimSpace(string(data)), 64)
+	if err != nil {
+		return 0, false
+	}
+
+	return v / 1000, true
+}
+
+// temperatures reads the hwmon sensors lm-sensors reports.
+func temperatures(ctx context.Context) ([]state.Temp, error) {
+	inputs, err := filepath.Glob("/sys/class/hwmon/hwmon*/temp*_input")
+	if err != nil {
+		return nil, err
+	}
+
+	var temps []state.Temp
+
+	for _, input := range inputs {
+		c, ok := readMilli(input)
+		if !ok {
+			continue
+		}
+
+		dir := filepath.Dir(input)
+		prefix := strings.TrimSuffix(input, "_input")
+
+		name := filepath.Base(prefix)
+		if chip, err := os.ReadFile(filepath.Join(dir, "name")); err == nil {
+			name = strings.TrimSpace(string(chip)) + " " + name
+		}
+		if label, err := os.ReadFile(prefix + "_label"); err == nil {
+			name = strings.TrimSpace(string(label))
+		}
+
+		t := state.Temp{
+			Name:    name,
+			Celsius: c,
+		}
+
+		if crit, ok := readMilli(prefix + "_crit"); ok {
+			t.Critical = crit
+		}
+
+		temps = append(temps, t)
+	}
+
+	return temps, nil
+}
+
+func smart(ctx context.Context) ([]state.Disk, error) {
+	return smartctl(ctx)
+}
@@ -0,0 +1,17 @@
+//go:build !linux && !windows
+
+package hw
+
+import (
+	"context"
+
+	"voiui/internal/state"
+)
+
+func temperatures(ctx context.Context) ([]state.Temp, error) {
+	return nil, nil
+}
+
+func smart(ctx context.Context) ([]state.Disk, error) {
+	return smartctl(ctx)
+}
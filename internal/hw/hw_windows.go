@@ -0,0 +1,92 @@
+package hw
+
+import (
+	"context"
+	"encoding/json"
+	"os/exec"
+	"syscall"
+
+	"github.com/pkg/errors"
+
+	"voiui/internal/state"
+)
+
+func powershell(ctx context.Context, script string, v interface{}) error {
+	cmd := exec.CommandContext(ctx, "powershell", "-NoProfile", "-NonInteractive", "-Command", script)
+	cmd.SysProcAttr = &syscall.SysProcAttr{HideWindow: true}
+
+	out, err := cmd.Output()
+	if err != nil {
+		return errors.Wrap(err, "failed to run powershell")
+	}
+
+	if len(out) == 0 {
+		return nil
+	}
+
+	return errors.Wrap(json.Unmarshal(out, v), "failed to decode powershell output")
+}
+
+const thermalScript = `ConvertTo-Json -Compress -InputObject @(Get-CimInstance -Namespace root/wmi -ClassName MSAcpi_ThermalZoneTemperature | Select-Object InstanceName, CurrentTemperature, CriticalTripPoint)`
+
+const failureScript = `ConvertTo-Json -Compress -InputObject @(Get-CimInstance -Namespace root/wmi -ClassName MSStorageDriver_FailurePredictStatus | Select-Object InstanceName, PredictFailure)`
+
+// temperatures reads ACPI thermal zones through WMI, values are in tenths of kelvin.
+func temperatures(ctx context.Context) ([]state.Temp, error) {
+	var zones []struct {
+		InstanceName       string
+		CurrentTemperature float64
+		CriticalTripPoint  float64
+	}
+
+	err := powershell(ctx, thermalScript, &zones)
+	if err != nil {
+		return nil, err
+	}
+
+	var temps []state.Temp
+
+	for _, z := range zones {
+		t := state.Temp{
+			Name:    z.InstanceName,
+			Celsius: z.CurrentTemperature/10 - 273.15,
+		}
+		if z.CriticalTripPoint > 0 {
+			t.Critical = z.CriticalTripPoint/10 - 273.15
+		}
+		temps = append(temps, t)
+	}
+
+	return temps, nil
+}
+
+func smart(ctx context.Context) ([]state.Disk, error) {
+	disks, err := smartctl(ctx)
+	if err != nil || len(disks) > 0 {
+		return disks, err
+	}
+
+	var items []struct {
+		InstanceName   string
+		PredictFailure bool
+	}
+
+	err = powershell(ctx, failureScript, &items)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, item := range items {
+		d := state.Disk{
+			Device:  item.InstanceName,
+			Healthy: !item.PredictFailure,
+			Status:  "OK",
+		}
+		if item.PredictFailure {
+			d.Status = "FAILURE PREDICTED"
+		}
+		disks = append(disks, d)
+	}
+
+	return disks, nil
+}
@@ -0,0 +1,75 @@
+package hw
+
+import (
+	"context"
+	"encoding/json"
+	"os/exec"
+
+	"github.com/pkg/errors"
+
+	"voiui/internal/state"
+)
+
+type smartScan struct {
+	Devices []struct {
+		Name string `json:"name"`
+		Type string `json:"type"`
+	} `json:"devices"`
+}
+
+type smartHealth struct {
+	SmartStatus *struct {
+		Passed bool `json:"passed"`
+	} `json:"smart_status"`
+	Temperature struct {
+		Current float64 `json:"current"`
+	} `json:"temperature"`
+}
+
+// smartctl reads disk health with smartmontools, it usually needs root.
+func smartctl(ctx context.Context) ([]state.Disk, error) {
+	path, err := exec.LookPath("smartctl")
+	if err != nil {
+		return nil, nil
+	}
+
+	out, err := exec.CommandContext(ctx, path, "--scan", "-j").Output()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list disks")
+	}
+
+	var scan smartScan
+
+	err = json.Unmarshal(out, &scan)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode disk list")
+	}
+
+	var disks []state.Disk
+
+	for _, dev := range scan.Devices {
+		// smartctl uses exit status bits for disk problems, the JSON is still valid
+		out, _ := exec.CommandContext(ctx, path, "-H", "-A", "-j", "-d", dev.Type, dev.Name).Output()
+
+		var h smartHealth
+
+		err = json.Unmarshal(out, &h)
+		if err != nil || h.SmartStatus == nil {
+			continue
+		}
+
+		d := state.Disk{
+			Device:  dev.Name,
+			Healthy: h.SmartStatus.Passed,
+			Status:  "PASSED",
+			Celsius: h.Temperature.Current,
+		}
+		if !d.Healthy {
+			d.Status = "FAILING"
+		}
+
+		disks = append(disks, d)
+	}
+
+	return disks, nil
+}
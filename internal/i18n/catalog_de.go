@@ -0,0 +1,295 @@
+package i18n
+
+// de is the German catalog.
+var de = map[string]string{
+	" (%.0f days)":                    " (%.0f Tage)",
+	" (attempt %d)":                   " (Versuch %d)",
+	" (in %d rounds)":                 " (in %d Runden)",
+	" (yours)":                        " (Ihre Stimme)",
+	" – in use":                       " – in Verwendung",
+	"%+.2f pts":                       "%+.2f Pkt.",
+	"%.1f%% participating":            "%.1f%% nehmen teil",
+	"%.1f%% uptime over %s monitored": "%.1f%% Verfügbarkeit in %s Überwachung",
+	"%.2f TPS, %.1f txns/block, %d in the last block": "%.2f TPS, %.1f Tx/Block, %d im letzten Block",
+	"%.2f – %d of %.1f expected proposals":            "%.2f – %d von %.1f erwarteten Vorschlägen",
+	"%.2f%% (was %.2f%%)":                             "%.2f%% (vorher %.2f%%)",
+	"%d (was %d)":                                     "%d (vorher %d)",
+	"%d is closed":                                    "%d ist geschlossen",
+	"%d is open":                                      "%d ist offen",
+	"%d pending":                                      "%d ausstehend",
+	"%d proposals recorded in 30 days, last %s":       "%d Vorschläge in 30 Tagen, zuletzt %s",
+	"%d relays: %s latency, %s jitter, %.0f%% loss":   "%d Relays: %s Latenz, %s Jitter, %.0f%% Verlust",
+	"%d restarts":                                     "%d Neustarts",
+	"%s %s from %s: %s":                               "%s %s von %s: %s",
+	"%s (was %s)":                                     "%s (vorher %s)",
+	"%s [%s] %s, %s":                                  "%s [%s] %s, %s",
+	"%s at %s":                                        "%s um %s",
+	"%s for %s":                                       "%s für %s",
+	"%s is in the bootstrap records":                  "%s ist in den Bootstrap-Einträgen",
+	"%s is missing from the bootstrap records":        "%s fehlt in den Bootstrap-Einträgen",
+	"%s off %s":                                       "%s Abweichung zu %s",
+	"%s since %s (%s)":                                "%s seit %s (%s)",
+	"%s – %s, %d rounds: ~%.2f":                       "%s – %s, %d Runden: ~%.2f",
+	"(default %s)":                                    "(Standard %s)",
+	", +%s/day":                                       ", +%s/Tag",
+	", disk full in ~%.0f days":                       ", Festplatte voll in ~%.0f Tagen",
+	", key valid until round %d":                      ", Schlüssel gültig bis Runde %d",
+	", offline":                                       ", offline",
+	", online":                                        ", online",
+	"1. Connect to your node":                         "1. Mit dem Node verbinden",
+	"2. Notifications":                                "2. Benachrichtigungen",
+	"24 hours":                                        "24 Stunden",
+	"3. Finish":                                       "3. Abschließen",
+	"30 days":                                         "30 Tage",
+	"30 days:":                                        "30 Tage:",
+	"7 days":                                          "7 Tage",
+	"7 days:":                                         "7 Tage:",
+	"API token":                                       "API-Token",
+	"Acknowledge":                                     "Bestätigen",
+	"Address":                                         "Adresse",
+	"Address copied":                                  "Adresse kopiert",
+	"Address:":                                        "Adresse:",
+	"Admin locked, enter passphrase to unlock:":                          "Admin gesperrt, Passphrase zum Entsperren eingeben:",
+	"Admin token locked":                                                 "Admin-Token gesperrt",
+	"Admin unlocked, locks in %s":                                        "Admin entsperrt, sperrt in %s",
+	"Alerts (%d unacknowledged)":                                         "Alarme (%d unbestätigt)",
+	"Alerts always show in the window and the tray, pick more channels:": "Alarme erscheinen immer im Fenster und im Tray, weitere Kanäle wählen:",
+	"Alerts muted for this profile":                                      "Warnungen für dieses Profil stummgeschaltet",
+	"App lock disabled":                                                  "App-Sperre deaktiviert",
+	"App lock enabled":                                                   "App-Sperre aktiviert",
+	"App lock is off, set a PIN to enable it:":                           "App-Sperre ist aus, zum Aktivieren eine PIN festlegen:",
+	"App lock is on, set a new PIN or disable it:":                       "App-Sperre ist an, neue PIN festlegen oder deaktivieren:",
+	"Availability (30 days):":                                            "Verfügbarkeit (30 Tage):",
+	"Average block time %s":                                              "Durchschnittliche Blockzeit %s",
+	"Avg block time":                                                     "Mittlere Blockzeit",
+	"Back":                                                               "Zurück",
+	"Back up keys":                                                       "Schlüssel sichern",
+	"Backup file to restore":                                             "Sicherungsdatei zum Wiederherstellen",
+	"Backup passphrase":                                                  "Passphrase der Sicherung",
+	"Block time %s average over 24 hours":                                "Blockzeit %s im Mittel über 24 Stunden",
+	"Block times over 24 hours":                                          "Blockzeiten über 24 Stunden",
+	"CPU %.0f%%, memory %s of %s":                                        "CPU %.0f%%, Speicher %s von %s",
+	"CPU and memory unavailable: %s":                                     "CPU und Speicher nicht verfügbar: %s",
+	"Cancel":                                                             "Abbrechen",
+	"Cannot reach algod":                                                 "algod nicht erreichbar",
+	"Clock drift:":                                                       "Uhrabweichung:",
+	"Close":                                                              "Schließen",
+	"Compact layout":                                                     "Kompakte Ansicht",
+	"Connection refused, algod is not listening at the address": "Verbindung abgelehnt, algod lauscht nicht unter der Adresse",
+	"Connectivity (purple: missed proposals):":                  "Erreichbarkeit (lila: verpasste Vorschläge):",
+	"Copy address":                   "Adresse kopieren",
+	"Copy log":                       "Log kopieren",
+	"Data %s, disk %s free (%.0f%%)": "Daten %s, Festplatte %s frei (%.0f%%)",
+	"Data directories found:":        "Gefundene Datenverzeichnisse:",
+	"Data directory":                 "Datenverzeichnis",
+	"Details":                        "Details",
+	"Disable":                        "Deaktivieren",
+	"Email, Gotify, MQTT and more can be set up in the config file later.": "E-Mail, Gotify, MQTT und mehr lassen sich später in der Konfigurationsdatei einrichten.",
+	"Estimated APR: %.2f%% on %s online":                                   "Geschätzter Jahreszins: %.2f%% auf %s online",
+	"Events:":                                                              "Ereignisse:",
+	"Expand":                                                               "Erweitern",
+	"Expanded layout":                                                      "Erweiterte Ansicht",
+	"Expected %s, this node is on a different network":                     "%s erwartet, dieser Node ist in einem anderen Netzwerk",
+	"Expected at current stake: %.2f per day":                              "Erwartet beim aktuellen Stake: %.2f pro Tag",
+	"Export history as CSV":                                                "Verlauf als CSV exportieren",
+	"Export history as JSON":                                               "Verlauf als JSON exportieren",
+	"Export history:":                                                      "Verlauf exportieren:",
+	"Export offline":                                                       "Offline exportieren",
+	"Export online":                                                        "Online exportieren",
+	"Export weekly report":                                                 "Wochenbericht exportieren",
+	"Find kmd wallets":                                                     "kmd-Wallets suchen",
+	"Follower:":                                                            "Follower:",
+	"Forget":                                                               "Vergessen",
+	"Found %d account(s) with participation keys on this node. Add them to the watch list?": "%d Konto/Konten mit Teilnahmeschlüsseln auf diesem Node gefunden. Zur Beobachtungsliste hinzufügen?",
+	"Found %d kmd wallet(s)":      "%d kmd-Wallet(s) gefunden",
+	"Gave up reconnecting":        "Wiederverbinden aufgegeben",
+	"Generate diagnostic report":  "Diagnosebericht erstellen",
+	"Go to account %s %s":         "Zu Konto %s %s",
+	"Gossip port, checked at %s:": "Gossip-Port, geprüft um %s:",
+	"Hardware:":                   "Hardware:",
+	"Hide alerts":                 "Alarme ausblenden",
+	"Hide diagnostics":            "Diagnose ausblenden",
+	"Hide node config":            "Node-Konfiguration ausblenden",
+	"History:":                    "Verlauf:",
+	"Host:":                       "Host:",
+	"Hosting template, presets the service commands and the log:": "Hosting-Vorlage, setzt die Dienstbefehle und das Log:",
+	"Hottest sensor: %s %.0f°C":                                   "Heißester Sensor: %s %.0f°C",
+	"Indexer:":                                                    "Indexer:",
+	"Key active, account offline":                                 "Schlüssel aktiv, Konto offline",
+	"Key backup:":                                                 "Schlüsselsicherung:",
+	"LOW BATTERY %.0f%%, %s left":                                 "AKKU SCHWACH %.0f%%, noch %s",
+	"Label":                                                       "Bezeichnung",
+	"Language:":                                                   "Sprache:",
+	"Last 7 days: %.2f (%s)":                                      "Letzte 7 Tage: %.2f (%s)",
+	"Last round:":                                                 "Letzte Runde:",
+	"Ledger %s":                                                   "Ledger %s",
+	"Lock":                                                        "Sperren",
+	"Lock admin token with passphrase:":                           "Admin-Token mit Passphrase sperren:",
+	"Lock now":                                                    "Jetzt sperren",
+	"Lock window":                                                 "Fenster sperren",
+	"Locked":                                                      "Gesperrt",
+	"Log copied":                                                  "Log kopiert",
+	"Log file: %s":                                                "Logdatei: %s",
+	"Missed proposals (estimated):":                               "Verpasste Vorschläge (geschätzt):",
+	"Mode:":                                                       "Modus:",
+	"Mute":                                                        "Stumm",
+	"Network activity:":                                           "Netzwerkaktivität:",
+	"Network:":                                                    "Netzwerk:",
+	"New PIN":                                                     "Neue PIN",
+	"Next":                                                        "Weiter",
+	"Next block expected, %.0f%% of the time left": "Nächster Block erwartet, %.0f%% der Zeit übrig",
+	"Next block in about %s":                       "Nächster Block in etwa %s",
+	"Next block overdue by %s":                     "Nächster Block ist %s überfällig",
+	"Node on this machine (data directory)":        "Node auf diesem Rechner (Datenverzeichnis)",
+	"Node unreachable":                             "Node nicht erreichbar",
+	"Node: %s":                                     "Node: %s",
+	"None":                                         "Keine",
+	"Not Running":                                  "Läuft nicht",
+	"Not now":                                      "Nicht jetzt",
+	"Not participating":                            "Nimmt nicht teil",
+	"Notifications muted":                          "Benachrichtigungen stummgeschaltet",
+	"Notifications silenced until %s":              "Benachrichtigungen stumm bis %s",
+	"Notifications snoozed until %s":               "Benachrichtigungen pausiert bis %s",
+	"OS keychain:":                                 "Schlüsselbund:",
+	"Open log":                                     "Log öffnen",
+	"Open node log":                                "Node-Log öffnen",
+	"Participating":                                "Nimmt teil",
+	"Participation keys:":                          "Teilnahmeschlüssel:",
+	"Participation unknown (admin locked)":         "Teilnahme unbekannt (Admin gesperrt)",
+	"Passphrase":                                   "Passphrase",
+	"Peers: %d in, %d out":                         "Peers: %d eingehend, %d ausgehend",
+	"Performance (30 days):":                       "Leistung (30 Tage):",
+	"Play sounds when the node goes down, stops participating or proposes": "Töne abspielen, wenn der Node ausfällt, nicht mehr teilnimmt oder vorschlägt",
+	"Plugin %s: %s": "Plugin %s: %s",
+	"Polling with the admin token, configure a non-admin token (algod.token / -api-token) to limit its exposure": "Abfrage mit dem Admin-Token, ein Nicht-Admin-Token (algod.token / -api-token) begrenzt dessen Preisgabe",
+	"Profile:":                            "Profil:",
+	"Proposals":                           "Vorschläge",
+	"Protocol %s":                         "Protokoll %s",
+	"QR code of the address":              "QR-Code der Adresse",
+	"QR code of the unsigned transaction": "QR-Code der unsignierten Transaktion",
+	"Ready to sign":                       "Bereit zum Signieren",
+	"Refresh now":                         "Jetzt aktualisieren",
+	"Register key:":                       "Schlüssel registrieren:",
+	"Relay latency, purple where proposals were missed": "Relay-Latenz, lila bei verpassten Vorschlägen",
+	"Relay:":                 "Relay:",
+	"Reload admin token":     "Admin-Token neu laden",
+	"Reload token":           "Token neu laden",
+	"Remote algod endpoint":  "Entfernter algod-Endpunkt",
+	"Remove":                 "Entfernen",
+	"Resolved: %s (%s – %s)": "Behoben: %s (%s – %s)",
+	"Restart node":           "Node neu starten",
+	"Restore":                "Wiederherstellen",
+	"Retry now":              "Jetzt erneut versuchen",
+	"Retrying in %s…":        "Neuer Versuch in %s…",
+	"Rewards":                "Belohnungen",
+	"Rewards:":               "Belohnungen:",
+	"Rotate admin token":     "Admin-Token erneuern",
+	"Rotate token":           "Token erneuern",
+	"Running":                "Läuft",
+	"Save":                   "Speichern",
+	"Save and start":         "Speichern und starten",
+	"Save telemetry":         "Telemetrie speichern",
+	"Save tokens":            "Tokens speichern",
+	"Scale:":                 "Skalierung:",
+	"Send telemetry":         "Telemetrie senden",
+	"Send test email":        "Test-E-Mail senden",
+	"Set PIN":                "PIN festlegen",
+	"Set a setting, an empty value restores the default:": "Einstellung setzen, ein leerer Wert stellt den Standard wieder her:",
+	"Setting":                     "Einstellung",
+	"Show diagnostics":            "Diagnose anzeigen",
+	"Show node config":            "Node-Konfiguration anzeigen",
+	"Sign elsewhere & go offline": "Anderswo signieren & offline gehen",
+	"Sign elsewhere & go online":  "Anderswo signieren & online gehen",
+	"Sign the transaction with goal clerk sign or an offline signer, then paste the signed transaction": "Transaktion mit goal clerk sign oder einem Offline-Signierer signieren, dann die signierte Transaktion einfügen",
+	"Sign to go offline":          "Signieren, um offline zu gehen",
+	"Sign to go online":           "Signieren, um online zu gehen",
+	"Sign with kmd & go offline":  "Mit kmd signieren & offline gehen",
+	"Sign with kmd & go online":   "Mit kmd signieren & online gehen",
+	"Signed transaction (base64)": "Signierte Transaktion (base64)",
+	"Signed transactions:":        "Signierte Transaktionen:",
+	"Significantly below stake (%.2f%% chance of bad luck), check the participation key and clock": "Deutlich unter dem Stake (%.2f%% Wahrscheinlichkeit für Pech), Teilnahmeschlüssel und Uhr prüfen",
+	"Snooze 1h":                            "1 h pausieren",
+	"Start node":                           "Node starten",
+	"Start voiui at login":                 "voiui bei der Anmeldung starten",
+	"State delta available":                "Zustandsdelta verfügbar",
+	"State delta missing":                  "Zustandsdelta fehlt",
+	"Stop node":                            "Node stoppen",
+	"Submit":                               "Senden",
+	"Switch to profile %s":                 "Zu Profil %s wechseln",
+	"Sync round %d, advanced at %s":        "Sync-Runde %d, vorgerückt um %s",
+	"System":                               "System",
+	"Telemetry GUID: %s":                   "Telemetrie-GUID: %s",
+	"Telemetry node name":                  "Node-Name für Telemetrie",
+	"Test connection":                      "Verbindung testen",
+	"Testing…":                             "Teste…",
+	"The algod certificate is not trusted": "Dem algod-Zertifikat wird nicht vertraut",
+	"The algod host name does not resolve": "Der algod-Hostname lässt sich nicht auflösen",
+	"The wallet does not hold the selected key's account":        "Die Wallet enthält das Konto des gewählten Schlüssels nicht",
+	"This node does not support the next protocol, update algod": "Dieser Node unterstützt das nächste Protokoll nicht, algod aktualisieren",
+	"This week vs last week:":                                    "Diese Woche gegenüber letzter Woche:",
+	"Timed out, the node or the network is too slow":             "Zeitüberschreitung, Node oder Netzwerk zu langsam",
+	"Today:": "Heute:",
+	"Token rejected (401), it may have been regenerated": "Token abgelehnt (401), es wurde möglicherweise neu erzeugt",
+	"Token rejected, check the algod token":              "Token abgelehnt, algod-Token prüfen",
+	"Traffic: %s/s out, %s/s in":                         "Verkehr: %s/s aus, %s/s ein",
+	"Transaction pool:":                                  "Transaktionspool:",
+	"Transactions per block over 24 hours":               "Transaktionen pro Block über 24 Stunden",
+	"Type a command…":                                    "Befehl eingeben…",
+	"Unlock":                                             "Entsperren",
+	"Unlock Voi Node Monitor":                            "Voi Node Monitor entsperren",
+	"Unlocked %s":                                        "%s entsperrt",
+	"Unmute":                                             "Laut",
+	"Unsigned transaction %s, msgpack in base64":         "Unsignierte Transaktion %s, msgpack in base64",
+	"Update available: algod %s":                         "Update verfügbar: algod %s",
+	"Upgrade to %s at round %d":                          "Upgrade auf %s in Runde %d",
+	"Upgrade vote: %d yes / %d no of %d rounds, %d needed, ends at round %d, this node votes %s": "Upgrade-Abstimmung: %d ja / %d nein von %d Runden, %d nötig, endet in Runde %d, dieser Node stimmt %s",
+	"Uptime":                      "Verfügbarkeit",
+	"Use system authentication":   "Systemanmeldung verwenden",
+	"Value":                       "Wert",
+	"Version:":                    "Version:",
+	"View last round on explorer": "Letzte Runde im Explorer ansehen",
+	"View on explorer":            "Im Explorer ansehen",
+	"View proposal":               "Vorschlag ansehen",
+	"Waiting %s for the next block, more than twice the average": "Seit %s kein neuer Block, mehr als doppelt so lange wie im Schnitt",
+	"Waiting for the next round":                                 "Warte auf die nächste Runde",
+	"Wallet password":                                            "Wallet-Passwort",
+	"Watch":                                                      "Beobachten",
+	"Watch list:":                                                "Beobachtungsliste:",
+	"Watching accounts through a public endpoint":                "Konten über einen öffentlichen Endpunkt beobachten",
+	"Weekly report":                                              "Wochenbericht",
+	"Wrong PIN":                                                  "Falsche PIN",
+	"acknowledged":                                               "bestätigt",
+	"active":                                                     "aktiv",
+	"algod RTT:":                                                 "algod-RTT:",
+	"algod URL, e.g. http://192.168.1.10:8080": "algod-URL, z. B. http://192.168.1.10:8080",
+	"algod usage unavailable: %s":              "algod-Auslastung nicht verfügbar: %s",
+	"algod: CPU %.0f%%, memory %s":             "algod: CPU %.0f%%, Speicher %s",
+	"and %d more":                              "und %d weitere",
+	"critical":                                 "kritisch",
+	"declining":                                "fallend",
+	"improving":                                "steigend",
+	"in sync at round %d":                      "synchron bei Runde %d",
+	"indexer behind node by %d rounds":         "Indexer liegt %d Runden hinter dem Node",
+	"info":                                     "Info",
+	"kmd has no wallets, create one with goal wallet new": "kmd hat keine Wallets, mit goal wallet new eine anlegen",
+	"kmd wallet %s unlocked":                              "kmd-Wallet %s entsperrt",
+	"last proposal %d":                                    "letzter Vorschlag %d",
+	"last vote %d":                                        "letzte Stimme %d",
+	"never proposed":                                      "nie vorgeschlagen",
+	"never voted":                                         "nie abgestimmt",
+	"no":                                                  "nein",
+	"ntfy topic URL for phone notifications, e.g. https://ntfy.sh/my-node": "ntfy-Topic-URL für Handy-Benachrichtigungen, z. B. https://ntfy.sh/my-node",
+	"on battery %.0f%%, %s left":          "im Akkubetrieb %.0f%%, noch %s",
+	"online, battery %.0f%%, load %.0f%%": "online, Akku %.0f%%, Last %.0f%%",
+	"resolved":                            "behoben",
+	"rounds %d–%d, %s, %s":                "Runden %d–%d, %s, %s",
+	"running":                             "läuft",
+	"steady":                              "stabil",
+	"stopped":                             "gestoppt",
+	"turn on time sync for the host":      "Zeitsynchronisierung auf dem Host einschalten",
+	"unreachable: %s":                     "nicht erreichbar: %s",
+	"voiui %s is available":               "voiui %s ist verfügbar",
+	"voiui %s will be installed on the next start": "voiui %s wird beim nächsten Start installiert",
+	"warning": "Warnung",
+	"yes":     "ja",
+}
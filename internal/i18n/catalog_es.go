@@ -0,0 +1,295 @@
+package i18n
+
+// es is the Spanish catalog.
+var es = map[string]string{
+	" (%.0f days)":                    " (%.0f días)",
+	" (attempt %d)":                   " (intento %d)",
+	" (in %d rounds)":                 " (en %d rondas)",
+	" (yours)":                        " (su voto)",
+	" – in use":                       " – en uso",
+	"%+.2f pts":                       "%+.2f pts",
+	"%.1f%% participating":            "%.1f%% participando",
+	"%.1f%% uptime over %s monitored": "%.1f%% de disponibilidad en %s monitorizados",
+	"%.2f TPS, %.1f txns/block, %d in the last block": "%.2f TPS, %.1f tx/bloque, %d en el último bloque",
+	"%.2f – %d of %.1f expected proposals":            "%.2f – %d de %.1f propuestas esperadas",
+	"%.2f%% (was %.2f%%)":                             "%.2f%% (antes %.2f%%)",
+	"%d (was %d)":                                     "%d (antes %d)",
+	"%d is closed":                                    "%d está cerrado",
+	"%d is open":                                      "%d está abierto",
+	"%d pending":                                      "%d pendientes",
+	"%d proposals recorded in 30 days, last %s":       "%d propuestas en 30 días, la última %s",
+	"%d relays: %s latency, %s jitter, %.0f%% loss":   "%d relays: %s de latencia, %s de jitter, %.0f%% de pérdida",
+	"%d restarts":                                     "%d reinicios",
+	"%s %s from %s: %s":                               "%s %s de %s: %s",
+	"%s (was %s)":                                     "%s (antes %s)",
+	"%s [%s] %s, %s":                                  "%s [%s] %s, %s",
+	"%s at %s":                                        "%s a las %s",
+	"%s for %s":                                       "%s para %s",
+	"%s is in the bootstrap records":                  "%s está en los registros de arranque",
+	"%s is missing from the bootstrap records":        "%s falta en los registros de arranque",
+	"%s off %s":                                       "%s de desfase con %s",
+	"%s since %s (%s)":                                "%s desde %s (%s)",
+	"%s – %s, %d rounds: ~%.2f":                       "%s – %s, %d rondas: ~%.2f",
+	"(default %s)":                                    "(por defecto %s)",
+	", +%s/day":                                       ", +%s/día",
+	", disk full in ~%.0f days":                       ", disco lleno en ~%.0f días",
+	", key valid until round %d":                      ", clave válida hasta la ronda %d",
+	", offline":                                       ", fuera de línea",
+	", online":                                        ", en línea",
+	"1. Connect to your node":                         "1. Conectar con tu nodo",
+	"2. Notifications":                                "2. Notificaciones",
+	"24 hours":                                        "24 horas",
+	"3. Finish":                                       "3. Finalizar",
+	"30 days":                                         "30 días",
+	"30 days:":                                        "30 días:",
+	"7 days":                                          "7 días",
+	"7 days:":                                         "7 días:",
+	"API token":                                       "Token de API",
+	"Acknowledge":                                     "Confirmar",
+	"Address":                                         "Dirección",
+	"Address copied":                                  "Dirección copiada",
+	"Address:":                                        "Dirección:",
+	"Admin locked, enter passphrase to unlock:":                          "Administración bloqueada, introduzca la frase de paso para desbloquear:",
+	"Admin token locked":                                                 "Token de administración bloqueado",
+	"Admin unlocked, locks in %s":                                        "Administración desbloqueada, se bloquea en %s",
+	"Alerts (%d unacknowledged)":                                         "Alertas (%d sin confirmar)",
+	"Alerts always show in the window and the tray, pick more channels:": "Las alertas siempre aparecen en la ventana y la bandeja, elige más canales:",
+	"Alerts muted for this profile":                                      "Alertas silenciadas para este perfil",
+	"App lock disabled":                                                  "Bloqueo de la aplicación desactivado",
+	"App lock enabled":                                                   "Bloqueo de la aplicación activado",
+	"App lock is off, set a PIN to enable it:":                           "El bloqueo está desactivado, defina un PIN para activarlo:",
+	"App lock is on, set a new PIN or disable it:":                       "El bloqueo está activado, defina un PIN nuevo o desactívelo:",
+	"Availability (30 days):":                                            "Disponibilidad (30 días):",
+	"Average block time %s":                                              "Tiempo medio de bloque %s",
+	"Avg block time":                                                     "Tiempo medio de bloque",
+	"Back":                                                               "Atrás",
+	"Back up keys":                                                       "Copiar claves",
+	"Backup file to restore":                                             "Archivo de copia a restaurar",
+	"Backup passphrase":                                                  "Frase de paso de la copia",
+	"Block time %s average over 24 hours":                                "Tiempo de bloque %s de media en 24 horas",
+	"Block times over 24 hours":                                          "Tiempos de bloque en 24 horas",
+	"CPU %.0f%%, memory %s of %s":                                        "CPU %.0f%%, memoria %s de %s",
+	"CPU and memory unavailable: %s":                                     "CPU y memoria no disponibles: %s",
+	"Cancel":                                                             "Cancelar",
+	"Cannot reach algod":                                                 "No se puede contactar con algod",
+	"Clock drift:":                                                       "Desfase del reloj:",
+	"Close":                                                              "Cerrar",
+	"Compact layout":                                                     "Vista compacta",
+	"Connection refused, algod is not listening at the address": "Conexión rechazada, algod no escucha en la dirección",
+	"Connectivity (purple: missed proposals):":                  "Conectividad (morado: propuestas perdidas):",
+	"Copy address":                   "Copiar dirección",
+	"Copy log":                       "Copiar registro",
+	"Data %s, disk %s free (%.0f%%)": "Datos %s, disco %s libre (%.0f%%)",
+	"Data directories found:":        "Directorios de datos encontrados:",
+	"Data directory":                 "Directorio de datos",
+	"Details":                        "Detalles",
+	"Disable":                        "Desactivar",
+	"Email, Gotify, MQTT and more can be set up in the config file later.": "Correo, Gotify, MQTT y más se pueden configurar después en el archivo de configuración.",
+	"Estimated APR: %.2f%% on %s online":                                   "TAE estimada: %.2f%% sobre %s en línea",
+	"Events:":                                                              "Eventos:",
+	"Expand":                                                               "Ampliar",
+	"Expanded layout":                                                      "Vista ampliada",
+	"Expected %s, this node is on a different network":                     "Se esperaba %s, este nodo está en otra red",
+	"Expected at current stake: %.2f per day":                              "Esperado con el stake actual: %.2f al día",
+	"Export history as CSV":                                                "Exportar historial como CSV",
+	"Export history as JSON":                                               "Exportar historial como JSON",
+	"Export history:":                                                      "Exportar historial:",
+	"Export offline":                                                       "Exportar fuera de línea",
+	"Export online":                                                        "Exportar en línea",
+	"Export weekly report":                                                 "Exportar informe semanal",
+	"Find kmd wallets":                                                     "Buscar carteras de kmd",
+	"Follower:":                                                            "Seguidor:",
+	"Forget":                                                               "Olvidar",
+	"Found %d account(s) with participation keys on this node. Add them to the watch list?": "Se encontraron %d cuenta(s) con claves de participación en este nodo. ¿Añadirlas a la lista de seguimiento?",
+	"Found %d kmd wallet(s)":      "Se encontraron %d cartera(s) de kmd",
+	"Gave up reconnecting":        "Se dejó de reintentar la conexión",
+	"Generate diagnostic report":  "Generar informe de diagnóstico",
+	"Go to account %s %s":         "Ir a la cuenta %s %s",
+	"Gossip port, checked at %s:": "Puerto de gossip, comprobado a las %s:",
+	"Hardware:":                   "Hardware:",
+	"Hide alerts":                 "Ocultar alertas",
+	"Hide diagnostics":            "Ocultar diagnóstico",
+	"Hide node config":            "Ocultar configuración del nodo",
+	"History:":                    "Historial:",
+	"Host:":                       "Equipo:",
+	"Hosting template, presets the service commands and the log:": "Plantilla de alojamiento, preconfigura los comandos del servicio y el log:",
+	"Hottest sensor: %s %.0f°C":                                   "Sensor más caliente: %s %.0f°C",
+	"Indexer:":                                                    "Indexador:",
+	"Key active, account offline":                                 "Clave activa, cuenta fuera de línea",
+	"Key backup:":                                                 "Copia de claves:",
+	"LOW BATTERY %.0f%%, %s left":                                 "BATERÍA BAJA %.0f%%, quedan %s",
+	"Label":                                                       "Etiqueta",
+	"Language:":                                                   "Idioma:",
+	"Last 7 days: %.2f (%s)":                                      "Últimos 7 días: %.2f (%s)",
+	"Last round:":                                                 "Última ronda:",
+	"Ledger %s":                                                   "Ledger %s",
+	"Lock":                                                        "Bloquear",
+	"Lock admin token with passphrase:":                           "Bloquear el token de administración con frase de paso:",
+	"Lock now":                                                    "Bloquear ahora",
+	"Lock window":                                                 "Bloquear ventana",
+	"Locked":                                                      "Bloqueado",
+	"Log copied":                                                  "Registro copiado",
+	"Log file: %s":                                                "Archivo de registro: %s",
+	"Missed proposals (estimated):":                               "Propuestas perdidas (estimadas):",
+	"Mode:":                                                       "Modo:",
+	"Mute":                                                        "Silenciar",
+	"Network activity:":                                           "Actividad de la red:",
+	"Network:":                                                    "Red:",
+	"New PIN":                                                     "PIN nuevo",
+	"Next":                                                        "Siguiente",
+	"Next block expected, %.0f%% of the time left": "Próximo bloque esperado, queda el %.0f%% del tiempo",
+	"Next block in about %s":                       "Siguiente bloque en unos %s",
+	"Next block overdue by %s":                     "El siguiente bloque lleva %s de retraso",
+	"Node on this machine (data directory)":        "Nodo en este equipo (directorio de datos)",
+	"Node unreachable":                             "Nodo inalcanzable",
+	"Node: %s":                                     "Nodo: %s",
+	"None":                                         "Ninguna",
+	"Not Running":                                  "Detenido",
+	"Not now":                                      "Ahora no",
+	"Not participating":                            "No participa",
+	"Notifications muted":                          "Notificaciones silenciadas",
+	"Notifications silenced until %s":              "Notificaciones silenciadas hasta %s",
+	"Notifications snoozed until %s":               "Notificaciones pospuestas hasta las %s",
+	"OS keychain:":                                 "Llavero del sistema:",
+	"Open log":                                     "Abrir registro",
+	"Open node log":                                "Abrir registro del nodo",
+	"Participating":                                "Participando",
+	"Participation keys:":                          "Claves de participación:",
+	"Participation unknown (admin locked)":         "Participación desconocida (administración bloqueada)",
+	"Passphrase":                                   "Frase de paso",
+	"Peers: %d in, %d out":                         "Pares: %d entrantes, %d salientes",
+	"Performance (30 days):":                       "Rendimiento (30 días):",
+	"Play sounds when the node goes down, stops participating or proposes": "Reproducir sonidos cuando el nodo cae, deja de participar o propone",
+	"Plugin %s: %s": "Plugin %s: %s",
+	"Polling with the admin token, configure a non-admin token (algod.token / -api-token) to limit its exposure": "Consultando con el token de administración, configure un token sin privilegios (algod.token / -api-token) para limitar su exposición",
+	"Profile:":                            "Perfil:",
+	"Proposals":                           "Propuestas",
+	"Protocol %s":                         "Protocolo %s",
+	"QR code of the address":              "Código QR de la dirección",
+	"QR code of the unsigned transaction": "Código QR de la transacción sin firmar",
+	"Ready to sign":                       "Listo para firmar",
+	"Refresh now":                         "Actualizar ahora",
+	"Register key:":                       "Registrar clave:",
+	"Relay latency, purple where proposals were missed": "Latencia de los relays, en morado donde se perdieron propuestas",
+	"Relay:":                 "Relay:",
+	"Reload admin token":     "Recargar token de administración",
+	"Reload token":           "Recargar token",
+	"Remote algod endpoint":  "Endpoint algod remoto",
+	"Remove":                 "Quitar",
+	"Resolved: %s (%s – %s)": "Resuelto: %s (%s – %s)",
+	"Restart node":           "Reiniciar nodo",
+	"Restore":                "Restaurar",
+	"Retry now":              "Reintentar ahora",
+	"Retrying in %s…":        "Reintentando en %s…",
+	"Rewards":                "Recompensas",
+	"Rewards:":               "Recompensas:",
+	"Rotate admin token":     "Rotar token de administración",
+	"Rotate token":           "Rotar token",
+	"Running":                "En ejecución",
+	"Save":                   "Guardar",
+	"Save and start":         "Guardar e iniciar",
+	"Save telemetry":         "Guardar telemetría",
+	"Save tokens":            "Guardar tokens",
+	"Scale:":                 "Escala:",
+	"Send telemetry":         "Enviar telemetría",
+	"Send test email":        "Enviar correo de prueba",
+	"Set PIN":                "Definir PIN",
+	"Set a setting, an empty value restores the default:": "Cambia un ajuste, un valor vacío restaura el valor por defecto:",
+	"Setting":                     "Ajuste",
+	"Show diagnostics":            "Mostrar diagnóstico",
+	"Show node config":            "Mostrar configuración del nodo",
+	"Sign elsewhere & go offline": "Firmar en otro lugar y pasar a fuera de línea",
+	"Sign elsewhere & go online":  "Firmar en otro lugar y pasar a en línea",
+	"Sign the transaction with goal clerk sign or an offline signer, then paste the signed transaction": "Firme la transacción con goal clerk sign o un firmante sin conexión y pegue la transacción firmada",
+	"Sign to go offline":          "Firmar para pasar a fuera de línea",
+	"Sign to go online":           "Firmar para pasar a en línea",
+	"Sign with kmd & go offline":  "Firmar con kmd y pasar a fuera de línea",
+	"Sign with kmd & go online":   "Firmar con kmd y pasar a en línea",
+	"Signed transaction (base64)": "Transacción firmada (base64)",
+	"Signed transactions:":        "Transacciones firmadas:",
+	"Significantly below stake (%.2f%% chance of bad luck), check the participation key and clock": "Muy por debajo del stake (%.2f%% de probabilidad de mala suerte), revise la clave de participación y el reloj",
+	"Snooze 1h":                            "Posponer 1 h",
+	"Start node":                           "Iniciar nodo",
+	"Start voiui at login":                 "Iniciar voiui al iniciar sesión",
+	"State delta available":                "Delta de estado disponible",
+	"State delta missing":                  "Falta el delta de estado",
+	"Stop node":                            "Detener nodo",
+	"Submit":                               "Enviar",
+	"Switch to profile %s":                 "Cambiar al perfil %s",
+	"Sync round %d, advanced at %s":        "Ronda de sincronización %d, avanzó a las %s",
+	"System":                               "Sistema",
+	"Telemetry GUID: %s":                   "GUID de telemetría: %s",
+	"Telemetry node name":                  "Nombre del nodo para telemetría",
+	"Test connection":                      "Probar conexión",
+	"Testing…":                             "Probando…",
+	"The algod certificate is not trusted": "El certificado de algod no es de confianza",
+	"The algod host name does not resolve": "El nombre de host de algod no se resuelve",
+	"The wallet does not hold the selected key's account":        "La cartera no contiene la cuenta de la clave seleccionada",
+	"This node does not support the next protocol, update algod": "Este nodo no admite el próximo protocolo, actualice algod",
+	"This week vs last week:":                                    "Esta semana frente a la anterior:",
+	"Timed out, the node or the network is too slow":             "Tiempo agotado, el nodo o la red son demasiado lentos",
+	"Today:": "Hoy:",
+	"Token rejected (401), it may have been regenerated": "Token rechazado (401), puede que se haya regenerado",
+	"Token rejected, check the algod token":              "Token rechazado, revise el token de algod",
+	"Traffic: %s/s out, %s/s in":                         "Tráfico: %s/s de salida, %s/s de entrada",
+	"Transaction pool:":                                  "Pool de transacciones:",
+	"Transactions per block over 24 hours":               "Transacciones por bloque en 24 horas",
+	"Type a command…":                                    "Escriba un comando…",
+	"Unlock":                                             "Desbloquear",
+	"Unlock Voi Node Monitor":                            "Desbloquear Voi Node Monitor",
+	"Unlocked %s":                                        "%s desbloqueada",
+	"Unmute":                                             "Reactivar",
+	"Unsigned transaction %s, msgpack in base64":         "Transacción sin firmar %s, msgpack en base64",
+	"Update available: algod %s":                         "Actualización disponible: algod %s",
+	"Upgrade to %s at round %d":                          "Actualización a %s en la ronda %d",
+	"Upgrade vote: %d yes / %d no of %d rounds, %d needed, ends at round %d, this node votes %s": "Votación de actualización: %d sí / %d no de %d rondas, %d necesarios, termina en la ronda %d, este nodo vota %s",
+	"Uptime":                      "Disponibilidad",
+	"Use system authentication":   "Usar la autenticación del sistema",
+	"Value":                       "Valor",
+	"Version:":                    "Versión:",
+	"View last round on explorer": "Ver la última ronda en el explorador",
+	"View on explorer":            "Ver en el explorador",
+	"View proposal":               "Ver propuesta",
+	"Waiting %s for the next block, more than twice the average": "Esperando %s el siguiente bloque, más del doble de la media",
+	"Waiting for the next round":                                 "Esperando la próxima ronda",
+	"Wallet password":                                            "Contraseña de la cartera",
+	"Watch":                                                      "Seguir",
+	"Watch list:":                                                "Lista de seguimiento:",
+	"Watching accounts through a public endpoint":                "Siguiendo cuentas a través de un endpoint público",
+	"Weekly report":                                              "Informe semanal",
+	"Wrong PIN":                                                  "PIN incorrecto",
+	"acknowledged":                                               "confirmada",
+	"active":                                                     "activa",
+	"algod RTT:":                                                 "RTT de algod:",
+	"algod URL, e.g. http://192.168.1.10:8080": "URL de algod, p. ej. http://192.168.1.10:8080",
+	"algod usage unavailable: %s":              "Uso de algod no disponible: %s",
+	"algod: CPU %.0f%%, memory %s":             "algod: CPU %.0f%%, memoria %s",
+	"and %d more":                              "y %d más",
+	"critical":                                 "crítica",
+	"declining":                                "empeorando",
+	"improving":                                "mejorando",
+	"in sync at round %d":                      "sincronizado en la ronda %d",
+	"indexer behind node by %d rounds":         "el indexador va %d rondas por detrás del nodo",
+	"info":                                     "info",
+	"kmd has no wallets, create one with goal wallet new": "kmd no tiene carteras, cree una con goal wallet new",
+	"kmd wallet %s unlocked":                              "cartera de kmd %s desbloqueada",
+	"last proposal %d":                                    "última propuesta %d",
+	"last vote %d":                                        "último voto %d",
+	"never proposed":                                      "nunca ha propuesto",
+	"never voted":                                         "nunca ha votado",
+	"no":                                                  "no",
+	"ntfy topic URL for phone notifications, e.g. https://ntfy.sh/my-node": "URL del tema de ntfy para notificaciones en el móvil, p. ej. https://ntfy.sh/my-node",
+	"on battery %.0f%%, %s left":          "con batería %.0f%%, quedan %s",
+	"online, battery %.0f%%, load %.0f%%": "en línea, batería %.0f%%, carga %.0f%%",
+	"resolved":                            "resuelta",
+	"rounds %d–%d, %s, %s":                "rondas %d–%d, %s, %s",
+	"running":                             "en ejecución",
+	"steady":                              "estable",
+	"stopped":                             "detenido",
+	"turn on time sync for the host":      "activa la sincronización horaria en el host",
+	"unreachable: %s":                     "inalcanzable: %s",
+	"voiui %s is available":               "voiui %s está disponible",
+	"voiui %s will be installed on the next start": "voiui %s se instalará en el próximo inicio",
+	"warning": "aviso",
+	"yes":     "sí",
+}
@@ -0,0 +1,26 @@
+package i18n
+
+import (
+	"os"
+)
+
+// Detect returns the system language, English when it isn't supported.
+func Detect() string {
+	for _, name := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if v := os.Getenv(name); v != "" {
+			if lang, ok := Match(v); ok {
+				return lang
+			}
+			// the first variable set wins, like setlocale
+			break
+		}
+	}
+
+	for _, locale := range systemLocales() {
+		if lang, ok := Match(locale); ok {
+			return lang
+		}
+	}
+
+	return "en"
+}
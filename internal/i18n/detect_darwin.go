@@ -0,0 +1,14 @@
+package i18n
+
+import (
+	"os/exec"
+	"strings"
+)
+
+func systemLocales() []string {
+	out, err := exec.Command("defaults", "read", "-g", "AppleLocale").Output()
+	if err != nil {
+		return nil
+	}
+	return []string{strings.TrimSpace(string(out))}
+}
@@ -0,0 +1,7 @@
+//go:build !windows && !darwin
+
+package i18n
+
+func systemLocales() []string {
+	return nil
+}
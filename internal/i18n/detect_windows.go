@@ -0,0 +1,13 @@
+package i18n
+
+import (
+	"golang.org/x/sys/windows"
+)
+
+func systemLocales() []string {
+	list, err := windows.GetUserPreferredUILanguages(windows.MUI_LANGUAGE_NAME)
+	if err != nil {
+		return nil
+	}
+	return list
+}
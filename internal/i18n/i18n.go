@@ -0,0 +1,80 @@
+// Package i18n translates UI strings. Catalogs are keyed by the English
+// text so untranslated strings fall back to the source as written.
+package i18n
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+)
+
+// Language is a catalog the UI can be switched to.
+type Language struct {
+	Code string
+	Name string
+}
+
+var languages = []Language{
+	{"en", "English"},
+	{"de", "Deutsch"},
+	{"es", "Español"},
+}
+
+var catalogs = map[string]map[string]string{
+	"de": de,
+	"es": es,
+}
+
+var current atomic.Value
+
+func init() {
+	current.Store("en")
+}
+
+func Languages() []Language {
+	return append([]Language(nil), languages...)
+}
+
+// Match returns the supported language for a locale such as de_AT.UTF-8,
+// or false when there is none.
+func Match(locale string) (string, bool) {
+	locale = strings.ToLower(locale)
+	if i := strings.IndexAny(locale, "_-.@"); i >= 0 {
+		locale = locale[:i]
+	}
+
+	for _, l := range languages {
+		if l.Code == locale {
+			return l.Code, true
+		}
+	}
+
+	return "", false
+}
+
+// Set switches the language, an empty or unknown code picks the system
+// language and then English.
+func Set(code string) {
+	lang, ok := Match(code)
+	if !ok {
+		lang = Detect()
+	}
+	current.Store(lang)
+}
+
+func Current() string {
+	return current.Load().(string)
+}
+
+// T returns s in the current language.
+func T(s string) string {
+	if t, ok := catalogs[Current()][s]; ok {
+		return t
+	}
+	return s
+}
+
+// Tf translates format and then formats it.
+func Tf(format string, args ...interface{}) string {
+	return fmt.Sprintf(T(format), args...)
+}
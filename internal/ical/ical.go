@@ -0,0 +1,223 @@
+package ical
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"voiui/internal/config"
+	"voiui/internal/node"
+	"voiui/internal/state"
+)
+
+// used until enough blocks were seen to measure the network
+const defaultBlockTime = 2800 * time.Millisecond
+
+type Snapshotter interface {
+	Snapshot() state.State
+}
+
+type Event struct {
+	UID      string
+	Title    string
+	Start    time.Time
+	Duration time.Duration
+	Notes    string
+	// Remind sets an alarm this long before Start; zero means none.
+	Remind time.Duration
+}
+
+func roundAt(s state.State, round uint64, now time.Time) time.Time {
+	bt := s.AvgBlockDuration
+	if bt <= 0 {
+		bt = defaultBlockTime
+	}
+
+	return now.Add(time.Duration(round-s.Round) * bt).Truncate(time.Minute)
+}
+
+// Events lists the upcoming operational dates known from s plus the
+// operator's own entries. Round-based dates are estimated from the
+// average block time.
+func Events(s state.State, extra []config.CalendarEvent, now time.Time) []Event {
+	var events []Event
+
+	if s.Round > 0 {
+		for _, k := range s.Keys {
+			if k.LastValid <= s.Round {
+				continue
+			}
+
+			events = append(events, Event{
+				UID:      "key-" + k.ID,
+				Title:    "Participation key expires (" + short(k.Address) + ")",
+				Start:    roundAt(s, k.LastValid, now),
+				Duration: time.Hour,
+				Notes:    fmt.Sprintf("Key %s for %s is valid until round %d. Register a new key before then.", k.ID, k.Address, k.LastValid),
+				Remind:   7 * 24 * time.Hour,
+			})
+		}
+
+		c := s.Consensus
+		if c.Next != "" && c.Next != c.Current {
+			if c.NextRound > s.Round {
+				events = append(events, Event{
+					UID:      "upgrade-" + hash(c.Next),
+					Title:    "Protocol upgrade activates",
+					Start:    roundAt(s, c.NextRound, now),
+					Duration: time.Hour,
+					Notes:    fmt.Sprintf("Consensus %s takes effect at round %d. The node must run a version that supports it.", c.Next, c.NextRound),
+					Remind:   24 * time.Hour,
+				})
+			}
+
+			if c.VoteBefore > s.Round {
+				events = append(events, Event{
+					UID:      "upgrade-vote-" + hash(c.Next),
+					Title:    "Protocol upgrade voting ends",
+					Start:    roundAt(s, c.VoteBefore, now),
+					Duration: time.Hour,
+					Notes:    fmt.Sprintf("Voting on %s ends at round %d.", c.Next, c.VoteBefore),
+				})
+			}
+		}
+	}
+
+	for _, e := range extra {
+		if e.Start.IsZero() {
+			continue
+		}
+
+		d := time.Duration(e.Duration)
+		if d <= 0 {
+			d = time.Hour
+		}
+
+		events = append(events, Event{
+			UID:      "config-" + hash(e.Title+e.Start.String()),
+			Title:    e.Title,
+			Start:    e.Start,
+			Duration: d,
+			Notes:    e.Notes,
+			Remind:   time.Hour,
+		})
+	}
+
+	return events
+}
+
+func short(address string) string {
+	if len(address) <= 10 {
+		return address
+	}
+	return address[:4] + "…" + address[len(address)-4:]
+}
+
+func hash(s string) string {
+	sum := sha1.Sum([]byte(s))
+	return hex.EncodeToString(sum[:8])
+}
+
+func escape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`).Replace(s)
+}
+
+func stamp(t time.Time) string {
+	return t.UTC().Format("20060102T150405Z")
+}
+
+// fold wraps content lines at 75 octets as RFC 5545 requires.
+func fold(buf *bytes.Buffer, line string) {
+	for len(line) > 75 {
+		cut := 75
+		for cut > 0 && line[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		buf.WriteString(line[:cut] + "\r\n ")
+		line = line[cut:]
+	}
+	buf.WriteString(line + "\r\n")
+}
+
+func Render(events []Event, now time.Time) []byte {
+	var buf bytes.Buffer
+
+	fold(&buf, "BEGIN:VCALENDAR")
+	fold(&buf, "VERSION:2.0")
+	fold(&buf, "PRODID:-//voiui//Voi Node Monitor//EN")
+	fold(&buf, "CALSCALE:GREGORIAN")
+	fold(&buf, "X-WR-CALNAME:Voi node")
+
+	for _, e := range events {
+		fold(&buf, "BEGIN:VEVENT")
+		fold(&buf, "UID:"+e.UID+"@voiui")
+		fold(&buf, "DTSTAMP:"+stamp(now))
+		fold(&buf, "DTSTART:"+stamp(e.Start))
+		fold(&buf, "DTEND:"+stamp(e.Start.Add(e.Duration)))
+		fold(&buf, "SUMMARY:"+escape(e.Title))
+		if e.Notes != "" {
+			fold(&buf, "DESCRIPTION:"+escape(e.Notes))
+		}
+		if e.Remind > 0 {
+			fold(&buf, "BEGIN:VALARM")
+			fold(&buf, "ACTION:DISPLAY")
+			fold(&buf, "DESCRIPTION:"+escape(e.Title))
+			fold(&buf, fmt.Sprintf("TRIGGER:-PT%dM", int(e.Remind.Minutes())))
+			fold(&buf, "END:VALARM")
+		}
+		fold(&buf, "END:VEVENT")
+	}
+
+	fold(&buf, "END:VCALENDAR")
+
+	return buf.Bytes()
+}
+
+// Feed publishes the calendar to a file and over HTTP.
+type Feed struct {
+	store Snapshotter
+	extra []config.CalendarEvent
+}
+
+func New(store Snapshotter, extra []config.CalendarEvent) *Feed {
+	return &Feed{
+		store: store,
+		extra: extra,
+	}
+}
+
+func (f *Feed) render() []byte {
+	now := time.Now()
+	return Render(Events(f.store.Snapshot(), f.extra, now), now)
+}
+
+func (f *Feed) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Write(f.render())
+}
+
+// Run writes path right away and then every interval until ctx is done.
+func (f *Feed) Run(ctx context.Context, path string, interval time.Duration) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	for {
+		err := node.WriteFileAtomic(filepath.Dir(path), filepath.Base(path), f.render())
+		if err != nil {
+			slog.Error("failed to write calendar", "err", err)
+		}
+
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
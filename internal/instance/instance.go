@@ -0,0 +1,106 @@
+package instance
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"log/slog"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const socketName = "voiui.sock"
+
+var ErrRunning = errors.New("voiui is already running")
+
+// Server holds the socket that makes this process the only instance and
+// answers commands from later invocations.
+type Server struct {
+	l net.Listener
+}
+
+func socketPath(dir string) string {
+	return filepath.Join(dir, socketName)
+}
+
+// Listen claims the instance socket in the config dir. It returns
+// ErrRunning when another instance answers on it and removes the socket of
+// one that exited without cleaning up.
+func Listen(dir string) (*Server, error) {
+	path := socketPath(dir)
+
+	c, err := net.DialTimeout("unix", path, time.Second)
+	if err == nil {
+		c.Close()
+		return nil, ErrRunning
+	}
+
+	os.Remove(path)
+
+	l, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to listen on instance socket")
+	}
+
+	return &Server{l: l}, nil
+}
+
+// Serve answers each command with the text handle returns until ctx is
+// done.
+func (s *Server) Serve(ctx context.Context, handle func(cmd string) string) {
+	go func() {
+		<-ctx.Done()
+		s.l.Close()
+	}()
+
+	for {
+		c, err := s.l.Accept()
+		if err != nil {
+			if ctx.Err() == nil {
+				slog.Error("instance socket failed", "err", err)
+			}
+			return
+		}
+
+		go func(c net.Conn) {
+			defer c.Close()
+
+			c.SetDeadline(time.Now().Add(10 * time.Second))
+
+			cmd, err := bufio.NewReader(io.LimitReader(c, 1024)).ReadString('\n')
+			if err != nil {
+				return
+			}
+
+			io.WriteString(c, handle(strings.TrimSpace(cmd)))
+		}(c)
+	}
+}
+
+// Send passes cmd to the running instance and returns its answer.
+func Send(dir string, cmd string) (string, error) {
+	c, err := net.DialTimeout("unix", socketPath(dir), time.Second)
+	if err != nil {
+		return "", errors.New("voiui is not running")
+	}
+	defer c.Close()
+
+	c.SetDeadline(time.Now().Add(10 * time.Second))
+
+	_, err = io.WriteString(c, cmd+"\n")
+	if err != nil {
+		return "", errors.Wrap(err, "failed to send command")
+	}
+
+	reply, err := io.ReadAll(c)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to read reply")
+	}
+
+	return string(reply), nil
+}
@@ -0,0 +1,19 @@
+package keychain
+
+import "github.com/pkg/errors"
+
+const service = "voiui"
+
+var ErrNotFound = errors.New("secret not found in keychain")
+
+func Get(account string) (string, error) {
+	return get(account)
+}
+
+func Set(account string, secret string) error {
+	return set(account, secret)
+}
+
+func Delete(account string) error {
+	return del(account)
+}
@@ -0,0 +1,59 @@
+package keychain
+
+import (
+	"os/exec"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+func get(account string) (string, error) {
+	out, err := exec.Command("security", "find-generic-password", "-s", service, "-a", account, "-w").Output()
+	if err != nil {
+		if _, ok := err.(*exec.ExitError); ok {
+			return "", ErrNotFound
+		}
+		return "", errors.Wrap(err, "failed to read keychain")
+	}
+
+	return strings.TrimSpace(string(out)), nil
+}
+
+// set feeds the command to security on stdin, so the secret never shows
+// up in a process listing.
+func set(account string, secret string) error {
+	line := strings.Join([]string{"add-generic-password", "-U", "-s", quote(service), "-a", quote(account), "-w", quote(secret)}, " ")
+
+	cmd := exec.Command("security", "-i")
+	cmd.Stdin = strings.NewReader(line + "\n")
+
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return errors.Wrap(err, "failed to write keychain")
+	}
+
+	// security -i exits cleanly even when a command fails
+	stored, err := get(account)
+	if err != nil || stored != secret {
+		return errors.Errorf("failed to write keychain: %s", strings.TrimSpace(string(out)))
+	}
+
+	return nil
+}
+
+// quote makes s one argument for security's interactive mode.
+func quote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+func del(account string) error {
+	err := exec.Command("security", "delete-generic-password", "-s", service, "-a", account).Run()
+	if err != nil {
+		if _, ok := err.(*exec.ExitError); ok {
+			return nil
+		}
+		return errors.Wrap(err, "failed to delete from keychain")
+	}
+
+	return nil
+}
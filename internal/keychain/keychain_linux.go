@@ -0,0 +1,49 @@
+package keychain
+
+import (
+	"os/exec"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+func get(account string) (string, error) {
+	out, err := exec.Command("secret-tool", "lookup", "service", service, "account", account).Output()
+	if err != nil {
+		if _, ok := err.(*exec.ExitError); ok {
+			return "", ErrNotFound
+		}
+		return "", errors.Wrap(err, "failed to read secret service")
+	}
+
+	secret := strings.TrimSpace(string(out))
+	if secret == "" {
+		return "", ErrNotFound
+	}
+
+	return secret, nil
+}
+
+func set(account string, secret string) error {
+	cmd := exec.Command("secret-tool", "store", "--label", service+" "+account, "service", service, "account", account)
+	cmd.Stdin = strings.NewReader(secret)
+
+	err := cmd.Run()
+	if err != nil {
+		return errors.Wrap(err, "failed to write secret service")
+	}
+
+	return nil
+}
+
+func del(account string) error {
+	err := exec.Command("secret-tool", "clear", "service", service, "account", account).Run()
+	if err != nil {
+		if _, ok := err.(*exec.ExitError); ok {
+			return nil
+		}
+		return errors.Wrap(err, "failed to delete from secret service")
+	}
+
+	return nil
+}
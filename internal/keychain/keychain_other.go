@@ -0,0 +1,19 @@
+//go:build !windows && !darwin && !linux
+
+package keychain
+
+import "github.com/pkg/errors"
+
+var errUnsupported = errors.New("keychain is not supported on this platform")
+
+func get(account string) (string, error) {
+	return "", ErrNotFound
+}
+
+func set(account string, secret string) error {
+	return errUnsupported
+}
+
+func del(account string) error {
+	return nil
+}
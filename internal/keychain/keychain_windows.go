@@ -0,0 +1,100 @@
+package keychain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"unsafe"
+
+	"github.com/pkg/errors"
+	"golang.org/x/sys/windows"
+
+	"voiui/internal/config"
+)
+
+func path(account string) (string, error) {
+	dir, err := config.Dir()
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256([]byte(account))
+
+	return filepath.Join(dir, "secret-"+hex.EncodeToString(sum[:8])+".dpapi"), nil
+}
+
+func blob(b []byte) *windows.DataBlob {
+	if len(b) == 0 {
+		return &windows.DataBlob{}
+	}
+	return &windows.DataBlob{Size: uint32(len(b)), Data: &b[0]}
+}
+
+func take(out *windows.DataBlob) []byte {
+	defer windows.LocalFree(windows.Handle(unsafe.Pointer(out.Data)))
+
+	b := make([]byte, out.Size)
+	copy(b, unsafe.Slice(out.Data, out.Size))
+
+	return b
+}
+
+func get(account string) (string, error) {
+	p, err := path(account)
+	if err != nil {
+		return "", err
+	}
+
+	data, err := os.ReadFile(p)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", ErrNotFound
+		}
+		return "", errors.Wrap(err, "failed to read protected secret")
+	}
+
+	var out windows.DataBlob
+
+	err = windows.CryptUnprotectData(blob(data), nil, nil, 0, nil, windows.CRYPTPROTECT_UI_FORBIDDEN, &out)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to unprotect secret")
+	}
+
+	return string(take(&out)), nil
+}
+
+func set(account string, secret string) error {
+	p, err := path(account)
+	if err != nil {
+		return err
+	}
+
+	var out windows.DataBlob
+
+	err = windows.CryptProtectData(blob([]byte(secret)), nil, nil, 0, nil, windows.CRYPTPROTECT_UI_FORBIDDEN, &out)
+	if err != nil {
+		return errors.Wrap(err, "failed to protect secret")
+	}
+
+	err = os.WriteFile(p, take(&out), 0600)
+	if err != nil {
+		return errors.Wrap(err, "failed to write protected secret")
+	}
+
+	return nil
+}
+
+func del(account string) error {
+	p, err := path(account)
+	if err != nil {
+		return err
+	}
+
+	err = os.Remove(p)
+	if err != nil && !os.IsNotExist(err) {
+		return errors.Wrap(err, "failed to delete protected secret")
+	}
+
+	return nil
+}
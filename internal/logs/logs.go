@@ -0,0 +1,157 @@
+// Package logs sets up slog for the monitor: stderr, a rotating file in the
+// config directory and the recent lines for the diagnostics pane.
+package logs
+
+import (
+	"bytes"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	fileName = "voiui.log"
+	// maxSize rotates the file to voiui.log.1, so at most twice this is kept.
+	maxSize = 5 << 20
+	// keep is how many lines Recent returns.
+	keep = 200
+)
+
+var recent = &ring{}
+
+// Setup makes slog and the log package write to stderr and, unless dir is
+// empty, the log file in dir, at debug level when verbose. The returned
+// file must be closed.
+func Setup(dir string, verbose bool) (io.Closer, error) {
+	out := []io.Writer{os.Stderr, recent}
+	var f io.Closer = noFile{}
+
+	if dir != "" {
+		r, err := openRotating(filepath.Join(dir, fileName))
+		if err != nil {
+			return nil, err
+		}
+		out, f = append(out, r), r
+	}
+
+	level := slog.LevelInfo
+	if verbose {
+		level = slog.LevelDebug
+	}
+
+	h := slog.NewTextHandler(io.MultiWriter(out...), &slog.HandlerOptions{Level: level})
+	slog.SetDefault(slog.New(h))
+
+	return f, nil
+}
+
+type noFile struct{}
+
+func (noFile) Close() error { return nil }
+
+// Path is where Setup writes the log for dir.
+func Path(dir string) string {
+	return filepath.Join(dir, fileName)
+}
+
+// Recent returns the last lines logged, oldest first.
+func Recent() []string {
+	return recent.lines()
+}
+
+// ring keeps the last lines written to it.
+type ring struct {
+	mu    sync.Mutex
+	buf   []string
+	start int
+}
+
+func (r *ring) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, line := range bytes.Split(bytes.TrimRight(p, "\n"), []byte("\n")) {
+		if len(r.buf) < keep {
+			r.buf = append(r.buf, string(line))
+			continue
+		}
+		r.buf[r.start] = string(line)
+		r.start = (r.start + 1) % keep
+	}
+
+	return len(p), nil
+}
+
+func (r *ring) lines() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append(append([]string(nil), r.buf[r.start:]...), r.buf[:r.start]...)
+}
+
+// rotating is a log file that moves to .1 when it grows past maxSize.
+type rotating struct {
+	mu   sync.Mutex
+	path string
+	f    *os.File
+	size int64
+}
+
+func openRotating(path string) (*rotating, error) {
+	r := &rotating{path: path}
+
+	err := r.open()
+	if err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+func (r *rotating) open() error {
+	f, err := os.OpenFile(r.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return errors.Wrap(err, "failed to open log file")
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return errors.Wrap(err, "failed to stat log file")
+	}
+
+	r.f, r.size = f, info.Size()
+	return nil
+}
+
+func (r *rotating) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.size+int64(len(p)) > maxSize {
+		r.f.Close()
+
+		// a failed rename keeps appending, the next write tries again
+		os.Rename(r.path, r.path+".1")
+
+		err := r.open()
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := r.f.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+func (r *rotating) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.f.Close()
+}
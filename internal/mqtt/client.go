@@ -0,0 +1,157 @@
+package mqtt
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/binary"
+	"io"
+	"net"
+	"net/url"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// client speaks just enough MQTT 3.1.1 to publish at QoS 0.
+type client struct {
+	conn net.Conn
+}
+
+type will struct {
+	topic   string
+	payload string
+}
+
+func str(b *bytes.Buffer, s string) {
+	binary.Write(b, binary.BigEndian, uint16(len(s)))
+	b.WriteString(s)
+}
+
+func packet(kind byte, body []byte) []byte {
+	out := []byte{kind}
+
+	n := len(body)
+	for {
+		d := byte(n % 128)
+		n /= 128
+		if n > 0 {
+			d |= 0x80
+		}
+		out = append(out, d)
+		if n == 0 {
+			break
+		}
+	}
+
+	return append(out, body...)
+}
+
+func dial(broker string, id string, user string, password string, keepAlive time.Duration, w will) (*client, error) {
+	u, err := url.Parse(broker)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid MQTT broker")
+	}
+
+	d := &net.Dialer{Timeout: 10 * time.Second}
+
+	var conn net.Conn
+	switch u.Scheme {
+	case "tcp", "mqtt":
+		conn, err = d.Dial("tcp", hostPort(u, "1883"))
+	case "tls", "ssl", "mqtts":
+		conn, err = tls.DialWithDialer(d, "tcp", hostPort(u, "8883"), &tls.Config{ServerName: u.Hostname()})
+	default:
+		return nil, errors.Errorf("unknown MQTT broker scheme %q, use tcp or tls", u.Scheme)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to connect to %s", u.Host)
+	}
+
+	flags := byte(0x02 | 0x04 | 0x20)
+	if user != "" {
+		flags |= 0x80
+		if password != "" {
+			flags |= 0x40
+		}
+	}
+
+	var b bytes.Buffer
+	str(&b, "MQTT")
+	b.WriteByte(4)
+	b.WriteByte(flags)
+	binary.Write(&b, binary.BigEndian, uint16(keepAlive/time.Second))
+	str(&b, id)
+	str(&b, w.topic)
+	str(&b, w.payload)
+	if user != "" {
+		str(&b, user)
+		if password != "" {
+			str(&b, password)
+		}
+	}
+
+	conn.SetDeadline(time.Now().Add(10 * time.Second))
+	defer conn.SetDeadline(time.Time{})
+
+	_, err = conn.Write(packet(0x10, b.Bytes()))
+	if err != nil {
+		conn.Close()
+		return nil, errors.Wrap(err, "failed to send MQTT connect")
+	}
+
+	ack := make([]byte, 4)
+	_, err = io.ReadFull(conn, ack)
+	if err != nil {
+		conn.Close()
+		return nil, errors.Wrap(err, "no MQTT connect ack")
+	}
+	if ack[0] != 0x20 || ack[3] != 0 {
+		conn.Close()
+		return nil, errors.Errorf("MQTT broker refused the connection, code %d", ack[3])
+	}
+
+	c := &client{conn: conn}
+	go c.drain()
+
+	return c, nil
+}
+
+func hostPort(u *url.URL, port string) string {
+	if u.Port() != "" {
+		return u.Host
+	}
+	return net.JoinHostPort(u.Hostname(), port)
+}
+
+// drain discards what the broker sends, only ping responses are expected.
+func (c *client) drain() {
+	io.Copy(io.Discard, c.conn)
+}
+
+func (c *client) write(p []byte) error {
+	c.conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
+	_, err := c.conn.Write(p)
+	return err
+}
+
+func (c *client) publish(topic string, payload []byte, retain bool) error {
+	var b bytes.Buffer
+	str(&b, topic)
+	b.Write(payload)
+
+	kind := byte(0x30)
+	if retain {
+		kind |= 0x01
+	}
+
+	return errors.Wrapf(c.write(packet(kind, b.Bytes())), "failed to publish %s", topic)
+}
+
+func (c *client) ping() error {
+	return errors.Wrap(c.write([]byte{0xc0, 0}), "MQTT ping failed")
+}
+
+func (c *client) close() {
+	c.write([]byte{0xe0, 0})
+	c.conn.Close()
+}
@@ -0,0 +1,224 @@
+package mqtt
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"voiui/internal/config"
+	"voiui/internal/state"
+)
+
+type Snapshotter interface {
+	Snapshot() state.State
+}
+
+// Publisher keeps the node state on an MQTT broker as a retained JSON
+// message and announces it to Home Assistant.
+type Publisher struct {
+	cfg      config.MQTT
+	password string
+	id       string
+	name     string
+	base     string
+	interval time.Duration
+	store    Snapshotter
+}
+
+type payload struct {
+	Round         uint64  `json:"round"`
+	Participating bool    `json:"participating"`
+	BlockTime     float64 `json:"block_time"`
+	AvgBlockTime  float64 `json:"avg_block_time"`
+	Alerts        int     `json:"alerts"`
+	Alert         string  `json:"alert"`
+}
+
+type entity struct {
+	component string
+	key       string
+	name      string
+	value     string
+	unit      string
+}
+
+var entities = []entity{
+	{"sensor", "round", "Round", "{{ value_json.round }}", ""},
+	{"binary_sensor", "participating", "Participating", "{{ 'ON' if value_json.participating else 'OFF' }}", ""},
+	{"sensor", "block_time", "Block time", "{{ value_json.block_time }}", "s"},
+	{"sensor", "avg_block_time", "Average block time", "{{ value_json.avg_block_time }}", "s"},
+	{"sensor", "alerts", "Active alerts", "{{ value_json.alerts }}", ""},
+	{"sensor", "alert", "Alert", "{{ value_json.alert }}", ""},
+}
+
+var unsafe = regexp.MustCompile(`[^a-zA-Z0-9_-]+`)
+
+func New(cfg config.MQTT, name string, store Snapshotter) (*Publisher, error) {
+	if cfg.Broker == "" {
+		return nil, errors.New("mqtt needs a broker")
+	}
+
+	if cfg.Topic == "" {
+		cfg.Topic = "voiui"
+	}
+	if cfg.Discovery == "" {
+		cfg.Discovery = "homeassistant"
+	}
+
+	interval := time.Duration(cfg.Interval)
+	if interval <= 0 {
+		interval = 10 * time.Second
+	}
+
+	if name == "" {
+		name = "default"
+	}
+	id := unsafe.ReplaceAllString(name, "_")
+
+	password := cfg.Password
+	if password == "" {
+		password = os.Getenv("VOIUI_MQTT_PASSWORD")
+	}
+
+	return &Publisher{
+		cfg:      cfg,
+		password: password,
+		id:       id,
+		name:     name,
+		base:     strings.TrimRight(cfg.Topic, "/") + "/" + id,
+		interval: interval,
+		store:    store,
+	}, nil
+}
+
+func (p *Publisher) payload() []byte {
+	s := p.store.Snapshot()
+
+	v := payload{
+		Round:         s.Round,
+		Participating: s.Participation == state.ParticipationActive,
+		BlockTime:     s.PrevBlockDuration.Seconds(),
+		AvgBlockTime:  s.AvgBlockDuration.Seconds(),
+	}
+
+	for _, a := range s.Alerts {
+		if a.Resolved.IsZero() {
+			v.Alerts++
+			v.Alert = a.Title
+		}
+	}
+
+	b, _ := json.Marshal(v)
+	return b
+}
+
+func (p *Publisher) discover(c *client) error {
+	device := map[string]interface{}{
+		"identifiers":  []string{"voiui_" + p.id},
+		"name":         "voiui " + p.name,
+		"manufacturer": "voiui",
+	}
+
+	for _, e := range entities {
+		cfg := map[string]interface{}{
+			"name":               e.name,
+			"unique_id":          fmt.Sprintf("voiui_%s_%s", p.id, e.key),
+			"state_topic":        p.base + "/state",
+			"value_template":     e.value,
+			"availability_topic": p.base + "/status",
+			"device":             device,
+		}
+		if e.unit != "" {
+			cfg["unit_of_measurement"] = e.unit
+			cfg["state_class"] = "measurement"
+		}
+
+		b, err := json.Marshal(cfg)
+		if err != nil {
+			return err
+		}
+
+		err = c.publish(fmt.Sprintf("%s/%s/voiui_%s/%s/config", p.cfg.Discovery, e.component, p.id, e.key), b, true)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (p *Publisher) session(ctx context.Context) error {
+	keepAlive := 3 * p.interval
+	if keepAlive < 30*time.Second {
+		keepAlive = 30 * time.Second
+	}
+
+	c, err := dial(p.cfg.Broker, "voiui-"+p.id, p.cfg.Username, p.password, keepAlive, will{p.base + "/status", "offline"})
+	if err != nil {
+		return err
+	}
+	defer c.close()
+
+	if p.cfg.Discovery != "-" {
+		err = p.discover(c)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = c.publish(p.base+"/status", []byte("online"), true)
+	if err != nil {
+		return err
+	}
+
+	var last []byte
+
+	t := time.NewTicker(p.interval)
+	defer t.Stop()
+
+	for {
+		b := p.payload()
+		if bytes.Equal(b, last) {
+			err = c.ping()
+		} else {
+			err = c.publish(p.base+"/state", b, true)
+			last = b
+		}
+		if err != nil {
+			return err
+		}
+
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			c.publish(p.base+"/status", []byte("offline"), true)
+			return nil
+		}
+	}
+}
+
+func (p *Publisher) Run(ctx context.Context) {
+	t := time.NewTicker(time.Minute)
+	defer t.Stop()
+
+	for {
+		err := p.session(ctx)
+		if err != nil {
+			slog.Error("mqtt publisher failed", "broker", p.cfg.Broker, "err", err)
+		}
+
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,168 @@
+package netcheck
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"net"
+	"sort"
+	"time"
+
+	"voiui/internal/state"
+)
+
+const (
+	maxRelays   = 5
+	probes      = 5
+	dialTimeout = 3 * time.Second
+	maxHistory  = 288
+)
+
+// Checker measures TCP connect latency to relays. Without explicit relays
+// they are looked up from the network's SRV bootstrap records.
+type Checker struct {
+	relays   []string
+	domain   string
+	network  func() string
+	interval time.Duration
+	updates  chan<- state.Update
+
+	history []state.Connectivity
+}
+
+func New(relays []string, domain string, network func() string, interval time.Duration, updates chan<- state.Update) *Checker {
+	return &Checker{
+		relays:   relays,
+		domain:   domain,
+		network:  network,
+		interval: interval,
+		updates:  updates,
+	}
+}
+
+func (c *Checker) lookup(ctx context.Context) ([]string, error) {
+	if len(c.relays) > 0 {
+		return c.relays, nil
+	}
+
+	network := c.network()
+	if network == "" {
+		return nil, nil
+	}
+
+	_, srvs, err := net.DefaultResolver.LookupSRV(ctx, "algobootstrap", "tcp", network+"."+c.domain)
+	if err != nil {
+		return nil, err
+	}
+
+	var relays []string
+	for _, srv := range srvs {
+		relays = append(relays, net.JoinHostPort(srv.Target, fmt.Sprint(srv.Port)))
+		if len(relays) == maxRelays {
+			break
+		}
+	}
+
+	return relays, nil
+}
+
+func probe(ctx context.Context, addr string) (time.Duration, bool) {
+	d := net.Dialer{Timeout: dialTimeout}
+
+	start := time.Now()
+
+	conn, err := d.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return 0, false
+	}
+	conn.Close()
+
+	return time.Since(start), true
+}
+
+func (c *Checker) check(ctx context.Context) error {
+	relays, err := c.lookup(ctx)
+	if err != nil {
+		return err
+	}
+
+	if len(relays) == 0 {
+		return nil
+	}
+
+	var rtts []time.Duration
+	var jitter time.Duration
+	var jitters int
+	sent := 0
+
+	for _, relay := range relays {
+		var prev time.Duration
+
+		for i := 0; i < probes; i++ {
+			sent++
+
+			rtt, ok := probe(ctx, relay)
+			if !ok {
+				continue
+			}
+
+			if prev > 0 {
+				jitter += time.Duration(math.Abs(float64(rtt - prev)))
+				jitters++
+			}
+			prev = rtt
+
+			rtts = append(rtts, rtt)
+		}
+	}
+
+	sample := state.Connectivity{
+		At:     time.Now(),
+		Relays: len(relays),
+		Loss:   1 - float64(len(rtts))/float64(sent),
+	}
+
+	if len(rtts) > 0 {
+		sort.Slice(rtts, func(i, j int) bool { return rtts[i] < rtts[j] })
+		sample.Latency = rtts[len(rtts)/2]
+	}
+
+	if jitters > 0 {
+		sample.Jitter = jitter / time.Duration(jitters)
+	}
+
+	c.history = append(c.history, sample)
+	if len(c.history) > maxHistory {
+		c.history = c.history[len(c.history)-maxHistory:]
+	}
+
+	history := append([]state.Connectivity(nil), c.history...)
+
+	c.updates <- func(s *state.State) error {
+		s.Connectivity = history
+		return nil
+	}
+
+	return nil
+}
+
+func (c *Checker) Run(ctx context.Context) {
+	t := time.NewTimer(30 * time.Second)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			return
+		}
+
+		err := c.check(ctx)
+		if err != nil {
+			slog.Error("connectivity check failed", "err", err)
+		}
+
+		t.Reset(c.interval)
+	}
+}
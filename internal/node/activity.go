@@ -0,0 +1,45 @@
+package node
+
+import (
+	"time"
+
+	"voiui/internal/state"
+)
+
+// activityBlocks is how many recent blocks the rolling rates cover.
+const activityBlocks = 50
+
+type activity struct {
+	txns      []int
+	durations []time.Duration
+}
+
+func (a *activity) add(txns int, d time.Duration) {
+	a.txns = append(a.txns, txns)
+	a.durations = append(a.durations, d)
+
+	if len(a.txns) > activityBlocks {
+		a.txns = a.txns[1:]
+		a.durations = a.durations[1:]
+	}
+}
+
+func (a *activity) state(last int) state.Activity {
+	s := state.Activity{LastTxns: last}
+
+	var txns int
+	var d time.Duration
+	for i := range a.txns {
+		txns += a.txns[i]
+		d += a.durations[i]
+	}
+
+	if len(a.txns) > 0 {
+		s.TxnsPerBlock = float64(txns) / float64(len(a.txns))
+	}
+	if d > 0 {
+		s.TPS = float64(txns) / d.Seconds()
+	}
+
+	return s
+}
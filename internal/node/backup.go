@@ -0,0 +1,135 @@
+package node
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const backupMagic = "voiui-partkeys-1\n"
+
+// largest key file accepted from an archive
+const maxPartkey = 64 << 20
+
+// BackupKeys packs the participation key files of the data directory into
+// an archive sealed with passphrase and returns it with the number of keys.
+func (n *Node) BackupKeys(passphrase string) ([]byte, int, error) {
+	if passphrase == "" {
+		return nil, 0, errors.New("choose a passphrase for the backup")
+	}
+
+	dir := n.DataDir()
+	if dir == "" {
+		return nil, 0, errors.New("backing up keys needs the node's data directory")
+	}
+
+	paths, err := filepath.Glob(filepath.Join(dir, "*", "*.partkey"))
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "failed to find key files")
+	}
+
+	if len(paths) == 0 {
+		return nil, 0, errors.Errorf("no .partkey files in %s", dir)
+	}
+
+	var buf bytes.Buffer
+
+	zw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(zw)
+
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, 0, errors.Wrapf(err, "failed to read %s", path)
+		}
+
+		err = tw.WriteHeader(&tar.Header{
+			Name:    filepath.Base(path),
+			Mode:    0600,
+			Size:    int64(len(data)),
+			ModTime: time.Now(),
+		})
+		if err == nil {
+			_, err = tw.Write(data)
+		}
+		if err != nil {
+			return nil, 0, errors.Wrap(err, "failed to pack keys")
+		}
+	}
+
+	err = tw.Close()
+	if err == nil {
+		err = zw.Close()
+	}
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "failed to pack keys")
+	}
+
+	sealed, err := seal(buf.Bytes(), passphrase)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return append([]byte(backupMagic), sealed...), len(paths), nil
+}
+
+// RestoreKeys installs the keys of a backup through algod. Keys algod
+// already has are skipped.
+func (n *Node) RestoreKeys(ctx context.Context, archive []byte, passphrase string) (installed int, skipped int, err error) {
+	if !bytes.HasPrefix(archive, []byte(backupMagic)) {
+		return 0, 0, errors.New("not a voiui key backup")
+	}
+
+	data, err := unseal(archive[len(backupMagic):], passphrase)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return 0, 0, errors.Wrap(err, "failed to unpack keys")
+	}
+
+	src := n.source()
+	tr := tar.NewReader(zr)
+
+	for {
+		h, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return installed, skipped, errors.Wrap(err, "failed to unpack keys")
+		}
+
+		if !strings.HasSuffix(h.Name, ".partkey") {
+			continue
+		}
+
+		key, err := io.ReadAll(io.LimitReader(tr, maxPartkey))
+		if err != nil {
+			return installed, skipped, errors.Wrapf(err, "failed to read %s", h.Name)
+		}
+
+		_, err = src.InstallKey(ctx, key)
+		if err != nil && strings.Contains(err.Error(), "already") {
+			skipped++
+			continue
+		}
+		if err != nil {
+			return installed, skipped, errors.Wrapf(err, "failed to restore %s", h.Name)
+		}
+
+		installed++
+	}
+
+	return installed, skipped, nil
+}
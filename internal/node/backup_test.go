@@ -0,0 +1,85 @@
+package node
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRestoreKeys(t *testing.T) {
+	dir := t.TempDir()
+	keys := filepath.Join(dir, "voimain-v1.0")
+
+	err := os.Mkdir(keys, 0o700)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"a.partkey", "b.partkey"} {
+		err := os.WriteFile(filepath.Join(keys, name), []byte(name), 0o600)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	n, _ := testNode(t, &fakeAlgod{}, Config{DataDir: dir, APIToken: "api", AdminToken: "admin"})
+
+	if _, _, err := n.BackupKeys(""); err == nil {
+		t.Error("backed up without a passphrase")
+	}
+
+	archive, count, err := n.BackupKeys("pass")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 2 {
+		t.Errorf("backed up %d keys, want 2", count)
+	}
+	if strings.Contains(string(archive), "a.partkey") {
+		t.Error("the archive is not sealed")
+	}
+
+	tests := []struct {
+		name       string
+		archive    []byte
+		passphrase string
+		installed  map[string]bool
+		want       [2]int
+		err        string
+	}{
+		{name: "restored", archive: archive, passphrase: "pass", want: [2]int{2, 0}},
+		{name: "already installed", archive: archive, passphrase: "pass", installed: map[string]bool{"a.partkey": true}, want: [2]int{1, 1}},
+		{name: "wrong passphrase", archive: archive, passphrase: "other", err: "wrong passphrase"},
+		{name: "not a backup", archive: archive[1:], passphrase: "pass", err: "not a voiui key backup"},
+		{name: "truncated", archive: archive[:len(backupMagic)+20], passphrase: "pass", err: "truncated"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &fakeAlgod{installed: tt.installed}
+			n, _ := testNode(t, f, Config{DataDir: dir, APIToken: "api", AdminToken: "admin"})
+
+			installed, skipped, err := n.RestoreKeys(context.Background(), tt.archive, tt.passphrase)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("err = %v, want %q", err, tt.err)
+				}
+				if len(f.installed) != len(tt.installed) {
+					t.Errorf("installed keys from a rejected backup")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := [2]int{installed, skipped}; got != tt.want {
+				t.Errorf("installed, skipped = %v, want %v", got, tt.want)
+			}
+			if !f.installed["a.partkey"] || !f.installed["b.partkey"] {
+				t.Errorf("installed %v", f.installed)
+			}
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package node
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/algod"
+	"github.com/pkg/errors"
+)
+
+// Check connects to algod at url with token and tells which network it is
+// on, for setting up a profile before there is a node to poll.
+func Check(ctx context.Context, url string, token string, timeout time.Duration) (Genesis, error) {
+	var g Genesis
+
+	ac, err := algod.MakeClient(url, token)
+	if err != nil {
+		return g, errors.Wrap(err, "invalid algod URL")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	_, err = ac.Status().Do(ctx)
+	if err != nil {
+		return g, errors.Wrap(err, "failed to get the node status")
+	}
+
+	raw, err := ac.GetGenesis().Do(ctx)
+	if err != nil {
+		return g, errors.Wrap(err, "failed to get genesis")
+	}
+
+	err = json.Unmarshal([]byte(raw), &g)
+	if err != nil {
+		return g, errors.Wrap(err, "failed to decode genesis")
+	}
+
+	return g, nil
+}
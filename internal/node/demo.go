@@ -0,0 +1,179 @@
+package node
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/common/models"
+	"github.com/algorand/go-algorand-sdk/v2/types"
+	"github.com/pkg/errors"
+)
+
+const demoAddress = "DEMOXAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA"
+
+var demoVoteKey = []byte("demo vote key")
+
+type Demo struct {
+	mu    sync.Mutex
+	round uint64
+	at    time.Time
+
+	BlockTime time.Duration
+}
+
+func NewDemo() *Demo {
+	return &Demo{
+		round:     1_000_000,
+		at:        time.Now(),
+		BlockTime: 2800 * time.Millisecond,
+	}
+}
+
+func (d *Demo) advance() uint64 {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for time.Since(d.at) >= d.BlockTime {
+		d.at = d.at.Add(d.BlockTime)
+		d.round++
+	}
+
+	return d.round
+}
+
+func (d *Demo) status(round uint64) models.NodeStatus {
+	return models.NodeStatus{
+		LastRound:            round,
+		LastVersion:          "https://github.com/algorandfoundation/specs/tree/demo",
+		NextVersion:          "https://github.com/algorandfoundation/specs/tree/demo",
+		NextVersionRound:     round + 1,
+		NextVersionSupported: true,
+		TimeSinceLastRound:   uint64(time.Since(d.at)),
+	}
+}
+
+func (d *Demo) Genesis(ctx context.Context) (Genesis, error) {
+	return Genesis{ID: "v1", Network: "voitest"}, nil
+}
+
+func (d *Demo) Version(ctx context.Context) (models.Version, error) {
+	return models.Version{
+		Build: models.BuildVersion{
+			Major:       3,
+			Minor:       21,
+			BuildNumber: 0,
+			Channel:     "demo",
+		},
+		GenesisID: "voitest-v1",
+	}, nil
+}
+
+func (d *Demo) Status(ctx context.Context) (models.NodeStatus, error) {
+	return d.status(d.advance()), nil
+}
+
+func (d *Demo) WaitForBlock(ctx context.Context, round uint64) (models.NodeStatus, error) {
+	for {
+		current := d.advance()
+		if current > round {
+			return d.status(current), nil
+		}
+
+		jitter := time.Duration(rand.Int63n(int64(d.BlockTime / 10)))
+
+		d.mu.Lock()
+		next := time.Until(d.at.Add(d.BlockTime)) + jitter
+		d.mu.Unlock()
+
+		t := time.NewTimer(next)
+
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			t.Stop()
+			return models.NodeStatus{}, ctx.Err()
+		}
+	}
+}
+
+func (d *Demo) Participation(ctx context.Context) ([]Participation, error) {
+	round := d.advance()
+
+	first := round - round%100_000
+	last := first + 3_000_000
+	vote := round - 1
+	proposal := round - round%397
+
+	return []Participation{
+		{
+			Address:             demoAddress,
+			EffectiveFirstValid: &first,
+			EffectiveLastValid:  &last,
+			Id:                  "DEMOKEY",
+			LastVote:            &vote,
+			LastBlockProposal:   &proposal,
+			Key:                 models.AccountParticipation{VoteParticipationKey: demoVoteKey},
+		},
+	}, nil
+}
+
+func (d *Demo) AccountInfo(ctx context.Context, address string) (models.Account, error) {
+	round := d.advance()
+
+	return models.Account{
+		Address: address,
+		Amount:  1_234_567_890_000 + (round%1000)*1_000_000,
+		Round:   round,
+		Status:  "Online",
+		Rewards: round / 10,
+		Participation: models.AccountParticipation{
+			VoteFirstValid:       round - round%100_000,
+			VoteLastValid:        round - round%100_000 + 3_000_000,
+			VoteParticipationKey: demoVoteKey,
+		},
+	}, nil
+}
+
+func (d *Demo) OnlineStake(ctx context.Context) (uint64, error) {
+	return 500_000_000_000_000, nil
+}
+
+func (d *Demo) Proposer(ctx context.Context, round uint64) (string, uint64, error) {
+	if round%397 == 0 {
+		return demoAddress, 5_000_000, nil
+	}
+	return "", 0, nil
+}
+
+func (d *Demo) BlockHash(ctx context.Context, round uint64) (string, error) {
+	return fmt.Sprintf("DEMO%016X", round), nil
+}
+
+func (d *Demo) Txns(ctx context.Context, round uint64) (int, error) {
+	return int(round%7) * 3, nil
+}
+
+func (d *Demo) InstallKey(ctx context.Context, partkey []byte) (string, error) {
+	return "", errors.New("cannot install keys in demo mode")
+}
+
+func (d *Demo) GenerateKey(ctx context.Context, address string, first uint64, last uint64) error {
+	return errors.New("cannot generate keys in demo mode")
+}
+
+func (d *Demo) PendingTxns(ctx context.Context, max uint64) (uint64, []types.SignedTxn, error) {
+	round := d.advance()
+
+	txns := []types.SignedTxn{{Txn: types.Transaction{
+		Type:   types.PaymentTx,
+		Header: types.Header{Fee: 1000, FirstValid: types.Round(round), LastValid: types.Round(round + 1000)},
+		PaymentTxnFields: types.PaymentTxnFields{
+			Amount: types.MicroAlgos(round % 1000 * 1_000_000),
+		},
+	}}}
+
+	return uint64(len(txns)) + round%7, txns, nil
+}
@@ -0,0 +1,178 @@
+package node
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	seenFile  = "seen.json"
+	seenEvery = time.Minute
+
+	// shorter gaps are restarts, not time away
+	digestGap = 15 * time.Minute
+
+	// about a week of rounds
+	keyWarnRounds = uint64(7 * 24 * time.Hour / (2800 * time.Millisecond))
+)
+
+// lastSeen is the last moment voiui watched the node.
+type lastSeen struct {
+	At      time.Time `json:"at"`
+	Round   uint64    `json:"round"`
+	Network string    `json:"network"`
+}
+
+func loadSeen(dir string) (lastSeen, error) {
+	var seen lastSeen
+
+	data, err := os.ReadFile(filepath.Join(dir, seenFile))
+	if os.IsNotExist(err) {
+		return seen, nil
+	}
+	if err != nil {
+		return seen, errors.Wrap(err, "failed to read last seen")
+	}
+
+	err = json.Unmarshal(data, &seen)
+	if err != nil {
+		return seen, errors.Wrap(err, "failed to parse last seen")
+	}
+
+	return seen, nil
+}
+
+func (n *Node) touchSeen(round uint64) {
+	if n.configDir == "" {
+		return
+	}
+
+	now := time.Now()
+	if now.Sub(n.seenSaved) < seenEvery {
+		return
+	}
+
+	n.mu.Lock()
+	network := n.detected
+	n.mu.Unlock()
+
+	data, err := json.Marshal(lastSeen{At: now, Round: round, Network: network})
+	if err == nil {
+		err = WriteFileAtomic(n.configDir, seenFile, data)
+	}
+	if err != nil {
+		slog.Error("failed to save last seen", "err", err)
+		return
+	}
+
+	n.seenSaved = now
+}
+
+// checkAway sends a digest once per run when voiui was not watching the
+// node for a while before this start.
+func (n *Node) checkAway(ctx context.Context, src NodeSource, round uint64) {
+	if n.digested || n.configDir == "" {
+		return
+	}
+	n.digested = true
+
+	prev := n.prevSeen
+	if prev.At.IsZero() {
+		return
+	}
+
+	now := time.Now()
+	away := now.Sub(prev.At)
+	if away < digestGap {
+		return
+	}
+
+	go func() {
+		body, err := n.digest(ctx, src, prev, now, round)
+		if err != nil {
+			slog.Error("failed to build offline digest", "err", err)
+		}
+
+		title := fmt.Sprintf("While voiui was away (%s)", away.Round(time.Minute))
+		n.alerts.Digest(title, body)
+	}()
+}
+
+func (n *Node) digest(ctx context.Context, src NodeSource, prev lastSeen, now time.Time, round uint64) (string, error) {
+	lines := []string{
+		fmt.Sprintf("Not monitored from %s to %s.", prev.At.Format("Jan 2 15:04"), now.Format("Jan 2 15:04")),
+	}
+
+	n.mu.Lock()
+	network := n.detected
+	n.mu.Unlock()
+
+	sameChain := prev.Round > 0 && round > prev.Round && (prev.Network == "" || network == "" || prev.Network == network)
+	if sameChain {
+		lines = append(lines, fmt.Sprintf("The chain advanced %d rounds (%d to %d).", round-prev.Round, prev.Round, round))
+	}
+
+	items, err := src.Participation(ctx)
+	if err != nil {
+		return strings.Join(lines, " "), errors.Wrap(err, "failed to get participation")
+	}
+
+	var accounts []string
+	var expiring []string
+
+	for _, item := range items {
+		if !contains(accounts, item.Address) {
+			accounts = append(accounts, item.Address)
+		}
+
+		if item.EffectiveLastValid != nil && *item.EffectiveLastValid > round && *item.EffectiveLastValid-round < keyWarnRounds {
+			expiring = append(expiring, item.Address)
+		}
+	}
+
+	if len(accounts) == 0 {
+		lines = append(lines, "No participation keys are installed.")
+		return strings.Join(lines, " "), nil
+	}
+
+	if sameChain && !n.isDemo() {
+		ic, err := n.indexerClient()
+		if err != nil {
+			return strings.Join(lines, " "), err
+		}
+
+		online, err := src.OnlineStake(ctx)
+		if err != nil {
+			return strings.Join(lines, " "), err
+		}
+
+		if online > 0 {
+			var expected float64
+
+			for _, address := range accounts {
+				stake, err := n.stakeAt(ctx, ic, address, prev.Round)
+				if err != nil {
+					return strings.Join(lines, " "), err
+				}
+
+				expected += float64(round-prev.Round) * float64(stake) / float64(online)
+			}
+
+			lines = append(lines, fmt.Sprintf("About %.1f proposals were expected in that time.", expected))
+		}
+	}
+
+	if len(expiring) > 0 {
+		lines = append(lines, fmt.Sprintf("%d participation key(s) expire within a week.", len(expiring)))
+	}
+
+	return strings.Join(lines, " "), nil
+}
@@ -0,0 +1,288 @@
+package node
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/scrypt"
+
+	"voiui/internal/state"
+)
+
+// legacySealedFile held the one sealed admin token before they were kept
+// per endpoint.
+const legacySealedFile = "admin.token.sealed"
+
+// Endpoint names the node a sealed admin token belongs to: its data
+// directory, else its URL as reached through the SSH host if any.
+func Endpoint(url string, dataDir string, ssh SSHConfig) string {
+	if dataDir != "" {
+		return dataDir
+	}
+	if ssh.Enabled() {
+		return ssh.Target + " " + url
+	}
+	return url
+}
+
+// SealedTokenFile is the name of the sealed admin token of endpoint in the
+// config directory.
+func SealedTokenFile(endpoint string) string {
+	sum := sha256.Sum256([]byte(endpoint))
+	return fmt.Sprintf("admin.token.%x.sealed", sum[:8])
+}
+
+// Sealed tells whether the admin token of endpoint is sealed in dir. A
+// token sealed before they were kept per endpoint goes to the first
+// endpoint asked about.
+func Sealed(dir string, endpoint string) bool {
+	name := SealedTokenFile(endpoint)
+
+	_, err := os.Stat(filepath.Join(dir, legacySealedFile))
+	if err == nil {
+		err = os.Rename(filepath.Join(dir, legacySealedFile), filepath.Join(dir, name))
+		if err != nil {
+			slog.Error("failed to move the sealed admin token", "err", err)
+		} else {
+			slog.Info("moved the sealed admin token to its node", "endpoint", endpoint)
+		}
+	}
+
+	_, err = os.Stat(filepath.Join(dir, name))
+	return err == nil
+}
+
+func sealKey(passphrase string, salt []byte) (cipher.AEAD, error) {
+	key, err := scrypt.Key([]byte(passphrase), salt, 1<<15, 8, 1, 32)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to derive key")
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create cipher")
+	}
+
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create gcm")
+	}
+
+	return gcm, nil
+}
+
+// seal encrypts data with a key derived from passphrase, the salt and
+// nonce lead the result.
+func seal(data []byte, passphrase string) ([]byte, error) {
+	salt := make([]byte, 16)
+
+	_, err := rand.Read(salt)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to generate salt")
+	}
+
+	gcm, err := sealKey(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+
+	_, err = rand.Read(nonce)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to generate nonce")
+	}
+
+	out := append(salt, nonce...)
+	return gcm.Seal(out, nonce, data, nil), nil
+}
+
+func unseal(data []byte, passphrase string) ([]byte, error) {
+	if len(data) < 16 {
+		return nil, errors.New("sealed data is truncated")
+	}
+
+	gcm, err := sealKey(passphrase, data[:16])
+	if err != nil {
+		return nil, err
+	}
+
+	data = data[16:]
+	if len(data) < gcm.NonceSize() {
+		return nil, errors.New("sealed data is truncated")
+	}
+
+	plain, err := gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], nil)
+	if err != nil {
+		return nil, errors.New("wrong passphrase")
+	}
+
+	return plain, nil
+}
+
+func sealToken(token string, passphrase string) ([]byte, error) {
+	return seal([]byte(token), passphrase)
+}
+
+func openToken(data []byte, passphrase string) (string, error) {
+	token, err := unseal(data, passphrase)
+	return string(token), err
+}
+
+func (n *Node) sealedName() string {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	return SealedTokenFile(n.endpoint)
+}
+
+func (n *Node) AdminSealed() bool {
+	_, err := os.Stat(filepath.Join(n.configDir, n.sealedName()))
+	return err == nil
+}
+
+func (n *Node) SealAdmin(passphrase string) (string, error) {
+	if passphrase == "" {
+		return "", errors.New("passphrase is empty")
+	}
+
+	apiToken, adminToken := n.Tokens()
+	if adminToken == "" {
+		return "", errors.New("no admin token to lock")
+	}
+
+	if apiToken == "" {
+		return "", errors.New("a non-admin token is required to keep polling while the admin token is locked")
+	}
+
+	data, err := sealToken(adminToken, passphrase)
+	if err != nil {
+		return "", err
+	}
+
+	err = WriteFileAtomic(n.configDir, n.sealedName(), data)
+	if err != nil {
+		return "", err
+	}
+
+	err = n.LockAdmin()
+	if err != nil {
+		return "", err
+	}
+
+	return "Admin token locked", nil
+}
+
+func (n *Node) UnlockAdmin(passphrase string) (string, error) {
+	data, err := os.ReadFile(filepath.Join(n.configDir, n.sealedName()))
+	if err != nil {
+		return "", errors.Wrap(err, "failed to read sealed admin token")
+	}
+
+	token, err := openToken(data, passphrase)
+	if err != nil {
+		return "", err
+	}
+
+	apiToken, _ := n.Tokens()
+
+	err = n.setTokens(apiToken, token)
+	if err != nil {
+		return "", err
+	}
+
+	until := time.Now().Add(n.elevateFor)
+
+	n.mu.Lock()
+	n.passphrase = passphrase
+	if n.lockTimer != nil {
+		n.lockTimer.Stop()
+	}
+	n.lockTimer = time.AfterFunc(n.elevateFor, func() {
+		err := n.LockAdmin()
+		if err != nil {
+			slog.Error("failed to lock admin token", "err", err)
+		}
+	})
+	n.mu.Unlock()
+
+	n.updates <- func(s *state.State) error {
+		s.AdminLocked = false
+		s.AdminSealed = true
+		s.AdminUnlockedUntil = until
+		return nil
+	}
+
+	return "Admin token unlocked", nil
+}
+
+func (n *Node) LockAdmin() error {
+	n.mu.Lock()
+	n.passphrase = ""
+	if n.lockTimer != nil {
+		n.lockTimer.Stop()
+		n.lockTimer = nil
+	}
+	n.mu.Unlock()
+
+	apiToken, _ := n.Tokens()
+
+	err := n.setTokens(apiToken, "")
+	if err != nil {
+		return err
+	}
+
+	n.updates <- func(s *state.State) error {
+		s.AdminLocked = true
+		s.AdminSealed = true
+		s.AdminUnlockedUntil = time.Time{}
+		s.Participation = state.ParticipationUnknown
+		return nil
+	}
+
+	return nil
+}
+
+// forgetElevation drops the unlock of the previous node after switching
+// to one whose admin token is not sealed.
+func (n *Node) forgetElevation() {
+	n.mu.Lock()
+	n.passphrase = ""
+	if n.lockTimer != nil {
+		n.lockTimer.Stop()
+		n.lockTimer = nil
+	}
+	n.mu.Unlock()
+
+	n.updates <- func(s *state.State) error {
+		s.AdminLocked = false
+		s.AdminSealed = false
+		s.AdminUnlockedUntil = time.Time{}
+		return nil
+	}
+}
+
+func (n *Node) resealAdmin(token string) error {
+	n.mu.Lock()
+	passphrase := n.passphrase
+	n.mu.Unlock()
+
+	if passphrase == "" {
+		return errors.New("unlock the admin token first")
+	}
+
+	data, err := sealToken(token, passphrase)
+	if err != nil {
+		return err
+	}
+
+	return WriteFileAtomic(n.configDir, n.sealedName(), data)
+}
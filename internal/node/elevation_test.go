@@ -0,0 +1,111 @@
+package node
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSealedPerEndpoint(t *testing.T) {
+	dir := t.TempDir()
+	f := &fakeAlgod{start: 100, last: 103}
+
+	local, _ := testNode(t, f, Config{DataDir: "/var/lib/algorand", APIToken: "api", AdminToken: "local", ConfigDir: dir})
+	remote, _ := testNode(t, f, Config{APIToken: "api", AdminToken: "remote", ConfigDir: dir})
+
+	_, err := local.SealAdmin("pass")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !local.AdminSealed() {
+		t.Error("the sealed node is not sealed")
+	}
+	if remote.AdminSealed() {
+		t.Error("sealing one node sealed another")
+	}
+	if _, err := remote.UnlockAdmin("pass"); err == nil {
+		t.Error("unlocked another node's admin token")
+	}
+	if _, admin := remote.Tokens(); admin != "remote" {
+		t.Errorf("remote admin token = %q", admin)
+	}
+
+	_, err = local.UnlockAdmin("pass")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, admin := local.Tokens(); admin != "local" {
+		t.Errorf("unlocked %q, want the local token", admin)
+	}
+}
+
+func TestSealedLegacy(t *testing.T) {
+	dir := t.TempDir()
+
+	err := os.WriteFile(filepath.Join(dir, legacySealedFile), []byte("sealed"), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !Sealed(dir, "/var/lib/algorand") {
+		t.Error("the legacy token did not go to the first endpoint")
+	}
+	if Sealed(dir, "http://node.example:8080") {
+		t.Error("the legacy token also went to a second endpoint")
+	}
+	if _, err := os.Stat(filepath.Join(dir, legacySealedFile)); !os.IsNotExist(err) {
+		t.Errorf("legacy file left behind: %v", err)
+	}
+}
+
+func TestUnseal(t *testing.T) {
+	sealed, err := seal([]byte("admin"), "pass")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tampered := append([]byte(nil), sealed...)
+	tampered[len(tampered)-1] ^= 1
+
+	tests := []struct {
+		name       string
+		data       []byte
+		passphrase string
+		err        string
+	}{
+		{name: "sealed", data: sealed, passphrase: "pass"},
+		{name: "wrong passphrase", data: sealed, passphrase: "other", err: "wrong passphrase"},
+		{name: "tampered", data: tampered, passphrase: "pass", err: "wrong passphrase"},
+		{name: "no salt", data: sealed[:8], passphrase: "pass", err: "truncated"},
+		{name: "no nonce", data: sealed[:20], passphrase: "pass", err: "truncated"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plain, err := unseal(tt.data, tt.passphrase)
+			if tt.err == "" {
+				if err != nil {
+					t.Fatal(err)
+				}
+				if string(plain) != "admin" {
+					t.Errorf("unsealed %q", plain)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("err = %v, want %q", err, tt.err)
+			}
+		})
+	}
+
+	again, err := seal([]byte("admin"), "pass")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Equal(again, sealed) {
+		t.Error("sealing twice gave the same output")
+	}
+}
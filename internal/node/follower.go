@@ -0,0 +1,131 @@
+package node
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"voiui/internal/alert"
+	"voiui/internal/state"
+)
+
+// get sends an admin request to algod and returns the response status,
+// decoding the body into v when it is OK.
+func (n *Node) get(ctx context.Context, path string, v interface{}) (int, error) {
+	n.mu.Lock()
+	url, hc, token, timeout := n.url, n.hc, n.adminToken, n.timeout
+	n.mu.Unlock()
+
+	if token == "" {
+		return 0, ErrAdminLocked
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url+path, nil)
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to create request for %s", path)
+	}
+
+	req.Header.Set("X-Algo-API-Token", token)
+
+	resp, err := hc.Do(req)
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to get %s", path)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		return resp.StatusCode, errors.Wrapf(ErrUnauthorized, "failed to get %s", path)
+	}
+
+	if resp.StatusCode != http.StatusOK || v == nil {
+		return resp.StatusCode, nil
+	}
+
+	err = json.NewDecoder(resp.Body).Decode(v)
+	if err != nil {
+		return resp.StatusCode, errors.Wrapf(err, "failed to decode %s", path)
+	}
+
+	return resp.StatusCode, nil
+}
+
+type follower struct {
+	round      uint64
+	advancedAt time.Time
+}
+
+// checkFollower reads the sync round a follower node holds its ledger at
+// for a data pipeline like Conduit, which moves it forward as it consumes
+// each round's state delta.
+func (n *Node) checkFollower(ctx context.Context, f *follower, stall time.Duration) error {
+	var sync struct {
+		Round uint64 `json:"round"`
+	}
+
+	code, err := n.get(ctx, "/v2/ledger/sync", &sync)
+	if err != nil {
+		return err
+	}
+	if code != http.StatusOK {
+		return errors.Errorf("failed to get the sync round: %d, is algod in follower mode?", code)
+	}
+
+	now := time.Now()
+
+	if sync.Round != f.round || f.advancedAt.IsZero() {
+		f.round = sync.Round
+		f.advancedAt = now
+	}
+
+	code, err = n.get(ctx, fmt.Sprintf("/v2/deltas/%d?format=json", sync.Round), nil)
+	if err != nil {
+		return err
+	}
+
+	fs := state.Follower{
+		SyncRound:  sync.Round,
+		Delta:      code == http.StatusOK,
+		AdvancedAt: f.advancedAt,
+		UpdatedAt:  now,
+	}
+
+	stalled := now.Sub(f.advancedAt) > stall
+	n.alerts.Set(alert.PipelineStalled, stalled, fmt.Sprintf("the sync round has been %d since %s", sync.Round, f.advancedAt.Format("15:04")))
+
+	n.updates <- func(s *state.State) error {
+		s.Follower = fs
+		return nil
+	}
+
+	return nil
+}
+
+// WatchFollower follows a follower node's sync round every interval and
+// alerts when the pipeline has not moved it for stall.
+func (n *Node) WatchFollower(ctx context.Context, interval time.Duration, stall time.Duration) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	var f follower
+
+	for {
+		err := n.checkFollower(ctx, &f, stall)
+		if err != nil {
+			slog.Warn("follower check failed", "err", err)
+		}
+
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package node
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/algod"
+
+	"voiui/internal/alert"
+)
+
+const (
+	forkEvery = 20
+	// compare a little behind the tip so the reference has the block too
+	forkLag = 5
+	// mismatches in a row before alerting, a flaky reference is not a fork
+	forkConfirm = 2
+)
+
+func (n *Node) referenceClient() *algod.Client {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	return n.reference
+}
+
+// checkFork compares a recent block hash with the reference endpoint.
+func (n *Node) checkFork(ctx context.Context, src NodeSource, round uint64) {
+	ref := n.referenceClient()
+	if ref == nil || round <= forkLag || round%forkEvery != 0 {
+		return
+	}
+
+	r := round - forkLag
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	local, err := src.BlockHash(ctx, r)
+	if err != nil {
+		slog.Error("failed to check fork", "err", err)
+		return
+	}
+
+	resp, err := ref.GetBlockHash(r).Do(ctx)
+	if err != nil {
+		slog.Error("failed to get reference block hash", "round", r, "err", err)
+		return
+	}
+
+	if resp.Blockhash == local {
+		n.forkMismatches = 0
+		n.alerts.Set(alert.Diverged, false, fmt.Sprintf("block %d matches the reference", r))
+		return
+	}
+
+	n.forkMismatches++
+	slog.Warn("block hash differs from reference", "round", r, "hash", local, "reference", resp.Blockhash)
+
+	if n.forkMismatches >= forkConfirm {
+		n.alerts.Set(alert.Diverged, true, fmt.Sprintf("block %d hash %s, reference has %s", r, local, resp.Blockhash))
+	}
+}
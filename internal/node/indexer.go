@@ -0,0 +1,73 @@
+package node
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"voiui/internal/alert"
+	"voiui/internal/state"
+)
+
+// checkIndexer compares the indexer's round with the node's.
+func (n *Node) checkIndexer(ctx context.Context, maxLag uint64) {
+	ic, err := n.indexerClient()
+	if err != nil || ic == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, n.timeout)
+	defer cancel()
+
+	ix := state.Indexer{UpdatedAt: time.Now()}
+
+	health, err := ic.HealthCheck().Do(ctx)
+	if err != nil {
+		ix.Err = err.Error()
+	} else {
+		ix.Round = health.Round
+		if round := n.round.Load(); round > health.Round {
+			ix.Behind = round - health.Round
+		}
+
+		switch {
+		case !health.DbAvailable:
+			ix.Err = "the database is not available"
+		case health.IsMigrating:
+			ix.Err = "migrating"
+		case len(health.Errors) > 0:
+			ix.Err = strings.Join(health.Errors, ", ")
+		}
+	}
+
+	msg := fmt.Sprintf("the indexer is %d rounds behind the node", ix.Behind)
+	if ix.Err != "" {
+		msg = "the indexer is unhealthy: " + ix.Err
+	}
+	n.alerts.Set(alert.IndexerBehind, ix.Err != "" || ix.Behind > maxLag, msg)
+
+	n.updates <- func(s *state.State) error {
+		s.Indexer = ix
+		return nil
+	}
+}
+
+// WatchIndexer checks the profile's indexer every interval and alerts
+// while it is unhealthy or more than maxLag rounds behind the node.
+func (n *Node) WatchIndexer(ctx context.Context, interval time.Duration, maxLag uint64) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	for {
+		if !n.isDemo() && !n.isAccountsOnly() {
+			n.checkIndexer(ctx, maxLag)
+		}
+
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
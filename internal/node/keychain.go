@@ -0,0 +1,85 @@
+package node
+
+import (
+	"github.com/pkg/errors"
+
+	"voiui/internal/keychain"
+)
+
+// apiAccount and adminAccount name the keychain entries of url's tokens.
+func apiAccount(url string) string {
+	return url + " api"
+}
+
+func adminAccount(url string) string {
+	return url + " admin"
+}
+
+func KeychainTokens(url string) (string, string, error) {
+	apiToken, err := keychain.Get(apiAccount(url))
+	if err != nil && err != keychain.ErrNotFound {
+		return "", "", err
+	}
+
+	adminToken, err := keychain.Get(adminAccount(url))
+	if err != nil && err != keychain.ErrNotFound {
+		return "", "", err
+	}
+
+	return apiToken, adminToken, nil
+}
+
+// SetKeychainTokens stores the tokens of url that are not empty.
+func SetKeychainTokens(url string, apiToken string, adminToken string) error {
+	if apiToken != "" {
+		err := keychain.Set(apiAccount(url), apiToken)
+		if err != nil {
+			return err
+		}
+	}
+
+	if adminToken != "" {
+		err := keychain.Set(adminAccount(url), adminToken)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (n *Node) StoreTokens() (string, error) {
+	url := n.URL()
+
+	apiToken, adminToken := n.Tokens()
+	if apiToken == "" && adminToken == "" {
+		return "", errors.New("no tokens to store")
+	}
+
+	if n.AdminSealed() {
+		adminToken = ""
+	}
+
+	err := SetKeychainTokens(url, apiToken, adminToken)
+	if err != nil {
+		return "", err
+	}
+
+	return "Tokens saved to the OS keychain, -token is no longer needed for " + url, nil
+}
+
+func (n *Node) ForgetTokens() (string, error) {
+	url := n.URL()
+
+	err := keychain.Delete(apiAccount(url))
+	if err != nil {
+		return "", err
+	}
+
+	err = keychain.Delete(adminAccount(url))
+	if err != nil {
+		return "", err
+	}
+
+	return "Tokens removed from the OS keychain", nil
+}
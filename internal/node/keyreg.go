@@ -0,0 +1,203 @@
+package node
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/algorand/go-algorand-sdk/v2/client/kmd"
+	"github.com/algorand/go-algorand-sdk/v2/encoding/msgpack"
+	"github.com/algorand/go-algorand-sdk/v2/types"
+	"github.com/pkg/errors"
+)
+
+// bytes a signature adds to an encoded transaction
+const sigSize = 75
+
+// Keyreg builds a transaction that registers the installed key keyID
+// online, or takes its account offline.
+func (n *Node) Keyreg(ctx context.Context, keyID string, online bool) (types.Transaction, error) {
+	if n.isDemo() {
+		return types.Transaction{}, errors.New("cannot register keys in demo mode")
+	}
+
+	items, err := n.source().Participation(ctx)
+	if err != nil {
+		return types.Transaction{}, errors.Wrap(err, "failed to get participation keys")
+	}
+
+	var key *Participation
+	for i := range items {
+		if items[i].Id == keyID {
+			key = &items[i]
+		}
+	}
+
+	if key == nil {
+		return types.Transaction{}, errors.Errorf("unknown participation key: %s", keyID)
+	}
+
+	ac, _ := n.client()
+
+	params, err := ac.SuggestedParams().Do(ctx)
+	if err != nil {
+		return types.Transaction{}, errors.Wrap(err, "failed to get transaction parameters")
+	}
+
+	sender, err := types.DecodeAddress(key.Address)
+	if err != nil {
+		return types.Transaction{}, errors.Wrap(err, "invalid key address")
+	}
+
+	tx := types.Transaction{
+		Type: types.KeyRegistrationTx,
+		Header: types.Header{
+			Sender:     sender,
+			FirstValid: params.FirstRoundValid,
+			LastValid:  params.LastRoundValid,
+			GenesisID:  params.GenesisID,
+		},
+	}
+	copy(tx.GenesisHash[:], params.GenesisHash)
+
+	if online {
+		k := key.Key
+		copy(tx.VotePK[:], k.VoteParticipationKey)
+		copy(tx.SelectionPK[:], k.SelectionParticipationKey)
+		copy(tx.StateProofPK[:], k.StateProofKey)
+		tx.VoteFirst = types.Round(k.VoteFirstValid)
+		tx.VoteLast = types.Round(k.VoteLastValid)
+		tx.VoteKeyDilution = k.VoteKeyDilution
+	}
+
+	tx.Fee = params.Fee
+	if !params.FlatFee {
+		tx.Fee = params.Fee * types.MicroAlgos(len(msgpack.Encode(tx))+sigSize)
+	}
+	if tx.Fee < types.MicroAlgos(params.MinFee) {
+		tx.Fee = types.MicroAlgos(params.MinFee)
+	}
+
+	return tx, nil
+}
+
+// kmdClient connects to the kmd running next to the node, which goal
+// starts in the data directory.
+func (n *Node) kmdClient() (kmd.Client, error) {
+	path := n.DataDir()
+	if path == "" {
+		return kmd.Client{}, errors.New("signing with kmd needs the node's data directory")
+	}
+
+	dirs, _ := filepath.Glob(filepath.Join(path, "kmd-v*"))
+	for _, dir := range dirs {
+		addr, err := os.ReadFile(filepath.Join(dir, "kmd.net"))
+		if err != nil {
+			continue
+		}
+
+		token, err := os.ReadFile(filepath.Join(dir, "kmd.token"))
+		if err != nil {
+			return kmd.Client{}, errors.Wrap(err, "failed to read kmd token")
+		}
+
+		c, err := kmd.MakeClient("http://"+strings.TrimSpace(string(addr)), strings.TrimSpace(string(token)))
+		return c, errors.Wrap(err, "failed to make kmd client")
+	}
+
+	return kmd.Client{}, errors.New("kmd is not running, start it with goal kmd start")
+}
+
+// KMDWallets lists the wallets of the node's kmd.
+func (n *Node) KMDWallets() ([]string, error) {
+	c, err := n.kmdClient()
+	if err != nil {
+		return nil, err
+	}
+
+	wallets, err := c.ListWallets()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list kmd wallets")
+	}
+
+	names := make([]string, 0, len(wallets.Wallets))
+	for _, w := range wallets.Wallets {
+		names = append(names, w.Name)
+	}
+
+	return names, nil
+}
+
+// openWallet returns a handle to the named wallet. An empty name picks the
+// only wallet.
+func openWallet(c kmd.Client, wallet string, password string) (string, error) {
+	wallets, err := c.ListWallets()
+	if err != nil {
+		return "", errors.Wrap(err, "failed to list kmd wallets")
+	}
+
+	var id string
+	var names []string
+
+	for _, w := range wallets.Wallets {
+		names = append(names, w.Name)
+		if w.Name == wallet || (wallet == "" && len(wallets.Wallets) == 1) {
+			id = w.ID
+		}
+	}
+
+	if id == "" {
+		return "", errors.Errorf("choose a kmd wallet: %s", strings.Join(names, ", "))
+	}
+
+	h, err := c.InitWalletHandle(id, password)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to open kmd wallet")
+	}
+
+	return h.WalletHandleToken, nil
+}
+
+// KMDAccounts unlocks wallet to check the password and lists the addresses
+// it holds keys for.
+func (n *Node) KMDAccounts(wallet string, password string) ([]string, error) {
+	c, err := n.kmdClient()
+	if err != nil {
+		return nil, err
+	}
+
+	h, err := openWallet(c, wallet, password)
+	if err != nil {
+		return nil, err
+	}
+	defer c.ReleaseWalletHandle(h)
+
+	keys, err := c.ListKeys(h)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list wallet keys")
+	}
+
+	return keys.Addresses, nil
+}
+
+// SignKMD signs tx with the sender's key from a kmd wallet.
+func (n *Node) SignKMD(tx types.Transaction, wallet string, password string) ([]byte, error) {
+	c, err := n.kmdClient()
+	if err != nil {
+		return nil, err
+	}
+
+	h, err := openWallet(c, wallet, password)
+	if err != nil {
+		return nil, err
+	}
+	defer c.ReleaseWalletHandle(h)
+
+	signed, err := c.SignTransaction(h, password, tx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to sign with kmd")
+	}
+
+	return signed.SignedTransaction, nil
+}
@@ -0,0 +1,75 @@
+package node
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Metrics reads algod's Prometheus metrics, summing the series of each
+// metric over their labels. algod only serves them with
+// EnableMetricReporting set in its config.json.
+func (n *Node) Metrics(ctx context.Context) (map[string]float64, error) {
+	n.mu.Lock()
+	url, hc, timeout := n.url, n.hc, n.timeout
+	n.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url+"/metrics", nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create metrics request")
+	}
+
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to fetch metrics")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("failed to fetch metrics: %s", resp.Status)
+	}
+
+	metrics := map[string]float64{}
+
+	sc := bufio.NewScanner(resp.Body)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		name, rest, ok := strings.Cut(line, " ")
+		if i := strings.IndexByte(line, '{'); i >= 0 {
+			j := strings.LastIndexByte(line, '}')
+			if j < i {
+				continue
+			}
+			name, rest, ok = line[:i], strings.TrimSpace(line[j+1:]), true
+		}
+		fields := strings.Fields(rest)
+		if !ok || len(fields) == 0 {
+			continue
+		}
+
+		value, err := strconv.ParseFloat(fields[0], 64)
+		if err != nil {
+			continue
+		}
+
+		metrics[name] += value
+	}
+
+	err = sc.Err()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read metrics")
+	}
+
+	return metrics, nil
+}
@@ -0,0 +1,131 @@
+package node
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/indexer"
+	"github.com/pkg/errors"
+
+	"voiui/internal/state"
+)
+
+const maxIncidents = 10
+
+type outage struct {
+	since time.Time
+	round uint64
+}
+
+func (n *Node) indexerClient() (*indexer.Client, error) {
+	n.mu.Lock()
+	url, token, transport := n.indexerURL, n.indexerToken, n.transport
+	n.mu.Unlock()
+
+	if url == "" {
+		return nil, nil
+	}
+
+	ic, err := indexer.MakeClientWithTransport(url, token, nil, transport)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to make indexer client")
+	}
+
+	return ic, nil
+}
+
+func (n *Node) stakeAt(ctx context.Context, ic *indexer.Client, address string, round uint64) (uint64, error) {
+	if ic != nil {
+		_, account, err := ic.LookupAccountByID(address).Round(round).Do(ctx)
+		if err == nil {
+			if account.Status != "Online" {
+				return 0, nil
+			}
+			return account.Amount, nil
+		}
+		slog.Warn("failed to get historical stake, falling back to algod", "address", address, "err", err)
+	}
+
+	account, err := n.source().AccountInfo(ctx, address)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to get account info")
+	}
+
+	if account.Status != "Online" {
+		return 0, nil
+	}
+
+	return account.Amount, nil
+}
+
+func (n *Node) analyzeMissed(ctx context.Context, o outage, end time.Time, endRound uint64, accounts []string) {
+	err := func() error {
+		ic, err := n.indexerClient()
+		if err != nil {
+			return err
+		}
+
+		if ic != nil {
+			health, err := ic.HealthCheck().Do(ctx)
+			if err != nil {
+				slog.Warn("failed to get indexer health, using algod round", "err", err)
+			} else if health.Round > endRound {
+				endRound = health.Round
+			}
+		}
+
+		if endRound <= o.round {
+			return nil
+		}
+
+		ac, _ := n.client()
+
+		supply, err := ac.Supply().Do(ctx)
+		if err != nil {
+			return errors.Wrap(err, "failed to get supply")
+		}
+
+		if supply.OnlineMoney == 0 {
+			return errors.New("no online stake reported")
+		}
+
+		rounds := endRound - o.round
+
+		var expected float64
+
+		for _, address := range accounts {
+			stake, err := n.stakeAt(ctx, ic, address, o.round)
+			if err != nil {
+				return err
+			}
+
+			expected += float64(rounds) * float64(stake) / float64(supply.OnlineMoney)
+		}
+
+		incident := state.Incident{
+			Start:           o.since,
+			End:             end,
+			StartRound:      o.round,
+			EndRound:        endRound,
+			MissedProposals: expected,
+		}
+
+		n.alerts.Note(fmt.Sprintf("~%.2f proposals missed over %d rounds", expected, rounds))
+
+		n.updates <- func(s *state.State) error {
+			s.Incidents = append(s.Incidents, incident)
+			if len(s.Incidents) > maxIncidents {
+				s.Incidents = s.Incidents[len(s.Incidents)-maxIncidents:]
+			}
+			return nil
+		}
+
+		return nil
+	}()
+
+	if err != nil {
+		slog.Error("failed to analyze missed proposals", "err", err)
+	}
+}
@@ -0,0 +1,388 @@
+package node
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/common/models"
+	"github.com/pkg/errors"
+
+	"voiui/internal/alert"
+	"voiui/internal/state"
+)
+
+func (n *Node) poll(ctx context.Context) error {
+	src := n.source()
+
+	status, err := src.Status(ctx)
+	if IsUnauthorized(err) && n.adoptRotated() {
+		src = n.source()
+		status, err = src.Status(ctx)
+	}
+	if err != nil {
+		n.raiseStatus(ctx, err)
+		if IsUnauthorized(err) {
+			n.updates <- func(s *state.State) error {
+				s.Running = false
+				s.Unauthorized = true
+				return nil
+			}
+		}
+		return errors.Wrap(err, "failed to get status")
+	}
+
+	n.clearStatus(status)
+
+	// a gap since the last block seen is an outage, not a block time
+	n.lastBlockAt = time.Time{}
+
+	n.checkNetwork(ctx, src)
+	n.checkVersion(ctx, src)
+
+	round := status.LastRound
+	n.round.Store(round)
+
+	n.rc.Reset()
+
+	n.checkAway(ctx, src, round)
+	n.touchSeen(round)
+
+	if n.down != nil {
+		if !n.isDemo() && len(n.accounts) > 0 {
+			go n.analyzeMissed(ctx, *n.down, time.Now(), round, append([]string(nil), n.accounts...))
+		}
+		n.down = nil
+	}
+
+	consensus := consensusOf(status)
+
+	n.updates <- func(s *state.State) error {
+		s.Round = round
+		s.Running = true
+		s.Unauthorized = false
+		s.PollError = state.PollError{}
+		s.Consensus = consensus
+		return nil
+	}
+
+	for {
+		next, err := src.WaitForBlock(ctx, status.LastRound)
+		if IsUnauthorized(err) && n.adoptRotated() {
+			src = n.source()
+			continue
+		}
+		if err != nil {
+			n.raiseStatus(ctx, err)
+			unauthorized := IsUnauthorized(err)
+			n.updates <- func(s *state.State) error {
+				s.Running = false
+				s.Unauthorized = unauthorized
+				return nil
+			}
+			return errors.Wrap(err, "failed to get status")
+		}
+		status = next
+
+		round := status.LastRound
+		n.round.Store(round)
+		n.touchSeen(round)
+
+		n.clearStatus(status)
+
+		currBlockAt := time.Now()
+		consensus := consensusOf(status)
+
+		txns, err := src.Txns(ctx, round)
+		if err != nil {
+			slog.Debug("failed to count transactions", "round", round, "err", err)
+		}
+
+		if !n.lastBlockAt.IsZero() {
+			d := currBlockAt.Sub(n.lastBlockAt)
+			if n.blockTime == 0 {
+				n.blockTime = d
+			} else {
+				n.blockTime = (n.blockTime*49 + d) / 50
+			}
+
+			if err == nil {
+				n.activity.add(txns, d)
+			}
+		}
+		n.lastBlockAt = currBlockAt
+		blockTime := n.blockTime
+		activity := n.activity.state(txns)
+
+		if n.history != nil {
+			n.history.Block(round, currBlockAt, txns)
+		}
+
+		n.checkFork(ctx, src, round)
+		n.checkWatched(ctx, src, round)
+		n.checkPending(ctx, src)
+
+		n.updates <- func(s *state.State) error {
+			s.Round = round
+			s.Running = true
+			s.Consensus = consensus
+
+			if !s.CurrBlockAt.IsZero() {
+				s.PrevBlockDuration = currBlockAt.Sub(s.CurrBlockAt)
+			}
+			s.CurrBlockAt = currBlockAt
+			s.AvgBlockDuration = blockTime
+			s.Activity = activity
+			return nil
+		}
+
+		if n.isAccountsOnly() {
+			n.trackWatched(ctx, src, round)
+			continue
+		}
+
+		items, err := src.Participation(ctx)
+		if errors.Cause(err) == ErrAdminLocked {
+			// proposals need no admin token, keep tracking the accounts
+			// last seen with it
+			err = n.trackPerformance(ctx, src, round)
+			if err != nil {
+				slog.Error("failed to track performance", "err", err)
+			}
+			continue
+		}
+		if IsUnauthorized(err) && n.adoptRotated() {
+			src = n.source()
+			continue
+		}
+		if err != nil {
+			if IsUnauthorized(err) {
+				n.alerts.Set(alert.Unauthorized, true, "the admin token was rejected")
+				n.updates <- func(s *state.State) error {
+					s.Unauthorized = true
+					return nil
+				}
+			}
+			return err
+		}
+
+		n.accounts = n.accounts[:0]
+
+		var covered []Participation
+		var keys []state.Key
+
+		for _, item := range items {
+			if !contains(n.accounts, item.Address) {
+				n.accounts = append(n.accounts, item.Address)
+			}
+
+			if item.EffectiveLastValid != nil {
+				key := state.Key{
+					ID:        item.Id,
+					Address:   item.Address,
+					LastValid: *item.EffectiveLastValid,
+				}
+
+				if item.EffectiveFirstValid != nil {
+					key.FirstValid = *item.EffectiveFirstValid
+				}
+				if item.LastVote != nil {
+					key.LastVote = *item.LastVote
+				}
+				if item.LastBlockProposal != nil {
+					key.LastProposal = *item.LastBlockProposal
+				}
+
+				keys = append(keys, key)
+			}
+
+			if n.covers(item, round) {
+				covered = append(covered, item)
+			}
+		}
+
+		participation := n.participation(ctx, src, covered)
+
+		n.updates <- func(s *state.State) error {
+			s.Participation = participation
+			s.Keys = keys
+			return nil
+		}
+
+		switch participation {
+		case state.ParticipationNoKey:
+			n.alerts.Set(alert.NotParticipating, len(n.accounts) > 0, fmt.Sprintf("no registered key covers round %d", round+n.grace))
+		case state.ParticipationOffline:
+			n.alerts.Set(alert.NotParticipating, true, "a key is installed but its account is offline")
+		case state.ParticipationActive:
+			n.alerts.Set(alert.NotParticipating, false, "a participation key is active")
+		}
+
+		if len(n.accounts) > 0 && participation != state.ParticipationUnknown {
+			n.markUptime(state.OutageParticipation, participation == state.ParticipationActive)
+		}
+
+		n.renewKeys(ctx, src, items, round)
+
+		err = n.trackPerformance(ctx, src, round)
+		if err != nil {
+			slog.Error("failed to track performance", "err", err)
+		}
+	}
+}
+
+func (n *Node) Run(ctx context.Context) {
+	for {
+		pctx, cancel := context.WithCancel(ctx)
+
+		n.mu.Lock()
+		n.switched = false
+		n.cancelPoll = cancel
+		n.mu.Unlock()
+
+		err := n.poll(pctx)
+		cancel()
+
+		n.mu.Lock()
+		switched := n.switched
+		n.cancelPoll = nil
+		n.mu.Unlock()
+
+		if switched && ctx.Err() == nil {
+			n.down = nil
+			n.accounts = nil
+			n.stakes = nil
+			n.perfRound = 0
+			n.forkMismatches = 0
+			n.watchSeen = nil
+			n.stakeBase = nil
+			n.renewals = nil
+			n.blockTime = 0
+			n.round.Store(0)
+			n.rc.Reset()
+			continue
+		}
+
+		if err != nil {
+			slog.Error("failed to poll the node", "err", err)
+
+			if ctx.Err() == nil {
+				pe := pollError(err)
+				n.updates <- func(s *state.State) error {
+					s.PollError = pe
+					return nil
+				}
+			}
+
+			n.touchSeen(n.LastRound())
+
+			if n.down == nil {
+				n.down = &outage{
+					since: time.Now(),
+					round: n.LastRound(),
+				}
+			}
+		}
+
+		err = n.rc.Wait(ctx, n.updates)
+		if err != nil {
+			return
+		}
+	}
+}
+
+func (n *Node) checkNetwork(ctx context.Context, src NodeSource) {
+	g, err := src.Genesis(ctx)
+	if err != nil {
+		slog.Error("failed to detect network", "err", err)
+		return
+	}
+
+	n.mu.Lock()
+	expected := n.network
+	n.detected = g.Network
+	n.mu.Unlock()
+
+	if expected != "" && g.Network != expected {
+		slog.Warn("node is on an unexpected network", "network", g.Network, "expected", expected)
+	}
+
+	n.updates <- func(s *state.State) error {
+		s.Network = g.Network
+		s.GenesisID = g.Network + "-" + g.ID
+		s.ExpectedNetwork = expected
+		return nil
+	}
+}
+
+func (n *Node) checkVersion(ctx context.Context, src NodeSource) {
+	v, err := src.Version(ctx)
+	if err != nil {
+		slog.Error("failed to get node version", "err", err)
+		return
+	}
+
+	version := fmt.Sprintf("%d.%d.%d %s", v.Build.Major, v.Build.Minor, v.Build.BuildNumber, v.Build.Channel)
+
+	n.mu.Lock()
+	n.version = version
+	n.mu.Unlock()
+
+	n.updates <- func(s *state.State) error {
+		s.NodeVersion = version
+		return nil
+	}
+}
+
+func consensusOf(status models.NodeStatus) state.Consensus {
+	return state.Consensus{
+		Current:       status.LastVersion,
+		Next:          status.NextVersion,
+		NextRound:     status.NextVersionRound,
+		NextSupported: status.NextVersionSupported,
+		VoteBefore:    status.UpgradeNextProtocolVoteBefore,
+		VoteRounds:    status.UpgradeVoteRounds,
+		Votes:         status.UpgradeVotes,
+		YesVotes:      status.UpgradeYesVotes,
+		NoVotes:       status.UpgradeNoVotes,
+		VotesRequired: status.UpgradeVotesRequired,
+		NodeVote:      status.UpgradeNodeVote,
+	}
+}
+
+func (n *Node) raiseStatus(ctx context.Context, err error) {
+	if ctx.Err() != nil {
+		return
+	}
+
+	n.markUptime(state.OutageConnection, false)
+
+	if IsUnauthorized(err) {
+		n.alerts.Set(alert.Unauthorized, true, err.Error())
+	} else {
+		n.alerts.Set(alert.Down, true, err.Error())
+	}
+}
+
+func (n *Node) clearStatus(status models.NodeStatus) {
+	n.markUptime(state.OutageConnection, true)
+
+	n.alerts.Set(alert.Down, false, "node is reachable")
+	n.alerts.Set(alert.Unauthorized, false, "token accepted")
+
+	if status.CatchupTime > 0 {
+		n.alerts.Set(alert.Lag, true, fmt.Sprintf("catching up for %s", time.Duration(status.CatchupTime).Round(time.Second)))
+	} else {
+		n.alerts.Set(alert.Lag, false, "node is in sync")
+	}
+}
+
+func contains(items []string, item string) bool {
+	for _, i := range items {
+		if i == item {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,489 @@
+package node
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/common/models"
+	"github.com/algorand/go-algorand-sdk/v2/encoding/msgpack"
+	"github.com/algorand/go-algorand-sdk/v2/types"
+
+	"voiui/internal/state"
+)
+
+var (
+	ours   = types.Address{1}.String()
+	theirs = types.Address{2}.String()
+
+	voteKey  = []byte{1, 2, 3}
+	otherKey = []byte{4, 5, 6}
+)
+
+// fakeAlgod serves rounds start to last, the wait after last fails and so
+// ends a poll.
+type fakeAlgod struct {
+	start uint64
+	last  uint64
+	// admin is the admin token the participation endpoint takes, any when
+	// empty
+	admin string
+
+	keys   []Participation
+	status string
+	// registered is the vote key the accounts are online with, failed
+	// makes reading them fail
+	registered []byte
+	failed     bool
+	amount     uint64
+	online     uint64
+	txns       int
+	payout     uint64
+	propose    map[uint64]string
+
+	mu      sync.Mutex
+	fetched map[uint64]int
+	// installed are the key files posted to the participation endpoint
+	installed map[string]bool
+}
+
+type fakeBlock struct {
+	Block struct {
+		ProposerPayout uint64           `codec:"pp"`
+		Payset         []map[string]int `codec:"txns"`
+	} `codec:"block"`
+	Cert struct {
+		Prop struct {
+			OriginalProposer types.Address `codec:"oprop"`
+		} `codec:"prop"`
+	} `codec:"cert"`
+}
+
+func (f *fakeAlgod) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := r.URL.Path
+
+	switch {
+	case path == "/v2/status":
+		json.NewEncoder(w).Encode(models.NodeStatus{LastRound: f.start})
+	case strings.HasPrefix(path, "/v2/status/wait-for-block-after/"):
+		round, _ := strconv.ParseUint(strings.TrimPrefix(path, "/v2/status/wait-for-block-after/"), 10, 64)
+		if round >= f.last {
+			http.Error(w, `{"message":"done"}`, http.StatusInternalServerError)
+			return
+		}
+		json.NewEncoder(w).Encode(models.NodeStatus{LastRound: round + 1})
+	case path == "/genesis":
+		w.Write([]byte(`{"id":"v1.0","network":"voimain"}`))
+	case path == "/versions":
+		json.NewEncoder(w).Encode(models.Version{})
+	case path == "/v2/participation" && r.Method == "POST":
+		key, _ := io.ReadAll(r.Body)
+
+		f.mu.Lock()
+		defer f.mu.Unlock()
+
+		if f.installed[string(key)] {
+			http.Error(w, `{"message":"key already installed"}`, http.StatusBadRequest)
+			return
+		}
+		if f.installed == nil {
+			f.installed = map[string]bool{}
+		}
+		f.installed[string(key)] = true
+		json.NewEncoder(w).Encode(map[string]string{"partId": "id"})
+	case path == "/v2/participation":
+		if f.admin != "" && r.Header.Get("X-Algo-API-Token") != f.admin {
+			http.Error(w, "Invalid API Token", http.StatusUnauthorized)
+			return
+		}
+		json.NewEncoder(w).Encode(f.keys)
+	case strings.HasPrefix(path, "/v2/accounts/"):
+		if f.failed {
+			http.Error(w, `{"message":"failed"}`, http.StatusInternalServerError)
+			return
+		}
+		address := strings.TrimPrefix(path, "/v2/accounts/")
+		json.NewEncoder(w).Encode(models.Account{
+			Address:       address,
+			Status:        f.status,
+			Amount:        f.amount,
+			Participation: models.AccountParticipation{VoteParticipationKey: f.registered},
+		})
+	case path == "/v2/ledger/supply":
+		json.NewEncoder(w).Encode(models.SupplyResponse{OnlineMoney: f.online})
+	case path == "/v2/transactions/pending":
+		w.Write(msgpack.Encode(models.PendingTransactionsResponse{}))
+	case strings.HasPrefix(path, "/v2/blocks/"):
+		round, _ := strconv.ParseUint(strings.TrimPrefix(path, "/v2/blocks/"), 10, 64)
+
+		f.mu.Lock()
+		f.fetched[round]++
+		f.mu.Unlock()
+
+		var b fakeBlock
+		b.Block.Payset = make([]map[string]int, f.txns)
+		if address, ok := f.propose[round]; ok {
+			b.Block.ProposerPayout = f.payout
+			b.Cert.Prop.OriginalProposer, _ = types.DecodeAddress(address)
+		}
+
+		w.Write(msgpack.Encode(b))
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+type proposed struct {
+	round   uint64
+	address string
+	payout  uint64
+}
+
+type fakeProposals struct {
+	mu   sync.Mutex
+	seen []proposed
+}
+
+func (p *fakeProposals) Proposed(round uint64, address string, payout uint64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.seen = append(p.seen, proposed{round, address, payout})
+}
+
+// testNode returns a node watching f and the state its updates have left
+// so far.
+func testNode(t *testing.T, f *fakeAlgod, cfg Config) (*Node, func() state.State) {
+	t.Helper()
+
+	f.fetched = map[uint64]int{}
+
+	srv := httptest.NewServer(f)
+	t.Cleanup(srv.Close)
+
+	updates := make(chan state.Update)
+	store := state.NewStore(state.State{})
+	done := make(chan struct{})
+
+	go func() {
+		for {
+			select {
+			case u := <-updates:
+				store.Apply(u)
+			case <-done:
+				return
+			}
+		}
+	}()
+	t.Cleanup(func() { close(done) })
+
+	cfg.URL = srv.URL
+	if cfg.ConfigDir == "" {
+		cfg.ConfigDir = t.TempDir()
+	}
+	cfg.Timeout = 5 * time.Second
+
+	n, err := New(cfg, updates)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return n, func() state.State {
+		// every update sent before this one is applied
+		applied := make(chan struct{})
+		updates <- func(s *state.State) error {
+			close(applied)
+			return nil
+		}
+		<-applied
+
+		return store.Snapshot()
+	}
+}
+
+// runPoll polls until f runs out of rounds.
+func runPoll(t *testing.T, n *Node) {
+	t.Helper()
+
+	err := n.poll(context.Background())
+	if err == nil {
+		t.Fatal("poll returned without the failing wait")
+	}
+}
+
+// pollOnce runs a poll against f until it runs out of rounds and returns
+// the state it left.
+func pollOnce(t *testing.T, f *fakeAlgod, grace uint64, proposals Proposals) state.State {
+	t.Helper()
+
+	n, snapshot := testNode(t, f, Config{APIToken: "api", AdminToken: "admin", Grace: grace, Proposals: proposals})
+	runPoll(t, n)
+
+	return snapshot()
+}
+
+func key(address string, first uint64, last uint64) Participation {
+	return Participation{
+		Address:             address,
+		Id:                  address[:8],
+		EffectiveFirstValid: &first,
+		EffectiveLastValid:  &last,
+		Key:                 models.AccountParticipation{VoteParticipationKey: voteKey},
+	}
+}
+
+func TestCovers(t *testing.T) {
+	tests := []struct {
+		name        string
+		first, last uint64
+		round       uint64
+		grace       uint64
+		want        bool
+	}{
+		{"inside", 10, 1000, 500, 0, true},
+		{"first round", 10, 1000, 10, 0, true},
+		{"last round", 10, 1000, 1000, 0, true},
+		{"not yet valid", 10, 1000, 9, 0, false},
+		{"expired", 10, 1000, 1001, 0, false},
+		{"within grace", 10, 1000, 900, 100, true},
+		{"expires in grace", 10, 1000, 901, 100, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n := &Node{grace: tt.grace}
+			if got := n.covers(key(ours, tt.first, tt.last), tt.round); got != tt.want {
+				t.Errorf("covers(%d..%d, %d+%d) = %v, want %v", tt.first, tt.last, tt.round, tt.grace, got, tt.want)
+			}
+		})
+	}
+
+	if (&Node{}).covers(Participation{Address: ours}, 1) {
+		t.Error("a key without a registered range covers a round")
+	}
+}
+
+func TestPoll(t *testing.T) {
+	tests := []struct {
+		name       string
+		keys       []Participation
+		status     string
+		registered []byte
+		failed     bool
+		grace      uint64
+		want       state.Participation
+	}{
+		{"online key", []Participation{key(ours, 1, 1000)}, "Online", voteKey, false, 0, state.ParticipationActive},
+		{"offline account", []Participation{key(ours, 1, 1000)}, "Offline", nil, false, 0, state.ParticipationOffline},
+		{"online with another key", []Participation{key(ours, 1, 1000)}, "Online", otherKey, false, 0, state.ParticipationNoKey},
+		{"account read fails", []Participation{key(ours, 1, 1000)}, "Online", voteKey, true, 0, state.ParticipationUnknown},
+		{"expired key", []Participation{key(ours, 1, 50)}, "Online", voteKey, false, 0, state.ParticipationNoKey},
+		{"expires within grace", []Participation{key(ours, 1, 200)}, "Online", voteKey, false, 100, state.ParticipationNoKey},
+		{"future key", []Participation{key(ours, 500, 1000)}, "Online", voteKey, false, 0, state.ParticipationNoKey},
+		{"no keys", nil, "Online", voteKey, false, 0, state.ParticipationNoKey},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &fakeAlgod{start: 100, last: 103, keys: tt.keys, status: tt.status, registered: tt.registered, failed: tt.failed, txns: 3}
+
+			s := pollOnce(t, f, tt.grace, nil)
+
+			if s.Round != 103 {
+				t.Errorf("round = %d, want 103", s.Round)
+			}
+			if s.Network != "voimain" {
+				t.Errorf("network = %q, want voimain", s.Network)
+			}
+			if s.Running {
+				t.Error("still running after the wait failed")
+			}
+			if s.Participation != tt.want {
+				t.Errorf("participation = %v, want %v", s.Participation, tt.want)
+			}
+			if len(s.Keys) != len(tt.keys) {
+				t.Errorf("%d keys, want %d", len(s.Keys), len(tt.keys))
+			}
+		})
+	}
+}
+
+func TestProposer(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  string
+		propose map[uint64]string
+		want    []proposed
+	}{
+		{"ours", "Online", map[uint64]string{102: ours}, []proposed{{102, ours, 5000}}},
+		{"theirs", "Online", map[uint64]string{102: theirs}, nil},
+		{"several", "Online", map[uint64]string{101: ours, 102: theirs, 103: ours}, []proposed{{101, ours, 5000}, {103, ours, 5000}}},
+		{"offline account", "Offline", map[uint64]string{102: ours}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &fakeAlgod{
+				start:      100,
+				last:       103,
+				keys:       []Participation{key(ours, 1, 1000)},
+				status:     tt.status,
+				registered: voteKey,
+				amount:     1_000_000,
+				online:     10_000_000,
+				txns:       2,
+				payout:     5000,
+				propose:    tt.propose,
+			}
+
+			p := &fakeProposals{}
+			pollOnce(t, f, 0, p)
+
+			if len(p.seen) != len(tt.want) {
+				t.Fatalf("proposed %v, want %v", p.seen, tt.want)
+			}
+			for i := range tt.want {
+				if p.seen[i] != tt.want[i] {
+					t.Errorf("proposal %d = %v, want %v", i, p.seen[i], tt.want[i])
+				}
+			}
+
+			for round, count := range f.fetched {
+				if count != 1 {
+					t.Errorf("block %d fetched %d times, want once", round, count)
+				}
+			}
+		})
+	}
+}
+
+func TestSealedAdmin(t *testing.T) {
+	f := &fakeAlgod{
+		start:      100,
+		last:       103,
+		keys:       []Participation{key(ours, 1, 1000)},
+		status:     "Online",
+		registered: voteKey,
+		amount:     1_000_000,
+		online:     10_000_000,
+		payout:     5000,
+		propose:    map[uint64]string{102: ours, 105: ours},
+	}
+
+	t.Run("never unlocked", func(t *testing.T) {
+		p := &fakeProposals{}
+		n, snapshot := testNode(t, f, Config{APIToken: "api", Proposals: p})
+		runPoll(t, n)
+
+		s := snapshot()
+		if s.Round != 103 {
+			t.Errorf("round = %d, want 103", s.Round)
+		}
+		if s.Participation != state.ParticipationUnknown {
+			t.Errorf("participation = %v without the admin token", s.Participation)
+		}
+		if len(p.seen) != 0 {
+			t.Errorf("proposed %v without known accounts", p.seen)
+		}
+	})
+
+	t.Run("sealed after unlocking", func(t *testing.T) {
+		p := &fakeProposals{}
+		n, _ := testNode(t, f, Config{APIToken: "api", AdminToken: "admin", Proposals: p})
+		runPoll(t, n)
+
+		err := n.setTokens("api", "")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		f.start, f.last = 103, 106
+		defer func() { f.start, f.last = 100, 103 }()
+
+		runPoll(t, n)
+
+		want := []proposed{{102, ours, 5000}, {105, ours, 5000}}
+		if len(p.seen) != len(want) {
+			t.Fatalf("proposed %v, want %v", p.seen, want)
+		}
+		for i := range want {
+			if p.seen[i] != want[i] {
+				t.Errorf("proposal %d = %v, want %v", i, p.seen[i], want[i])
+			}
+		}
+	})
+}
+
+func TestRotateToken(t *testing.T) {
+	t.Run("no data directory", func(t *testing.T) {
+		wd := t.TempDir()
+
+		prev, _ := os.Getwd()
+		os.Chdir(wd)
+		defer os.Chdir(prev)
+
+		f := &fakeAlgod{start: 100, last: 103}
+		n, _ := testNode(t, f, Config{APIToken: "api", AdminToken: "admin", ConfigDir: t.TempDir()})
+
+		if _, err := n.RotateToken(); err == nil {
+			t.Error("rotated without a data directory")
+		}
+		if _, err := n.ReloadToken(); err == nil {
+			t.Error("reloaded without a data directory")
+		}
+
+		entries, _ := os.ReadDir(wd)
+		if len(entries) != 0 {
+			t.Errorf("wrote %v into the working directory", entries)
+		}
+	})
+
+	t.Run("kept until the node restarts", func(t *testing.T) {
+		dir := t.TempDir()
+		os.WriteFile(filepath.Join(dir, apiTokenFile), []byte("api"), 0o600)
+		os.WriteFile(filepath.Join(dir, adminTokenFile), []byte("old"), 0o600)
+
+		f := &fakeAlgod{start: 100, last: 103, admin: "old", keys: []Participation{key(ours, 1, 1000)}, status: "Online", registered: voteKey}
+		n, snapshot := testNode(t, f, Config{DataDir: dir, APIToken: "api", AdminToken: "old"})
+
+		_, err := n.RotateToken()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rotated, err := readToken(dir, adminTokenFile)
+		if err != nil || rotated == "old" {
+			t.Fatalf("token file holds %q, %v", rotated, err)
+		}
+
+		runPoll(t, n)
+
+		if _, admin := n.Tokens(); admin != "old" {
+			t.Errorf("switched to %q before the node restarted", admin)
+		}
+		if s := snapshot(); s.Participation != state.ParticipationActive {
+			t.Errorf("participation = %v with the old token", s.Participation)
+		}
+
+		// the node restarts with the rotated token
+		f.admin = rotated
+		runPoll(t, n)
+
+		if _, admin := n.Tokens(); admin != rotated {
+			t.Errorf("admin token = %q after the restart, want the rotated one", admin)
+		}
+		s := snapshot()
+		if s.Participation != state.ParticipationActive || s.Unauthorized {
+			t.Errorf("participation = %v, unauthorized %v after the restart", s.Participation, s.Unauthorized)
+		}
+	})
+}
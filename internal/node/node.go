@@ -0,0 +1,479 @@
+package node
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/algod"
+	"github.com/pkg/errors"
+
+	"voiui/internal/alert"
+	"voiui/internal/state"
+)
+
+type Config struct {
+	URL     string
+	DataDir string
+
+	APIToken   string
+	AdminToken string
+
+	ConfigDir  string
+	ElevateFor time.Duration
+
+	// StakeChange is the change of a watched account's online stake, in
+	// percent, that sends a notification; zero disables it.
+	StakeChange float64
+
+	// Grace is how many rounds past the current one a key must cover to
+	// count as participating.
+	Grace uint64
+
+	Renew RenewConfig
+
+	RetryMin   time.Duration
+	RetryMax   time.Duration
+	MaxRetries int
+
+	// Timeout bounds each request to algod, zero means ten seconds.
+	Timeout time.Duration
+
+	IndexerURL   string
+	IndexerToken string
+
+	TLS TLSConfig
+	SSH SSHConfig
+
+	Demo bool
+
+	// AccountsOnly tracks the watched accounts instead of the participation
+	// keys, for public endpoints without an admin API.
+	AccountsOnly bool
+
+	// Network is the expected network name, e.g. voimain; empty accepts any.
+	Network string
+
+	// Reference is an independent algod used to detect forks.
+	Reference      string
+	ReferenceToken string
+
+	Notifier  alert.Notifier
+	History   History
+	Proposals Proposals
+}
+
+// History receives what the node observes, to keep it across restarts.
+type History interface {
+	Block(round uint64, at time.Time, txns int)
+	Proposal(round uint64, address string, at time.Time)
+	Outage(o state.Outage)
+}
+
+// Proposals is told about blocks won by the tracked accounts.
+type Proposals interface {
+	Proposed(round uint64, address string, payout uint64)
+}
+
+type Node struct {
+	url      string
+	path     string
+	endpoint string
+
+	configDir   string
+	elevateFor  time.Duration
+	stakeChange float64
+	renew       RenewConfig
+	grace       uint64
+
+	transport http.RoundTripper
+	hc        *http.Client
+	tunnel    *Tunnel
+	timeout   time.Duration
+
+	mu         sync.Mutex
+	apiToken   string
+	adminToken string
+	ac         *algod.Client
+	passphrase string
+	lockTimer  *time.Timer
+	// rotated is a new admin token algod loads on its next restart.
+	rotated string
+
+	updates chan<- state.Update
+
+	rc *Reconnect
+
+	round atomic.Uint64
+
+	indexerURL   string
+	indexerToken string
+
+	down     *outage
+	accounts []string
+
+	demo         *Demo
+	accountsOnly bool
+
+	network  string
+	detected string
+	version  string
+
+	reference      *algod.Client
+	forkMismatches int
+
+	watched   []state.Account
+	watchSeen map[string]state.Account
+	stakeBase map[string]uint64
+
+	renewals map[string]*renewal
+
+	perf        *perfLog
+	rewards     *rewardsLog
+	uptime      *uptimeLog
+	stakes      map[string]uint64
+	onlineStake uint64
+	stakesRound uint64
+	perfRound   uint64
+
+	lastBlockAt time.Time
+	blockTime   time.Duration
+	activity    activity
+
+	alerts    *alert.Correlator
+	history   History
+	proposals Proposals
+
+	prevSeen  lastSeen
+	seenSaved time.Time
+	digested  bool
+
+	cancelPoll context.CancelFunc
+	switched   bool
+}
+
+func New(cfg Config, updates chan<- state.Update) (*Node, error) {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 10 * time.Second
+	}
+
+	n := &Node{
+		configDir:   cfg.ConfigDir,
+		elevateFor:  cfg.ElevateFor,
+		stakeChange: cfg.StakeChange,
+		renew:       cfg.Renew,
+		grace:       cfg.Grace,
+		timeout:     cfg.Timeout,
+		updates:     updates,
+		rc:          NewReconnect(cfg.RetryMin, cfg.RetryMax, cfg.MaxRetries),
+		alerts:      alert.NewCorrelator(cfg.Notifier, updates),
+		history:     cfg.History,
+		proposals:   cfg.Proposals,
+	}
+
+	err := n.apply(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.ConfigDir != "" {
+		n.perf, err = loadPerf(cfg.ConfigDir)
+		if err != nil {
+			return nil, err
+		}
+
+		n.rewards, err = loadRewards(cfg.ConfigDir)
+		if err != nil {
+			return nil, err
+		}
+
+		n.uptime, err = loadUptime(cfg.ConfigDir)
+		if err != nil {
+			return nil, err
+		}
+
+		n.prevSeen, err = loadSeen(cfg.ConfigDir)
+		if err != nil {
+			slog.Error("failed to load last seen", "err", err)
+		}
+	}
+
+	return n, nil
+}
+
+func (n *Node) apply(cfg Config) error {
+	transport, err := cfg.TLS.Transport()
+	if err != nil {
+		return err
+	}
+
+	var tunnel *Tunnel
+	if cfg.SSH.Enabled() {
+		tunnel = NewTunnel(cfg.SSH)
+
+		t, ok := transport.(*http.Transport)
+		if !ok {
+			t = http.DefaultTransport.(*http.Transport).Clone()
+		}
+		t.Proxy = nil
+		t.DialContext = tunnel.DialContext
+		transport = t
+	}
+
+	var demo *Demo
+	if cfg.Demo {
+		demo = NewDemo()
+	}
+
+	var reference *algod.Client
+	if cfg.Reference != "" {
+		reference, err = algod.MakeClient(cfg.Reference, cfg.ReferenceToken)
+		if err != nil {
+			return errors.Wrap(err, "failed to make reference client")
+		}
+	}
+
+	n.mu.Lock()
+	n.url = cfg.URL
+	n.path = cfg.DataDir
+	n.endpoint = Endpoint(cfg.URL, cfg.DataDir, cfg.SSH)
+	n.indexerURL = cfg.IndexerURL
+	n.indexerToken = cfg.IndexerToken
+	n.transport = transport
+	n.hc = &http.Client{Transport: transport}
+	prev := n.tunnel
+	n.tunnel = tunnel
+	n.demo = demo
+	n.accountsOnly = cfg.AccountsOnly
+	n.reference = reference
+	n.network = cfg.Network
+	n.version = ""
+	n.detected = ""
+	n.mu.Unlock()
+
+	if prev != nil {
+		prev.Close()
+	}
+
+	return n.setTokens(cfg.APIToken, cfg.AdminToken)
+}
+
+// Switch points the node at another endpoint and restarts monitoring.
+// Only the endpoint, token, indexer, TLS, SSH, reference and demo settings
+// of cfg are used.
+func (n *Node) Switch(cfg Config) error {
+	err := n.apply(cfg)
+	if err != nil {
+		return err
+	}
+
+	n.mu.Lock()
+	n.switched = true
+	n.rotated = ""
+	cancel := n.cancelPoll
+	n.mu.Unlock()
+
+	if cancel != nil {
+		cancel()
+	}
+
+	n.alerts.Reset()
+
+	if n.uptime != nil {
+		n.uptime.forget()
+	}
+
+	if n.AdminSealed() {
+		err = n.LockAdmin()
+		if err != nil {
+			return err
+		}
+	} else {
+		n.forgetElevation()
+	}
+
+	n.updates <- func(s *state.State) error {
+		s.Running = false
+		s.Round = 0
+		s.Participation = state.ParticipationUnknown
+		s.Unauthorized = false
+		s.PollError = state.PollError{}
+		s.PrevBlockDuration = 0
+		s.CurrBlockAt = time.Time{}
+		s.AvgBlockDuration = 0
+		s.Keys = nil
+		s.Incidents = nil
+		s.Performance = state.Performance{}
+		s.Rewards = state.Rewards{}
+		s.Pending = state.Pending{}
+		s.KMD = state.KMD{}
+		s.Signing = state.Signing{}
+		s.Network = ""
+		s.GenesisID = ""
+		return nil
+	}
+
+	n.rc.RetryNow()
+
+	return nil
+}
+
+func IsUnauthorized(err error) bool {
+	return err != nil && (errors.Cause(err) == ErrUnauthorized || strings.Contains(err.Error(), "HTTP 401"))
+}
+
+func (n *Node) URL() string {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	return n.url
+}
+
+func (n *Node) DataDir() string {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	return n.path
+}
+
+func (n *Node) Alerts() *alert.Correlator {
+	return n.alerts
+}
+
+// Network is the network the node reported in its genesis, once known.
+func (n *Node) Network() string {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	return n.detected
+}
+
+// Version is the algod build version, once known.
+func (n *Node) Version() string {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	return n.version
+}
+
+func (n *Node) LastRound() uint64 {
+	return n.round.Load()
+}
+
+func (n *Node) SubmitRaw(ctx context.Context, raw []byte) (string, error) {
+	if n.isDemo() {
+		return "", errors.New("cannot submit transactions in demo mode")
+	}
+
+	ac, _ := n.client()
+
+	return ac.SendRawTransaction(raw).Do(ctx)
+}
+
+// WaitConfirmed polls until the submitted transaction txid is in a block
+// and returns its round.
+func (n *Node) WaitConfirmed(ctx context.Context, txid string) (uint64, error) {
+	ac, _ := n.client()
+
+	t := time.NewTicker(2 * time.Second)
+	defer t.Stop()
+
+	for {
+		info, _, err := ac.PendingTransactionInformation(txid).Do(ctx)
+		if err != nil {
+			return 0, errors.Wrap(err, "failed to check the transaction")
+		}
+
+		if info.ConfirmedRound > 0 {
+			return info.ConfirmedRound, nil
+		}
+
+		if info.PoolError != "" {
+			return 0, errors.Errorf("the transaction was dropped: %s", info.PoolError)
+		}
+
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			return 0, errors.Wrap(ctx.Err(), "the transaction is not confirmed yet")
+		}
+	}
+}
+
+func (n *Node) RetryNow() {
+	n.rc.RetryNow()
+}
+
+func (n *Node) client() (*algod.Client, string) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	return n.ac, n.adminToken
+}
+
+func (n *Node) isDemo() bool {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	return n.demo != nil
+}
+
+func (n *Node) isAccountsOnly() bool {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	return n.accountsOnly
+}
+
+func (n *Node) source() NodeSource {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.demo != nil {
+		return n.demo
+	}
+
+	return &algodSource{
+		url:        n.url,
+		hc:         n.hc,
+		ac:         n.ac,
+		adminToken: n.adminToken,
+		timeout:    n.timeout,
+	}
+}
+
+func (n *Node) Tokens() (string, string) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	return n.apiToken, n.adminToken
+}
+
+func (n *Node) setTokens(apiToken string, adminToken string) error {
+	pollToken := apiToken
+	if pollToken == "" {
+		pollToken = adminToken
+	}
+
+	n.mu.Lock()
+	url, transport := n.url, n.transport
+	n.mu.Unlock()
+
+	ac, err := algod.MakeClientWithTransport(url, pollToken, nil, transport)
+	if err != nil {
+		return errors.Wrap(err, "failed to make algod client")
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.apiToken = apiToken
+	n.adminToken = adminToken
+	n.ac = ac
+
+	return nil
+}
@@ -0,0 +1,61 @@
+package node
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/common/models"
+
+	"voiui/internal/state"
+)
+
+// covers tells whether the registered range of key includes round plus the
+// grace margin.
+func (n *Node) covers(key Participation, round uint64) bool {
+	if key.EffectiveFirstValid == nil || key.EffectiveLastValid == nil {
+		return false
+	}
+
+	return *key.EffectiveFirstValid <= round && *key.EffectiveLastValid >= round+n.grace
+}
+
+// participation checks the accounts of covering keys, a key only votes
+// while its account is online with that very key registered.
+func (n *Node) participation(ctx context.Context, src NodeSource, covered []Participation) state.Participation {
+	if len(covered) == 0 {
+		return state.ParticipationNoKey
+	}
+
+	accounts := map[string]models.Account{}
+	online := false
+
+	for _, item := range covered {
+		account, ok := accounts[item.Address]
+		if !ok {
+			var err error
+			account, err = src.AccountInfo(ctx, item.Address)
+			if err != nil {
+				slog.Error("failed to check account", "address", item.Address, "err", err)
+				// rather not raise or clear an alert on a failed read
+				return state.ParticipationUnknown
+			}
+			accounts[item.Address] = account
+		}
+
+		if account.Status != "Online" {
+			continue
+		}
+		online = true
+
+		if bytes.Equal(account.Participation.VoteParticipationKey, item.Key.VoteParticipationKey) {
+			return state.ParticipationActive
+		}
+	}
+
+	if online {
+		return state.ParticipationNoKey
+	}
+
+	return state.ParticipationOffline
+}
@@ -0,0 +1,67 @@
+package node
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/algorand/go-algorand-sdk/v2/crypto"
+	"github.com/algorand/go-algorand-sdk/v2/types"
+
+	"voiui/internal/state"
+)
+
+// how many pool transactions are listed, the total is always reported
+const maxPending = 20
+
+func describe(tx types.Transaction) string {
+	switch tx.Type {
+	case types.PaymentTx:
+		return fmt.Sprintf("%.6f VOI to %s", float64(tx.Amount)/1e6, tx.Receiver)
+	case types.KeyRegistrationTx:
+		if tx.VotePK == (types.VotePK{}) {
+			return "go offline"
+		}
+		return fmt.Sprintf("go online for rounds %d–%d", tx.VoteFirst, tx.VoteLast)
+	case types.AssetTransferTx:
+		return fmt.Sprintf("%d of asset %d to %s", tx.AssetAmount, tx.XferAsset, tx.AssetReceiver)
+	case types.ApplicationCallTx:
+		return fmt.Sprintf("call app %d", tx.ApplicationID)
+	default:
+		return string(tx.Type)
+	}
+}
+
+// checkPending lists the transaction pool, marking transactions sent by
+// the node's or the watched accounts.
+func (n *Node) checkPending(ctx context.Context, src NodeSource) {
+	total, stxns, err := src.PendingTxns(ctx, maxPending)
+	if err != nil {
+		slog.Error("failed to get pending transactions", "err", err)
+		return
+	}
+
+	n.mu.Lock()
+	watched := append([]state.Account(nil), n.watched...)
+	n.mu.Unlock()
+
+	txns := make([]state.PendingTxn, 0, len(stxns))
+
+	for _, stxn := range stxns {
+		tx := stxn.Txn
+		sender := tx.Sender.String()
+
+		txns = append(txns, state.PendingTxn{
+			ID:          crypto.GetTxID(tx),
+			Type:        string(tx.Type),
+			Sender:      sender,
+			Description: describe(tx),
+			Ours:        contains(n.accounts, sender) || watching(watched, sender),
+		})
+	}
+
+	n.updates <- func(s *state.State) error {
+		s.Pending = state.Pending{Total: total, Txns: txns}
+		return nil
+	}
+}
@@ -0,0 +1,280 @@
+package node
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"voiui/internal/alert"
+	"voiui/internal/state"
+)
+
+const (
+	perfFile = "perf.json"
+
+	perfWindow       = 30
+	perfRecentWindow = 7
+
+	// below this many expected proposals the index is mostly noise
+	perfMinExpected = 10
+	// flag underperformance that luck explains less than 1% of the time
+	perfSignificance = 0.01
+
+	stakeRefreshRounds = 100
+	maxCatchupRounds   = 20
+)
+
+type perfDay struct {
+	Day      string  `json:"day"`
+	Expected float64 `json:"expected"`
+	Actual   int     `json:"actual"`
+}
+
+type perfLog struct {
+	mu   sync.Mutex
+	path string
+
+	Accounts map[string][]perfDay `json:"accounts"`
+}
+
+func loadPerf(dir string) (*perfLog, error) {
+	p := &perfLog{
+		path:     filepath.Join(dir, perfFile),
+		Accounts: map[string][]perfDay{},
+	}
+
+	data, err := os.ReadFile(p.path)
+	if os.IsNotExist(err) {
+		return p, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read performance log")
+	}
+
+	err = json.Unmarshal(data, p)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse performance log")
+	}
+
+	if p.Accounts == nil {
+		p.Accounts = map[string][]perfDay{}
+	}
+
+	return p, nil
+}
+
+func (p *perfLog) save() error {
+	p.mu.Lock()
+	data, err := json.Marshal(p)
+	p.mu.Unlock()
+
+	if err != nil {
+		return errors.Wrap(err, "failed to encode performance log")
+	}
+
+	return WriteFileAtomic(filepath.Dir(p.path), perfFile, data)
+}
+
+func (p *perfLog) add(address string, now time.Time, expected float64, proposed bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	day := now.UTC().Format("2006-01-02")
+	cutoff := now.UTC().AddDate(0, 0, -perfWindow).Format("2006-01-02")
+
+	days := p.Accounts[address]
+	for len(days) > 0 && days[0].Day <= cutoff {
+		days = days[1:]
+	}
+
+	if len(days) == 0 || days[len(days)-1].Day != day {
+		days = append(days, perfDay{Day: day})
+	}
+
+	last := &days[len(days)-1]
+	last.Expected += expected
+	if proposed {
+		last.Actual++
+	}
+
+	p.Accounts[address] = days
+}
+
+func (p *perfLog) score(accounts []string, now time.Time) state.Performance {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	cutoff := now.UTC().AddDate(0, 0, -perfWindow).Format("2006-01-02")
+	recent := now.UTC().AddDate(0, 0, -perfRecentWindow).Format("2006-01-02")
+
+	var perf state.Performance
+	var recentActual int
+
+	for _, address := range accounts {
+		for _, d := range p.Accounts[address] {
+			if d.Day <= cutoff {
+				continue
+			}
+
+			perf.Expected += d.Expected
+			perf.Actual += d.Actual
+
+			if d.Day > recent {
+				perf.RecentExpected += d.Expected
+				recentActual += d.Actual
+			}
+		}
+	}
+
+	if perf.Expected > 0 {
+		perf.Index = float64(perf.Actual) / perf.Expected
+	}
+
+	if perf.RecentExpected > 0 {
+		perf.RecentIndex = float64(recentActual) / perf.RecentExpected
+	}
+
+	perf.PValue = poissonCDF(perf.Actual, perf.Expected)
+	perf.Underperforming = perf.Expected >= perfMinExpected && perf.PValue < perfSignificance
+
+	return perf
+}
+
+// poissonCDF is P(X <= k) for X ~ Poisson(lambda).
+func poissonCDF(k int, lambda float64) float64 {
+	if lambda <= 0 {
+		return 1
+	}
+
+	var p float64
+	for i := 0; i <= k; i++ {
+		lg, _ := math.Lgamma(float64(i + 1))
+		p += math.Exp(-lambda + float64(i)*math.Log(lambda) - lg)
+	}
+
+	return math.Min(p, 1)
+}
+
+func (n *Node) refreshStakes(ctx context.Context, src NodeSource, round uint64) error {
+	online, err := src.OnlineStake(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to get online stake")
+	}
+
+	stakes := map[string]uint64{}
+	now := time.Now()
+
+	for _, address := range n.accounts {
+		account, err := src.AccountInfo(ctx, address)
+		if err != nil {
+			return errors.Wrapf(err, "failed to get account %s", address)
+		}
+
+		n.rewards.observe(address, now, account.Rewards)
+
+		if account.Status == "Online" {
+			stakes[address] = account.Amount
+		}
+	}
+
+	n.stakes = stakes
+	n.onlineStake = online
+	n.stakesRound = round
+
+	return nil
+}
+
+func (n *Node) trackPerformance(ctx context.Context, src NodeSource, round uint64) error {
+	if n.perf == nil || len(n.accounts) == 0 {
+		return nil
+	}
+
+	if n.stakes == nil || round >= n.stakesRound+stakeRefreshRounds {
+		err := n.refreshStakes(ctx, src, round)
+		if err != nil {
+			return err
+		}
+
+		err = n.perf.save()
+		if err != nil {
+			return err
+		}
+
+		err = n.rewards.save()
+		if err != nil {
+			return err
+		}
+	}
+
+	if n.onlineStake == 0 || len(n.stakes) == 0 {
+		return nil
+	}
+
+	from := n.perfRound + 1
+	if n.perfRound == 0 || round-n.perfRound > maxCatchupRounds {
+		from = round
+	}
+
+	now := time.Now()
+
+	for r := from; r <= round; r++ {
+		proposer, payout, err := src.Proposer(ctx, r)
+		if err != nil {
+			return err
+		}
+
+		if _, ok := n.stakes[proposer]; ok && payout > 0 {
+			n.rewards.payout(proposer, now, payout)
+		}
+
+		for address, stake := range n.stakes {
+			n.perf.add(address, now, float64(stake)/float64(n.onlineStake), proposer == address)
+		}
+
+		if _, ok := n.stakes[proposer]; ok && n.history != nil {
+			n.history.Proposal(r, proposer, now)
+		}
+
+		if _, ok := n.stakes[proposer]; ok && n.proposals != nil {
+			n.proposals.Proposed(r, proposer, payout)
+		}
+
+		n.perfRound = r
+	}
+
+	accounts := make([]string, 0, len(n.stakes))
+	for address := range n.stakes {
+		accounts = append(accounts, address)
+	}
+
+	perf := n.perf.score(accounts, now)
+
+	var stake uint64
+	for _, s := range n.stakes {
+		stake += s
+	}
+
+	rewards := n.rewards.totals(accounts, stake, now)
+
+	if n.blockTime > 0 {
+		perf.PerDay = float64(stake) / float64(n.onlineStake) * float64(24*time.Hour) / float64(n.blockTime)
+	}
+
+	n.alerts.Set(alert.Underperforming, perf.Underperforming,
+		fmt.Sprintf("%d proposals against %.1f expected, %.2f%% likely by chance", perf.Actual, perf.Expected, perf.PValue*100))
+
+	n.updates <- func(s *state.State) error {
+		s.Performance = perf
+		s.Rewards = rewards
+		return nil
+	}
+
+	return nil
+}
@@ -0,0 +1,40 @@
+package node
+
+import (
+	"context"
+	"crypto/x509"
+	"net"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"voiui/internal/state"
+)
+
+// pollError sorts err so that the window can tell a wrong token from a node
+// that is down, slow or behind a bad certificate.
+func pollError(err error) state.PollError {
+	var (
+		dns       *net.DNSError
+		netErr    net.Error
+		authority x509.UnknownAuthorityError
+		invalid   x509.CertificateInvalidError
+		hostname  x509.HostnameError
+	)
+
+	kind := state.ErrorOther
+	switch {
+	case IsUnauthorized(err):
+		kind = state.ErrorUnauthorized
+	case errors.Is(err, errRefused):
+		kind = state.ErrorRefused
+	case errors.As(err, &dns):
+		kind = state.ErrorDNS
+	case errors.As(err, &authority), errors.As(err, &invalid), errors.As(err, &hostname):
+		kind = state.ErrorTLS
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+		kind = state.ErrorTimeout
+	}
+
+	return state.PollError{Kind: kind, Message: err.Error(), At: time.Now()}
+}
@@ -0,0 +1,77 @@
+package node
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"voiui/internal/alert"
+	"voiui/internal/state"
+)
+
+// probe times a request to algod's health endpoint, which is cheap enough
+// to send often even to a remote node.
+func (n *Node) probe(ctx context.Context) (time.Duration, error) {
+	n.mu.Lock()
+	url, hc, timeout := n.url, n.hc, n.timeout
+	n.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url+"/health", nil)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to create health request")
+	}
+
+	start := time.Now()
+
+	resp, err := hc.Do(req)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to do health request")
+	}
+	resp.Body.Close()
+
+	rtt := time.Since(start)
+
+	if resp.StatusCode >= 400 {
+		return 0, errors.Errorf("health check failed: %s", resp.Status)
+	}
+
+	return rtt, nil
+}
+
+// Probe measures the round trip to algod every interval and alerts while
+// it takes longer than slow; zero slow only measures.
+func (n *Node) Probe(ctx context.Context, interval time.Duration, slow time.Duration) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	for {
+		if !n.isDemo() {
+			rtt, err := n.probe(ctx)
+			if err != nil {
+				slog.Debug("health probe failed", "err", err)
+			} else {
+				late := slow > 0 && rtt > slow
+				n.alerts.Set(alert.SlowNode, late, fmt.Sprintf("algod took %s to answer, more than %s", rtt.Round(time.Millisecond), slow))
+
+				n.updates <- func(s *state.State) error {
+					s.RTT = rtt
+					s.RTTSlow = late
+					return nil
+				}
+			}
+		}
+
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package node
+
+import (
+	"context"
+	"math/rand"
+	"time"
+
+	"voiui/internal/state"
+)
+
+type Reconnect struct {
+	min time.Duration
+	max time.Duration
+
+	maxAttempts int
+	attempt     int
+
+	retry chan struct{}
+}
+
+func NewReconnect(min, max time.Duration, maxAttempts int) *Reconnect {
+	return &Reconnect{
+		min:         min,
+		max:         max,
+		maxAttempts: maxAttempts,
+		retry:       make(chan struct{}, 1),
+	}
+}
+
+func (r *Reconnect) Reset() {
+	r.attempt = 0
+}
+
+func (r *Reconnect) delay() time.Duration {
+	d := r.max
+	if r.attempt < 32 {
+		if exp := r.min << r.attempt; exp > 0 && exp < r.max {
+			d = exp
+		}
+	}
+
+	half := d / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+func (r *Reconnect) exhausted() bool {
+	return r.maxAttempts > 0 && r.attempt >= r.maxAttempts
+}
+
+func (r *Reconnect) RetryNow() {
+	select {
+	case r.retry <- struct{}{}:
+	default:
+	}
+}
+
+func (r *Reconnect) Wait(ctx context.Context, updates chan<- state.Update) error {
+	if r.exhausted() {
+		updates <- func(s *state.State) error {
+			s.RetryAt = time.Time{}
+			s.RetryStopped = true
+			return nil
+		}
+
+		select {
+		case <-r.retry:
+			r.Reset()
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	} else {
+		d := r.delay()
+		r.attempt++
+
+		at := time.Now().Add(d)
+		attempt := r.attempt
+		max := r.maxAttempts
+
+		updates <- func(s *state.State) error {
+			s.RetryAt = at
+			s.RetryAttempt = attempt
+			s.RetryMax = max
+			s.RetryStopped = false
+			return nil
+		}
+
+		t := time.NewTimer(d)
+		defer t.Stop()
+
+		select {
+		case <-t.C:
+		case <-r.retry:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	updates <- func(s *state.State) error {
+		s.RetryAt = time.Time{}
+		s.RetryStopped = false
+		return nil
+	}
+
+	return nil
+}
@@ -0,0 +1,9 @@
+//go:build !windows
+
+package node
+
+import (
+	"syscall"
+)
+
+var errRefused error = syscall.ECONNREFUSED
@@ -0,0 +1,7 @@
+package node
+
+import (
+	"golang.org/x/sys/windows"
+)
+
+var errRefused error = windows.WSAECONNREFUSED
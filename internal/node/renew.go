@@ -0,0 +1,129 @@
+package node
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// ask algod again if a requested key has not shown up after this many
+// rounds, generation takes minutes
+const renewRetryRounds = 1000
+
+// RenewConfig has replacement participation keys generated before the
+// current ones expire.
+type RenewConfig struct {
+	// For is how long new keys are valid; zero disables renewal.
+	For time.Duration
+	// Wallet and Password register new keys through kmd. Without them the
+	// operator is asked to register the key.
+	Wallet   string
+	Password string
+}
+
+// renewal follows the replacement of one account's key.
+type renewal struct {
+	requested uint64
+	handled   string
+}
+
+// renewKeys generates a replacement for keys about to expire and, once
+// it is installed, registers it or asks the operator to.
+func (n *Node) renewKeys(ctx context.Context, src NodeSource, items []Participation, round uint64) {
+	if n.renew.For <= 0 {
+		return
+	}
+
+	if n.renewals == nil {
+		n.renewals = map[string]*renewal{}
+	}
+
+	newest := map[string]Participation{}
+	for _, item := range items {
+		if cur, ok := newest[item.Address]; !ok || item.Key.VoteLastValid > cur.Key.VoteLastValid {
+			newest[item.Address] = item
+		}
+	}
+
+	for address, key := range newest {
+		r, ok := n.renewals[address]
+		if !ok {
+			r = &renewal{}
+			n.renewals[address] = r
+		}
+
+		if key.Key.VoteLastValid < round+keyWarnRounds {
+			if r.requested > 0 && round < r.requested+renewRetryRounds {
+				continue
+			}
+
+			bt := n.blockTime
+			if bt <= 0 {
+				bt = 2800 * time.Millisecond
+			}
+			last := round + uint64(n.renew.For/bt)
+
+			err := src.GenerateKey(ctx, address, round, last)
+			if err != nil {
+				slog.Error("failed to renew the key", "address", address, "err", err)
+				n.alerts.Event("renewal", "failed", fmt.Sprintf("could not generate a replacement key for %s: %v", address, err))
+				r.requested = round
+				continue
+			}
+
+			r.requested = round
+			n.alerts.Event("renewal", "generating", fmt.Sprintf("generating a replacement key for %s valid until round %d", address, last))
+			continue
+		}
+
+		// the newest key has not been registered yet
+		if key.EffectiveLastValid != nil || r.handled == key.Id || r.requested == 0 {
+			continue
+		}
+
+		r.handled = key.Id
+
+		if n.renew.Wallet == "" {
+			n.alerts.Notice("renewal", "Replacement key ready",
+				fmt.Sprintf("A new participation key for %s is installed, register it online before the current one expires.", address))
+			continue
+		}
+
+		go n.registerKey(ctx, key.Id, address)
+	}
+}
+
+// registerKey takes a renewed key online with the configured kmd wallet.
+func (n *Node) registerKey(ctx context.Context, keyID string, address string) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Minute)
+	defer cancel()
+
+	err := func() error {
+		tx, err := n.Keyreg(ctx, keyID, true)
+		if err != nil {
+			return err
+		}
+
+		signed, err := n.SignKMD(tx, n.renew.Wallet, n.renew.Password)
+		if err != nil {
+			return err
+		}
+
+		id, err := n.SubmitRaw(ctx, signed)
+		if err != nil {
+			return err
+		}
+
+		_, err = n.WaitConfirmed(ctx, id)
+		return err
+	}()
+
+	if err != nil {
+		n.alerts.Notice("renewal", "Key renewal needs attention",
+			fmt.Sprintf("The new participation key for %s could not be registered: %v", address, err))
+		return
+	}
+
+	n.alerts.Notice("renewal", "Key renewed", fmt.Sprintf("The new participation key for %s is registered online.", address))
+}
@@ -0,0 +1,185 @@
+package node
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"voiui/internal/state"
+)
+
+const (
+	rewardsFile   = "rewards.json"
+	rewardsWindow = 30
+)
+
+type rewardsDay struct {
+	Day     string `json:"day"`
+	Payouts uint64 `json:"payouts"`
+	Rewards uint64 `json:"rewards"`
+}
+
+// rewardsLog keeps daily earnings per account: proposer payouts seen in
+// blocks and growth of the account's rewards balance.
+type rewardsLog struct {
+	mu   sync.Mutex
+	path string
+
+	Accounts map[string][]rewardsDay `json:"accounts"`
+	// Last is the rewards balance of each account when it was last read.
+	Last map[string]uint64 `json:"last"`
+}
+
+func loadRewards(dir string) (*rewardsLog, error) {
+	l := &rewardsLog{
+		path:     filepath.Join(dir, rewardsFile),
+		Accounts: map[string][]rewardsDay{},
+		Last:     map[string]uint64{},
+	}
+
+	data, err := os.ReadFile(l.path)
+	if os.IsNotExist(err) {
+		return l, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read rewards log")
+	}
+
+	err = json.Unmarshal(data, l)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse rewards log")
+	}
+
+	if l.Accounts == nil {
+		l.Accounts = map[string][]rewardsDay{}
+	}
+
+	if l.Last == nil {
+		l.Last = map[string]uint64{}
+	}
+
+	return l, nil
+}
+
+func (l *rewardsLog) save() error {
+	l.mu.Lock()
+	data, err := json.Marshal(l)
+	l.mu.Unlock()
+
+	if err != nil {
+		return errors.Wrap(err, "failed to encode rewards log")
+	}
+
+	return WriteFileAtomic(filepath.Dir(l.path), rewardsFile, data)
+}
+
+func (l *rewardsLog) today(address string, now time.Time) *rewardsDay {
+	day := now.UTC().Format("2006-01-02")
+	cutoff := now.UTC().AddDate(0, 0, -rewardsWindow).Format("2006-01-02")
+
+	days := l.Accounts[address]
+	for len(days) > 0 && days[0].Day <= cutoff {
+		days = days[1:]
+	}
+
+	if len(days) == 0 || days[len(days)-1].Day != day {
+		days = append(days, rewardsDay{Day: day})
+	}
+
+	l.Accounts[address] = days
+
+	return &days[len(days)-1]
+}
+
+func (l *rewardsLog) payout(address string, now time.Time, amount uint64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.today(address, now).Payouts += amount
+}
+
+// observe credits the growth of the rewards balance since the last read.
+func (l *rewardsLog) observe(address string, now time.Time, rewards uint64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	last, ok := l.Last[address]
+	l.Last[address] = rewards
+
+	if ok && rewards > last {
+		l.today(address, now).Rewards += rewards - last
+	}
+}
+
+// totals sums the earnings of accounts and annualizes them against stake
+// over the days recorded.
+func (l *rewardsLog) totals(accounts []string, stake uint64, now time.Time) state.Rewards {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	today := now.UTC().Format("2006-01-02")
+	week := now.UTC().AddDate(0, 0, -7).Format("2006-01-02")
+	cutoff := now.UTC().AddDate(0, 0, -rewardsWindow).Format("2006-01-02")
+
+	r := state.Rewards{Stake: stake}
+	days := map[string]bool{}
+
+	for _, address := range accounts {
+		for _, d := range l.Accounts[address] {
+			if d.Day <= cutoff {
+				continue
+			}
+
+			earned := d.Payouts + d.Rewards
+			days[d.Day] = true
+
+			r.Month += earned
+			if d.Day > week {
+				r.Week += earned
+			}
+			if d.Day == today {
+				r.Today += earned
+			}
+		}
+	}
+
+	if stake > 0 && len(days) > 0 {
+		r.APR = float64(r.Month) / float64(stake) * 365 / float64(len(days)) * 100
+	}
+
+	return r
+}
+
+// between sums the earnings of every logged account on the days after from
+// up to and including to.
+func (l *rewardsLog) between(from time.Time, to time.Time) uint64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	first := from.UTC().Format("2006-01-02")
+	last := to.UTC().Format("2006-01-02")
+
+	var sum uint64
+	for _, days := range l.Accounts {
+		for _, d := range days {
+			if d.Day > first && d.Day <= last {
+				sum += d.Payouts + d.Rewards
+			}
+		}
+	}
+
+	return sum
+}
+
+// Earned sums payouts and rewards of the tracked accounts between from and
+// to, by whole days.
+func (n *Node) Earned(from time.Time, to time.Time) uint64 {
+	if n.rewards == nil {
+		return 0
+	}
+	return n.rewards.between(from, to)
+}
@@ -0,0 +1,343 @@
+package node
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/algod"
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/common/models"
+	"github.com/algorand/go-algorand-sdk/v2/encoding/msgpack"
+	"github.com/algorand/go-algorand-sdk/v2/types"
+	"github.com/pkg/errors"
+)
+
+var (
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrAdminLocked  = errors.New("admin token is locked")
+)
+
+type Participation struct {
+	Address             string  `json:"address"`
+	EffectiveFirstValid *uint64 `json:"effective-first-valid"`
+	EffectiveLastValid  *uint64 `json:"effective-last-valid"`
+	Id                  string  `json:"id"`
+	LastVote            *uint64 `json:"last-vote"`
+	LastBlockProposal   *uint64 `json:"last-block-proposal"`
+
+	Key models.AccountParticipation `json:"key"`
+}
+
+type Genesis struct {
+	ID      string `json:"id"`
+	Network string `json:"network"`
+}
+
+type NodeSource interface {
+	Genesis(ctx context.Context) (Genesis, error)
+	Version(ctx context.Context) (models.Version, error)
+	Status(ctx context.Context) (models.NodeStatus, error)
+	WaitForBlock(ctx context.Context, round uint64) (models.NodeStatus, error)
+	Participation(ctx context.Context) ([]Participation, error)
+	AccountInfo(ctx context.Context, address string) (models.Account, error)
+	OnlineStake(ctx context.Context) (uint64, error)
+	Proposer(ctx context.Context, round uint64) (proposer string, payout uint64, err error)
+	BlockHash(ctx context.Context, round uint64) (string, error)
+	Txns(ctx context.Context, round uint64) (int, error)
+	PendingTxns(ctx context.Context, max uint64) (uint64, []types.SignedTxn, error)
+	GenerateKey(ctx context.Context, address string, first uint64, last uint64) error
+	InstallKey(ctx context.Context, partkey []byte) (string, error)
+}
+
+type algodSource struct {
+	url        string
+	hc         *http.Client
+	ac         *algod.Client
+	adminToken string
+	timeout    time.Duration
+
+	// block is the last block fetched, Txns and Proposer read the same one.
+	blockMu    sync.Mutex
+	blockRound uint64
+	block      []byte
+}
+
+// blockWait is how long algod holds a wait for the next block open.
+const blockWait = time.Minute
+
+func (a *algodSource) bound(ctx context.Context, extra time.Duration) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(ctx, a.timeout+extra)
+}
+
+func (a *algodSource) Genesis(ctx context.Context) (Genesis, error) {
+	ctx, cancel := a.bound(ctx, 0)
+	defer cancel()
+
+	var g Genesis
+
+	raw, err := a.ac.GetGenesis().Do(ctx)
+	if err != nil {
+		return g, errors.Wrap(err, "failed to get genesis")
+	}
+
+	err = json.Unmarshal([]byte(raw), &g)
+	if err != nil {
+		return g, errors.Wrap(err, "failed to decode genesis")
+	}
+
+	return g, nil
+}
+
+func (a *algodSource) Version(ctx context.Context) (models.Version, error) {
+	ctx, cancel := a.bound(ctx, 0)
+	defer cancel()
+
+	return a.ac.Versions().Do(ctx)
+}
+
+func (a *algodSource) Status(ctx context.Context) (models.NodeStatus, error) {
+	ctx, cancel := a.bound(ctx, 0)
+	defer cancel()
+
+	return a.ac.Status().Do(ctx)
+}
+
+func (a *algodSource) WaitForBlock(ctx context.Context, round uint64) (models.NodeStatus, error) {
+	ctx, cancel := a.bound(ctx, blockWait)
+	defer cancel()
+
+	return a.ac.StatusAfterBlock(round).Do(ctx)
+}
+
+func (a *algodSource) AccountInfo(ctx context.Context, address string) (models.Account, error) {
+	ctx, cancel := a.bound(ctx, 0)
+	defer cancel()
+
+	return a.ac.AccountInformation(address).Do(ctx)
+}
+
+func (a *algodSource) OnlineStake(ctx context.Context) (uint64, error) {
+	ctx, cancel := a.bound(ctx, 0)
+	defer cancel()
+
+	supply, err := a.ac.Supply().Do(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return supply.OnlineMoney, nil
+}
+
+type blockCert struct {
+	Block struct {
+		ProposerPayout uint64 `codec:"pp"`
+	} `codec:"block"`
+	Cert struct {
+		Prop struct {
+			OriginalProposer types.Address `codec:"oprop"`
+		} `codec:"prop"`
+	} `codec:"cert"`
+}
+
+// blockRaw fetches the block of round in msgpack, once for each round.
+func (a *algodSource) blockRaw(ctx context.Context, round uint64) ([]byte, error) {
+	a.blockMu.Lock()
+	defer a.blockMu.Unlock()
+
+	if a.block != nil && a.blockRound == round {
+		return a.block, nil
+	}
+
+	ctx, cancel := a.bound(ctx, 0)
+	defer cancel()
+
+	raw, err := a.ac.BlockRaw(round).Do(ctx)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get block %d", round)
+	}
+
+	a.block, a.blockRound = raw, round
+
+	return raw, nil
+}
+
+func (a *algodSource) Proposer(ctx context.Context, round uint64) (string, uint64, error) {
+	raw, err := a.blockRaw(ctx, round)
+	if err != nil {
+		return "", 0, err
+	}
+
+	var b blockCert
+
+	err = msgpack.NewLenientDecoder(bytes.NewReader(raw)).Decode(&b)
+	if err != nil {
+		return "", 0, errors.Wrapf(err, "failed to decode block %d", round)
+	}
+
+	return b.Cert.Prop.OriginalProposer.String(), b.Block.ProposerPayout, nil
+}
+
+type blockTxns struct {
+	Block struct {
+		Payset []struct{} `codec:"txns"`
+	} `codec:"block"`
+}
+
+func (a *algodSource) Txns(ctx context.Context, round uint64) (int, error) {
+	raw, err := a.blockRaw(ctx, round)
+	if err != nil {
+		return 0, err
+	}
+
+	var b blockTxns
+
+	err = msgpack.NewLenientDecoder(bytes.NewReader(raw)).Decode(&b)
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to decode block %d", round)
+	}
+
+	return len(b.Block.Payset), nil
+}
+
+func (a *algodSource) BlockHash(ctx context.Context, round uint64) (string, error) {
+	ctx, cancel := a.bound(ctx, 0)
+	defer cancel()
+
+	resp, err := a.ac.GetBlockHash(round).Do(ctx)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get hash of block %d", round)
+	}
+
+	return resp.Blockhash, nil
+}
+
+func (a *algodSource) PendingTxns(ctx context.Context, max uint64) (uint64, []types.SignedTxn, error) {
+	ctx, cancel := a.bound(ctx, 0)
+	defer cancel()
+
+	return a.ac.PendingTransactions().Max(max).Do(ctx)
+}
+
+func (a *algodSource) Participation(ctx context.Context) ([]Participation, error) {
+	ctx, cancel := a.bound(ctx, 0)
+	defer cancel()
+
+	if a.adminToken == "" {
+		return nil, ErrAdminLocked
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/v2/participation", a.url), nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create participation request")
+	}
+
+	req.Header.Set("X-Algo-API-Token", a.adminToken)
+
+	resp, err := a.hc.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to do participation request")
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		return nil, errors.Wrap(ErrUnauthorized, "failed to check participation")
+	}
+
+	if resp.StatusCode >= 400 {
+		return nil, errors.Errorf("failed to check participation: %s", resp.Status)
+	}
+
+	var items []Participation
+
+	err = json.NewDecoder(resp.Body).Decode(&items)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode participation response")
+	}
+
+	return items, nil
+}
+
+// InstallKey adds a participation key file to algod and returns its ID.
+func (a *algodSource) InstallKey(ctx context.Context, partkey []byte) (string, error) {
+	ctx, cancel := a.bound(ctx, 0)
+	defer cancel()
+
+	if a.adminToken == "" {
+		return "", ErrAdminLocked
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", fmt.Sprintf("%s/v2/participation", a.url), bytes.NewReader(partkey))
+	if err != nil {
+		return "", errors.Wrap(err, "failed to create key install request")
+	}
+
+	req.Header.Set("X-Algo-API-Token", a.adminToken)
+	req.Header.Set("Content-Type", "application/msgpack")
+
+	resp, err := a.hc.Do(req)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to install key")
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		return "", errors.Wrap(ErrUnauthorized, "failed to install key")
+	}
+
+	var body struct {
+		PartID  string `json:"partId"`
+		Message string `json:"message"`
+	}
+
+	json.NewDecoder(resp.Body).Decode(&body)
+
+	if resp.StatusCode >= 400 {
+		if body.Message != "" {
+			return "", errors.Errorf("failed to install key: %s", body.Message)
+		}
+		return "", errors.Errorf("failed to install key: %s", resp.Status)
+	}
+
+	return body.PartID, nil
+}
+
+// GenerateKey has algod generate and install a participation key for
+// address in the background.
+func (a *algodSource) GenerateKey(ctx context.Context, address string, first uint64, last uint64) error {
+	ctx, cancel := a.bound(ctx, 0)
+	defer cancel()
+
+	if a.adminToken == "" {
+		return ErrAdminLocked
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", fmt.Sprintf("%s/v2/participation/generate/%s?first=%d&last=%d", a.url, address, first, last), nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to create key generation request")
+	}
+
+	req.Header.Set("X-Algo-API-Token", a.adminToken)
+
+	resp, err := a.hc.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "failed to request key generation")
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		return errors.Wrap(ErrUnauthorized, "failed to generate key")
+	}
+
+	if resp.StatusCode >= 400 {
+		return errors.Errorf("failed to generate key: %s", resp.Status)
+	}
+
+	return nil
+}
@@ -0,0 +1,285 @@
+package node
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// SSHConfig describes a jump host through which algod is reached.
+type SSHConfig struct {
+	// Target is user@host[:port].
+	Target     string
+	KeyFile    string
+	KnownHosts string
+}
+
+func (c SSHConfig) Enabled() bool {
+	return c.Target != ""
+}
+
+func (c SSHConfig) parse() (string, string, error) {
+	user, host, ok := strings.Cut(c.Target, "@")
+	if !ok {
+		host = user
+		user = os.Getenv("USER")
+		if user == "" {
+			user = os.Getenv("USERNAME")
+		}
+	}
+
+	if user == "" || host == "" {
+		return "", "", errors.Errorf("invalid SSH target: %s", c.Target)
+	}
+
+	if _, _, err := net.SplitHostPort(host); err != nil {
+		host = net.JoinHostPort(host, "22")
+	}
+
+	return user, host, nil
+}
+
+func (c SSHConfig) signers() ([]ssh.Signer, error) {
+	home, _ := os.UserHomeDir()
+
+	files := []string{c.KeyFile}
+	if c.KeyFile == "" {
+		files = []string{
+			filepath.Join(home, ".ssh", "id_ed25519"),
+			filepath.Join(home, ".ssh", "id_ecdsa"),
+			filepath.Join(home, ".ssh", "id_rsa"),
+		}
+	}
+
+	var signers []ssh.Signer
+
+	for _, name := range files {
+		pem, err := os.ReadFile(name)
+		if err != nil {
+			if c.KeyFile != "" {
+				return nil, errors.Wrap(err, "failed to read SSH key")
+			}
+			continue
+		}
+
+		signer, err := ssh.ParsePrivateKey(pem)
+		if err != nil {
+			if _, ok := err.(*ssh.PassphraseMissingError); ok && c.KeyFile == "" {
+				continue
+			}
+			return nil, errors.Wrapf(err, "failed to parse SSH key %s, use ssh-agent for keys with a passphrase", name)
+		}
+
+		signers = append(signers, signer)
+	}
+
+	return signers, nil
+}
+
+func (c SSHConfig) dial(ctx context.Context) (*ssh.Client, error) {
+	user, host, err := c.parse()
+	if err != nil {
+		return nil, err
+	}
+
+	known := c.KnownHosts
+	if known == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to find the home directory")
+		}
+		known = filepath.Join(home, ".ssh", "known_hosts")
+	}
+
+	hostKey, err := knownhosts.New(known)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load known hosts, connect once with ssh to add the host")
+	}
+
+	var auth []ssh.AuthMethod
+
+	if sock := os.Getenv("SSH_AUTH_SOCK"); sock != "" {
+		ac, err := net.Dial("unix", sock)
+		if err == nil {
+			defer ac.Close()
+			auth = append(auth, ssh.PublicKeysCallback(agent.NewClient(ac).Signers))
+		}
+	}
+
+	signers, err := c.signers()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(signers) > 0 {
+		auth = append(auth, ssh.PublicKeys(signers...))
+	}
+
+	if len(auth) == 0 {
+		return nil, errors.New("no SSH key found, pass -ssh-key or start ssh-agent")
+	}
+
+	d := net.Dialer{Timeout: 15 * time.Second}
+
+	conn, err := d.DialContext(ctx, "tcp", host)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to connect to %s", host)
+	}
+
+	sc, chans, reqs, err := ssh.NewClientConn(conn, host, &ssh.ClientConfig{
+		User:            user,
+		Auth:            auth,
+		HostKeyCallback: hostKey,
+		Timeout:         15 * time.Second,
+	})
+	if err != nil {
+		conn.Close()
+		return nil, errors.Wrapf(err, "SSH handshake with %s failed", host)
+	}
+
+	return ssh.NewClient(sc, chans, reqs), nil
+}
+
+// Tunnel forwards connections over a shared SSH session that is reopened
+// on demand after the link drops.
+type Tunnel struct {
+	cfg SSHConfig
+
+	mu     sync.Mutex
+	client *ssh.Client
+}
+
+func NewTunnel(cfg SSHConfig) *Tunnel {
+	return &Tunnel{cfg: cfg}
+}
+
+func (t *Tunnel) connect(ctx context.Context) (*ssh.Client, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.client != nil {
+		return t.client, nil
+	}
+
+	client, err := t.cfg.dial(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	t.client = client
+
+	go func() {
+		client.Wait()
+
+		t.mu.Lock()
+		if t.client == client {
+			t.client = nil
+		}
+		t.mu.Unlock()
+	}()
+
+	return client, nil
+}
+
+func (t *Tunnel) DialContext(ctx context.Context, network string, addr string) (net.Conn, error) {
+	client, err := t.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := client.Dial(network, addr)
+	if err != nil {
+		if _, ok := err.(*ssh.OpenChannelError); !ok {
+			client.Close()
+		}
+		return nil, errors.Wrapf(err, "failed to reach %s over SSH", addr)
+	}
+
+	return conn, nil
+}
+
+func (t *Tunnel) Close() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.client == nil {
+		return nil
+	}
+
+	err := t.client.Close()
+	t.client = nil
+
+	return err
+}
+
+func quote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+func readRemote(client *ssh.Client, name string) (string, bool, error) {
+	session, err := client.NewSession()
+	if err != nil {
+		return "", false, errors.Wrap(err, "failed to open SSH session")
+	}
+	defer session.Close()
+
+	var stderr bytes.Buffer
+	session.Stderr = &stderr
+
+	out, err := session.Output("cat -- " + quote(name))
+	if err != nil {
+		if _, ok := err.(*ssh.ExitError); ok && strings.Contains(stderr.String(), "No such file") {
+			return "", false, nil
+		}
+		return "", false, errors.Wrapf(err, "failed to read remote %s: %s", name, strings.TrimSpace(stderr.String()))
+	}
+
+	return strings.TrimSpace(string(out)), true, nil
+}
+
+// ReadRemote reads the algod address and tokens from a data directory on
+// the SSH host.
+func ReadRemote(cfg SSHConfig, dir string) (string, string, string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	client, err := cfg.dial(ctx)
+	if err != nil {
+		return "", "", "", err
+	}
+	defer client.Close()
+
+	addr, ok, err := readRemote(client, path.Join(dir, "algod.net"))
+	if err == nil && !ok {
+		err = errors.New("algod.net not found on the SSH host, is algod running?")
+	}
+	if err != nil {
+		return "", "", "", err
+	}
+
+	apiToken, _, err := readRemote(client, path.Join(dir, apiTokenFile))
+	if err != nil {
+		return "", "", "", err
+	}
+
+	adminToken, ok, err := readRemote(client, path.Join(dir, adminTokenFile))
+	if err == nil && !ok {
+		err = errors.Errorf("%s not found on the SSH host", adminTokenFile)
+	}
+	if err != nil {
+		return "", "", "", err
+	}
+
+	return addr, apiToken, adminToken, nil
+}
@@ -0,0 +1,182 @@
+package node
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	apiTokenFile   = "algod.token"
+	adminTokenFile = "algod.admin.token"
+)
+
+func readToken(path string, name string) (string, error) {
+	tokenBytes, err := os.ReadFile(filepath.Join(path, name))
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to read %s", name)
+	}
+
+	return strings.TrimSpace(string(tokenBytes)), nil
+}
+
+func ReadTokens(path string) (string, string, error) {
+	apiToken, err := readToken(path, apiTokenFile)
+	if err != nil && !os.IsNotExist(errors.Cause(err)) {
+		return "", "", err
+	}
+
+	adminToken, err := readToken(path, adminTokenFile)
+	if err != nil {
+		return "", "", err
+	}
+
+	return apiToken, adminToken, nil
+}
+
+func WriteFileAtomic(path string, name string, data []byte) error {
+	f, err := os.CreateTemp(path, name+".*")
+	if err != nil {
+		return errors.Wrapf(err, "failed to create temporary %s", name)
+	}
+
+	defer os.Remove(f.Name())
+
+	_, err = f.Write(data)
+	if err == nil {
+		err = f.Sync()
+	}
+
+	cerr := f.Close()
+	if err == nil {
+		err = cerr
+	}
+
+	if err != nil {
+		return errors.Wrapf(err, "failed to write temporary %s", name)
+	}
+
+	err = os.Rename(f.Name(), filepath.Join(path, name))
+	if err != nil {
+		return errors.Wrapf(err, "failed to replace %s", name)
+	}
+
+	return nil
+}
+
+// errNoDataDir is returned for token files of nodes monitored over the
+// network.
+var errNoDataDir = errors.New("the tokens are only on the node's host, this profile has no data directory")
+
+func (n *Node) ReloadToken() (string, error) {
+	dir := n.DataDir()
+	if dir == "" {
+		return "", errNoDataDir
+	}
+
+	apiToken, adminToken, err := ReadTokens(dir)
+	if err != nil {
+		return "", err
+	}
+
+	currentAPI, currentAdmin := n.Tokens()
+
+	if n.AdminSealed() {
+		if currentAdmin == "" {
+			adminToken = ""
+		} else if adminToken != currentAdmin {
+			err = n.resealAdmin(adminToken)
+			if err != nil {
+				return "", err
+			}
+		}
+	}
+
+	if apiToken == currentAPI && adminToken == currentAdmin {
+		return "Tokens on disk are unchanged", nil
+	}
+
+	err = n.setTokens(apiToken, adminToken)
+	if err != nil {
+		return "", err
+	}
+
+	return "Tokens reloaded from data directory", nil
+}
+
+// RotateToken writes a new admin token for algod to load on its next
+// restart. voiui keeps the current one until algod rejects it.
+func (n *Node) RotateToken() (string, error) {
+	dir := n.DataDir()
+	if dir == "" {
+		return "", errNoDataDir
+	}
+
+	sealed := n.AdminSealed()
+
+	_, adminToken := n.Tokens()
+	if sealed && adminToken == "" {
+		return "", errors.New("unlock the admin token to rotate it")
+	}
+
+	b := make([]byte, 32)
+
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to generate token")
+	}
+
+	token := hex.EncodeToString(b)
+
+	if sealed {
+		err = n.resealAdmin(token)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	err = WriteFileAtomic(dir, adminTokenFile, []byte(token))
+	if err != nil {
+		return "", err
+	}
+
+	n.mu.Lock()
+	n.rotated = token
+	n.mu.Unlock()
+
+	return "Admin token rotated, restart the node to apply it", nil
+}
+
+// adoptRotated switches to a rotated admin token once algod rejects the
+// old one after its restart, and tells whether it did.
+func (n *Node) adoptRotated() bool {
+	n.mu.Lock()
+	token := n.rotated
+	n.rotated = ""
+	n.mu.Unlock()
+
+	if token == "" {
+		return false
+	}
+
+	apiToken, adminToken := n.Tokens()
+	if adminToken == "" {
+		// locked, unlocking unseals the rotated token
+		return false
+	}
+
+	err := n.setTokens(apiToken, token)
+	if err != nil {
+		slog.Error("failed to switch to the rotated admin token", "err", err)
+		return false
+	}
+
+	slog.Info("switched to the rotated admin token")
+
+	return true
+}
@@ -0,0 +1,257 @@
+package node
+
+import (
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"voiui/internal/state"
+)
+
+const (
+	uptimeFile = "uptime.json"
+
+	uptimeWindow = 30
+
+	// gaps longer than this (voiui closed, laptop asleep) are not counted
+	uptimeMaxGap = 10 * time.Minute
+
+	uptimeSaveEvery = 5 * time.Minute
+	maxOutages      = 50
+)
+
+type uptimeDay struct {
+	Day string `json:"day"`
+
+	Observed time.Duration `json:"observed"`
+	Down     time.Duration `json:"down"`
+
+	Participating    time.Duration `json:"participating"`
+	NotParticipating time.Duration `json:"not_participating"`
+}
+
+type uptimeMark struct {
+	at time.Time
+	ok bool
+}
+
+type uptimeLog struct {
+	mu    sync.Mutex
+	path  string
+	saved time.Time
+	marks map[string]uptimeMark
+
+	Updated time.Time      `json:"updated"`
+	Days    []uptimeDay    `json:"days"`
+	Outages []state.Outage `json:"outages"`
+}
+
+func loadUptime(dir string) (*uptimeLog, error) {
+	u := &uptimeLog{
+		path:  filepath.Join(dir, uptimeFile),
+		marks: map[string]uptimeMark{},
+	}
+
+	data, err := os.ReadFile(u.path)
+	if os.IsNotExist(err) {
+		return u, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read uptime log")
+	}
+
+	err = json.Unmarshal(data, u)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse uptime log")
+	}
+
+	// outages left open by a previous run end where the log stops
+	for i := range u.Outages {
+		if u.Outages[i].End.IsZero() {
+			u.Outages[i].End = u.Updated
+			if u.Outages[i].End.Before(u.Outages[i].Start) {
+				u.Outages[i].End = u.Outages[i].Start
+			}
+		}
+	}
+
+	return u, nil
+}
+
+func (u *uptimeLog) day(now time.Time) *uptimeDay {
+	day := now.UTC().Format("2006-01-02")
+	cutoff := now.UTC().AddDate(0, 0, -uptimeWindow).Format("2006-01-02")
+
+	for len(u.Days) > 0 && u.Days[0].Day <= cutoff {
+		u.Days = u.Days[1:]
+	}
+
+	if len(u.Days) == 0 || u.Days[len(u.Days)-1].Day != day {
+		u.Days = append(u.Days, uptimeDay{Day: day})
+	}
+
+	return &u.Days[len(u.Days)-1]
+}
+
+// mark records that kind was ok (or not) at now, crediting the time since
+// the previous mark to the previous state and opening or closing outages.
+// It returns the outage it opened or closed, if any.
+func (u *uptimeLog) mark(kind string, now time.Time, ok bool) (state.Outage, bool) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	prev, seen := u.marks[kind]
+	u.marks[kind] = uptimeMark{at: now, ok: ok}
+
+	if seen {
+		if gap := now.Sub(prev.at); gap > 0 && gap <= uptimeMaxGap {
+			d := u.day(now)
+
+			switch kind {
+			case state.OutageConnection:
+				d.Observed += gap
+				if !prev.ok {
+					d.Down += gap
+				}
+			case state.OutageParticipation:
+				if prev.ok {
+					d.Participating += gap
+				} else {
+					d.NotParticipating += gap
+				}
+			}
+		}
+	}
+
+	open := -1
+	for i := len(u.Outages) - 1; i >= 0; i-- {
+		if u.Outages[i].Kind == kind && u.Outages[i].End.IsZero() {
+			open = i
+			break
+		}
+	}
+
+	switch {
+	case !ok && open < 0:
+		o := state.Outage{Kind: kind, Start: now}
+		u.Outages = append(u.Outages, o)
+		if len(u.Outages) > maxOutages {
+			u.Outages = u.Outages[len(u.Outages)-maxOutages:]
+		}
+		return o, true
+	case ok && open >= 0:
+		u.Outages[open].End = now
+		return u.Outages[open], true
+	}
+
+	return state.Outage{}, false
+}
+
+// forget drops the in-memory marks so time spent on another endpoint is
+// not credited to the current state.
+func (u *uptimeLog) forget() {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	u.marks = map[string]uptimeMark{}
+
+	now := time.Now()
+	for i := range u.Outages {
+		if u.Outages[i].End.IsZero() {
+			u.Outages[i].End = now
+		}
+	}
+}
+
+func (u *uptimeLog) stats(now time.Time) state.Uptime {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	cutoff := now.UTC().AddDate(0, 0, -uptimeWindow).Format("2006-01-02")
+
+	var observed, down, part, notPart time.Duration
+
+	for _, d := range u.Days {
+		if d.Day <= cutoff {
+			continue
+		}
+
+		observed += d.Observed
+		down += d.Down
+		part += d.Participating
+		notPart += d.NotParticipating
+	}
+
+	s := state.Uptime{
+		Observed:      observed,
+		Connection:    -1,
+		Participation: -1,
+	}
+
+	since := now.AddDate(0, 0, -uptimeWindow)
+	for _, o := range u.Outages {
+		if o.End.IsZero() || o.End.After(since) {
+			s.Outages = append(s.Outages, o)
+		}
+	}
+
+	if observed > 0 {
+		s.Connection = 1 - float64(down)/float64(observed)
+	}
+
+	if part+notPart > 0 {
+		s.Participation = float64(part) / float64(part+notPart)
+	}
+
+	return s
+}
+
+func (u *uptimeLog) save() error {
+	u.mu.Lock()
+	u.saved = time.Now()
+	u.Updated = u.saved
+	data, err := json.Marshal(u)
+	u.mu.Unlock()
+
+	if err != nil {
+		return errors.Wrap(err, "failed to encode uptime log")
+	}
+
+	return WriteFileAtomic(filepath.Dir(u.path), uptimeFile, data)
+}
+
+func (n *Node) markUptime(kind string, ok bool) {
+	if n.uptime == nil {
+		return
+	}
+
+	now := time.Now()
+
+	o, changed := n.uptime.mark(kind, now, ok)
+	if changed && n.history != nil {
+		n.history.Outage(o)
+	}
+
+	n.uptime.mu.Lock()
+	due := changed || now.Sub(n.uptime.saved) >= uptimeSaveEvery
+	n.uptime.mu.Unlock()
+
+	if due {
+		err := n.uptime.save()
+		if err != nil {
+			slog.Error("failed to save uptime log", "err", err)
+		}
+	}
+
+	stats := n.uptime.stats(now)
+
+	n.updates <- func(s *state.State) error {
+		s.Uptime = stats
+		return nil
+	}
+}
@@ -0,0 +1,194 @@
+package node
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+
+	"voiui/internal/alert"
+	"voiui/internal/state"
+)
+
+// Watch replaces the accounts checked every round in addition to the
+// node's own.
+func (n *Node) Watch(accounts []state.Account) {
+	n.mu.Lock()
+	prev := n.watched
+	n.watched = append([]state.Account(nil), accounts...)
+	n.mu.Unlock()
+
+	for _, a := range prev {
+		if !watching(accounts, a.Address) {
+			n.alerts.Set(accountAlert(a, "offline"), false, accountName(a)+" is no longer watched")
+			n.alerts.Set(accountAlert(a, "key-expiring"), false, accountName(a)+" is no longer watched")
+		}
+	}
+}
+
+func watching(accounts []state.Account, address string) bool {
+	for _, a := range accounts {
+		if a.Address == address {
+			return true
+		}
+	}
+	return false
+}
+
+func accountAlert(a state.Account, condition string) alert.Kind {
+	return alert.Kind("account:" + a.Address + ":" + condition)
+}
+
+func accountName(a state.Account) string {
+	if a.Label != "" {
+		return a.Label
+	}
+	return a.Address
+}
+
+// checkWatched refreshes balances, status and keys of the watched accounts
+// and alerts when one goes offline or its key is about to expire.
+func (n *Node) checkWatched(ctx context.Context, src NodeSource, round uint64) {
+	n.mu.Lock()
+	watched := append([]state.Account(nil), n.watched...)
+	n.mu.Unlock()
+
+	if len(watched) == 0 {
+		return
+	}
+
+	if n.watchSeen == nil {
+		n.watchSeen = map[string]state.Account{}
+	}
+
+	infos := map[string]state.Account{}
+
+	for _, w := range watched {
+		info := state.Account{Address: w.Address, Label: w.Label, Round: round}
+
+		account, err := src.AccountInfo(ctx, w.Address)
+		if err != nil {
+			info.Err = err.Error()
+			infos[w.Address] = info
+			continue
+		}
+
+		info.Balance = account.Amount
+		info.Online = account.Status == "Online"
+		if info.Online {
+			info.KeyLastValid = account.Participation.VoteLastValid
+		}
+
+		name := accountName(w)
+		prev, seen := n.watchSeen[w.Address]
+
+		if seen && prev.Err == "" && prev.Balance != info.Balance {
+			change := "received"
+			diff := info.Balance - prev.Balance
+			if info.Balance < prev.Balance {
+				change = "sent"
+				diff = prev.Balance - info.Balance
+			}
+
+			n.alerts.Event("account", "balance", fmt.Sprintf("%s %s %.6f VOI", name, change, float64(diff)/1e6))
+		}
+
+		if info.Online {
+			n.alerts.Set(accountAlert(w, "offline"), false, name+" is online")
+		} else if seen && prev.Online {
+			n.alerts.Set(accountAlert(w, "offline"), true, name+" went offline")
+		}
+
+		if info.Online && info.KeyLastValid > round && info.KeyLastValid-round < keyWarnRounds {
+			n.alerts.Set(accountAlert(w, "key-expiring"), true, fmt.Sprintf("the participation key of %s expires at round %d", name, info.KeyLastValid))
+		} else {
+			n.alerts.Set(accountAlert(w, "key-expiring"), false, name+" has no expiring key")
+		}
+
+		n.checkStake(name, info)
+
+		n.watchSeen[w.Address] = info
+		infos[w.Address] = info
+	}
+
+	n.updates <- func(s *state.State) error {
+		for i, a := range s.Watched {
+			if info, ok := infos[a.Address]; ok {
+				info.Label = a.Label
+				s.Watched[i] = info
+			}
+		}
+		return nil
+	}
+}
+
+// trackWatched follows proposals and rewards of the watched accounts when
+// there are no participation keys to take them from.
+func (n *Node) trackWatched(ctx context.Context, src NodeSource, round uint64) {
+	n.mu.Lock()
+	accounts := make([]string, 0, len(n.watched))
+	for _, a := range n.watched {
+		accounts = append(accounts, a.Address)
+	}
+	n.mu.Unlock()
+
+	if !sameAccounts(accounts, n.accounts) {
+		n.accounts = accounts
+		n.stakes = nil
+	}
+
+	err := n.trackPerformance(ctx, src, round)
+	if err != nil {
+		slog.Error("failed to track performance", "err", err)
+	}
+}
+
+func sameAccounts(a []string, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// checkStake notifies when the online stake of a watched account moved by
+// more than the threshold since it was last reported.
+func (n *Node) checkStake(name string, info state.Account) {
+	if n.stakeChange <= 0 {
+		return
+	}
+
+	if n.stakeBase == nil {
+		n.stakeBase = map[string]uint64{}
+	}
+
+	if !info.Online {
+		delete(n.stakeBase, info.Address)
+		return
+	}
+
+	base, ok := n.stakeBase[info.Address]
+	if !ok || base == 0 {
+		n.stakeBase[info.Address] = info.Balance
+		return
+	}
+
+	change := (float64(info.Balance) - float64(base)) / float64(base) * 100
+	if math.Abs(change) < n.stakeChange {
+		return
+	}
+
+	n.stakeBase[info.Address] = info.Balance
+
+	direction := "increased"
+	if change < 0 {
+		direction = "decreased"
+	}
+
+	n.alerts.Notice("stake", fmt.Sprintf("Stake of %s %s", name, direction),
+		fmt.Sprintf("Online stake went from %.2f to %.2f VOI (%+.1f%%), expected proposals change with it.", float64(base)/1e6, float64(info.Balance)/1e6, change))
+}
@@ -0,0 +1,214 @@
+package nodeconfig
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"voiui/internal/state"
+)
+
+const fileName = "config.json"
+
+// defaults are algod's defaults for the settings operators change most,
+// settings missing here are shown as they are set.
+var defaults = map[string]interface{}{
+	"Archival":                  false,
+	"BaseLoggerDebugLevel":      json.Number("4"),
+	"CatchpointInterval":        json.Number("10000"),
+	"CatchpointTracking":        json.Number("0"),
+	"CatchupParallelBlocks":     json.Number("16"),
+	"EnableBlockService":        false,
+	"EnableDeveloperAPI":        false,
+	"EnableFollowMode":          false,
+	"EnableLedgerService":       false,
+	"EnableMetricReporting":     false,
+	"EnableP2P":                 false,
+	"EndpointAddress":           "127.0.0.1:0",
+	"ForceRelayMessages":        false,
+	"GossipFanout":              json.Number("4"),
+	"IncomingConnectionsLimit":  json.Number("2400"),
+	"LogSizeLimit":              json.Number("1073741824"),
+	"MaxAcctLookback":           json.Number("4"),
+	"NetAddress":                "",
+	"NodeExporterListenAddress": ":9100",
+	"TxPoolSize":                json.Number("75000"),
+}
+
+func read(dir string) (map[string]interface{}, error) {
+	if dir == "" {
+		return nil, errors.New("the node has no local data directory")
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, fileName))
+	if os.IsNotExist(err) {
+		return map[string]interface{}{}, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read algod config")
+	}
+
+	values := map[string]interface{}{}
+
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+
+	err = d.Decode(&values)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode algod config")
+	}
+
+	return values, nil
+}
+
+// marshal keeps <network> in DNSBootstrapID and the like readable.
+func marshal(v interface{}, indent string) ([]byte, error) {
+	var b bytes.Buffer
+
+	e := json.NewEncoder(&b)
+	e.SetEscapeHTML(false)
+	e.SetIndent("", indent)
+
+	err := e.Encode(v)
+	return b.Bytes(), err
+}
+
+func encode(v interface{}) string {
+	data, _ := marshal(v, "")
+	return strings.TrimSpace(string(data))
+}
+
+// Settings lists the effective settings of the node in dir, the ones in
+// config.json merged over the defaults.
+func Settings(dir string) ([]state.NodeSetting, error) {
+	values, err := read(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(values)+len(defaults))
+	for name := range values {
+		names = append(names, name)
+	}
+	for name := range defaults {
+		if _, ok := values[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	settings := make([]state.NodeSetting, len(names))
+	for i, name := range names {
+		s := state.NodeSetting{Name: name}
+
+		def, known := defaults[name]
+		if known {
+			s.Default = encode(def)
+			s.Value = s.Default
+		}
+
+		if v, ok := values[name]; ok {
+			s.Value = encode(v)
+			s.Changed = !known || s.Value != s.Default
+		}
+
+		settings[i] = s
+	}
+
+	return settings, nil
+}
+
+// parse checks raw against the type of the setting's default, settings
+// without one take any JSON and otherwise a string.
+func parse(name string, raw string) (interface{}, error) {
+	switch defaults[name].(type) {
+	case bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, errors.Errorf("%s takes true or false", name)
+		}
+		return b, nil
+	case json.Number:
+		_, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return nil, errors.Errorf("%s takes a whole number", name)
+		}
+		return json.Number(raw), nil
+	case string:
+		if s, err := strconv.Unquote(raw); err == nil {
+			return s, nil
+		}
+		return raw, nil
+	default:
+		var v interface{}
+
+		d := json.NewDecoder(strings.NewReader(raw))
+		d.UseNumber()
+		if d.Decode(&v) == nil && !d.More() {
+			return v, nil
+		}
+		return raw, nil
+	}
+}
+
+// Set changes name in config.json in dir to raw, an empty raw removes it
+// so the default applies.
+func Set(dir string, name string, raw string) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return errors.New("no setting name")
+	}
+
+	values, err := read(dir)
+	if err != nil {
+		return err
+	}
+
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		delete(values, name)
+	} else {
+		v, err := parse(name, raw)
+		if err != nil {
+			return err
+		}
+		values[name] = v
+	}
+
+	return write(filepath.Join(dir, fileName), values)
+}
+
+// write replaces path with v through a temporary file, so algod never
+// sees half a file.
+func write(path string, v interface{}) error {
+	data, err := marshal(v, "\t")
+	if err != nil {
+		return errors.Wrap(err, "failed to encode")
+	}
+
+	mode := os.FileMode(0o644)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+
+	tmp := path + ".tmp"
+
+	err = os.WriteFile(tmp, data, mode)
+	if err != nil {
+		return errors.Wrapf(err, "failed to write %s", filepath.Base(path))
+	}
+
+	err = os.Rename(tmp, path)
+	if err != nil {
+		os.Remove(tmp)
+		return errors.Wrapf(err, "failed to replace %s", filepath.Base(path))
+	}
+
+	return nil
+}
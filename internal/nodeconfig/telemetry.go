@@ -0,0 +1,100 @@
+package nodeconfig
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"voiui/internal/state"
+)
+
+const loggingFile = "logging.config"
+
+func readLogging(dir string) (map[string]interface{}, error) {
+	if dir == "" {
+		return nil, errors.New("the node has no local data directory")
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, loggingFile))
+	if os.IsNotExist(err) {
+		return map[string]interface{}{}, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read logging.config")
+	}
+
+	values := map[string]interface{}{}
+
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+
+	err = d.Decode(&values)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode logging.config")
+	}
+
+	return values, nil
+}
+
+// Telemetry reads the telemetry settings from logging.config in dir.
+func Telemetry(dir string) (state.Telemetry, error) {
+	values, err := readLogging(dir)
+	if err != nil {
+		return state.Telemetry{}, err
+	}
+
+	t := state.Telemetry{}
+	t.Enabled, _ = values["Enable"].(bool)
+	t.Name, _ = values["Name"].(string)
+	t.GUID, _ = values["GUID"].(string)
+
+	return t, nil
+}
+
+func guid() (string, error) {
+	b := make([]byte, 16)
+
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:]), nil
+}
+
+// SetTelemetry turns telemetry on or off and names the node in
+// logging.config in dir, the other settings stay as they are and a GUID
+// is made up when there is none.
+func SetTelemetry(dir string, enabled bool, name string) error {
+	values, err := readLogging(dir)
+	if err != nil {
+		return err
+	}
+
+	name = strings.TrimSpace(name)
+	if enabled && name == "" {
+		return errors.New("telemetry needs a node name")
+	}
+
+	values["Enable"] = enabled
+	values["Name"] = name
+
+	if id, _ := values["GUID"].(string); id == "" {
+		id, err = guid()
+		if err != nil {
+			return errors.Wrap(err, "failed to create a telemetry GUID")
+		}
+		values["GUID"] = id
+	}
+
+	return write(filepath.Join(dir, loggingFile), values)
+}
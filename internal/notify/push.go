@@ -0,0 +1,144 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"voiui/internal/alert"
+	"voiui/internal/config"
+)
+
+var defaultPriorities = map[string]map[alert.Severity]int{
+	"ntfy":   {alert.Info: 2, alert.Warning: 3, alert.Critical: 5},
+	"gotify": {alert.Info: 2, alert.Warning: 5, alert.Critical: 8},
+}
+
+// Push posts notifications to an ntfy topic or a Gotify app.
+type Push struct {
+	service    string
+	url        string
+	token      string
+	priorities map[alert.Severity]int
+	client     *http.Client
+}
+
+func NewPush(cfg config.Push) (*Push, error) {
+	defaults, ok := defaultPriorities[cfg.Service]
+	if !ok {
+		return nil, errors.Errorf("unknown push service %q, use ntfy or gotify", cfg.Service)
+	}
+	if cfg.URL == "" {
+		return nil, errors.Errorf("%s push needs a url", cfg.Service)
+	}
+
+	url := strings.TrimRight(cfg.URL, "/")
+	if cfg.Service == "ntfy" {
+		if cfg.Topic == "" {
+			return nil, errors.New("ntfy push needs a topic")
+		}
+		url += "/" + cfg.Topic
+	} else {
+		url += "/message"
+	}
+
+	token := cfg.Token
+	if token == "" {
+		token = os.Getenv("VOIUI_PUSH_TOKEN")
+	}
+	if cfg.Service == "gotify" && token == "" {
+		return nil, errors.New("gotify push needs an app token")
+	}
+
+	priorities := make(map[alert.Severity]int, len(defaults))
+	for s, p := range defaults {
+		priorities[s] = p
+	}
+	for s, p := range cfg.Priorities {
+		switch alert.Severity(s) {
+		case alert.Info, alert.Warning, alert.Critical:
+			priorities[alert.Severity(s)] = p
+		default:
+			return nil, errors.Errorf("unknown push severity %q, use info, warning or critical", s)
+		}
+	}
+
+	return &Push{
+		service:    cfg.Service,
+		url:        url,
+		token:      token,
+		priorities: priorities,
+		client:     &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+func (p *Push) request(ctx context.Context, n alert.Notification) (*http.Request, error) {
+	severity := n.Severity
+	if severity == "" {
+		severity = alert.Warning
+	}
+	priority := p.priorities[severity]
+
+	if p.service == "gotify" {
+		body, err := json.Marshal(map[string]interface{}{
+			"title":    n.Title,
+			"message":  n.Body,
+			"priority": priority,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		req, err := http.NewRequestWithContext(ctx, "POST", p.url, bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Gotify-Key", p.token)
+		return req, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", p.url, strings.NewReader(n.Body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Title", n.Title)
+	req.Header.Set("Priority", strconv.Itoa(priority))
+	req.Header.Set("Tags", string(severity))
+	if p.token != "" {
+		req.Header.Set("Authorization", "Bearer "+p.token)
+	}
+	return req, nil
+}
+
+// Notify posts n in the background so that a slow server does not hold up
+// the alerts.
+func (p *Push) Notify(n alert.Notification) error {
+	req, err := p.request(context.Background(), n)
+	if err != nil {
+		return errors.Wrapf(err, "invalid %s notification", p.service)
+	}
+
+	go func() {
+		resp, err := p.client.Do(req)
+		if err != nil {
+			slog.Error("failed to push notification", "service", p.service, "err", err)
+			return
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode >= 300 {
+			slog.Error("failed to push notification", "service", p.service, "status", resp.Status)
+		}
+	}()
+
+	return nil
+}
@@ -0,0 +1,290 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"log/slog"
+	"mime"
+	"net"
+	"net/smtp"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"voiui/internal/alert"
+	"voiui/internal/config"
+)
+
+const (
+	defaultSubject = "voiui: {{.Title}}"
+	defaultBody    = "{{.Body}}\n\n{{.Time.Format \"2006-01-02 15:04:05 MST\"}}\n"
+
+	smtpTimeout = 30 * time.Second
+)
+
+type message struct {
+	Title    string
+	Body     string
+	Resolved bool
+	Time     time.Time
+}
+
+// SMTP emails notifications, one by one or collected into a digest.
+type SMTP struct {
+	cfg      config.Email
+	password string
+	subject  *template.Template
+	body     *template.Template
+
+	queue chan message
+
+	mu      sync.Mutex
+	pending []message
+}
+
+func NewSMTP(cfg config.Email) (*SMTP, error) {
+	if cfg.Host == "" || cfg.From == "" || len(cfg.To) == 0 {
+		return nil, errors.New("email needs a host, from and to")
+	}
+
+	switch cfg.TLS {
+	case "":
+		cfg.TLS = "starttls"
+	case "starttls", "tls", "none":
+	default:
+		return nil, errors.Errorf("unknown email TLS mode %q, use starttls, tls or none", cfg.TLS)
+	}
+
+	if cfg.Port == 0 {
+		cfg.Port = 587
+		if cfg.TLS == "tls" {
+			cfg.Port = 465
+		}
+	}
+
+	if cfg.Subject == "" {
+		cfg.Subject = defaultSubject
+	}
+	if cfg.Body == "" {
+		cfg.Body = defaultBody
+	}
+
+	subject, err := template.New("subject").Parse(cfg.Subject)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid email subject template")
+	}
+
+	body, err := template.New("body").Parse(cfg.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid email body template")
+	}
+
+	password := cfg.Password
+	if password == "" {
+		password = os.Getenv("VOIUI_SMTP_PASSWORD")
+	}
+
+	return &SMTP{
+		cfg:      cfg,
+		password: password,
+		subject:  subject,
+		body:     body,
+		queue:    make(chan message, 100),
+	}, nil
+}
+
+func render(t *template.Template, m message) (string, error) {
+	var b bytes.Buffer
+
+	err := t.Execute(&b, m)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to render the email %s", t.Name())
+	}
+
+	return b.String(), nil
+}
+
+func (s *SMTP) dial() (*smtp.Client, error) {
+	addr := net.JoinHostPort(s.cfg.Host, strconv.Itoa(s.cfg.Port))
+	tc := &tls.Config{ServerName: s.cfg.Host}
+	d := &net.Dialer{Timeout: smtpTimeout}
+
+	var conn net.Conn
+	var err error
+
+	if s.cfg.TLS == "tls" {
+		conn, err = tls.DialWithDialer(d, "tcp", addr, tc)
+	} else {
+		conn, err = d.Dial("tcp", addr)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to connect to %s", addr)
+	}
+
+	conn.SetDeadline(time.Now().Add(smtpTimeout))
+
+	c, err := smtp.NewClient(conn, s.cfg.Host)
+	if err != nil {
+		conn.Close()
+		return nil, errors.Wrap(err, "failed to start SMTP")
+	}
+
+	if s.cfg.TLS == "starttls" {
+		err = c.StartTLS(tc)
+		if err != nil {
+			c.Close()
+			return nil, errors.Wrap(err, "failed to start TLS")
+		}
+	}
+
+	if s.cfg.Username != "" {
+		err = c.Auth(smtp.PlainAuth("", s.cfg.Username, s.password, s.cfg.Host))
+		if err != nil {
+			c.Close()
+			return nil, errors.Wrap(err, "SMTP login failed")
+		}
+	}
+
+	return c, nil
+}
+
+func (s *SMTP) send(subject string, body string) error {
+	c, err := s.dial()
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	err = c.Mail(s.cfg.From)
+	if err != nil {
+		return errors.Wrap(err, "sender rejected")
+	}
+
+	for _, to := range s.cfg.To {
+		err = c.Rcpt(to)
+		if err != nil {
+			return errors.Wrapf(err, "recipient %s rejected", to)
+		}
+	}
+
+	w, err := c.Data()
+	if err != nil {
+		return errors.Wrap(err, "failed to send email")
+	}
+
+	fmt.Fprintf(w, "From: %s\r\n", s.cfg.From)
+	fmt.Fprintf(w, "To: %s\r\n", strings.Join(s.cfg.To, ", "))
+	fmt.Fprintf(w, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", strings.TrimSpace(subject)))
+	fmt.Fprintf(w, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(w, "MIME-Version: 1.0\r\nContent-Type: text/plain; charset=utf-8\r\n\r\n")
+	fmt.Fprint(w, strings.ReplaceAll(body, "\n", "\r\n"))
+
+	err = w.Close()
+	if err != nil {
+		return errors.Wrap(err, "failed to send email")
+	}
+
+	return c.Quit()
+}
+
+func (s *SMTP) sendOne(m message) error {
+	subject, err := render(s.subject, m)
+	if err != nil {
+		return err
+	}
+
+	body, err := render(s.body, m)
+	if err != nil {
+		return err
+	}
+
+	return s.send(subject, body)
+}
+
+func (s *SMTP) sendDigest(ms []message) error {
+	var b strings.Builder
+
+	for _, m := range ms {
+		body, err := render(s.body, m)
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(&b, "%s\n%s\n\n", m.Title, body)
+	}
+
+	return s.send(fmt.Sprintf("voiui: %d notifications", len(ms)), b.String())
+}
+
+// Notify queues n, Run sends it.
+func (s *SMTP) Notify(n alert.Notification) error {
+	select {
+	case s.queue <- message{Title: n.Title, Body: n.Body, Resolved: n.Resolved, Time: time.Now()}:
+		return nil
+	default:
+		return errors.New("the email queue is full")
+	}
+}
+
+// Test sends a test email right away.
+func (s *SMTP) Test() (string, error) {
+	err := s.sendOne(message{Title: "Test email", Body: "voiui can send you email notifications.", Time: time.Now()})
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("Sent a test email to %s", strings.Join(s.cfg.To, ", ")), nil
+}
+
+func (s *SMTP) flush() {
+	s.mu.Lock()
+	pending := s.pending
+	s.pending = nil
+	s.mu.Unlock()
+
+	if len(pending) == 0 {
+		return
+	}
+
+	err := s.sendDigest(pending)
+	if err != nil {
+		slog.Error("failed to send email digest", "err", err)
+	}
+}
+
+func (s *SMTP) Run(ctx context.Context) {
+	var tick <-chan time.Time
+	if s.cfg.Digest > 0 {
+		t := time.NewTicker(time.Duration(s.cfg.Digest))
+		defer t.Stop()
+		tick = t.C
+	}
+
+	for {
+		select {
+		case m := <-s.queue:
+			if tick != nil {
+				s.mu.Lock()
+				s.pending = append(s.pending, m)
+				s.mu.Unlock()
+				continue
+			}
+
+			err := s.sendOne(m)
+			if err != nil {
+				slog.Error("failed to send email", "err", err)
+			}
+		case <-tick:
+			s.flush()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
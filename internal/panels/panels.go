@@ -0,0 +1,123 @@
+package panels
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"voiui/internal/config"
+	"voiui/internal/state"
+)
+
+const (
+	defaultInterval = time.Minute
+	maxBody         = 1 << 20
+)
+
+// Poller fetches the JSON sources of user-defined panels.
+type Poller struct {
+	panels  []config.Panel
+	updates chan<- state.Update
+
+	hc *http.Client
+}
+
+func New(panels []config.Panel, updates chan<- state.Update) *Poller {
+	return &Poller{
+		panels:  panels,
+		updates: updates,
+		hc:      &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+func (p *Poller) fetch(ctx context.Context, panel config.Panel) (interface{}, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", panel.URL, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create request")
+	}
+
+	for k, v := range panel.Headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := p.hc.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to fetch")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("failed to fetch: %s", resp.Status)
+	}
+
+	var v interface{}
+
+	err = json.NewDecoder(io.LimitReader(resp.Body, maxBody)).Decode(&v)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode")
+	}
+
+	return v, nil
+}
+
+func (p *Poller) check(ctx context.Context, i int) {
+	panel := p.panels[i]
+
+	ps := state.Panel{
+		Title:     panel.Title,
+		UpdatedAt: time.Now(),
+	}
+
+	v, err := p.fetch(ctx, panel)
+	if err != nil {
+		ps.Err = err.Error()
+	} else {
+		for _, f := range panel.Fields {
+			value := "–"
+			if x, err := Extract(v, f.Path); err == nil {
+				value = format(x)
+			}
+			ps.Values = append(ps.Values, state.PanelValue{Label: f.Label, Value: value})
+		}
+	}
+
+	count := len(p.panels)
+
+	p.updates <- func(s *state.State) error {
+		if len(s.Panels) != count {
+			s.Panels = make([]state.Panel, count)
+		}
+		s.Panels[i] = ps
+		return nil
+	}
+}
+
+func (p *Poller) run(ctx context.Context, i int) {
+	interval := time.Duration(p.panels[i].Interval)
+	if interval <= 0 {
+		interval = defaultInterval
+	}
+
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	for {
+		p.check(ctx, i)
+
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (p *Poller) Run(ctx context.Context) {
+	for i := range p.panels {
+		go p.run(ctx, i)
+	}
+}
@@ -0,0 +1,60 @@
+package panels
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Extract walks v along a JSONPath-like path such as $.a.b[0].c or a.b.0.c.
+func Extract(v interface{}, path string) (interface{}, error) {
+	path = strings.TrimPrefix(path, "$")
+	path = strings.ReplaceAll(path, "[", ".")
+	path = strings.ReplaceAll(path, "]", "")
+
+	for _, key := range strings.Split(path, ".") {
+		if key == "" {
+			continue
+		}
+
+		switch node := v.(type) {
+		case map[string]interface{}:
+			next, ok := node[strings.Trim(key, `'"`)]
+			if !ok {
+				return nil, errors.Errorf("no field %q", key)
+			}
+			v = next
+		case []interface{}:
+			i, err := strconv.Atoi(key)
+			if err != nil {
+				return nil, errors.Errorf("%q is not an index", key)
+			}
+			if i < 0 {
+				i += len(node)
+			}
+			if i < 0 || i >= len(node) {
+				return nil, errors.Errorf("index %d out of range", i)
+			}
+			v = node[i]
+		default:
+			return nil, errors.Errorf("cannot select %q from %T", key, v)
+		}
+	}
+
+	return v, nil
+}
+
+func format(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return "null"
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,9 @@
+//go:build !windows
+
+package plugins
+
+import "io/fs"
+
+func executable(info fs.FileInfo) bool {
+	return info.Mode()&0o111 != 0
+}
@@ -0,0 +1,15 @@
+package plugins
+
+import (
+	"io/fs"
+	"path/filepath"
+	"strings"
+)
+
+func executable(info fs.FileInfo) bool {
+	switch strings.ToLower(filepath.Ext(info.Name())) {
+	case ".exe", ".bat", ".cmd":
+		return true
+	}
+	return false
+}
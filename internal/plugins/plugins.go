@@ -0,0 +1,327 @@
+package plugins
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"voiui/internal/alert"
+	"voiui/internal/state"
+)
+
+const (
+	minBackoff = 10 * time.Second
+	maxBackoff = 5 * time.Minute
+	queueSize  = 100
+)
+
+type Sink interface {
+	Set(kind alert.Kind, active bool, message string)
+	Notice(kind string, title string, body string)
+}
+
+// Value is a labelled reading from a collector plugin.
+type Value struct {
+	Label string `json:"label"`
+	Value string `json:"value"`
+}
+
+// Message is one line of JSON on a plugin's stdin or stdout.
+//
+// Plugins write "hello" with Sink set to receive notifications, "values"
+// with the readings to show, "alert" with Kind, Active and Message to
+// raise or clear an alert, and "event" with Title and Body for a one-off
+// notification. voiui writes "notification" with Kind, Severity, Title,
+// Body and Resolved to sink plugins.
+type Message struct {
+	Type string `json:"type"`
+
+	Sink bool `json:"sink,omitempty"`
+
+	Values []Value `json:"values,omitempty"`
+
+	Kind     string `json:"kind,omitempty"`
+	Active   bool   `json:"active,omitempty"`
+	Message  string `json:"message,omitempty"`
+	Severity string `json:"severity,omitempty"`
+	Title    string `json:"title,omitempty"`
+	Body     string `json:"body,omitempty"`
+	Resolved bool   `json:"resolved,omitempty"`
+}
+
+type plugin struct {
+	name string
+	path string
+	in   chan Message
+
+	mu   sync.Mutex
+	sink bool
+}
+
+// Manager runs the executables in the plugins directory as subprocesses
+// that talk JSON lines over stdio, and restarts them when they exit.
+type Manager struct {
+	dir     string
+	plugins []*plugin
+	alerts  Sink
+	updates chan<- state.Update
+}
+
+// New finds the plugins in dir, a missing dir has none.
+func New(dir string, updates chan<- state.Update) (*Manager, error) {
+	m := &Manager{
+		dir:     dir,
+		updates: updates,
+	}
+
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return m, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read the plugins directory")
+	}
+
+	for _, e := range entries {
+		info, err := e.Info()
+		if err != nil || !info.Mode().IsRegular() || !executable(info) {
+			continue
+		}
+
+		m.plugins = append(m.plugins, &plugin{
+			name: strings.TrimSuffix(e.Name(), filepath.Ext(e.Name())),
+			path: filepath.Join(dir, e.Name()),
+			in:   make(chan Message, queueSize),
+		})
+	}
+
+	return m, nil
+}
+
+func (m *Manager) Len() int {
+	return len(m.plugins)
+}
+
+// Notify passes n on to the sink plugins, dropping it for those that fall
+// behind.
+func (m *Manager) Notify(n alert.Notification) error {
+	msg := Message{
+		Type:     "notification",
+		Kind:     string(n.Kind),
+		Severity: string(n.Severity),
+		Title:    n.Title,
+		Body:     n.Body,
+		Resolved: n.Resolved,
+	}
+
+	for _, p := range m.plugins {
+		p.mu.Lock()
+		sink := p.sink
+		p.mu.Unlock()
+
+		if !sink {
+			continue
+		}
+
+		select {
+		case p.in <- msg:
+		default:
+			slog.Warn("plugin is not keeping up, notification dropped", "plugin", p.name)
+		}
+	}
+
+	return nil
+}
+
+func (m *Manager) status(i int, f func(p *state.Plugin)) {
+	count := len(m.plugins)
+	name := m.plugins[i].name
+
+	m.updates <- func(s *state.State) error {
+		if len(s.Plugins) != count {
+			s.Plugins = make([]state.Plugin, count)
+		}
+		s.Plugins[i].Name = name
+		f(&s.Plugins[i])
+		return nil
+	}
+}
+
+func (m *Manager) handle(i int, msg Message) {
+	p := m.plugins[i]
+
+	switch msg.Type {
+	case "hello":
+		p.mu.Lock()
+		p.sink = msg.Sink
+		p.mu.Unlock()
+	case "values":
+		values := make([]state.PanelValue, len(msg.Values))
+		for j, v := range msg.Values {
+			values[j] = state.PanelValue{Label: v.Label, Value: v.Value}
+		}
+
+		m.status(i, func(s *state.Plugin) {
+			s.Values = values
+			s.UpdatedAt = time.Now()
+		})
+	case "alert":
+		if msg.Kind == "" {
+			slog.Warn("plugin alert without a kind", "plugin", p.name)
+			return
+		}
+		m.alerts.Set(alert.Kind("plugin:"+p.name+":"+msg.Kind), msg.Active, msg.Message)
+	case "event":
+		m.alerts.Notice("plugin:"+p.name, msg.Title, msg.Body)
+	default:
+		slog.Warn("unknown plugin message", "plugin", p.name, "type", msg.Type)
+	}
+}
+
+func (m *Manager) run(ctx context.Context, i int) error {
+	p := m.plugins[i]
+
+	cmd := exec.CommandContext(ctx, p.path)
+	cmd.Dir = m.dir
+	cmd.Env = append(os.Environ(), "VOIUI_PLUGIN_PROTOCOL=1")
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return err
+	}
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return err
+	}
+
+	err = cmd.Start()
+	if err != nil {
+		return errors.Wrap(err, "failed to start")
+	}
+
+	m.status(i, func(s *state.Plugin) {
+		s.Running = true
+		s.Err = ""
+	})
+
+	done := make(chan struct{})
+	defer close(done)
+
+	go func() {
+		enc := json.NewEncoder(stdin)
+		for {
+			select {
+			case msg := <-p.in:
+				if enc.Encode(msg) != nil {
+					return
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	go logLines(p.name, stderr)
+
+	sc := bufio.NewScanner(stdout)
+	sc.Buffer(nil, 1<<20)
+
+	for sc.Scan() {
+		var msg Message
+
+		err := json.Unmarshal(sc.Bytes(), &msg)
+		if err != nil {
+			slog.Warn("invalid plugin message", "plugin", p.name, "err", err)
+			continue
+		}
+
+		m.handle(i, msg)
+	}
+
+	return cmd.Wait()
+}
+
+func logLines(name string, r io.Reader) {
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		slog.Info("plugin: "+sc.Text(), "plugin", name)
+	}
+}
+
+func (m *Manager) supervise(ctx context.Context, i int) {
+	p := m.plugins[i]
+	backoff := minBackoff
+
+	for {
+		started := time.Now()
+
+		err := m.run(ctx, i)
+		if ctx.Err() != nil {
+			return
+		}
+
+		if err == nil {
+			err = errors.New("exited")
+		}
+		slog.Error("plugin stopped", "plugin", p.name, "err", err)
+
+		p.mu.Lock()
+		p.sink = false
+		p.mu.Unlock()
+
+		m.status(i, func(s *state.Plugin) {
+			s.Running = false
+			s.Restarts++
+			s.Err = err.Error()
+		})
+
+		if time.Since(started) > maxBackoff {
+			backoff = minBackoff
+		}
+
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return
+		}
+
+		backoff *= 2
+		if backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
+}
+
+// Run starts the plugins, their alerts and events go to alerts. It returns
+// once ctx is done and they are stopped.
+func (m *Manager) Run(ctx context.Context, alerts Sink) {
+	m.alerts = alerts
+
+	var wg sync.WaitGroup
+
+	for i := range m.plugins {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			m.supervise(ctx, i)
+		}(i)
+	}
+
+	wg.Wait()
+}
@@ -0,0 +1,167 @@
+package portcheck
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"voiui/internal/alert"
+	"voiui/internal/state"
+)
+
+type Sink interface {
+	Set(kind alert.Kind, active bool, message string)
+}
+
+// Checker asks an external service whether the gossip port accepts
+// connections from the internet. The service is given the port in place
+// of {port} in its URL, connects back to the caller and answers with
+// {"open": true} or {"open": false}.
+type Checker struct {
+	url      string
+	port     int
+	dataDir  func() string
+	interval time.Duration
+	alerts   Sink
+	updates  chan<- state.Update
+	client   *http.Client
+}
+
+// New checks port, or the NetAddress port from algod's config.json in the
+// data directory when it is zero.
+func New(url string, port int, dataDir func() string, interval time.Duration, alerts Sink, updates chan<- state.Update) *Checker {
+	return &Checker{
+		url:      url,
+		port:     port,
+		dataDir:  dataDir,
+		interval: interval,
+		alerts:   alerts,
+		updates:  updates,
+		client:   &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// gossipPort reads the port algod listens on for peers, zero when it only
+// connects out.
+func gossipPort(dir string) (int, error) {
+	data, err := os.ReadFile(filepath.Join(dir, "config.json"))
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to read algod config")
+	}
+
+	var cfg struct {
+		NetAddress string
+	}
+
+	err = json.Unmarshal(data, &cfg)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to decode algod config")
+	}
+
+	if cfg.NetAddress == "" {
+		return 0, nil
+	}
+
+	_, port, err := net.SplitHostPort(cfg.NetAddress)
+	if err != nil {
+		return 0, errors.Wrapf(err, "invalid NetAddress %s", cfg.NetAddress)
+	}
+
+	return strconv.Atoi(port)
+}
+
+func (c *Checker) open(ctx context.Context, port int) (bool, error) {
+	u := strings.ReplaceAll(c.url, "{port}", strconv.Itoa(port))
+
+	req, err := http.NewRequestWithContext(ctx, "GET", u, nil)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to create port check request")
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to reach the port checker")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return false, errors.Errorf("port checker failed: %s", resp.Status)
+	}
+
+	var r struct {
+		Open bool `json:"open"`
+	}
+
+	err = json.NewDecoder(resp.Body).Decode(&r)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to decode the port checker response")
+	}
+
+	return r.Open, nil
+}
+
+func (c *Checker) check(ctx context.Context) error {
+	port := c.port
+	if port == 0 {
+		var err error
+		port, err = gossipPort(c.dataDir())
+		if err != nil {
+			return err
+		}
+	}
+
+	if port == 0 {
+		return nil
+	}
+
+	open, err := c.open(ctx, port)
+	if err != nil {
+		return err
+	}
+
+	c.alerts.Set(alert.PortClosed, !open, fmt.Sprintf("port %d cannot be reached from the internet, check the firewall and port forwarding", port))
+
+	p := state.Port{
+		Port:      port,
+		Open:      open,
+		CheckedAt: time.Now(),
+	}
+
+	c.updates <- func(s *state.State) error {
+		s.Port = p
+		return nil
+	}
+
+	return nil
+}
+
+func (c *Checker) Run(ctx context.Context) {
+	t := time.NewTicker(c.interval)
+	defer t.Stop()
+
+	for {
+		err := c.check(ctx)
+		if err != nil {
+			slog.Warn("port check failed", "err", err)
+		}
+
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package relay
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+	"strings"
+	"time"
+
+	"voiui/internal/alert"
+	"voiui/internal/state"
+)
+
+const (
+	inboundPeers  = "algod_network_incoming_peers"
+	outboundPeers = "algod_network_outgoing_peers"
+	sentBytes     = "algod_network_sent_bytes_total"
+	receivedBytes = "algod_network_received_bytes_total"
+)
+
+type Source interface {
+	Metrics(ctx context.Context) (map[string]float64, error)
+	Network() string
+}
+
+type Sink interface {
+	Set(kind alert.Kind, active bool, message string)
+}
+
+// Monitor follows a relay's peers and traffic through algod's metrics and
+// checks that the relay is listed in the network's SRV bootstrap records.
+type Monitor struct {
+	src      Source
+	name     string
+	domain   string
+	interval time.Duration
+	alerts   Sink
+	updates  chan<- state.Update
+
+	prev   map[string]float64
+	prevAt time.Time
+}
+
+// New monitors the relay reachable as name, a host or host:port expected
+// among the bootstrap records; empty skips that check.
+func New(src Source, name string, domain string, interval time.Duration, alerts Sink, updates chan<- state.Update) *Monitor {
+	return &Monitor{
+		src:      src,
+		name:     name,
+		domain:   domain,
+		interval: interval,
+		alerts:   alerts,
+		updates:  updates,
+	}
+}
+
+// listed looks the relay up in the bootstrap records.
+func (m *Monitor) listed(ctx context.Context, network string) (bool, error) {
+	_, srvs, err := net.DefaultResolver.LookupSRV(ctx, "algobootstrap", "tcp", network+"."+m.domain)
+	if err != nil {
+		return false, err
+	}
+
+	host, port, err := net.SplitHostPort(m.name)
+	if err != nil {
+		host = m.name
+	}
+
+	for _, srv := range srvs {
+		if !strings.EqualFold(strings.TrimSuffix(srv.Target, "."), strings.TrimSuffix(host, ".")) {
+			continue
+		}
+		if port == "" || port == fmt.Sprint(srv.Port) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func (m *Monitor) rate(cur map[string]float64, now time.Time, name string) float64 {
+	if m.prev == nil || cur[name] < m.prev[name] {
+		return 0
+	}
+
+	return (cur[name] - m.prev[name]) / now.Sub(m.prevAt).Seconds()
+}
+
+func (m *Monitor) check(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, m.interval)
+	defer cancel()
+
+	metrics, err := m.src.Metrics(ctx)
+	if err != nil {
+		slog.Warn("failed to read relay metrics", "err", err)
+		return
+	}
+
+	now := time.Now()
+
+	r := state.Relay{
+		Inbound:   int(metrics[inboundPeers]),
+		Outbound:  int(metrics[outboundPeers]),
+		Sent:      m.rate(metrics, now, sentBytes),
+		Received:  m.rate(metrics, now, receivedBytes),
+		Name:      m.name,
+		UpdatedAt: now,
+	}
+
+	m.prev, m.prevAt = metrics, now
+
+	if network := m.src.Network(); m.name != "" && network != "" {
+		listed, err := m.listed(ctx, network)
+		if err != nil {
+			slog.Warn("failed to look up bootstrap records", "err", err)
+		} else {
+			r.Checked = true
+			r.Listed = listed
+		}
+	}
+
+	m.alerts.Set(alert.NoInbound, r.Inbound == 0, "the relay has no inbound peers, check the firewall and the bootstrap records")
+
+	m.updates <- func(s *state.State) error {
+		s.Relay = r
+		return nil
+	}
+}
+
+func (m *Monitor) Run(ctx context.Context) {
+	t := time.NewTicker(m.interval)
+	defer t.Stop()
+
+	for {
+		m.check(ctx)
+
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
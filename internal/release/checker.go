@@ -0,0 +1,101 @@
+package release
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"voiui/internal/alert"
+	"voiui/internal/state"
+)
+
+type Sink interface {
+	Set(kind alert.Kind, active bool, message string)
+}
+
+// Checker compares the running algod with the latest release in feed.
+type Checker struct {
+	feed     string
+	interval time.Duration
+	current  func() string
+	alerts   Sink
+	updates  chan<- state.Update
+	notice   func(text string)
+
+	hc *http.Client
+}
+
+func NewChecker(feed string, interval time.Duration, current func() string, alerts Sink, updates chan<- state.Update, notice func(text string)) *Checker {
+	return &Checker{
+		feed:     feed,
+		interval: interval,
+		current:  current,
+		alerts:   alerts,
+		updates:  updates,
+		notice:   notice,
+		hc:       &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+func (c *Checker) check(ctx context.Context) error {
+	current, ok := ParseVersion(c.current())
+	if !ok {
+		return nil
+	}
+
+	r, err := Latest(ctx, c.hc, c.feed)
+	if err != nil {
+		return err
+	}
+
+	latest, ok := ParseVersion(r.Tag)
+	if !ok {
+		return nil
+	}
+
+	behind := current.Less(latest)
+
+	msg := "algod " + latest.String() + " is available, running " + current.String()
+	c.alerts.Set(alert.NodeOutdated, behind, msg)
+
+	update := state.NodeUpdate{
+		Latest:    latest.String(),
+		URL:       r.URL,
+		Available: behind,
+	}
+
+	if behind {
+		c.notice("Update available: algod " + latest.String())
+	} else {
+		c.notice("")
+	}
+
+	c.updates <- func(s *state.State) error {
+		s.NodeUpdate = update
+		return nil
+	}
+
+	return nil
+}
+
+func (c *Checker) Run(ctx context.Context) {
+	// give the monitor a moment to learn the algod version
+	t := time.NewTimer(time.Minute)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			return
+		}
+
+		err := c.check(ctx)
+		if err != nil {
+			slog.Error("failed to check for node updates", "err", err)
+		}
+
+		t.Reset(c.interval)
+	}
+}
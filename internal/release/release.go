@@ -0,0 +1,87 @@
+package release
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+type Asset struct {
+	Name string `json:"name"`
+	URL  string `json:"browser_download_url"`
+}
+
+type Release struct {
+	Tag    string  `json:"tag_name"`
+	Name   string  `json:"name"`
+	URL    string  `json:"html_url"`
+	Assets []Asset `json:"assets"`
+}
+
+// Latest fetches the latest release from a GitHub releases API URL, e.g.
+// https://api.github.com/repos/algorand/go-algorand/releases/latest.
+func Latest(ctx context.Context, hc *http.Client, feed string) (Release, error) {
+	var r Release
+
+	req, err := http.NewRequestWithContext(ctx, "GET", feed, nil)
+	if err != nil {
+		return r, errors.Wrap(err, "failed to create release request")
+	}
+
+	req.Header.Set("Accept", "application/vnd.github+json")
+
+	resp, err := hc.Do(req)
+	if err != nil {
+		return r, errors.Wrap(err, "failed to fetch release feed")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return r, errors.Errorf("failed to fetch release feed: %s", resp.Status)
+	}
+
+	err = json.NewDecoder(resp.Body).Decode(&r)
+	if err != nil {
+		return r, errors.Wrap(err, "failed to decode release feed")
+	}
+
+	return r, nil
+}
+
+type Version [3]int
+
+var versionRe = regexp.MustCompile(`(\d+)\.(\d+)\.(\d+)`)
+
+// ParseVersion finds the first x.y.z in s, e.g. in "v3.21.0-stable".
+func ParseVersion(s string) (Version, bool) {
+	var v Version
+
+	m := versionRe.FindStringSubmatch(s)
+	if m == nil {
+		return v, false
+	}
+
+	for i := range v {
+		v[i], _ = strconv.Atoi(m[i+1])
+	}
+
+	return v, true
+}
+
+func (v Version) Less(o Version) bool {
+	for i := range v {
+		if v[i] != o[i] {
+			return v[i] < o[i]
+		}
+	}
+	return false
+}
+
+func (v Version) String() string {
+	return fmt.Sprintf("%d.%d.%d", v[0], v[1], v[2])
+}
@@ -0,0 +1,106 @@
+package rules
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"voiui/internal/state"
+)
+
+// metrics are the values rules can compare, missing ones are not known
+// yet and never match.
+var metrics = map[string]func(s state.State, now time.Time) (float64, bool){
+	"round": func(s state.State, now time.Time) (float64, bool) {
+		return float64(s.Round), s.Round > 0
+	},
+	"block_time": func(s state.State, now time.Time) (float64, bool) {
+		return s.PrevBlockDuration.Seconds(), s.PrevBlockDuration > 0
+	},
+	"avg_block_time": func(s state.State, now time.Time) (float64, bool) {
+		return s.AvgBlockDuration.Seconds(), s.AvgBlockDuration > 0
+	},
+	"block_wait": func(s state.State, now time.Time) (float64, bool) {
+		return now.Sub(s.CurrBlockAt).Seconds(), !s.CurrBlockAt.IsZero()
+	},
+	"rtt_ms": func(s state.State, now time.Time) (float64, bool) {
+		return float64(s.RTT.Milliseconds()), s.RTT > 0
+	},
+	"tps": func(s state.State, now time.Time) (float64, bool) {
+		return s.Activity.TPS, s.Activity.TxnsPerBlock > 0 || s.Activity.LastTxns > 0
+	},
+	"txns_per_block": func(s state.State, now time.Time) (float64, bool) {
+		return s.Activity.TxnsPerBlock, s.Activity.TxnsPerBlock > 0 || s.Activity.LastTxns > 0
+	},
+	"peers_in": func(s state.State, now time.Time) (float64, bool) {
+		return float64(s.Relay.Inbound), !s.Relay.UpdatedAt.IsZero()
+	},
+	"peers_out": func(s state.State, now time.Time) (float64, bool) {
+		return float64(s.Relay.Outbound), !s.Relay.UpdatedAt.IsZero()
+	},
+	"cpu": func(s state.State, now time.Time) (float64, bool) {
+		return s.Resources.CPU, !s.Resources.UpdatedAt.IsZero()
+	},
+	"mem_percent": func(s state.State, now time.Time) (float64, bool) {
+		r := s.Resources
+		return percent(r.MemUsed, r.MemTotal), r.MemTotal > 0
+	},
+	"disk_free_percent": func(s state.State, now time.Time) (float64, bool) {
+		r := s.Resources
+		return percent(r.DiskFree, r.DiskTotal), r.DiskTotal > 0
+	},
+	"days_until_full": func(s state.State, now time.Time) (float64, bool) {
+		return s.Resources.DaysUntilFull, s.Resources.DaysUntilFull > 0
+	},
+	"clock_drift": func(s state.State, now time.Time) (float64, bool) {
+		return math.Abs(s.Clock.Offset.Seconds()), !s.Clock.CheckedAt.IsZero()
+	},
+	"indexer_lag": func(s state.State, now time.Time) (float64, bool) {
+		return float64(s.Indexer.Behind), !s.Indexer.UpdatedAt.IsZero()
+	},
+	// balance_min is the lowest watched balance in Voi.
+	"balance_min": func(s state.State, now time.Time) (float64, bool) {
+		var balances []float64
+		for _, a := range s.Watched {
+			if a.Err == "" && a.Round > 0 {
+				balances = append(balances, float64(a.Balance)/1e6)
+			}
+		}
+		return lowest(balances)
+	},
+	// key_rounds_left is how many rounds the key expiring first has left.
+	"key_rounds_left": func(s state.State, now time.Time) (float64, bool) {
+		var left []float64
+		for _, k := range s.Keys {
+			if k.LastValid > 0 && s.Round > 0 {
+				left = append(left, float64(k.LastValid)-float64(s.Round))
+			}
+		}
+		return lowest(left)
+	},
+}
+
+func percent(part uint64, total uint64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(part) / float64(total) * 100
+}
+
+func lowest(values []float64) (float64, bool) {
+	if len(values) == 0 {
+		return 0, false
+	}
+	sort.Float64s(values)
+	return values[0], true
+}
+
+// Metrics lists the names rules can use.
+func Metrics() []string {
+	names := make([]string, 0, len(metrics))
+	for name := range metrics {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,196 @@
+package rules
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"voiui/internal/alert"
+	"voiui/internal/config"
+	"voiui/internal/state"
+)
+
+const interval = 10 * time.Second
+
+var ops = map[string]func(a float64, b float64) bool{
+	">":  func(a, b float64) bool { return a > b },
+	">=": func(a, b float64) bool { return a >= b },
+	"<":  func(a, b float64) bool { return a < b },
+	"<=": func(a, b float64) bool { return a <= b },
+	"==": func(a, b float64) bool { return a == b },
+	"!=": func(a, b float64) bool { return a != b },
+}
+
+type Snapshotter interface {
+	Snapshot() state.State
+}
+
+type Sink interface {
+	Set(kind alert.Kind, active bool, message string)
+	Event(source string, kind string, message string)
+	SetSeverity(kind alert.Kind, severity alert.Severity)
+}
+
+type rule struct {
+	config.Rule
+
+	since  time.Time
+	active bool
+}
+
+// Engine evaluates the user-defined rules against the state.
+type Engine struct {
+	rules  []*rule
+	store  Snapshotter
+	alerts Sink
+	client *http.Client
+}
+
+func New(rules []config.Rule, store Snapshotter, alerts Sink) (*Engine, error) {
+	e := &Engine{
+		store:  store,
+		alerts: alerts,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+
+	for _, r := range rules {
+		if r.Name == "" {
+			return nil, errors.Errorf("alert rule on %s has no name", r.Metric)
+		}
+		if _, ok := metrics[r.Metric]; !ok {
+			return nil, errors.Errorf("alert rule %s: unknown metric %q, use one of %s", r.Name, r.Metric, strings.Join(Metrics(), ", "))
+		}
+		if _, ok := ops[r.Op]; !ok {
+			return nil, errors.Errorf("alert rule %s: unknown op %q", r.Name, r.Op)
+		}
+
+		switch r.Severity {
+		case "":
+			r.Severity = "warning"
+		case "info", "warning", "critical":
+		default:
+			return nil, errors.Errorf("alert rule %s: unknown severity %q", r.Name, r.Severity)
+		}
+
+		if len(r.Channels) == 0 {
+			r.Channels = []string{"alert"}
+			if r.Severity == "info" {
+				r.Channels = []string{"event"}
+			}
+		}
+
+		alerts.SetSeverity(alert.Kind("rule:"+r.Name), alert.Severity(r.Severity))
+		e.rules = append(e.rules, &rule{Rule: r})
+	}
+
+	return e, nil
+}
+
+type change struct {
+	Rule      string  `json:"rule"`
+	Severity  string  `json:"severity"`
+	Metric    string  `json:"metric"`
+	Value     float64 `json:"value"`
+	Threshold float64 `json:"threshold"`
+	Active    bool    `json:"active"`
+	Message   string  `json:"message"`
+}
+
+func (e *Engine) post(ctx context.Context, url string, c change) {
+	body, err := json.Marshal(c)
+	if err != nil {
+		return
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(body))
+	if err != nil {
+		slog.Error("invalid alert rule webhook", "rule", c.Rule, "err", err)
+		return
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		slog.Error("failed to post alert rule", "rule", c.Rule, "err", err)
+		return
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		slog.Error("failed to post alert rule", "rule", c.Rule, "status", resp.Status)
+	}
+}
+
+func (e *Engine) notify(ctx context.Context, r *rule, value float64) {
+	msg := fmt.Sprintf("[%s] %s: %s is %g, %s %g", r.Severity, r.Name, r.Metric, value, r.Op, r.Threshold)
+	if !r.active {
+		msg = fmt.Sprintf("%s: %s is back to %g", r.Name, r.Metric, value)
+	}
+
+	for _, ch := range r.Channels {
+		switch ch {
+		case "alert":
+			e.alerts.Set(alert.Kind("rule:"+r.Name), r.active, msg)
+		case "event":
+			e.alerts.Event("rule", r.Name, msg)
+		default:
+			go e.post(ctx, ch, change{
+				Rule:      r.Name,
+				Severity:  r.Severity,
+				Metric:    r.Metric,
+				Value:     value,
+				Threshold: r.Threshold,
+				Active:    r.active,
+				Message:   msg,
+			})
+		}
+	}
+}
+
+func (e *Engine) check(ctx context.Context, now time.Time) {
+	s := e.store.Snapshot()
+
+	for _, r := range e.rules {
+		value, ok := metrics[r.Metric](s, now)
+
+		if !ok || !ops[r.Op](value, r.Threshold) {
+			r.since = time.Time{}
+			if r.active {
+				r.active = false
+				e.notify(ctx, r, value)
+			}
+			continue
+		}
+
+		if r.since.IsZero() {
+			r.since = now
+		}
+
+		if !r.active && now.Sub(r.since) >= time.Duration(r.For) {
+			r.active = true
+			e.notify(ctx, r, value)
+		}
+	}
+}
+
+func (e *Engine) Run(ctx context.Context) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	for {
+		select {
+		case now := <-t.C:
+			e.check(ctx, now)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
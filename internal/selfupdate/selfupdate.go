@@ -0,0 +1,337 @@
+package selfupdate
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"voiui/internal/release"
+	"voiui/internal/state"
+)
+
+const (
+	Feed = "https://api.github.com/repos/dragmz/voiui/releases/latest"
+
+	checksumsAsset = "checksums.txt"
+	signatureAsset = "checksums.txt.sig"
+
+	maxChecksums = 1 << 20
+)
+
+// PublicKey is the hex encoded ed25519 key the checksums file is signed
+// with. Release builds set it with
+// -ldflags "-X voiui/internal/selfupdate.PublicKey=...". Without a valid
+// key updates are only announced, never downloaded or installed.
+var PublicKey string
+
+func publicKey() (ed25519.PublicKey, error) {
+	key, err := hex.DecodeString(PublicKey)
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return nil, errors.New("no valid release public key")
+	}
+	return key, nil
+}
+
+func assetName() string {
+	name := fmt.Sprintf("voiui_%s_%s", runtime.GOOS, runtime.GOARCH)
+	if runtime.GOOS == "windows" {
+		name += ".exe"
+	}
+	return name
+}
+
+func executable() (string, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return "", errors.Wrap(err, "failed to find executable")
+	}
+
+	exe, err = filepath.EvalSymlinks(exe)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to resolve executable")
+	}
+
+	return exe, nil
+}
+
+// Apply installs an update staged by a previous run. It returns true when
+// the executable was replaced and the process should restart.
+func Apply() (bool, error) {
+	exe, err := executable()
+	if err != nil {
+		return false, err
+	}
+
+	os.Remove(exe + ".old")
+
+	staged := exe + ".new"
+	if _, err := os.Stat(staged); os.IsNotExist(err) {
+		return false, nil
+	}
+
+	if _, err := publicKey(); err != nil {
+		os.Remove(staged)
+		return false, errors.Wrap(err, "refusing to install the staged update")
+	}
+
+	err = os.Rename(exe, exe+".old")
+	if err != nil {
+		return false, errors.Wrap(err, "failed to move the current executable")
+	}
+
+	err = os.Rename(staged, exe)
+	if err != nil {
+		os.Rename(exe+".old", exe)
+		return false, errors.Wrap(err, "failed to install the update")
+	}
+
+	return true, nil
+}
+
+// Restart starts the installed executable with the same arguments.
+func Restart() error {
+	exe, err := executable()
+	if err != nil {
+		return err
+	}
+
+	cmd := exec.Command(exe, os.Args[1:]...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	return errors.Wrap(cmd.Start(), "failed to restart")
+}
+
+type Updater struct {
+	current string
+	updates chan<- state.Update
+
+	hc *http.Client
+}
+
+func New(current string, updates chan<- state.Update) *Updater {
+	return &Updater{
+		current: current,
+		updates: updates,
+		hc:      &http.Client{Timeout: 10 * time.Minute},
+	}
+}
+
+func (u *Updater) get(ctx context.Context, url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create request")
+	}
+
+	resp, err := u.hc.Do(req)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to download %s", url)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, errors.Errorf("failed to download %s: %s", url, resp.Status)
+	}
+
+	return resp, nil
+}
+
+func (u *Updater) fetch(ctx context.Context, url string) ([]byte, error) {
+	resp, err := u.get(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	return io.ReadAll(io.LimitReader(resp.Body, maxChecksums))
+}
+
+func checksum(sums []byte, name string) (string, error) {
+	sc := bufio.NewScanner(bytes.NewReader(sums))
+	for sc.Scan() {
+		fields := strings.Fields(sc.Text())
+		if len(fields) == 2 && strings.TrimPrefix(fields[1], "*") == name {
+			return fields[0], nil
+		}
+	}
+
+	return "", errors.Errorf("no checksum for %s", name)
+}
+
+func verify(key ed25519.PublicKey, sums []byte, sig []byte) error {
+	sig, err := hex.DecodeString(strings.TrimSpace(string(sig)))
+	if err != nil {
+		return errors.Wrap(err, "invalid checksums signature")
+	}
+
+	if !ed25519.Verify(key, sums, sig) {
+		return errors.New("checksums signature does not verify")
+	}
+
+	return nil
+}
+
+// Check downloads a newer release next to the executable, it is installed
+// by Apply on the next start. It returns the newer version, if any, and
+// whether it was staged: without a valid PublicKey it is only announced.
+func (u *Updater) Check(ctx context.Context) (string, bool, error) {
+	current, ok := release.ParseVersion(u.current)
+	if !ok {
+		return "", false, nil
+	}
+
+	r, err := release.Latest(ctx, u.hc, Feed)
+	if err != nil {
+		return "", false, err
+	}
+
+	latest, ok := release.ParseVersion(r.Tag)
+	if !ok || !current.Less(latest) {
+		return "", false, nil
+	}
+
+	key, err := publicKey()
+	if err != nil {
+		return latest.String(), false, nil
+	}
+
+	err = u.stage(ctx, r, key)
+	if err != nil {
+		return latest.String(), false, err
+	}
+
+	return latest.String(), true, nil
+}
+
+// stage downloads the release r next to the executable once its checksums
+// verify with key.
+func (u *Updater) stage(ctx context.Context, r release.Release, key ed25519.PublicKey) error {
+	assets := map[string]string{}
+	for _, a := range r.Assets {
+		assets[a.Name] = a.URL
+	}
+
+	name := assetName()
+
+	if assets[name] == "" || assets[checksumsAsset] == "" {
+		return errors.Errorf("release %s has no %s or %s", r.Tag, name, checksumsAsset)
+	}
+
+	sums, err := u.fetch(ctx, assets[checksumsAsset])
+	if err != nil {
+		return err
+	}
+
+	if assets[signatureAsset] == "" {
+		return errors.Errorf("release %s is not signed", r.Tag)
+	}
+
+	sig, err := u.fetch(ctx, assets[signatureAsset])
+	if err != nil {
+		return err
+	}
+
+	err = verify(key, sums, sig)
+	if err != nil {
+		return err
+	}
+
+	want, err := checksum(sums, name)
+	if err != nil {
+		return err
+	}
+
+	exe, err := executable()
+	if err != nil {
+		return err
+	}
+
+	resp, err := u.get(ctx, assets[name])
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	f, err := os.CreateTemp(filepath.Dir(exe), filepath.Base(exe)+".download.*")
+	if err != nil {
+		return errors.Wrap(err, "failed to create download file")
+	}
+	defer os.Remove(f.Name())
+
+	h := sha256.New()
+
+	_, err = io.Copy(io.MultiWriter(f, h), resp.Body)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return errors.Wrap(err, "failed to download update")
+	}
+
+	if got := hex.EncodeToString(h.Sum(nil)); !strings.EqualFold(got, want) {
+		return errors.Errorf("checksum mismatch for %s", name)
+	}
+
+	err = os.Chmod(f.Name(), 0755)
+	if err != nil {
+		return errors.Wrap(err, "failed to make update executable")
+	}
+
+	err = os.Rename(f.Name(), exe+".new")
+	if err != nil {
+		return errors.Wrap(err, "failed to stage update")
+	}
+
+	return nil
+}
+
+func (u *Updater) Run(ctx context.Context) {
+	t := time.NewTimer(time.Minute)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			return
+		}
+
+		latest, staged, err := u.Check(ctx)
+		if err != nil {
+			slog.Error("self-update failed", "err", err)
+		}
+
+		if staged {
+			u.updates <- func(s *state.State) error {
+				s.StagedUpdate = latest
+				return nil
+			}
+			return
+		}
+
+		if latest != "" {
+			u.updates <- func(s *state.State) error {
+				s.AvailableUpdate = latest
+				return nil
+			}
+		}
+
+		t.Reset(24 * time.Hour)
+	}
+}
@@ -0,0 +1,176 @@
+package selfupdate
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"voiui/internal/release"
+)
+
+func TestStage(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, other, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	binary := []byte("new voiui")
+	sum := sha256.Sum256(binary)
+	name := assetName()
+
+	sums := func(digest []byte) string {
+		return fmt.Sprintf("%s  %s\n", hex.EncodeToString(digest), name)
+	}
+	sign := func(key ed25519.PrivateKey, sums string) string {
+		return hex.EncodeToString(ed25519.Sign(key, []byte(sums)))
+	}
+
+	good := sums(sum[:])
+	bad := sums(make([]byte, sha256.Size))
+
+	tests := []struct {
+		name   string
+		files  map[string]string
+		staged bool
+		err    string
+	}{
+		{
+			name:   "verified",
+			files:  map[string]string{name: string(binary), checksumsAsset: good, signatureAsset: sign(priv, good)},
+			staged: true,
+		},
+		{
+			name:  "missing asset",
+			files: map[string]string{checksumsAsset: good, signatureAsset: sign(priv, good)},
+			err:   "has no",
+		},
+		{
+			name:  "unsigned",
+			files: map[string]string{name: string(binary), checksumsAsset: good},
+			err:   "is not signed",
+		},
+		{
+			name:  "bad signature",
+			files: map[string]string{name: string(binary), checksumsAsset: good, signatureAsset: sign(other, good)},
+			err:   "does not verify",
+		},
+		{
+			name:  "malformed signature",
+			files: map[string]string{name: string(binary), checksumsAsset: good, signatureAsset: "not hex"},
+			err:   "invalid checksums signature",
+		},
+		{
+			name:  "no checksum",
+			files: map[string]string{name: string(binary), checksumsAsset: "\n", signatureAsset: sign(priv, "\n")},
+			err:   "no checksum",
+		},
+		{
+			name:  "checksum mismatch",
+			files: map[string]string{name: string(binary), checksumsAsset: bad, signatureAsset: sign(priv, bad)},
+			err:   "checksum mismatch",
+		},
+	}
+
+	exe, err := executable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	staged := exe + ".new"
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, ok := tt.files[strings.TrimPrefix(r.URL.Path, "/")]
+				if !ok {
+					http.NotFound(w, r)
+					return
+				}
+				w.Write([]byte(body))
+			}))
+			defer srv.Close()
+
+			r := release.Release{Tag: "v9.9.9"}
+			for file := range tt.files {
+				r.Assets = append(r.Assets, release.Asset{Name: file, URL: srv.URL + "/" + file})
+			}
+
+			os.Remove(staged)
+			defer os.Remove(staged)
+
+			err := New("v1.0.0", nil).stage(context.Background(), r, pub)
+			if tt.err == "" && err != nil {
+				t.Fatal(err)
+			}
+			if tt.err != "" && (err == nil || !strings.Contains(err.Error(), tt.err)) {
+				t.Fatalf("err = %v, want %q", err, tt.err)
+			}
+
+			data, err := os.ReadFile(staged)
+			if tt.staged != (err == nil) {
+				t.Fatalf("staged = %v, want %v", err == nil, tt.staged)
+			}
+			if tt.staged && string(data) != string(binary) {
+				t.Errorf("staged %q", data)
+			}
+		})
+	}
+}
+
+func TestApply(t *testing.T) {
+	exe, err := executable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	staged := exe + ".new"
+
+	defer func(key string) { PublicKey = key }(PublicKey)
+	PublicKey = ""
+
+	tests := []struct {
+		name   string
+		staged bool
+		err    bool
+	}{
+		{name: "nothing staged"},
+		{name: "no release key", staged: true, err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Remove(staged)
+			defer os.Remove(staged)
+
+			if tt.staged {
+				err := os.WriteFile(staged, []byte("unverified"), 0o755)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			restart, err := Apply()
+			if restart {
+				t.Error("replaced the executable")
+			}
+			if tt.err != (err != nil) {
+				t.Errorf("err = %v, want error %v", err, tt.err)
+			}
+			if _, err := os.Stat(staged); !os.IsNotExist(err) {
+				t.Errorf("staged update left behind: %v", err)
+			}
+			if _, err := os.Stat(exe); err != nil {
+				t.Errorf("executable gone: %v", err)
+			}
+		})
+	}
+}
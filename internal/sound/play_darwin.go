@@ -0,0 +1,11 @@
+package sound
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+)
+
+func play(ctx context.Context, path string, volume float64) error {
+	return exec.CommandContext(ctx, "afplay", "-v", fmt.Sprintf("%.2f", volume), path).Run()
+}
@@ -0,0 +1,15 @@
+package sound
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+)
+
+func play(ctx context.Context, path string, volume float64) error {
+	err := exec.CommandContext(ctx, "paplay", fmt.Sprintf("--volume=%d", int(volume*65536)), path).Run()
+	if err != nil {
+		return exec.CommandContext(ctx, "aplay", "-q", path).Run()
+	}
+	return nil
+}
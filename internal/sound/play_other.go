@@ -0,0 +1,13 @@
+//go:build !windows && !darwin && !linux
+
+package sound
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+)
+
+func play(ctx context.Context, path string, volume float64) error {
+	return errors.New("playing sounds is not supported on this platform")
+}
@@ -0,0 +1,13 @@
+package sound
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+)
+
+// SoundPlayer has no volume control, files always play at full volume.
+func play(ctx context.Context, path string, volume float64) error {
+	quoted := "'" + strings.ReplaceAll(path, "'", "''") + "'"
+	return exec.CommandContext(ctx, "powershell", "-NoProfile", "-Command", "(New-Object Media.SoundPlayer "+quoted+").PlaySync()").Run()
+}
@@ -0,0 +1,97 @@
+package sound
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"voiui/internal/alert"
+	"voiui/internal/config"
+)
+
+const timeout = 30 * time.Second
+
+// Play plays an audio file at volume, from 0 to 1, where the platform's
+// player supports it.
+func Play(ctx context.Context, path string, volume float64) error {
+	return play(ctx, path, volume)
+}
+
+// ProposalTone is the on_proposal sound that plays the built-in tone
+// instead of a file.
+const ProposalTone = "tone"
+
+// PlayProposal plays the built-in proposal tone.
+func PlayProposal(ctx context.Context) error {
+	return tone(ctx, "proposal", proposalTone, 1)
+}
+
+// tone plays notes at volume through a temporary file.
+func tone(ctx context.Context, name string, notes []note, volume float64) error {
+	path := filepath.Join(os.TempDir(), "voiui-"+name+".wav")
+
+	err := os.WriteFile(path, wav(notes, volume), 0600)
+	if err != nil {
+		return errors.Wrap(err, "failed to write tone")
+	}
+
+	return Play(ctx, path, 1)
+}
+
+// Player sounds the node going down and losing participation. As a
+// notifier it stays quiet with the other notifications.
+type Player struct {
+	cfg config.Sounds
+}
+
+func New(cfg config.Sounds) *Player {
+	return &Player{cfg: cfg}
+}
+
+// Enabled tells whether any sound is turned on.
+func (p *Player) Enabled() bool {
+	return p.cfg.Down.Enabled || p.cfg.Participation.Enabled
+}
+
+func (p *Player) play(name string, s config.Sound, notes []note) {
+	if !s.Enabled {
+		return
+	}
+
+	volume := s.Volume
+	if volume <= 0 || volume > 1 {
+		volume = 1
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var err error
+	if s.File != "" {
+		err = Play(ctx, s.File, volume)
+	} else {
+		err = tone(ctx, name, notes, volume)
+	}
+	if err != nil {
+		slog.Error("failed to play sound", "sound", name, "err", err)
+	}
+}
+
+func (p *Player) Notify(n alert.Notification) error {
+	if n.Resolved {
+		return nil
+	}
+
+	switch n.Kind {
+	case alert.Down:
+		go p.play("down", p.cfg.Down, downTone)
+	case alert.NotParticipating:
+		go p.play("participation", p.cfg.Participation, participationTone)
+	}
+
+	return nil
+}
@@ -0,0 +1,70 @@
+package sound
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+)
+
+const rate = 22050
+
+// note is a sine tone in Hz, a zero frequency is a pause.
+type note struct {
+	freq float64
+	ms   int
+}
+
+// wav renders notes as 16-bit mono PCM, fading each in and out so they do
+// not click.
+func wav(notes []note, volume float64) []byte {
+	var samples []int16
+
+	for _, n := range notes {
+		count := rate * n.ms / 1000
+		fade := rate / 100
+
+		for i := 0; i < count; i++ {
+			if n.freq == 0 {
+				samples = append(samples, 0)
+				continue
+			}
+
+			env := 1.0
+			if i < fade {
+				env = float64(i) / float64(fade)
+			} else if count-i < fade {
+				env = float64(count-i) / float64(fade)
+			}
+
+			v := math.Sin(2*math.Pi*n.freq*float64(i)/rate) * env * volume
+			samples = append(samples, int16(v*math.MaxInt16*0.8))
+		}
+	}
+
+	var b bytes.Buffer
+	size := len(samples) * 2
+
+	b.WriteString("RIFF")
+	binary.Write(&b, binary.LittleEndian, uint32(36+size))
+	b.WriteString("WAVEfmt ")
+	binary.Write(&b, binary.LittleEndian, struct {
+		Size             uint32
+		Format, Channels uint16
+		Rate, ByteRate   uint32
+		Align, Bits      uint16
+	}{16, 1, 1, rate, rate * 2, 2, 16})
+	b.WriteString("data")
+	binary.Write(&b, binary.LittleEndian, uint32(size))
+	binary.Write(&b, binary.LittleEndian, samples)
+
+	return b.Bytes()
+}
+
+var (
+	// falling, the node went away
+	downTone = []note{{880, 250}, {0, 60}, {587, 250}, {0, 60}, {440, 450}}
+	// low repeated beeps, votes are not counted
+	participationTone = []note{{330, 150}, {0, 100}, {330, 150}, {0, 100}, {330, 150}}
+	// rising chime, a block was won
+	proposalTone = []note{{660, 120}, {880, 120}, {1320, 300}}
+)
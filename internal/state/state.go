@@ -0,0 +1,521 @@
+package state
+
+import "time"
+
+// ErrorKind sorts poll errors by what the operator can do about them.
+type ErrorKind string
+
+const (
+	ErrorUnauthorized ErrorKind = "unauthorized"
+	ErrorRefused      ErrorKind = "refused"
+	ErrorTimeout      ErrorKind = "timeout"
+	ErrorDNS          ErrorKind = "dns"
+	ErrorTLS          ErrorKind = "tls"
+	ErrorOther        ErrorKind = "other"
+)
+
+type PollError struct {
+	Kind    ErrorKind
+	Message string
+	At      time.Time
+}
+
+// Service tells which node service controls the active profile has.
+type Service struct {
+	Start   bool
+	Stop    bool
+	Restart bool
+	Log     bool
+}
+
+type State struct {
+	// Version counts the updates the Store applied, snapshots with the
+	// same Version hold the same state.
+	Version uint64
+
+	Locked bool
+
+	Profile     string
+	AlertsMuted bool
+	// DataDirs are the data directories found when none was given, DataDir
+	// is the one in use.
+	DataDir  string
+	DataDirs []string
+	// AlertsSilenced holds notifications back until then.
+	AlertsSilenced time.Time
+	// Snoozed alert kinds send no notifications until then.
+	Snoozed []Snooze
+
+	Running bool
+	Service Service
+
+	// AccountsOnly is set when watching accounts without a node of our own.
+	AccountsOnly bool
+
+	Network         string
+	GenesisID       string
+	ExpectedNetwork string
+
+	NodeVersion string
+	NodeUpdate  NodeUpdate
+	Consensus   Consensus
+
+	Round         uint64
+	Participation Participation
+
+	PrevBlockDuration time.Duration
+	CurrBlockAt       time.Time
+	AvgBlockDuration  time.Duration
+	Activity          Activity
+
+	Keys []Key
+
+	Watched   []Account
+	Dismissed []string
+
+	Unauthorized bool
+	TokenNote    string
+	// PollError is why the node could not be polled, zero while it can.
+	PollError PollError
+	// RTT is how long algod took to answer the last health probe, zero
+	// until one succeeds.
+	RTT     time.Duration
+	RTTSlow bool
+
+	AdminSealed        bool
+	AdminLocked        bool
+	AdminUnlockedUntil time.Time
+
+	RetryAt      time.Time
+	RetryAttempt int
+	RetryMax     int
+	RetryStopped bool
+
+	SignedTxns []SignedTxn
+	Pending    Pending
+	KMD        KMD
+	Signing    Signing
+
+	Incidents []Incident
+
+	Performance Performance
+	Rewards     Rewards
+	Uptime      Uptime
+	History     History
+
+	Alerts []AlertIncident
+	Events []Event
+
+	UPS UPS
+
+	StagedUpdate string
+	// AvailableUpdate is a newer voiui that this build cannot verify and
+	// so does not download.
+	AvailableUpdate string
+
+	Hardware Hardware
+	Clock    Clock
+	Port     Port
+	Relay    Relay
+	Indexer  Indexer
+	Follower Follower
+
+	Connectivity []Connectivity
+
+	Resources Resources
+
+	Panels  []Panel
+	Plugins []Plugin
+}
+
+type PanelValue struct {
+	Label string
+	Value string
+}
+
+type Panel struct {
+	Title  string
+	Values []PanelValue
+
+	Err       string
+	UpdatedAt time.Time
+}
+
+// Plugin is a subprocess from the plugins directory, Values are what it
+// last reported.
+type Plugin struct {
+	Name     string
+	Running  bool
+	Restarts int
+	Err      string
+
+	Values    []PanelValue
+	UpdatedAt time.Time
+}
+
+// NodeSetting is an effective setting of algod's config.json, values are
+// JSON and Default is empty when voiui does not know it.
+type NodeSetting struct {
+	Name    string
+	Value   string
+	Default string
+	Changed bool
+}
+
+// Telemetry is the telemetry part of algod's logging.config.
+type Telemetry struct {
+	Enabled bool
+	Name    string
+	GUID    string
+}
+
+type Resources struct {
+	CPU      float64
+	MemUsed  uint64
+	MemTotal uint64
+
+	DataDir     string
+	DataDirSize uint64
+	DiskFree    uint64
+	DiskTotal   uint64
+	LowDisk     bool
+
+	LedgerSize    uint64
+	LedgerGrowth  float64
+	DaysUntilFull float64
+
+	AlgodRunning bool
+	AlgodCPU     float64
+	AlgodRSS     uint64
+
+	// HostErr and AlgodErr tell why the host or the algod usage is missing.
+	HostErr  string
+	AlgodErr string
+
+	UpdatedAt time.Time
+}
+
+type Connectivity struct {
+	At      time.Time
+	Relays  int
+	Latency time.Duration
+	Jitter  time.Duration
+	Loss    float64
+}
+
+type Temp struct {
+	Name     string
+	Celsius  float64
+	Critical float64
+}
+
+type Disk struct {
+	Device  string
+	Healthy bool
+	Status  string
+	Celsius float64
+}
+
+type Hardware struct {
+	Temps []Temp
+	Disks []Disk
+
+	UpdatedAt time.Time
+}
+
+// Clock is how far the local clock is from an NTP server, positive when
+// it is behind.
+type Clock struct {
+	Offset    time.Duration
+	Server    string
+	Off       bool
+	CheckedAt time.Time
+}
+
+// Port is whether the gossip port is reachable from the internet.
+type Port struct {
+	Port      int
+	Open      bool
+	CheckedAt time.Time
+}
+
+// Relay is what a relay node reports about its peers, traffic in bytes
+// per second.
+type Relay struct {
+	Inbound  int
+	Outbound int
+	Sent     float64
+	Received float64
+
+	// Name is the relay's address in the bootstrap records, Listed is only
+	// meaningful when Checked.
+	Name    string
+	Checked bool
+	Listed  bool
+
+	UpdatedAt time.Time
+}
+
+// Indexer is the health of the profile's indexer, Err is empty while it
+// is healthy.
+type Indexer struct {
+	Round     uint64
+	Behind    uint64
+	Err       string
+	UpdatedAt time.Time
+}
+
+// Follower is the round a follower node holds its ledger at for a data
+// pipeline, and whether the state delta for it is available.
+type Follower struct {
+	SyncRound  uint64
+	Delta      bool
+	AdvancedAt time.Time
+	UpdatedAt  time.Time
+}
+
+// Activity is the network's transaction rate over the last blocks.
+type Activity struct {
+	LastTxns     int
+	TxnsPerBlock float64
+	TPS          float64
+}
+
+type UPS struct {
+	Name   string
+	Status string
+
+	OnBattery  bool
+	LowBattery bool
+
+	Charge  float64
+	Runtime time.Duration
+	Load    float64
+
+	Err       string
+	UpdatedAt time.Time
+}
+
+type Event struct {
+	At      time.Time
+	Source  string
+	Kind    string
+	Message string
+}
+
+type AlertEvent struct {
+	At      time.Time
+	Kind    string
+	Message string
+	Cleared bool
+}
+
+type AlertIncident struct {
+	ID    int
+	Title string
+	// Kind is the root cause the title is from.
+	Kind     string
+	Severity string
+
+	Opened   time.Time
+	Resolved time.Time
+	Acked    time.Time
+
+	Timeline []AlertEvent
+}
+
+type Snooze struct {
+	Kind  string
+	Until time.Time
+}
+
+type NodeUpdate struct {
+	Latest    string
+	URL       string
+	Available bool
+}
+
+type Consensus struct {
+	Current       string
+	Next          string
+	NextRound     uint64
+	NextSupported bool
+
+	// upgrade vote, only while a proposal is being voted on
+	VoteBefore    uint64
+	VoteRounds    uint64
+	Votes         uint64
+	YesVotes      uint64
+	NoVotes       uint64
+	VotesRequired uint64
+	NodeVote      bool
+}
+
+const (
+	OutageConnection    = "connection"
+	OutageParticipation = "participation"
+)
+
+type Outage struct {
+	Kind  string    `json:"kind"`
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end,omitempty"`
+}
+
+// Uptime covers the last 30 days. Connection and Participation are -1
+// until there is data.
+type Uptime struct {
+	Connection    float64
+	Participation float64
+	Observed      time.Duration
+	Outages       []Outage
+}
+
+// History summarizes the local history database.
+type History struct {
+	// BlockTimes are hourly averages over the last 24 hours, oldest first.
+	BlockTimes []time.Duration
+	// TxnsPerBlock are hourly averages over the same hours.
+	TxnsPerBlock []float64
+	Proposals    int
+	LastProposal time.Time
+	Weekly       Comparison
+}
+
+// Week sums up seven days of history for the weekly comparison.
+type Week struct {
+	From      time.Time
+	To        time.Time
+	Uptime    float64
+	Proposals int
+	// Rewards are only known when HasRewards is set.
+	Rewards    uint64
+	HasRewards bool
+	AvgBlock   time.Duration
+}
+
+type Comparison struct {
+	This  Week
+	Last  Week
+	Notes []string
+}
+
+type Performance struct {
+	Index    float64
+	Expected float64
+	Actual   int
+
+	RecentIndex    float64
+	RecentExpected float64
+
+	// PerDay is the expected number of proposals per day at current stake.
+	PerDay float64
+	// PValue is the chance of proposing this few blocks or fewer by luck.
+	PValue float64
+
+	Underperforming bool
+}
+
+// Rewards are earnings of the participating accounts in microVoi.
+type Rewards struct {
+	Today uint64
+	Week  uint64
+	Month uint64
+
+	Stake uint64
+	// APR is estimated from the last 30 days, in percent.
+	APR float64
+}
+
+type Incident struct {
+	Start time.Time
+	End   time.Time
+
+	StartRound uint64
+	EndRound   uint64
+
+	MissedProposals float64
+}
+
+type Key struct {
+	ID         string
+	Address    string
+	FirstValid uint64
+	LastValid  uint64
+
+	// zero until the key voted or proposed
+	LastVote     uint64
+	LastProposal uint64
+}
+
+// Account is a watched address, refreshed every round.
+type Account struct {
+	Address string
+	Label   string
+
+	Balance      uint64
+	Online       bool
+	KeyLastValid uint64
+	Round        uint64
+	Err          string
+}
+
+type PendingTxn struct {
+	ID          string
+	Type        string
+	Sender      string
+	Description string
+	// Ours is set for transactions from the node's or watched accounts.
+	Ours bool
+}
+
+// Pending is the node's transaction pool, Txns lists only the first ones.
+type Pending struct {
+	Total uint64
+	Txns  []PendingTxn
+}
+
+// KMD is what is known about the node's kmd wallets. Passwords are never
+// kept here.
+type KMD struct {
+	Wallets []string
+	// Unlocked is the wallet whose password was last accepted.
+	Unlocked string
+	// Accounts are the addresses Unlocked holds keys for.
+	Accounts []string
+}
+
+// Signing is a key registration waiting to be signed on a phone.
+type Signing struct {
+	// Txn is the unsigned transaction, msgpack in base64.
+	Txn    string
+	TxID   string
+	Online bool
+}
+
+type SignedTxn struct {
+	Path    string
+	Name    string
+	Summary string
+	Err     string
+}
+
+// Participation tells whether the node's keys are voting.
+type Participation string
+
+const (
+	// ParticipationUnknown is used until keys and their accounts were read,
+	// e.g. while the admin token is locked.
+	ParticipationUnknown Participation = ""
+	// ParticipationActive means a key covers the current round plus the
+	// grace margin and its account is online with that key registered.
+	ParticipationActive Participation = "active"
+	// ParticipationOffline means a key covers the round but its account is
+	// offline, so it does not vote.
+	ParticipationOffline Participation = "account-offline"
+	// ParticipationNoKey means no registered key covers the round plus the
+	// grace margin, e.g. the account went online with another key.
+	ParticipationNoKey Participation = "no-key"
+)
+
+type Update func(*State) error
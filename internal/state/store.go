@@ -0,0 +1,124 @@
+package state
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// Store owns the state. Updates are applied in one place and every window
+// and other reader works from snapshots, so none of them can hold the
+// others up.
+type Store struct {
+	mu   sync.RWMutex
+	s    State
+	subs map[chan struct{}]struct{}
+}
+
+func NewStore(s State) *Store {
+	return &Store{s: s, subs: map[chan struct{}]struct{}{}}
+}
+
+func (st *Store) Apply(u Update) error {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	version := st.s.Version
+	err := u(&st.s)
+	st.s.Version = version + 1
+
+	for c := range st.subs {
+		select {
+		case c <- struct{}{}:
+		default:
+		}
+	}
+
+	return err
+}
+
+// Subscribe signals on the channel after changes; signals coalesce while
+// the subscriber is busy. The returned func unsubscribes.
+func (st *Store) Subscribe() (<-chan struct{}, func()) {
+	c := make(chan struct{}, 1)
+
+	st.mu.Lock()
+	st.subs[c] = struct{}{}
+	st.mu.Unlock()
+
+	return c, func() {
+		st.mu.Lock()
+		delete(st.subs, c)
+		st.mu.Unlock()
+	}
+}
+
+func (st *Store) Snapshot() State {
+	st.mu.RLock()
+	defer st.mu.RUnlock()
+
+	s := st.s
+	s.Keys = append([]Key(nil), s.Keys...)
+	s.Watched = append([]Account(nil), s.Watched...)
+	s.Dismissed = append([]string(nil), s.Dismissed...)
+	s.SignedTxns = append([]SignedTxn(nil), s.SignedTxns...)
+	s.Pending.Txns = append([]PendingTxn(nil), s.Pending.Txns...)
+	s.KMD.Wallets = append([]string(nil), s.KMD.Wallets...)
+	s.KMD.Accounts = append([]string(nil), s.KMD.Accounts...)
+	s.Incidents = append([]Incident(nil), s.Incidents...)
+	s.Alerts = append([]AlertIncident(nil), s.Alerts...)
+	s.Events = append([]Event(nil), s.Events...)
+	s.Snoozed = append([]Snooze(nil), s.Snoozed...)
+	s.Connectivity = append([]Connectivity(nil), s.Connectivity...)
+	s.Panels = append([]Panel(nil), s.Panels...)
+	s.Plugins = append([]Plugin(nil), s.Plugins...)
+	s.DataDirs = append([]string(nil), s.DataDirs...)
+	s.Hardware.Temps = append([]Temp(nil), s.Hardware.Temps...)
+	s.Hardware.Disks = append([]Disk(nil), s.Hardware.Disks...)
+	s.Uptime.Outages = append([]Outage(nil), s.Uptime.Outages...)
+	s.History.BlockTimes = append([]time.Duration(nil), s.History.BlockTimes...)
+	s.History.TxnsPerBlock = append([]float64(nil), s.History.TxnsPerBlock...)
+	s.History.Weekly.Notes = append([]string(nil), s.History.Weekly.Notes...)
+
+	for i := range s.Alerts {
+		s.Alerts[i].Timeline = append([]AlertEvent(nil), s.Alerts[i].Timeline...)
+	}
+	for i := range s.Panels {
+		s.Panels[i].Values = append([]PanelValue(nil), s.Panels[i].Values...)
+	}
+	for i := range s.Plugins {
+		s.Plugins[i].Values = append([]PanelValue(nil), s.Plugins[i].Values...)
+	}
+
+	return s
+}
+
+// Watch calls f with a snapshot after changes until ctx is done. Changes
+// made while f runs are seen together by the next call, so a slow view
+// skips intermediate states but never the latest one.
+func (st *Store) Watch(ctx context.Context, f func(s State)) {
+	changed, unsubscribe := st.Subscribe()
+	defer unsubscribe()
+
+	f(st.Snapshot())
+
+	for {
+		select {
+		case <-changed:
+			f(st.Snapshot())
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// Run applies every update from in to the store.
+func (st *Store) Run(in <-chan Update) {
+	for u := range in {
+		err := st.Apply(u)
+		if err != nil {
+			slog.Error("failed to update state", "err", err)
+		}
+	}
+}
@@ -0,0 +1,190 @@
+package statuspage
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"fmt"
+	"html/template"
+	"log/slog"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"voiui/internal/alert"
+	"voiui/internal/node"
+	"voiui/internal/state"
+)
+
+//go:embed page.html
+var pageHTML string
+
+var page = template.Must(template.New("page").Funcs(template.FuncMap{
+	"pct": func(f float64) string {
+		return fmt.Sprintf("%.2f%%", f*100)
+	},
+	"time": func(t time.Time) string {
+		return t.UTC().Format("2006-01-02 15:04 UTC")
+	},
+}).Parse(pageHTML))
+
+type Config struct {
+	Dir      string
+	S3       string
+	Interval time.Duration
+}
+
+type Snapshotter interface {
+	Snapshot() state.State
+}
+
+// Generator renders a public status page. Only public chain facts are
+// included: no addresses of the host, tokens or data paths.
+type Generator struct {
+	cfg     Config
+	store   Snapshotter
+	started time.Time
+}
+
+func New(cfg Config, store Snapshotter) *Generator {
+	return &Generator{
+		cfg:     cfg,
+		store:   store,
+		started: time.Now(),
+	}
+}
+
+type view struct {
+	Generated     time.Time
+	Network       string
+	Running       bool
+	Participating bool
+	Round         uint64
+	Uptime        float64
+	Since         time.Time
+	Monitored     time.Duration
+	Proposals     int
+	Expected      float64
+	Index         float64
+	Incidents     []incident
+}
+
+type incident struct {
+	Title    string
+	Opened   time.Time
+	Resolved time.Time
+}
+
+// Uptime is the share of time since start not covered by "down" alerts.
+func Uptime(s state.State, since time.Time, now time.Time) float64 {
+	total := now.Sub(since)
+	if total <= 0 {
+		return 1
+	}
+
+	var down time.Duration
+	var downAt time.Time
+
+	for _, inc := range s.Alerts {
+		for _, e := range inc.Timeline {
+			if e.Kind != string(alert.Down) || e.At.Before(since) {
+				continue
+			}
+			if !e.Cleared && downAt.IsZero() {
+				downAt = e.At
+			} else if e.Cleared && !downAt.IsZero() {
+				down += e.At.Sub(downAt)
+				downAt = time.Time{}
+			}
+		}
+	}
+
+	if !downAt.IsZero() {
+		down += now.Sub(downAt)
+	}
+
+	return 1 - float64(down)/float64(total)
+}
+
+func (g *Generator) render() ([]byte, error) {
+	s := g.store.Snapshot()
+	now := time.Now()
+
+	v := view{
+		Generated:     now,
+		Network:       s.Network,
+		Running:       s.Running,
+		Participating: s.Participation == state.ParticipationActive,
+		Round:         s.Round,
+		Uptime:        Uptime(s, g.started, now),
+		Since:         g.started,
+		Proposals:     s.Performance.Actual,
+		Expected:      s.Performance.Expected,
+		Index:         s.Performance.Index,
+	}
+
+	if s.Uptime.Observed > 0 {
+		v.Uptime = s.Uptime.Connection
+		v.Since = now.Add(-s.Uptime.Observed)
+		v.Monitored = s.Uptime.Observed.Round(time.Hour)
+	}
+
+	for i := len(s.Alerts) - 1; i >= 0 && len(v.Incidents) < 5; i-- {
+		inc := s.Alerts[i]
+		v.Incidents = append(v.Incidents, incident{
+			Title:    inc.Title,
+			Opened:   inc.Opened,
+			Resolved: inc.Resolved,
+		})
+	}
+
+	var buf bytes.Buffer
+
+	err := page.Execute(&buf, v)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to render status page")
+	}
+
+	return buf.Bytes(), nil
+}
+
+func (g *Generator) publish(ctx context.Context) error {
+	data, err := g.render()
+	if err != nil {
+		return err
+	}
+
+	if g.cfg.Dir != "" {
+		err = node.WriteFileAtomic(g.cfg.Dir, "index.html", data)
+		if err != nil {
+			return err
+		}
+	}
+
+	if g.cfg.S3 != "" {
+		err = upload(ctx, g.cfg.S3, data)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (g *Generator) Run(ctx context.Context) {
+	t := time.NewTicker(g.cfg.Interval)
+	defer t.Stop()
+
+	for {
+		err := g.publish(ctx)
+		if err != nil {
+			slog.Error("failed to publish status page", "err", err)
+		}
+
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
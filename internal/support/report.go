@@ -0,0 +1,242 @@
+package support
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"voiui/internal/config"
+	"voiui/internal/state"
+)
+
+// maxLog is how much of the end of the log file a report takes.
+const maxLog = 1 << 20
+
+const redacted = "[redacted]"
+
+// Report is what goes into a diagnostic report for a support request.
+type Report struct {
+	Version string
+	Profile string
+	Config  *config.File
+	State   state.State
+	LogPath string
+	// Recent log lines, used when the log file cannot be read.
+	Recent []string
+}
+
+// secret tells whether a config or state key holds something that must not
+// leave the machine.
+func secret(key string) bool {
+	key = strings.ToLower(key)
+	for _, s := range []string{"token", "password", "secret", "mnemonic", "passphrase", "authorization", "topic"} {
+		if strings.Contains(key, s) {
+			return true
+		}
+	}
+	return key == "pin" || key == "headers"
+}
+
+// sanitize blanks secrets and the private parts of URLs in a decoded JSON
+// value.
+func sanitize(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, x := range v {
+			if secret(k) && x != nil && x != "" {
+				v[k] = redacted
+				continue
+			}
+			v[k] = sanitize(x)
+		}
+		return v
+	case []interface{}:
+		for i, x := range v {
+			v[i] = sanitize(x)
+		}
+		return v
+	case string:
+		return urls.ReplaceAllStringFunc(v, redactURL)
+	}
+	return v
+}
+
+// urls finds URLs in text, stopping at quotes so that they are found in
+// quoted log values too.
+var urls = regexp.MustCompile(`[a-zA-Z][a-zA-Z0-9+.-]*://[^\s"'<>\\]+`)
+
+// redactURL keeps where an endpoint is but drops credentials, the path
+// and the query, where webhooks, push URLs and panels keep their keys.
+func redactURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return raw
+	}
+
+	s := u.Scheme + "://" + u.Host
+	if u.Path != "" && u.Path != "/" {
+		s += "/" + redacted
+	}
+	if u.RawQuery != "" {
+		s += "?" + redacted
+	}
+	return s
+}
+
+// attrs finds key=value pairs in slog's text format.
+var attrs = regexp.MustCompile(`([\w.]+)=("(?:[^"\\]|\\.)*"|\S*)`)
+
+// sanitizeLine blanks secret attributes and URLs in a log line.
+func sanitizeLine(line string) string {
+	line = attrs.ReplaceAllStringFunc(line, func(attr string) string {
+		key, _, _ := strings.Cut(attr, "=")
+		if secret(key) {
+			return key + "=" + redacted
+		}
+		return attr
+	})
+	return urls.ReplaceAllStringFunc(line, redactURL)
+}
+
+func sanitizeLog(data []byte) []byte {
+	lines := strings.Split(string(data), "\n")
+	for i, line := range lines {
+		lines[i] = sanitizeLine(line)
+	}
+	return []byte(strings.Join(lines, "\n"))
+}
+
+func sanitized(v interface{}) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var generic interface{}
+
+	err = json.Unmarshal(data, &generic)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.MarshalIndent(sanitize(generic), "", "  ")
+}
+
+func (r Report) version() []byte {
+	var b bytes.Buffer
+
+	fmt.Fprintf(&b, "voiui %s\n", r.Version)
+	fmt.Fprintf(&b, "%s %s/%s\n", runtime.Version(), runtime.GOOS, runtime.GOARCH)
+	fmt.Fprintf(&b, "profile: %s\n", r.Profile)
+	fmt.Fprintf(&b, "node: %s\n", r.State.NodeVersion)
+	fmt.Fprintf(&b, "network: %s %s\n", r.State.Network, r.State.GenesisID)
+	fmt.Fprintf(&b, "round: %d\n", r.State.Round)
+	fmt.Fprintf(&b, "generated: %s\n", time.Now().Format(time.RFC3339))
+
+	return b.Bytes()
+}
+
+func (r Report) errors() []byte {
+	var b bytes.Buffer
+
+	s := r.State
+
+	if s.PollError.Message != "" {
+		fmt.Fprintf(&b, "poll error (%s) at %s: %s\n\n", s.PollError.Kind, s.PollError.At.Format(time.RFC3339), sanitizeLine(s.PollError.Message))
+	}
+
+	for _, a := range s.Alerts {
+		if a.Resolved.IsZero() {
+			fmt.Fprintf(&b, "active alert since %s: %s\n", a.Opened.Format(time.RFC3339), a.Title)
+		}
+	}
+
+	for _, line := range r.Recent {
+		if strings.Contains(line, "level=ERROR") || strings.Contains(line, "level=WARN") {
+			fmt.Fprintln(&b, sanitizeLine(line))
+		}
+	}
+
+	return b.Bytes()
+}
+
+func (r Report) log() []byte {
+	f, err := os.Open(r.LogPath)
+	if err != nil {
+		return []byte(strings.Join(r.Recent, "\n") + "\n")
+	}
+	defer f.Close()
+
+	if info, err := f.Stat(); err == nil && info.Size() > maxLog {
+		f.Seek(-maxLog, io.SeekEnd)
+	}
+
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return []byte(strings.Join(r.Recent, "\n") + "\n")
+	}
+
+	return data
+}
+
+// Write saves the report as a zip in dir and returns its path.
+func Write(dir string, r Report) (string, error) {
+	cfg, err := sanitized(r.Config)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to encode the config")
+	}
+
+	st, err := sanitized(r.State)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to encode the node status")
+	}
+
+	path := filepath.Join(dir, fmt.Sprintf("voiui-report-%s.zip", time.Now().Format("20060102-150405")))
+
+	f, err := os.Create(path)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to create the report")
+	}
+	defer f.Close()
+
+	z := zip.NewWriter(f)
+
+	for _, file := range []struct {
+		name string
+		data []byte
+	}{
+		{"version.txt", r.version()},
+		{"config.json", cfg},
+		{"status.json", st},
+		{"errors.txt", r.errors()},
+		{"voiui.log", sanitizeLog(r.log())},
+	} {
+		w, err := z.Create(file.name)
+		if err != nil {
+			return "", errors.Wrap(err, "failed to write the report")
+		}
+
+		_, err = w.Write(file.data)
+		if err != nil {
+			return "", errors.Wrap(err, "failed to write the report")
+		}
+	}
+
+	err = z.Close()
+	if err != nil {
+		return "", errors.Wrap(err, "failed to write the report")
+	}
+
+	return path, f.Close()
+}
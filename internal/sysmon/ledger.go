@@ -0,0 +1,112 @@
+package sysmon
+
+import (
+	"encoding/json"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	ledgerFile       = "ledger.json"
+	ledgerSampleEach = time.Hour
+	ledgerKeep       = 30 * 24 * time.Hour
+	ledgerRateWindow = 7 * 24 * time.Hour
+)
+
+type ledgerSample struct {
+	At   time.Time `json:"at"`
+	Size uint64    `json:"size"`
+}
+
+type ledgerLog struct {
+	path    string
+	Dir     string         `json:"dir"`
+	Samples []ledgerSample `json:"samples"`
+}
+
+func loadLedgerLog(dir string) *ledgerLog {
+	l := &ledgerLog{path: filepath.Join(dir, ledgerFile)}
+
+	data, err := os.ReadFile(l.path)
+	if err == nil {
+		json.Unmarshal(data, l)
+	}
+
+	return l
+}
+
+func (l *ledgerLog) save() error {
+	data, err := json.Marshal(l)
+	if err != nil {
+		return errors.Wrap(err, "failed to encode ledger log")
+	}
+
+	return errors.Wrap(os.WriteFile(l.path, data, 0600), "failed to write ledger log")
+}
+
+// add records a sample at most once per ledgerSampleEach, starting over
+// when the data directory changes.
+func (l *ledgerLog) add(dir string, now time.Time, size uint64) bool {
+	if l.Dir != dir {
+		l.Dir = dir
+		l.Samples = nil
+	}
+
+	if n := len(l.Samples); n > 0 && now.Sub(l.Samples[n-1].At) < ledgerSampleEach {
+		return false
+	}
+
+	l.Samples = append(l.Samples, ledgerSample{At: now, Size: size})
+
+	for len(l.Samples) > 0 && now.Sub(l.Samples[0].At) > ledgerKeep {
+		l.Samples = l.Samples[1:]
+	}
+
+	return true
+}
+
+// rate is the growth in bytes per day over the recent samples.
+func (l *ledgerLog) rate(now time.Time, size uint64) float64 {
+	var first *ledgerSample
+	for i := range l.Samples {
+		if now.Sub(l.Samples[i].At) <= ledgerRateWindow {
+			first = &l.Samples[i]
+			break
+		}
+	}
+
+	if first == nil {
+		return 0
+	}
+
+	days := now.Sub(first.At).Hours() / 24
+	if days < 1.0/24 {
+		return 0
+	}
+
+	return (float64(size) - float64(first.Size)) / days
+}
+
+// ledgerSize sums the ledger databases, e.g. voimain-v1.0/ledger.block.sqlite.
+func ledgerSize(dir string) uint64 {
+	var size uint64
+
+	filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if !d.IsDir() && strings.HasPrefix(d.Name(), "ledger.") {
+			if info, err := d.Info(); err == nil {
+				size += uint64(info.Size())
+			}
+		}
+		return nil
+	})
+
+	return size
+}
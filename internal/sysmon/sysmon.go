@@ -0,0 +1,199 @@
+package sysmon
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"voiui/internal/alert"
+	"voiui/internal/state"
+)
+
+var errUnsupported = errors.New("not supported on this platform")
+
+type Sink interface {
+	Set(kind alert.Kind, active bool, message string)
+}
+
+type cpuTimes struct {
+	idle  uint64
+	total uint64
+}
+
+// Monitor samples host CPU, memory and disk usage, the algod process found
+// through algod.pid and the size of the data directory.
+type Config struct {
+	DataDir   func() string
+	ConfigDir string
+	Interval  time.Duration
+
+	// DiskWarn is the free space percentage that raises a low disk alert.
+	DiskWarn float64
+	// FullDays raises a low disk alert when the ledger growth would fill
+	// the disk within that many days.
+	FullDays float64
+}
+
+type Monitor struct {
+	cfg     Config
+	alerts  Sink
+	updates chan<- state.Update
+
+	ledger *ledgerLog
+
+	prevCPU     cpuTimes
+	prevProc    time.Duration
+	prevProcPID int
+	prevAt      time.Time
+}
+
+func NewMonitor(cfg Config, alerts Sink, updates chan<- state.Update) *Monitor {
+	return &Monitor{
+		cfg:     cfg,
+		alerts:  alerts,
+		updates: updates,
+		ledger:  loadLedgerLog(cfg.ConfigDir),
+	}
+}
+
+func algodPID(dir string) int {
+	data, err := os.ReadFile(filepath.Join(dir, "algod.pid"))
+	if err != nil {
+		return 0
+	}
+
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil {
+		return 0
+	}
+
+	return pid
+}
+
+func dirSize(dir string) uint64 {
+	var size uint64
+
+	filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if !d.IsDir() {
+			if info, err := d.Info(); err == nil {
+				size += uint64(info.Size())
+			}
+		}
+		return nil
+	})
+
+	return size
+}
+
+func (m *Monitor) check(ctx context.Context) {
+	now := time.Now()
+
+	r := state.Resources{
+		UpdatedAt: now,
+	}
+
+	cpu, err := readCPU()
+	if err == nil {
+		if m.prevCPU.total > 0 && cpu.total > m.prevCPU.total {
+			busy := (cpu.total - m.prevCPU.total) - (cpu.idle - m.prevCPU.idle)
+			r.CPU = 100 * float64(busy) / float64(cpu.total-m.prevCPU.total)
+		}
+		m.prevCPU = cpu
+	} else {
+		r.HostErr = err.Error()
+	}
+
+	r.MemUsed, r.MemTotal, err = readMemory()
+	if err != nil {
+		r.HostErr = err.Error()
+		if err != errUnsupported {
+			slog.Error("failed to read memory usage", "err", err)
+		}
+	}
+
+	if dir := m.cfg.DataDir(); dir != "" {
+		r.DataDir = dir
+
+		r.DiskFree, r.DiskTotal, err = diskUsage(dir)
+		if err != nil && err != errUnsupported {
+			slog.Error("failed to read disk usage", "err", err)
+		}
+
+		r.DataDirSize = dirSize(dir)
+		r.LedgerSize = ledgerSize(dir)
+
+		if m.ledger.add(dir, now, r.LedgerSize) {
+			err := m.ledger.save()
+			if err != nil {
+				slog.Error("failed to save ledger size", "err", err)
+			}
+		}
+
+		r.LedgerGrowth = m.ledger.rate(now, r.LedgerSize)
+		if r.LedgerGrowth > 0 {
+			r.DaysUntilFull = float64(r.DiskFree) / r.LedgerGrowth
+		}
+
+		if pid := algodPID(dir); pid > 0 {
+			cpuTime, rss, err := readProcess(pid)
+			if err == nil {
+				r.AlgodRunning = true
+				r.AlgodRSS = rss
+
+				if pid == m.prevProcPID && !m.prevAt.IsZero() {
+					r.AlgodCPU = 100 * float64(cpuTime-m.prevProc) / float64(now.Sub(m.prevAt))
+				}
+
+				m.prevProc = cpuTime
+				m.prevProcPID = pid
+			} else {
+				r.AlgodErr = err.Error()
+			}
+		}
+	}
+
+	m.prevAt = now
+
+	if r.DiskTotal > 0 {
+		free := 100 * float64(r.DiskFree) / float64(r.DiskTotal)
+		filling := r.DaysUntilFull > 0 && r.DaysUntilFull < m.cfg.FullDays
+		r.LowDisk = free < m.cfg.DiskWarn || filling
+
+		msg := fmt.Sprintf("%.1f%% disk space left for %s", free, r.DataDir)
+		if filling {
+			msg += fmt.Sprintf(", full in ~%.0f days at the current ledger growth", r.DaysUntilFull)
+		}
+		m.alerts.Set(alert.LowDisk, r.LowDisk, msg)
+	}
+
+	m.updates <- func(s *state.State) error {
+		s.Resources = r
+		return nil
+	}
+}
+
+func (m *Monitor) Run(ctx context.Context) {
+	t := time.NewTicker(m.cfg.Interval)
+	defer t.Stop()
+
+	for {
+		m.check(ctx)
+
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,19 @@
+//go:build freebsd || (darwin && cgo)
+
+package sysmon
+
+import (
+	"github.com/pkg/errors"
+	"golang.org/x/sys/unix"
+)
+
+func diskUsage(path string) (uint64, uint64, error) {
+	var st unix.Statfs_t
+
+	err := unix.Statfs(path, &st)
+	if err != nil {
+		return 0, 0, errors.Wrap(err, "failed to stat filesystem")
+	}
+
+	return uint64(st.Bavail) * uint64(st.Bsize), uint64(st.Blocks) * uint64(st.Bsize), nil
+}
@@ -0,0 +1,102 @@
+//go:build cgo
+
+package sysmon
+
+/*
+#include <libproc.h>
+#include <mach/mach.h>
+#include <mach/mach_time.h>
+#include <sys/sysctl.h>
+
+static int cpuTimes(unsigned long long *idle, unsigned long long *total) {
+	host_cpu_load_info_data_t info;
+	mach_msg_type_number_t count = HOST_CPU_LOAD_INFO_COUNT;
+
+	if (host_statistics(mach_host_self(), HOST_CPU_LOAD_INFO, (host_info_t)&info, &count) != KERN_SUCCESS) {
+		return -1;
+	}
+
+	*idle = info.cpu_ticks[CPU_STATE_IDLE];
+	*total = 0;
+	for (int i = 0; i < CPU_STATE_MAX; i++) {
+		*total += info.cpu_ticks[i];
+	}
+
+	return 0;
+}
+
+// memUsed counts app, wired and compressed memory like Activity Monitor.
+static int memUsed(unsigned long long *used) {
+	vm_statistics64_data_t vm;
+	mach_msg_type_number_t count = HOST_VM_INFO64_COUNT;
+
+	if (host_statistics64(mach_host_self(), HOST_VM_INFO64, (host_info64_t)&vm, &count) != KERN_SUCCESS) {
+		return -1;
+	}
+
+	unsigned long long pages = vm.internal_page_count - vm.purgeable_count + vm.wire_count + vm.compressor_page_count;
+	*used = pages * vm_kernel_page_size;
+
+	return 0;
+}
+
+// procTimes reports the CPU time in nanoseconds and the resident size.
+static int procTimes(int pid, unsigned long long *cpu, unsigned long long *rss) {
+	struct proc_taskinfo ti;
+
+	if (proc_pidinfo(pid, PROC_PIDTASKINFO, 0, &ti, sizeof(ti)) != sizeof(ti)) {
+		return -1;
+	}
+
+	mach_timebase_info_data_t tb;
+	mach_timebase_info(&tb);
+
+	*cpu = (ti.pti_total_user + ti.pti_total_system) * tb.numer / tb.denom;
+	*rss = ti.pti_resident_size;
+
+	return 0;
+}
+*/
+import "C"
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/sys/unix"
+)
+
+func readCPU() (cpuTimes, error) {
+	var idle, total C.ulonglong
+
+	if C.cpuTimes(&idle, &total) != 0 {
+		return cpuTimes{}, errors.New("failed to read cpu times")
+	}
+
+	return cpuTimes{idle: uint64(idle), total: uint64(total)}, nil
+}
+
+func readMemory() (uint64, uint64, error) {
+	total, err := unix.SysctlUint64("hw.memsize")
+	if err != nil {
+		return 0, 0, errors.Wrap(err, "failed to read memory size")
+	}
+
+	var used C.ulonglong
+
+	if C.memUsed(&used) != 0 {
+		return 0, 0, errors.New("failed to read memory usage")
+	}
+
+	return uint64(used), total, nil
+}
+
+func readProcess(pid int) (time.Duration, uint64, error) {
+	var cpu, rss C.ulonglong
+
+	if C.procTimes(C.int(pid), &cpu, &rss) != 0 {
+		return 0, 0, errors.Errorf("failed to read process %d", pid)
+	}
+
+	return time.Duration(cpu), uint64(rss), nil
+}
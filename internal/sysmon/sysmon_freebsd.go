@@ -0,0 +1,62 @@
+package sysmon
+
+import (
+	"encoding/binary"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/sys/unix"
+)
+
+func readCPU() (cpuTimes, error) {
+	var t cpuTimes
+
+	// user nice system interrupt idle, as longs
+	raw, err := unix.SysctlRaw("kern.cp_time")
+	if err != nil {
+		return t, errors.Wrap(err, "failed to read cpu times")
+	}
+
+	size := len(raw) / 5
+	if size != 4 && size != 8 {
+		return t, errors.New("unexpected kern.cp_time size")
+	}
+
+	for i := 0; i < 5; i++ {
+		var v uint64
+		if size == 8 {
+			v = binary.NativeEndian.Uint64(raw[i*8:])
+		} else {
+			v = uint64(binary.NativeEndian.Uint32(raw[i*4:]))
+		}
+
+		t.total += v
+		if i == 4 {
+			t.idle = v
+		}
+	}
+
+	return t, nil
+}
+
+func readMemory() (uint64, uint64, error) {
+	var pages [4]uint32
+
+	for i, name := range []string{"vm.stats.vm.v_page_count", "vm.stats.vm.v_free_count", "vm.stats.vm.v_inactive_count", "hw.pagesize"} {
+		v, err := unix.SysctlUint32(name)
+		if err != nil {
+			return 0, 0, errors.Wrapf(err, "failed to read %s", name)
+		}
+		pages[i] = v
+	}
+
+	size := uint64(pages[3])
+	total := uint64(pages[0]) * size
+	available := uint64(pages[1]+pages[2]) * size
+
+	return total - available, total, nil
+}
+
+func readProcess(pid int) (time.Duration, uint64, error) {
+	return 0, 0, errUnsupported
+}
@@ -0,0 +1,104 @@
+package sysmon
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/sys/unix"
+)
+
+const clockTicks = 100
+
+func readCPU() (cpuTimes, error) {
+	var t cpuTimes
+
+	f, err := os.Open("/proc/stat")
+	if err != nil {
+		return t, errors.Wrap(err, "failed to read cpu times")
+	}
+	defer f.Close()
+
+	sc := bufio.NewScanner(f)
+	if !sc.Scan() {
+		return t, errors.New("empty /proc/stat")
+	}
+
+	// cpu user nice system idle iowait irq softirq steal ...
+	fields := strings.Fields(sc.Text())
+	for i, field := range fields[1:] {
+		v, _ := strconv.ParseUint(field, 10, 64)
+		t.total += v
+		if i == 3 || i == 4 {
+			t.idle += v
+		}
+	}
+
+	return t, nil
+}
+
+func readMemory() (uint64, uint64, error) {
+	f, err := os.Open("/proc/meminfo")
+	if err != nil {
+		return 0, 0, errors.Wrap(err, "failed to read memory info")
+	}
+	defer f.Close()
+
+	var total, available uint64
+
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		fields := strings.Fields(sc.Text())
+		if len(fields) < 2 {
+			continue
+		}
+
+		v, _ := strconv.ParseUint(fields[1], 10, 64)
+
+		switch fields[0] {
+		case "MemTotal:":
+			total = v * 1024
+		case "MemAvailable:":
+			available = v * 1024
+		}
+	}
+
+	return total - available, total, nil
+}
+
+func readProcess(pid int) (time.Duration, uint64, error) {
+	data, err := os.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
+	if err != nil {
+		return 0, 0, errors.Wrap(err, "failed to read process stats")
+	}
+
+	// the command name may contain spaces, fields start after the closing paren
+	s := string(data)
+	fields := strings.Fields(s[strings.LastIndex(s, ")")+1:])
+	if len(fields) < 22 {
+		return 0, 0, errors.New("unexpected process stats format")
+	}
+
+	utime, _ := strconv.ParseUint(fields[11], 10, 64)
+	stime, _ := strconv.ParseUint(fields[12], 10, 64)
+	rss, _ := strconv.ParseUint(fields[21], 10, 64)
+
+	cpu := time.Duration(utime+stime) * time.Second / clockTicks
+
+	return cpu, rss * uint64(os.Getpagesize()), nil
+}
+
+func diskUsage(path string) (uint64, uint64, error) {
+	var st unix.Statfs_t
+
+	err := unix.Statfs(path, &st)
+	if err != nil {
+		return 0, 0, errors.Wrap(err, "failed to stat filesystem")
+	}
+
+	return st.Bavail * uint64(st.Bsize), st.Blocks * uint64(st.Bsize), nil
+}
@@ -0,0 +1,21 @@
+//go:build !linux && !windows && !freebsd && !(darwin && cgo)
+
+package sysmon
+
+import "time"
+
+func readCPU() (cpuTimes, error) {
+	return cpuTimes{}, errUnsupported
+}
+
+func readMemory() (uint64, uint64, error) {
+	return 0, 0, errUnsupported
+}
+
+func readProcess(pid int) (time.Duration, uint64, error) {
+	return 0, 0, errUnsupported
+}
+
+func diskUsage(path string) (uint64, uint64, error) {
+	return 0, 0, errUnsupported
+}
@@ -0,0 +1,120 @@
+package sysmon
+
+import (
+	"time"
+	"unsafe"
+
+	"github.com/pkg/errors"
+	"golang.org/x/sys/windows"
+)
+
+var (
+	kernel32                 = windows.NewLazySystemDLL("kernel32.dll")
+	procGetSystemTimes       = kernel32.NewProc("GetSystemTimes")
+	procGlobalMemoryStatusEx = kernel32.NewProc("GlobalMemoryStatusEx")
+	procGetProcessMemoryInfo = kernel32.NewProc("K32GetProcessMemoryInfo")
+)
+
+func filetime(ft windows.Filetime) uint64 {
+	return uint64(ft.HighDateTime)<<32 | uint64(ft.LowDateTime)
+}
+
+func readCPU() (cpuTimes, error) {
+	var idle, kernel, user windows.Filetime
+
+	r, _, err := procGetSystemTimes.Call(
+		uintptr(unsafe.Pointer(&idle)),
+		uintptr(unsafe.Pointer(&kernel)),
+		uintptr(unsafe.Pointer(&user)),
+	)
+	if r == 0 {
+		return cpuTimes{}, errors.Wrap(err, "failed to read cpu times")
+	}
+
+	// kernel time includes idle time
+	return cpuTimes{
+		idle:  filetime(idle),
+		total: filetime(kernel) + filetime(user),
+	}, nil
+}
+
+type memoryStatusEx struct {
+	Length               uint32
+	MemoryLoad           uint32
+	TotalPhys            uint64
+	AvailPhys            uint64
+	TotalPageFile        uint64
+	AvailPageFile        uint64
+	TotalVirtual         uint64
+	AvailVirtual         uint64
+	AvailExtendedVirtual uint64
+}
+
+func readMemory() (uint64, uint64, error) {
+	m := memoryStatusEx{}
+	m.Length = uint32(unsafe.Sizeof(m))
+
+	r, _, err := procGlobalMemoryStatusEx.Call(uintptr(unsafe.Pointer(&m)))
+	if r == 0 {
+		return 0, 0, errors.Wrap(err, "failed to read memory status")
+	}
+
+	return m.TotalPhys - m.AvailPhys, m.TotalPhys, nil
+}
+
+type processMemoryCounters struct {
+	Cb                         uint32
+	PageFaultCount             uint32
+	PeakWorkingSetSize         uintptr
+	WorkingSetSize             uintptr
+	QuotaPeakPagedPoolUsage    uintptr
+	QuotaPagedPoolUsage        uintptr
+	QuotaPeakNonPagedPoolUsage uintptr
+	QuotaNonPagedPoolUsage     uintptr
+	PagefileUsage              uintptr
+	PeakPagefileUsage          uintptr
+}
+
+func readProcess(pid int) (time.Duration, uint64, error) {
+	h, err := windows.OpenProcess(windows.PROCESS_QUERY_LIMITED_INFORMATION, false, uint32(pid))
+	if err != nil {
+		return 0, 0, errors.Wrap(err, "failed to open process")
+	}
+	defer windows.CloseHandle(h)
+
+	var creation, exit, kernel, user windows.Filetime
+
+	err = windows.GetProcessTimes(h, &creation, &exit, &kernel, &user)
+	if err != nil {
+		return 0, 0, errors.Wrap(err, "failed to read process times")
+	}
+
+	c := processMemoryCounters{}
+	c.Cb = uint32(unsafe.Sizeof(c))
+
+	r, _, err := procGetProcessMemoryInfo.Call(uintptr(h), uintptr(unsafe.Pointer(&c)), uintptr(c.Cb))
+	if r == 0 {
+		return 0, 0, errors.Wrap(err, "failed to read process memory")
+	}
+
+	// filetimes count 100ns intervals
+	cpu := time.Duration(filetime(kernel)+filetime(user)) * 100
+
+	return cpu, uint64(c.WorkingSetSize), nil
+}
+
+func diskUsage(path string) (uint64, uint64, error) {
+	p, err := windows.UTF16PtrFromString(path)
+	if err != nil {
+		return 0, 0, errors.Wrap(err, "invalid path")
+	}
+
+	var free, total, totalFree uint64
+
+	err = windows.GetDiskFreeSpaceEx(p, &free, &total, &totalFree)
+	if err != nil {
+		return 0, 0, errors.Wrap(err, "failed to read disk space")
+	}
+
+	return free, total, nil
+}
@@ -0,0 +1,103 @@
+package templates
+
+import (
+	"voiui/internal/config"
+)
+
+// Template preconfigures a profile for a common way of hosting a node.
+type Template struct {
+	Name        string
+	Title       string
+	Description string
+
+	Path    string
+	Service config.Service
+}
+
+var all = []Template{
+	{
+		Name:        "voi-swarm",
+		Title:       "Voi Swarm",
+		Description: "node installed by the Voi Swarm script, running as a Docker swarm service",
+		Path:        "/var/lib/voi/algod/data",
+		Service: config.Service{
+			Start:   "docker service scale voinetwork_algod=1",
+			Stop:    "docker service scale voinetwork_algod=0",
+			Restart: "docker service update --force voinetwork_algod",
+			Log:     "/var/lib/voi/algod/data/node.log",
+		},
+	},
+	{
+		Name:        "ubuntu",
+		Title:       "Ubuntu service",
+		Description: "bare-metal node from the Debian package, managed by systemd",
+		Path:        "/var/lib/algorand",
+		Service: config.Service{
+			Start:   "systemctl start algorand",
+			Stop:    "systemctl stop algorand",
+			Restart: "systemctl restart algorand",
+			Log:     "/var/lib/algorand/node.log",
+		},
+	},
+	{
+		Name:        "windows",
+		Title:       "Windows binary",
+		Description: "node binaries unpacked to C:\\voi, controlled with goal",
+		Path:        `C:\voi\data`,
+		Service: config.Service{
+			Start:   `C:\voi\goal.exe node start -d C:\voi\data`,
+			Stop:    `C:\voi\goal.exe node stop -d C:\voi\data`,
+			Restart: `C:\voi\goal.exe node restart -d C:\voi\data`,
+			Log:     `C:\voi\data\node.log`,
+		},
+	},
+	{
+		Name:        "docker",
+		Title:       "Docker container",
+		Description: "VPS image running the node in a container named voi with its data in /var/lib/voi/data",
+		Path:        "/var/lib/voi/data",
+		Service: config.Service{
+			Start:   "docker start voi",
+			Stop:    "docker stop voi",
+			Restart: "docker restart voi",
+			Log:     "/var/lib/voi/data/node.log",
+		},
+	},
+}
+
+func All() []Template {
+	return append([]Template(nil), all...)
+}
+
+func Get(name string) (Template, bool) {
+	for _, t := range all {
+		if t.Name == name {
+			return t, true
+		}
+	}
+	return Template{}, false
+}
+
+// Apply fills what p leaves empty with the template's paths and commands.
+func (t Template) Apply(p config.Profile) config.Profile {
+	p.Template = t.Name
+
+	if p.Path == "" && !p.Demo && !p.AccountsOnly {
+		p.Path = t.Path
+	}
+
+	if p.Service.Start == "" {
+		p.Service.Start = t.Service.Start
+	}
+	if p.Service.Stop == "" {
+		p.Service.Stop = t.Service.Stop
+	}
+	if p.Service.Restart == "" {
+		p.Service.Restart = t.Service.Restart
+	}
+	if p.Service.Log == "" {
+		p.Service.Log = t.Service.Log
+	}
+
+	return p
+}
@@ -0,0 +1,212 @@
+package tray
+
+import (
+	_ "embed"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/getlantern/systray"
+)
+
+//go:embed voi.ico
+var icon []byte
+
+type Menu struct {
+	Open    <-chan struct{}
+	Mini    <-chan struct{}
+	Quit    <-chan struct{}
+	Profile <-chan string
+}
+
+var (
+	mu       sync.Mutex
+	profiles = map[string]*systray.MenuItem{}
+	notice   *systray.MenuItem
+	mini     *systray.MenuItem
+	title    string
+
+	noticeText string
+	status     string
+	unacked    int
+)
+
+func Run(t string, names []string, onReady func(m Menu)) {
+	systray.Run(func() {
+		systray.SetIcon(icon)
+		systray.SetTitle(t)
+		systray.SetTooltip(t)
+
+		mu.Lock()
+		title = t
+		notice = systray.AddMenuItem("", "")
+		notice.Disable()
+		notice.Hide()
+		mu.Unlock()
+
+		mOpen := systray.AddMenuItem("Open", "Open monitor")
+		mMini := systray.AddMenuItemCheckbox("Mini widget", "Show the status in a small window on top", false)
+
+		mu.Lock()
+		mini = mMini
+		mu.Unlock()
+
+		profile := make(chan string)
+
+		if len(names) > 0 {
+			mProfiles := systray.AddMenuItem("Profiles", "Switch profile")
+
+			mu.Lock()
+			for _, name := range names {
+				item := mProfiles.AddSubMenuItem(name, "Switch to "+name)
+				profiles[name] = item
+
+				go func(name string, item *systray.MenuItem) {
+					for range item.ClickedCh {
+						profile <- name
+					}
+				}(name, item)
+			}
+			mu.Unlock()
+		}
+
+		mQuit := systray.AddMenuItem("Quit", "Quit monitor")
+
+		onReady(Menu{
+			Open:    mOpen.ClickedCh,
+			Mini:    mMini.ClickedCh,
+			Quit:    mQuit.ClickedCh,
+			Profile: profile,
+		})
+	}, nil)
+}
+
+func SetProfile(name string) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	for n, item := range profiles {
+		if n == name {
+			item.Check()
+		} else {
+			item.Uncheck()
+		}
+	}
+}
+
+// SetMini checks the mini widget item while the widget is open.
+func SetMini(on bool) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if mini == nil {
+		return
+	}
+
+	if on {
+		mini.Check()
+	} else {
+		mini.Uncheck()
+	}
+}
+
+// SetStatus shows text after the title in the tooltip while there is no
+// notice.
+func SetStatus(text string) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	status = text
+
+	// the tray is not up yet, Run shows the title alone
+	if notice == nil {
+		return
+	}
+
+	systray.SetTooltip(tooltip())
+}
+
+// SetAlerts shows how many alerts are not acknowledged yet as a badge on
+// the tray title.
+func SetAlerts(n int) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	unacked = n
+
+	if notice == nil {
+		return
+	}
+
+	systray.SetTitle(badged())
+	systray.SetTooltip(tooltip())
+}
+
+func badged() string {
+	if unacked == 0 {
+		return title
+	}
+	return fmt.Sprintf("%s (%d)", title, unacked)
+}
+
+func tooltip() string {
+	switch {
+	case noticeText != "":
+		return badged() + " – " + noticeText
+	case status != "":
+		return badged() + " – " + status
+	default:
+		return badged()
+	}
+}
+
+// SetNotice shows text at the top of the menu and in the tooltip, or hides
+// it when text is empty.
+func SetNotice(text string) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if notice == nil {
+		return
+	}
+
+	noticeText = text
+
+	if text == "" {
+		notice.Hide()
+	} else {
+		notice.SetTitle(text)
+		notice.Show()
+	}
+
+	systray.SetTooltip(tooltip())
+}
+
+var flashFrames = []string{"🎉", "✨", "🥳", "✨"}
+
+// Flash animates the tray title with celebration frames for a few seconds
+// and shows text in the tooltip meanwhile.
+func Flash(text string) {
+	mu.Lock()
+	t := badged()
+	mu.Unlock()
+
+	go func() {
+		systray.SetTooltip(t + " – " + text)
+
+		for i := 0; i < 3*len(flashFrames); i++ {
+			systray.SetTitle(flashFrames[i%len(flashFrames)] + " " + t)
+			time.Sleep(250 * time.Millisecond)
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		systray.SetTitle(badged())
+		systray.SetTooltip(tooltip())
+	}()
+}
+
+func Quit() {
+	systray.Quit()
+}
@@ -0,0 +1,251 @@
+package txwatch
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/algorand/go-algorand-sdk/v2/crypto"
+	"github.com/algorand/go-algorand-sdk/v2/encoding/msgpack"
+	"github.com/algorand/go-algorand-sdk/v2/types"
+	"github.com/pkg/errors"
+
+	"voiui/internal/state"
+)
+
+const submittedDir = "submitted"
+
+var extensions = []string{".stxn", ".txn", ".signed"}
+
+type Submitter interface {
+	SubmitRaw(ctx context.Context, raw []byte) (string, error)
+	LastRound() uint64
+}
+
+type Watcher struct {
+	dir      string
+	interval time.Duration
+	sub      Submitter
+
+	updates chan<- state.Update
+
+	mu    sync.Mutex
+	files map[string]time.Time
+}
+
+func New(dir string, interval time.Duration, sub Submitter, updates chan<- state.Update) *Watcher {
+	return &Watcher{
+		dir:      dir,
+		interval: interval,
+		sub:      sub,
+		updates:  updates,
+		files:    map[string]time.Time{},
+	}
+}
+
+func decode(data []byte) ([]types.SignedTxn, error) {
+	var txns []types.SignedTxn
+
+	dec := msgpack.NewDecoder(bytes.NewReader(data))
+	for {
+		var stxn types.SignedTxn
+
+		err := dec.Decode(&stxn)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "not a signed transaction file")
+		}
+
+		txns = append(txns, stxn)
+	}
+
+	if len(txns) == 0 {
+		return nil, errors.New("file is empty")
+	}
+
+	return txns, nil
+}
+
+func short(addr string) string {
+	if len(addr) < 12 {
+		return addr
+	}
+	return addr[:6] + "…" + addr[len(addr)-4:]
+}
+
+func validate(txns []types.SignedTxn, round uint64) (string, error) {
+	var parts []string
+
+	for _, stxn := range txns {
+		if stxn.Sig == (types.Signature{}) && stxn.Msig.Blank() && stxn.Lsig.Blank() {
+			return "", errors.Errorf("transaction %s is not signed", crypto.GetTxID(stxn.Txn))
+		}
+
+		if round > 0 && uint64(stxn.Txn.LastValid) < round {
+			return "", errors.Errorf("transaction %s expired at round %d", crypto.GetTxID(stxn.Txn), stxn.Txn.LastValid)
+		}
+
+		parts = append(parts, fmt.Sprintf("%s from %s (valid %d-%d)", stxn.Txn.Type, short(stxn.Txn.Sender.String()), stxn.Txn.FirstValid, stxn.Txn.LastValid))
+	}
+
+	return strings.Join(parts, ", "), nil
+}
+
+func (w *Watcher) scan() ([]state.SignedTxn, bool, error) {
+	entries, err := os.ReadDir(w.dir)
+	if err != nil {
+		return nil, false, errors.Wrap(err, "failed to read signed transactions folder")
+	}
+
+	round := w.sub.LastRound()
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	seen := map[string]time.Time{}
+	changed := false
+
+	var items []state.SignedTxn
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		ext := strings.ToLower(filepath.Ext(entry.Name()))
+
+		ok := false
+		for _, e := range extensions {
+			if ext == e {
+				ok = true
+				break
+			}
+		}
+
+		if !ok {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+
+		path := filepath.Join(w.dir, entry.Name())
+
+		seen[path] = info.ModTime()
+		if prev, ok := w.files[path]; !ok || !prev.Equal(info.ModTime()) {
+			changed = true
+		}
+
+		item := state.SignedTxn{
+			Path: path,
+			Name: entry.Name(),
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			item.Err = err.Error()
+		} else if txns, err := decode(data); err != nil {
+			item.Err = err.Error()
+		} else if summary, err := validate(txns, round); err != nil {
+			item.Err = err.Error()
+		} else {
+			item.Summary = summary
+		}
+
+		items = append(items, item)
+	}
+
+	if len(seen) != len(w.files) {
+		changed = true
+	}
+
+	w.files = seen
+
+	sort.Slice(items, func(i, j int) bool { return items[i].Name < items[j].Name })
+
+	return items, changed, nil
+}
+
+func (w *Watcher) refresh(force bool) {
+	items, changed, err := w.scan()
+	if err != nil {
+		slog.Error("failed to watch signed transactions", "err", err)
+		return
+	}
+
+	if !changed && !force {
+		return
+	}
+
+	w.updates <- func(s *state.State) error {
+		s.SignedTxns = items
+		return nil
+	}
+}
+
+func (w *Watcher) Run(ctx context.Context) {
+	t := time.NewTicker(w.interval)
+	defer t.Stop()
+
+	w.refresh(true)
+
+	for {
+		select {
+		case <-t.C:
+			w.refresh(false)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (w *Watcher) Submit(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to read signed transaction")
+	}
+
+	txns, err := decode(data)
+	if err != nil {
+		return "", err
+	}
+
+	_, err = validate(txns, w.sub.LastRound())
+	if err != nil {
+		return "", err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	txid, err := w.sub.SubmitRaw(ctx, data)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to submit transaction")
+	}
+
+	dst := filepath.Join(w.dir, submittedDir)
+
+	err = os.MkdirAll(dst, 0700)
+	if err == nil {
+		err = os.Rename(path, filepath.Join(dst, filepath.Base(path)))
+	}
+	if err != nil {
+		slog.Error("failed to move submitted transaction file", "err", err)
+	}
+
+	w.refresh(true)
+
+	return fmt.Sprintf("Submitted %s", txid), nil
+}
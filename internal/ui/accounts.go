@@ -0,0 +1,253 @@
+package ui
+
+import (
+	"log/slog"
+	"time"
+
+	"gioui.org/io/clipboard"
+	"gioui.org/layout"
+	"gioui.org/unit"
+	"gioui.org/widget"
+	"gioui.org/widget/material"
+	qrcode "github.com/skip2/go-qrcode"
+
+	"voiui/internal/i18n"
+	"voiui/internal/state"
+)
+
+func short(address string) string {
+	if len(address) <= 10 {
+		return address
+	}
+	return address[:4] + "…" + address[len(address)-4:]
+}
+
+// discovered lists participating addresses on the node that are neither
+// watched nor dismissed.
+func (v *view) discovered() []string {
+	if v.accounts == nil {
+		return nil
+	}
+
+	known := map[string]bool{}
+	for _, a := range v.s.Watched {
+		known[a.Address] = true
+	}
+	for _, address := range v.s.Dismissed {
+		known[address] = true
+	}
+
+	var found []string
+	for _, k := range v.s.Keys {
+		if !known[k.Address] {
+			known[k.Address] = true
+			found = append(found, k.Address)
+		}
+	}
+
+	return found
+}
+
+func (v *view) layoutDiscovered(gtx C) D {
+	found := v.discovered()
+	if len(found) == 0 {
+		return D{}
+	}
+
+	children := []layout.FlexChild{
+		layout.Rigid(material.Body2(v.th, i18n.Tf("Found %d account(s) with participation keys on this node. Add them to the watch list?", len(found))).Layout),
+	}
+
+	for _, address := range found {
+		children = append(children, layout.Rigid(material.Caption(v.th, address).Layout))
+	}
+
+	children = append(children, layout.Rigid(func(gtx C) D {
+		return layout.Flex{}.Layout(
+			gtx,
+			layout.Rigid(func(gtx C) D {
+				return layout.Inset{Top: unit.Dp(4), Right: unit.Dp(4)}.Layout(gtx, material.Button(v.th, &v.watchBtn, i18n.T("Watch")).Layout)
+			}),
+			layout.Rigid(func(gtx C) D {
+				return layout.Inset{Top: unit.Dp(4)}.Layout(gtx, material.Button(v.th, &v.dismissBtn, i18n.T("Not now")).Layout)
+			}),
+		)
+	}))
+
+	in := layout.UniformInset(unit.Dp(8))
+	return in.Layout(gtx, func(gtx C) D {
+		return layout.Flex{Axis: layout.Vertical}.Layout(gtx, children...)
+	})
+}
+
+func (v *view) accountDetails(a state.Account) (string, bool) {
+	if a.Err != "" {
+		return a.Err, false
+	}
+
+	if a.Round == 0 {
+		return i18n.T("Waiting for the next round"), true
+	}
+
+	text := voi(a.Balance)
+
+	if !a.Online {
+		return text + i18n.T(", offline"), false
+	}
+
+	text += i18n.T(", online")
+
+	if a.KeyLastValid > v.s.Round {
+		left := a.KeyLastValid - v.s.Round
+		text += i18n.Tf(", key valid until round %d", a.KeyLastValid)
+
+		if bt := v.s.AvgBlockDuration; bt > 0 {
+			text += i18n.Tf(" (%.0f days)", (time.Duration(left)*bt).Hours()/24)
+		}
+	}
+
+	return text, true
+}
+
+func (v *view) layoutWatchList(gtx C) D {
+	if v.accounts == nil {
+		return D{}
+	}
+
+	children := []layout.FlexChild{
+		layout.Rigid(material.Caption(v.th, i18n.T("Watch list:")).Layout),
+	}
+
+	for _, a := range v.s.Watched {
+		a := a
+
+		btn, ok := v.removeBtns[a.Address]
+		if !ok {
+			btn = &widget.Clickable{}
+			v.removeBtns[a.Address] = btn
+		}
+
+		detailBtn, ok := v.detailBtns[a.Address]
+		if !ok {
+			detailBtn = &widget.Clickable{}
+			v.detailBtns[a.Address] = detailBtn
+		}
+
+		children = append(children, layout.Rigid(func(gtx C) D {
+			return layout.Inset{Top: unit.Dp(4)}.Layout(gtx, func(gtx C) D {
+				return layout.Flex{Alignment: layout.Middle}.Layout(
+					gtx,
+					layout.Flexed(1, func(gtx C) D {
+						text, ok := v.accountDetails(a)
+
+						return layout.Flex{Axis: layout.Vertical}.Layout(
+							gtx,
+							layout.Rigid(material.Body2(v.th, a.Label+" – "+short(a.Address)).Layout),
+							layout.Rigid(func(gtx C) D {
+								label := material.Caption(v.th, text)
+								if !ok {
+									label.Color = red
+								}
+								return label.Layout(gtx)
+							}),
+						)
+					}),
+					layout.Rigid(func(gtx C) D {
+						return layout.Inset{Left: unit.Dp(8)}.Layout(gtx, material.Button(v.th, detailBtn, i18n.T("Details")).Layout)
+					}),
+					layout.Rigid(func(gtx C) D {
+						return layout.Inset{Left: unit.Dp(8)}.Layout(gtx, material.Button(v.th, btn, i18n.T("Remove")).Layout)
+					}),
+				)
+			})
+		}))
+
+		if v.detail == a.Address {
+			children = append(children, layout.Rigid(func(gtx C) D {
+				return v.layoutDetail(gtx, a)
+			}))
+		}
+	}
+
+	children = append(children, layout.Rigid(func(gtx C) D {
+		return layout.Inset{Top: unit.Dp(4)}.Layout(gtx, func(gtx C) D {
+			return layout.Flex{Alignment: layout.Middle}.Layout(
+				gtx,
+				layout.Flexed(2, material.Editor(v.th, &v.watchAddress, i18n.T("Address")).Layout),
+				layout.Flexed(1, func(gtx C) D {
+					return layout.Inset{Left: unit.Dp(8)}.Layout(gtx, material.Editor(v.th, &v.watchLabel, i18n.T("Label")).Layout)
+				}),
+				layout.Rigid(func(gtx C) D {
+					return layout.Inset{Left: unit.Dp(8)}.Layout(gtx, material.Button(v.th, &v.watchAddBtn, i18n.T("Watch")).Layout)
+				}),
+			)
+		})
+	}))
+
+	in := layout.UniformInset(unit.Dp(8))
+	return in.Layout(gtx, func(gtx C) D {
+		return layout.Flex{Axis: layout.Vertical}.Layout(gtx, children...)
+	})
+}
+
+// showDetail opens the details of address, or closes them when it is
+// already open or empty.
+func (v *view) showDetail(address string) {
+	v.detailNote = ""
+
+	if address == "" || address == v.detail {
+		v.detail = ""
+		v.detailQR = nil
+		return
+	}
+
+	v.detail = address
+
+	q, err := qrcode.New(address, qrcode.Medium)
+	if err != nil {
+		slog.Error("failed to encode QR code", "err", err)
+		v.detailQR = nil
+		return
+	}
+
+	v.detailQR = q.Bitmap()
+}
+
+func (v *view) layoutDetail(gtx C, a state.Account) D {
+	if v.copyBtn.Clicked() {
+		clipboard.WriteOp{Text: a.Address}.Add(gtx.Ops)
+		v.detailNote = i18n.T("Address copied")
+	}
+
+	return layout.Inset{Top: unit.Dp(4), Bottom: unit.Dp(8)}.Layout(gtx, func(gtx C) D {
+		return layout.Flex{Axis: layout.Vertical}.Layout(
+			gtx,
+			layout.Rigid(func(gtx C) D {
+				return described(gtx, i18n.T("QR code of the address"), func(gtx C) D {
+					return qrCode(gtx, 200, v.detailQR)
+				})
+			}),
+			layout.Rigid(func(gtx C) D {
+				return layout.Inset{Top: unit.Dp(4)}.Layout(gtx, material.Body2(v.th, a.Address).Layout)
+			}),
+			layout.Rigid(func(gtx C) D {
+				return layout.Flex{Alignment: layout.Middle}.Layout(
+					gtx,
+					layout.Rigid(func(gtx C) D {
+						return layout.Inset{Top: unit.Dp(4), Right: unit.Dp(4)}.Layout(gtx, material.Button(v.th, &v.copyBtn, i18n.T("Copy address")).Layout)
+					}),
+					layout.Rigid(func(gtx C) D {
+						if v.explorer == nil {
+							return D{}
+						}
+						return layout.Inset{Top: unit.Dp(4), Right: unit.Dp(4)}.Layout(gtx, material.Button(v.th, &v.accountBtn, i18n.T("View on explorer")).Layout)
+					}),
+					layout.Rigid(func(gtx C) D {
+						return layout.Inset{Top: unit.Dp(4), Right: unit.Dp(8)}.Layout(gtx, material.Button(v.th, &v.closeBtn, i18n.T("Close")).Layout)
+					}),
+					layout.Rigid(material.Caption(v.th, v.detailNote).Layout),
+				)
+			}),
+		)
+	})
+}
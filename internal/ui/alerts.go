@@ -0,0 +1,164 @@
+package ui
+
+import (
+	"time"
+
+	"gioui.org/layout"
+	"gioui.org/unit"
+	"gioui.org/widget"
+	"gioui.org/widget/material"
+
+	"voiui/internal/i18n"
+	"voiui/internal/state"
+)
+
+type alertButtons struct {
+	ack    widget.Clickable
+	snooze widget.Clickable
+	mute   widget.Clickable
+}
+
+// muted snoozes until further notice.
+var muted = time.Date(9999, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func unacked(incidents []state.AlertIncident) int {
+	n := 0
+	for _, inc := range incidents {
+		if inc.Acked.IsZero() {
+			n++
+		}
+	}
+	return n
+}
+
+func snoozedUntil(s state.State, kind string) time.Time {
+	for _, sn := range s.Snoozed {
+		if sn.Kind == kind {
+			return sn.Until
+		}
+	}
+	return time.Time{}
+}
+
+func (v *view) alertButtons(id int) *alertButtons {
+	if v.alertBtns == nil {
+		v.alertBtns = map[int]*alertButtons{}
+	}
+
+	b, ok := v.alertBtns[id]
+	if !ok {
+		b = &alertButtons{}
+		v.alertBtns[id] = b
+	}
+	return b
+}
+
+// layoutAlertHistory lists the incidents, newest first, to acknowledge
+// them or snooze their notifications.
+func (v *view) layoutAlertHistory(gtx C) D {
+	if v.alerter == nil || len(v.s.Alerts) == 0 {
+		return D{}
+	}
+
+	if v.alertsBtn.Clicked() {
+		v.alertsOpen = !v.alertsOpen
+	}
+
+	title := i18n.Tf("Alerts (%d unacknowledged)", unacked(v.s.Alerts))
+	if v.alertsOpen {
+		title = i18n.T("Hide alerts")
+	}
+
+	children := []layout.FlexChild{
+		layout.Rigid(material.Button(v.th, &v.alertsBtn, title).Layout),
+	}
+
+	if v.alertsOpen {
+		for i := len(v.s.Alerts) - 1; i >= 0; i-- {
+			inc := v.s.Alerts[i]
+			children = append(children, layout.Rigid(func(gtx C) D {
+				return layout.Inset{Top: unit.Dp(8)}.Layout(gtx, func(gtx C) D {
+					return v.layoutIncident(gtx, inc)
+				})
+			}))
+		}
+	}
+
+	in := layout.UniformInset(unit.Dp(8))
+	return in.Layout(gtx, func(gtx C) D {
+		return layout.Flex{Axis: layout.Vertical}.Layout(gtx, children...)
+	})
+}
+
+func (v *view) layoutIncident(gtx C, inc state.AlertIncident) D {
+	b := v.alertButtons(inc.ID)
+	until := snoozedUntil(v.s, inc.Kind)
+
+	if b.ack.Clicked() {
+		go v.alerter.Acknowledge(inc.ID)
+	}
+	if b.snooze.Clicked() {
+		go v.alerter.Snooze(inc.Kind, time.Now().Add(time.Hour))
+	}
+	if b.mute.Clicked() {
+		if until.IsZero() {
+			until = muted
+		} else {
+			until = time.Time{}
+		}
+		go v.alerter.Snooze(inc.Kind, until)
+	}
+
+	status := i18n.T("active")
+	switch {
+	case !inc.Acked.IsZero():
+		status = i18n.T("acknowledged")
+	case !inc.Resolved.IsZero():
+		status = i18n.T("resolved")
+	}
+
+	severity := inc.Severity
+	if severity == "" {
+		severity = "warning"
+	}
+
+	head := material.Body1(v.th, i18n.Tf("%s [%s] %s, %s", inc.Opened.Format("Jan 2 15:04"), i18n.T(severity), inc.Title, status))
+	if inc.Resolved.IsZero() {
+		head.Color = red
+	}
+
+	note := ""
+	switch {
+	case until.Equal(muted):
+		note = i18n.T("Notifications muted")
+	case until.After(time.Now()):
+		note = i18n.Tf("Notifications snoozed until %s", until.Format("15:04"))
+	}
+
+	muteText := "Mute"
+	if !until.IsZero() {
+		muteText = "Unmute"
+	}
+
+	var buttons []layout.FlexChild
+	if inc.Acked.IsZero() {
+		buttons = append(buttons, v.keyregButton(&b.ack, "Acknowledge"))
+	}
+	if inc.Kind != "" {
+		buttons = append(buttons, v.keyregButton(&b.snooze, "Snooze 1h"), v.keyregButton(&b.mute, muteText))
+	}
+
+	return layout.Flex{Axis: layout.Vertical}.Layout(
+		gtx,
+		layout.Rigid(head.Layout),
+		layout.Rigid(func(gtx C) D {
+			if note == "" {
+				return D{}
+			}
+			return material.Caption(v.th, note).Layout(gtx)
+		}),
+		layout.Rigid(func(gtx C) D {
+			return layout.Flex{}.Layout(gtx, buttons...)
+		}),
+	)
+}
@@ -0,0 +1,35 @@
+package ui
+
+import (
+	"time"
+
+	"gioui.org/io/system"
+	"gioui.org/op"
+
+	"voiui/internal/state"
+)
+
+// progress is the share of the expected block time left at now, all of it
+// until the block time is known.
+func progress(s state.State, now time.Time) float32 {
+	if s.PrevBlockDuration == 0 {
+		return 1
+	}
+	return 1 - float32(now.Sub(s.CurrBlockAt))/float32(s.PrevBlockDuration)
+}
+
+// animate asks for the frame that moves the progress bar along: soon while
+// the window is in front and once a second in the background. Hidden
+// windows get no frames, so they stop on their own.
+func animate(gtx C, s state.State, stage system.Stage) {
+	if s.PrevBlockDuration == 0 {
+		return
+	}
+
+	rate := 20 * time.Millisecond
+	if stage < system.StageRunning {
+		rate = time.Second
+	}
+
+	op.InvalidateOp{At: gtx.Now.Add(rate)}.Add(gtx.Ops)
+}
@@ -0,0 +1,332 @@
+package ui
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"strconv"
+	"time"
+
+	"gioui.org/gpu/headless"
+	"gioui.org/layout"
+	"gioui.org/op"
+	"gioui.org/op/paint"
+	"gioui.org/unit"
+	"gioui.org/widget"
+	"github.com/pkg/errors"
+
+	"voiui/internal/i18n"
+)
+
+// Driver operates the open window through stable element IDs, for
+// scripted walkthroughs, end-to-end tests and screenshots.
+type Driver struct {
+	cmds chan func(v *view)
+}
+
+func NewDriver() *Driver {
+	return &Driver{cmds: make(chan func(v *view))}
+}
+
+// do runs f on the UI goroutine.
+func (d *Driver) do(f func(v *view)) error {
+	done := make(chan struct{})
+
+	select {
+	case d.cmds <- func(v *view) {
+		defer close(done)
+		f(v)
+	}:
+	case <-time.After(5 * time.Second):
+		return errors.New("the window is not open")
+	}
+
+	<-done
+
+	return nil
+}
+
+type element struct {
+	kind  string
+	apply func(text string)
+}
+
+func clickable(b *widget.Clickable) element {
+	return element{kind: "button", apply: func(string) { b.Click() }}
+}
+
+func editor(e *widget.Editor) element {
+	return element{kind: "editor", apply: e.SetText}
+}
+
+// elements maps IDs to the widgets a script may operate. IDs only change
+// when the widget's meaning does.
+func (v *view) elements() map[string]element {
+	els := map[string]element{
+		"token.reload":          clickable(&v.reloadBtn),
+		"token.rotate":          clickable(&v.rotateBtn),
+		"keychain.store":        clickable(&v.storeBtn),
+		"keychain.forget":       clickable(&v.forgetBtn),
+		"node.retry":            clickable(&v.retryBtn),
+		"node.start":            clickable(&v.startBtn),
+		"node.stop":             clickable(&v.stopBtn),
+		"node.restart":          clickable(&v.restartBtn),
+		"node.log":              clickable(&v.logBtn),
+		"palette.query":         editor(&v.paletteQuery),
+		"admin.unlock":          clickable(&v.unlockBtn),
+		"admin.lock":            clickable(&v.lockBtn),
+		"admin.passphrase":      editor(&v.passphrase),
+		"lock.pin":              editor(&v.pin),
+		"lock.unlock":           clickable(&v.pinBtn),
+		"lock.os":               clickable(&v.osAuthBtn),
+		"lock.new-pin":          editor(&v.newPin),
+		"lock.set-pin":          clickable(&v.setPinBtn),
+		"lock.disable":          clickable(&v.noPinBtn),
+		"lock.now":              clickable(&v.lockAppBtn),
+		"export.csv":            clickable(&v.exportCSVBtn),
+		"export.report":         clickable(&v.reportBtn),
+		"export.json":           clickable(&v.exportJSONBtn),
+		"accounts.watch":        clickable(&v.watchBtn),
+		"accounts.dismiss":      clickable(&v.dismissBtn),
+		"accounts.address":      editor(&v.watchAddress),
+		"accounts.label":        editor(&v.watchLabel),
+		"accounts.add":          clickable(&v.watchAddBtn),
+		"accounts.copy":         clickable(&v.copyBtn),
+		"accounts.close":        clickable(&v.closeBtn),
+		"explorer.round":        clickable(&v.roundBtn),
+		"explorer.account":      clickable(&v.accountBtn),
+		"keyreg.wallets":        clickable(&v.walletsBtn),
+		"keyreg.unlock":         clickable(&v.unlockWalletBtn),
+		"keyreg.lock":           clickable(&v.lockWalletBtn),
+		"keyreg.phone.online":   clickable(&v.phoneOnlineBtn),
+		"keyreg.phone.offline":  clickable(&v.phoneOfflineBtn),
+		"keyreg.phone.signed":   editor(&v.phoneSigned),
+		"keyreg.phone.submit":   clickable(&v.phoneSubmitBtn),
+		"keyreg.phone.cancel":   clickable(&v.phoneCancelBtn),
+		"backup.passphrase":     editor(&v.backupPassphrase),
+		"backup.create":         clickable(&v.backupBtn),
+		"backup.path":           editor(&v.restorePath),
+		"backup.restore":        clickable(&v.restoreBtn),
+		"keyreg.password":       editor(&v.keyregPassword),
+		"keyreg.export.online":  clickable(&v.exportOnlineBtn),
+		"keyreg.export.offline": clickable(&v.exportOfflineBtn),
+		"keyreg.sign.online":    clickable(&v.signOnlineBtn),
+		"keyreg.sign.offline":   clickable(&v.signOfflineBtn),
+	}
+
+	for _, days := range []string{"1", "7", "30"} {
+		days := days
+		els["export.range."+days] = element{kind: "option", apply: func(string) { v.exportRange.Value = days }}
+	}
+
+	if v.login != nil {
+		els["settings.login"] = element{kind: "toggle", apply: func(string) {
+			v.loginBox.Value = !v.loginBox.Value
+			on := v.loginBox.Value
+			go v.action(func() (string, error) { return v.login.Set(on) })
+		}}
+	}
+
+	if v.lang != nil {
+		codes := []string{systemLanguage}
+		for _, l := range i18n.Languages() {
+			codes = append(codes, l.Code)
+		}
+		for _, code := range codes {
+			code := code
+			els["settings.language."+code] = element{kind: "option", apply: func(string) {
+				v.langOpt.Value = code
+				v.setLanguage(code)
+			}}
+		}
+	}
+
+	if v.look != nil {
+		els["settings.compact"] = element{kind: "toggle", apply: func(string) { v.setCompact(!v.compactBox.Value) }}
+		els["window.expand"] = clickable(&v.expandBtn)
+
+		for _, scale := range scales {
+			scale := scale
+			els["settings.scale."+scale] = element{kind: "option", apply: func(string) { v.setScale(scale) }}
+		}
+	}
+
+	if v.nodeCfg != nil {
+		els["nodeconfig.toggle"] = clickable(&v.nodeCfgBtn)
+		els["nodeconfig.save"] = clickable(&v.nodeCfgSaveBtn)
+		els["nodeconfig.restart"] = clickable(&v.nodeCfgRestartBtn)
+		els["nodeconfig.telemetry.save"] = clickable(&v.telemetrySaveBtn)
+	}
+
+	if v.mailer != nil {
+		els["email.test"] = clickable(&v.testMailBtn)
+	}
+
+	if v.diag != nil {
+		els["diagnostics.toggle"] = clickable(&v.diagBtn)
+		els["diagnostics.copy"] = clickable(&v.diagCopyBtn)
+		els["diagnostics.report"] = clickable(&v.diagReportBtn)
+	}
+
+	if v.alerter != nil && len(v.s.Alerts) > 0 {
+		els["alerts.toggle"] = clickable(&v.alertsBtn)
+
+		if v.alertsOpen {
+			for _, inc := range v.s.Alerts {
+				id := strconv.Itoa(inc.ID)
+				b := v.alertButtons(inc.ID)
+				els["alerts."+id+".ack"] = clickable(&b.ack)
+				els["alerts."+id+".snooze"] = clickable(&b.snooze)
+				els["alerts."+id+".mute"] = clickable(&b.mute)
+			}
+		}
+	}
+
+	els["palette.open"] = element{kind: "button", apply: func(string) { v.openPalette(!v.paletteOpen) }}
+
+	if v.paletteOpen {
+		for i := range v.matches() {
+			els["palette.item."+strconv.Itoa(i)] = clickable(&v.paletteBtns[i])
+		}
+	}
+
+	for path, btn := range v.submitBtns {
+		els["txn.submit."+path] = clickable(btn)
+	}
+
+	for address, btn := range v.removeBtns {
+		els["accounts.remove."+address] = clickable(btn)
+	}
+
+	for address, btn := range v.detailBtns {
+		els["accounts.details."+address] = clickable(btn)
+	}
+
+	for id, btn := range v.proposalBtns {
+		els["explorer.proposal."+id] = clickable(btn)
+	}
+
+	for _, k := range v.s.Keys {
+		id := k.ID
+		els["keyreg.key."+id] = element{kind: "option", apply: func(string) { v.keyregKey.Value = id }}
+	}
+
+	for _, name := range v.s.KMD.Wallets {
+		name := name
+		els["keyreg.wallet."+name] = element{kind: "option", apply: func(string) { v.keyregWallet.Value = name }}
+	}
+
+	if v.profiles != nil {
+		for _, name := range v.profiles.Names() {
+			name := name
+			els["profile."+name] = element{kind: "option", apply: func(string) {
+				v.profile.Value = name
+				go v.action(func() (string, error) { return v.profiles.Switch(name) })
+			}}
+		}
+	}
+
+	return els
+}
+
+// Elements returns the kind of every element the current view offers,
+// keyed by ID.
+func (d *Driver) Elements() (map[string]string, error) {
+	list := map[string]string{}
+
+	err := d.do(func(v *view) {
+		for id, e := range v.elements() {
+			list[id] = e.kind
+		}
+	})
+
+	return list, err
+}
+
+func (d *Driver) apply(id string, text string) error {
+	var found bool
+
+	err := d.do(func(v *view) {
+		var e element
+		e, found = v.elements()[id]
+		if found {
+			e.apply(text)
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	if !found {
+		return errors.Errorf("unknown element: %s", id)
+	}
+
+	return nil
+}
+
+func (d *Driver) Click(id string) error {
+	return d.apply(id, "")
+}
+
+func (d *Driver) Type(id string, text string) error {
+	return d.apply(id, text)
+}
+
+// Screenshot renders the current view off-screen at the given size in dp.
+func (d *Driver) Screenshot(width int, height int) ([]byte, error) {
+	var data []byte
+	var rerr error
+
+	err := d.do(func(v *view) {
+		w, err := headless.NewWindow(width, height)
+		if err != nil {
+			rerr = errors.Wrap(err, "failed to create off-screen window")
+			return
+		}
+		defer w.Release()
+
+		var ops op.Ops
+		gtx := layout.Context{
+			Ops:         &ops,
+			Now:         time.Now(),
+			Metric:      unit.Metric{PxPerDp: 1, PxPerSp: 1},
+			Constraints: layout.Exact(image.Pt(width, height)),
+		}
+
+		paint.Fill(gtx.Ops, color.NRGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff})
+
+		if v.s.Locked {
+			v.layoutLock(gtx)
+		} else {
+			v.layout(gtx)
+		}
+
+		err = w.Frame(&ops)
+		if err != nil {
+			rerr = errors.Wrap(err, "failed to render")
+			return
+		}
+
+		img := image.NewRGBA(image.Rect(0, 0, width, height))
+		err = w.Screenshot(img)
+		if err != nil {
+			rerr = errors.Wrap(err, "failed to capture")
+			return
+		}
+
+		var buf bytes.Buffer
+		err = png.Encode(&buf, img)
+		if err != nil {
+			rerr = errors.Wrap(err, "failed to encode screenshot")
+			return
+		}
+
+		data = buf.Bytes()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return data, rerr
+}
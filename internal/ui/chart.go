@@ -0,0 +1,81 @@
+package ui
+
+import (
+	"image"
+	"image/color"
+
+	"gioui.org/op/clip"
+	"gioui.org/op/paint"
+	"gioui.org/unit"
+)
+
+var gray = color.NRGBA{R: 0x88, G: 0x88, B: 0x88, A: 0xff}
+
+type bar struct {
+	value float64
+	color color.NRGBA
+}
+
+// bars draws a bar chart scaled to the largest value, newest on the right.
+func bars(gtx C, height unit.Dp, items []bar) D {
+	w := gtx.Constraints.Max.X
+	h := gtx.Dp(height)
+
+	if len(items) > w {
+		items = items[len(items)-w:]
+	}
+
+	max := 0.0
+	for _, b := range items {
+		if b.value > max {
+			max = b.value
+		}
+	}
+
+	if len(items) == 0 || max == 0 {
+		return D{Size: image.Pt(w, h)}
+	}
+
+	bw := w / len(items)
+	gap := 0
+	if bw > 3 {
+		gap = 1
+	}
+
+	for i, b := range items {
+		bh := int(float64(h) * b.value / max)
+		if bh < 1 {
+			bh = 1
+		}
+
+		x := i * bw
+		paint.FillShape(gtx.Ops, b.color, clip.Rect(image.Rect(x, h-bh, x+bw-gap, h)).Op())
+	}
+
+	return D{Size: image.Pt(w, h)}
+}
+
+// qrCode draws a QR bitmap as a square of the given size on white.
+func qrCode(gtx C, size unit.Dp, bits [][]bool) D {
+	s := gtx.Dp(size)
+
+	paint.FillShape(gtx.Ops, color.NRGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}, clip.Rect(image.Rect(0, 0, s, s)).Op())
+
+	if len(bits) == 0 {
+		return D{Size: image.Pt(s, s)}
+	}
+
+	m := s / len(bits)
+	off := (s - m*len(bits)) / 2
+
+	for y, row := range bits {
+		for x, on := range row {
+			if on {
+				r := image.Rect(off+x*m, off+y*m, off+(x+1)*m, off+(y+1)*m)
+				paint.FillShape(gtx.Ops, color.NRGBA{A: 0xff}, clip.Rect(r).Op())
+			}
+		}
+	}
+
+	return D{Size: image.Pt(s, s)}
+}
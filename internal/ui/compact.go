@@ -0,0 +1,94 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+
+	"gioui.org/app"
+	"gioui.org/layout"
+	"gioui.org/unit"
+	"gioui.org/widget/material"
+
+	"voiui/internal/i18n"
+)
+
+var scales = []string{"0.9", "1", "1.25", "1.5", "2"}
+
+func (v *view) scale() float32 {
+	scale, err := strconv.ParseFloat(v.scaleOpt.Value, 32)
+	if err != nil || scale <= 0 {
+		return 1
+	}
+	return float32(scale)
+}
+
+func (v *view) setScale(value string) {
+	v.scaleOpt.Value = value
+	v.resize()
+
+	scale := v.scale()
+	go v.action(func() (string, error) { return v.look.SetScale(scale) })
+}
+
+func (v *view) setCompact(on bool) {
+	v.compactBox.Value = on
+	v.resize()
+
+	go v.action(func() (string, error) { return v.look.SetCompact(on) })
+}
+
+// resize fits the window to the layout, the sizes are scaled by hand as
+// the window options don't see the scale.
+func (v *view) resize() {
+	scale := unit.Dp(v.scale())
+
+	if v.compactBox.Value {
+		v.win.Option(app.MinSize(200*scale, 110*scale), app.Size(240*scale, 130*scale))
+	} else {
+		v.win.Option(app.MinSize(300*scale, 200*scale), app.Size(300*scale, 200*scale))
+	}
+}
+
+// layoutCompact is the widget-sized layout with just the round,
+// participation and block progress.
+func (v *view) layoutCompact(gtx C) D {
+	return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+		layout.Rigid(v.layoutPalette),
+		layout.Rigid(func(gtx C) D {
+			return layout.Flex{Alignment: layout.Middle}.Layout(
+				gtx,
+				layout.Flexed(1, func(gtx C) D {
+					return v.field(gtx, i18n.T("Last round:"), fmt.Sprintf("%d", v.s.Round))
+				}),
+				layout.Rigid(material.Button(v.th, &v.expandBtn, i18n.T("Expand")).Layout),
+			)
+		}),
+		layout.Rigid(v.layoutParticipation),
+		layout.Rigid(v.layoutProgress),
+	)
+}
+
+func (v *view) layoutAppearance(gtx C) D {
+	if v.look == nil {
+		return D{}
+	}
+
+	children := []layout.FlexChild{
+		layout.Rigid(material.Caption(v.th, i18n.T("Scale:")).Layout),
+	}
+	for _, s := range scales {
+		f, _ := strconv.ParseFloat(s, 32)
+		children = append(children, layout.Rigid(material.RadioButton(v.th, &v.scaleOpt, s, fmt.Sprintf("%.0f%%", f*100)).Layout))
+	}
+
+	in := layout.UniformInset(unit.Dp(8))
+	return in.Layout(gtx, func(gtx C) D {
+		return layout.Flex{Axis: layout.Vertical}.Layout(
+			gtx,
+			layout.Rigid(func(gtx C) D {
+				return layout.Flex{Alignment: layout.Middle}.Layout(gtx, children...)
+			}),
+			layout.Rigid(material.CheckBox(v.th, &v.compactBox, i18n.T("Compact layout")).Layout),
+		)
+	})
+}
@@ -0,0 +1,71 @@
+package ui
+
+import (
+	"strings"
+
+	"gioui.org/io/clipboard"
+	"gioui.org/layout"
+	"gioui.org/unit"
+	"gioui.org/widget/material"
+
+	"voiui/internal/i18n"
+)
+
+// diagLines is how many of the recent log lines the pane shows, Copy log
+// takes all of them.
+const diagLines = 20
+
+func (v *view) layoutDiagnostics(gtx C) D {
+	if v.diag == nil {
+		return D{}
+	}
+
+	if v.diagBtn.Clicked() {
+		v.diagOpen = !v.diagOpen
+		v.diagNote = ""
+	}
+
+	if v.diagCopyBtn.Clicked() {
+		clipboard.WriteOp{Text: strings.Join(v.diag.Recent(), "\n")}.Add(gtx.Ops)
+		v.diagNote = i18n.T("Log copied")
+	}
+
+	if v.diagReportBtn.Clicked() {
+		go v.action(v.diag.Report)
+	}
+
+	title := "Show diagnostics"
+	if v.diagOpen {
+		title = "Hide diagnostics"
+	}
+
+	children := []layout.FlexChild{
+		layout.Rigid(func(gtx C) D {
+			return layout.Flex{}.Layout(gtx, v.keyregButton(&v.diagBtn, title), v.keyregButton(&v.diagCopyBtn, "Copy log"), v.keyregButton(&v.diagReportBtn, "Generate diagnostic report"))
+		}),
+	}
+
+	if v.diagNote != "" {
+		children = append(children, layout.Rigid(material.Caption(v.th, v.diagNote).Layout))
+	}
+
+	if v.diagOpen {
+		children = append(children, layout.Rigid(material.Caption(v.th, i18n.Tf("Log file: %s", v.diag.LogPath())).Layout))
+
+		lines := v.diag.Recent()
+		if len(lines) > diagLines {
+			lines = lines[len(lines)-diagLines:]
+		}
+
+		for _, line := range lines {
+			l := material.Caption(v.th, line)
+			l.Font.Variant = "Mono"
+			children = append(children, layout.Rigid(l.Layout))
+		}
+	}
+
+	in := layout.UniformInset(unit.Dp(8))
+	return in.Layout(gtx, func(gtx C) D {
+		return layout.Flex{Axis: layout.Vertical}.Layout(gtx, children...)
+	})
+}
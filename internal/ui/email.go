@@ -0,0 +1,17 @@
+package ui
+
+import (
+	"gioui.org/layout"
+)
+
+func (v *view) layoutEmail(gtx C) D {
+	if v.mailer == nil {
+		return D{}
+	}
+
+	if v.testMailBtn.Clicked() {
+		go v.action(v.mailer.Test)
+	}
+
+	return layout.Flex{}.Layout(gtx, v.keyregButton(&v.testMailBtn, "Send test email"))
+}
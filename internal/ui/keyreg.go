@@ -0,0 +1,199 @@
+package ui
+
+import (
+	"gioui.org/layout"
+	"gioui.org/unit"
+	"gioui.org/widget"
+	"gioui.org/widget/material"
+
+	"voiui/internal/i18n"
+	"voiui/internal/state"
+)
+
+func (v *view) keyregButton(btn *widget.Clickable, text string) layout.FlexChild {
+	return layout.Rigid(func(gtx C) D {
+		return layout.Inset{Top: unit.Dp(4), Right: unit.Dp(4)}.Layout(gtx, material.Button(v.th, btn, i18n.T(text)).Layout)
+	})
+}
+
+// layoutKeyreg builds key registration transactions for a key on the node,
+// either for signing elsewhere or through the node's kmd.
+func (v *view) layoutKeyreg(gtx C) D {
+	if v.keyreg == nil || v.s.AccountsOnly || len(v.s.Keys) == 0 {
+		return D{}
+	}
+
+	children := []layout.FlexChild{
+		layout.Rigid(material.Caption(v.th, i18n.T("Register key:")).Layout),
+	}
+
+	for _, k := range v.s.Keys {
+		children = append(children, layout.Rigid(material.RadioButton(v.th, &v.keyregKey, k.ID, i18n.Tf("%s for %s", short(k.ID), short(k.Address))).Layout))
+	}
+
+	children = append(children,
+		layout.Rigid(func(gtx C) D {
+			return layout.Flex{}.Layout(gtx,
+				v.keyregButton(&v.exportOnlineBtn, "Export online"),
+				v.keyregButton(&v.exportOfflineBtn, "Export offline"),
+			)
+		}),
+		layout.Rigid(func(gtx C) D {
+			return layout.Flex{}.Layout(gtx,
+				v.keyregButton(&v.phoneOnlineBtn, "Sign elsewhere & go online"),
+				v.keyregButton(&v.phoneOfflineBtn, "Sign elsewhere & go offline"),
+			)
+		}),
+		layout.Rigid(v.layoutPhone),
+		layout.Rigid(v.layoutWallet),
+	)
+
+	in := layout.UniformInset(unit.Dp(8))
+	return in.Layout(gtx, func(gtx C) D {
+		return layout.Flex{Axis: layout.Vertical}.Layout(gtx, children...)
+	})
+}
+
+func (v *view) handleWallet() {
+	if v.walletsBtn.Clicked() {
+		go v.action(func() (string, error) {
+			wallets, err := v.keyreg.Wallets()
+			if err != nil {
+				return "", err
+			}
+
+			v.send <- func(s *state.State) error {
+				s.KMD.Wallets = wallets
+				return nil
+			}
+
+			if len(wallets) == 0 {
+				return i18n.T("kmd has no wallets, create one with goal wallet new"), nil
+			}
+			return i18n.Tf("Found %d kmd wallet(s)", len(wallets)), nil
+		})
+	}
+
+	if v.unlockWalletBtn.Clicked() && v.keyregWallet.Value != "" {
+		wallet, password := v.keyregWallet.Value, v.keyregPassword.Text()
+		go v.action(func() (string, error) {
+			accounts, err := v.keyreg.Unlock(wallet, password)
+			if err != nil {
+				return "", err
+			}
+
+			v.send <- func(s *state.State) error {
+				s.KMD.Unlocked = wallet
+				s.KMD.Accounts = accounts
+				return nil
+			}
+
+			return i18n.Tf("Unlocked %s", wallet), nil
+		})
+	}
+
+	if v.lockWalletBtn.Clicked() {
+		v.keyregPassword.SetText("")
+		go func() {
+			v.send <- func(s *state.State) error {
+				s.KMD.Unlocked = ""
+				s.KMD.Accounts = nil
+				return nil
+			}
+		}()
+	}
+}
+
+// signable tells whether the unlocked wallet holds the key of the selected
+// participation key's account.
+func (v *view) signable() bool {
+	for _, k := range v.s.Keys {
+		if k.ID == v.keyregKey.Value {
+			for _, address := range v.s.KMD.Accounts {
+				if address == k.Address {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// layoutWallet lists kmd wallets, asks for the password and, once it was
+// accepted, offers to sign with it.
+func (v *view) layoutWallet(gtx C) D {
+	if v.s.KMD.Unlocked != "" {
+		note := i18n.T("The wallet does not hold the selected key's account")
+		if v.signable() {
+			note = i18n.T("Ready to sign")
+		}
+
+		return layout.Inset{Top: unit.Dp(8)}.Layout(gtx, func(gtx C) D {
+			return layout.Flex{Axis: layout.Vertical}.Layout(
+				gtx,
+				layout.Rigid(material.Body2(v.th, i18n.Tf("kmd wallet %s unlocked", v.s.KMD.Unlocked)).Layout),
+				layout.Rigid(material.Caption(v.th, note).Layout),
+				layout.Rigid(func(gtx C) D {
+					return layout.Flex{}.Layout(gtx,
+						v.keyregButton(&v.signOnlineBtn, "Sign with kmd & go online"),
+						v.keyregButton(&v.signOfflineBtn, "Sign with kmd & go offline"),
+						v.keyregButton(&v.lockWalletBtn, "Lock"),
+					)
+				}),
+			)
+		})
+	}
+
+	children := []layout.FlexChild{
+		layout.Rigid(func(gtx C) D {
+			return layout.Flex{}.Layout(gtx, v.keyregButton(&v.walletsBtn, "Find kmd wallets"))
+		}),
+	}
+
+	for _, name := range v.s.KMD.Wallets {
+		children = append(children, layout.Rigid(material.RadioButton(v.th, &v.keyregWallet, name, name).Layout))
+	}
+
+	if len(v.s.KMD.Wallets) > 0 {
+		children = append(children, layout.Rigid(func(gtx C) D {
+			return layout.Flex{Alignment: layout.Middle}.Layout(
+				gtx,
+				layout.Flexed(1, material.Editor(v.th, &v.keyregPassword, i18n.T("Wallet password")).Layout),
+				v.keyregButton(&v.unlockWalletBtn, "Unlock"),
+			)
+		}))
+	}
+
+	return layout.Inset{Top: unit.Dp(8)}.Layout(gtx, func(gtx C) D {
+		return layout.Flex{Axis: layout.Vertical}.Layout(gtx, children...)
+	})
+}
+
+// layoutBackup saves the node's key files to a sealed archive or installs
+// them from one, e.g. when moving to new hardware.
+func (v *view) layoutBackup(gtx C) D {
+	if v.backup == nil || v.s.AccountsOnly {
+		return D{}
+	}
+
+	in := layout.UniformInset(unit.Dp(8))
+	return in.Layout(gtx, func(gtx C) D {
+		return layout.Flex{Axis: layout.Vertical}.Layout(
+			gtx,
+			layout.Rigid(material.Caption(v.th, i18n.T("Key backup:")).Layout),
+			layout.Rigid(material.Editor(v.th, &v.backupPassphrase, i18n.T("Backup passphrase")).Layout),
+			layout.Rigid(func(gtx C) D {
+				return layout.Flex{}.Layout(gtx, v.keyregButton(&v.backupBtn, "Back up keys"))
+			}),
+			layout.Rigid(func(gtx C) D {
+				return layout.Inset{Top: unit.Dp(8)}.Layout(gtx, func(gtx C) D {
+					return layout.Flex{Alignment: layout.Middle}.Layout(
+						gtx,
+						layout.Flexed(1, material.Editor(v.th, &v.restorePath, i18n.T("Backup file to restore")).Layout),
+						v.keyregButton(&v.restoreBtn, "Restore"),
+					)
+				})
+			}),
+		)
+	})
+}
@@ -0,0 +1,60 @@
+package ui
+
+import (
+	"image"
+
+	"gioui.org/io/key"
+	"gioui.org/io/semantic"
+	"gioui.org/layout"
+	"gioui.org/op"
+	"gioui.org/op/clip"
+)
+
+// handleKeys runs the keyboard shortcuts and reports whether the window
+// should be hidden. Tab and Shift+Tab move the focus and Enter or Space
+// press the focused button, Gio does that for us. It must come before
+// handle so that clicks it makes are seen this frame.
+func (v *view) handleKeys(gtx C) (hide bool) {
+	for _, e := range gtx.Events(&v.paletteOpen) {
+		e, ok := e.(key.Event)
+		if !ok || e.State != key.Press {
+			continue
+		}
+
+		switch e.Name {
+		case "K":
+			v.openPalette(!v.paletteOpen)
+		case "R", key.NameF5:
+			v.retryBtn.Click()
+		case "L":
+			if v.lock.Enabled() {
+				v.lockAppBtn.Click()
+			}
+		case key.NameEscape:
+			if v.paletteOpen {
+				v.openPalette(false)
+			} else {
+				hide = true
+			}
+		}
+	}
+
+	// the first handler added gets the keys nobody else takes
+	key.InputOp{Tag: &v.paletteOpen, Keys: "Short-K|Short-R|F5|Short-L|" + key.NameEscape}.Add(gtx.Ops)
+
+	return hide
+}
+
+// described gives a widget that only draws, like a chart or a QR code, a
+// label for screen readers.
+func described(gtx C, label string, w layout.Widget) D {
+	m := op.Record(gtx.Ops)
+	dims := w(gtx)
+	call := m.Stop()
+
+	defer clip.Rect(image.Rectangle{Max: dims.Size}).Push(gtx.Ops).Pop()
+	semantic.LabelOp(label).Add(gtx.Ops)
+	call.Add(gtx.Ops)
+
+	return dims
+}
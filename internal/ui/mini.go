@@ -0,0 +1,129 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"image/color"
+	"log/slog"
+
+	"gioui.org/app"
+	"gioui.org/font/gofont"
+	"gioui.org/io/key"
+	"gioui.org/io/system"
+	"gioui.org/layout"
+	"gioui.org/op"
+	"gioui.org/op/clip"
+	"gioui.org/op/paint"
+	"gioui.org/unit"
+	"gioui.org/widget/material"
+
+	"voiui/internal/i18n"
+	"voiui/internal/state"
+)
+
+// RunMini shows the mini widget in w, a borderless window kept on top with
+// the participation dot, the round and the block progress. Dragging moves
+// it and Esc closes it.
+func (u *UI) RunMini(ctx context.Context, w *app.Window) error {
+	th := material.NewTheme(gofont.Collection())
+
+	changed, unsubscribe := u.store.Subscribe()
+	defer unsubscribe()
+
+	s := u.store.Snapshot()
+
+	stage := system.StageRunning
+
+	var ops op.Ops
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-changed:
+			next := u.store.Snapshot()
+			if next.Version == s.Version {
+				continue
+			}
+			s = next
+			w.Invalidate()
+		case e := <-w.Events():
+			switch e := e.(type) {
+			case app.ViewEvent:
+				err := keepOnTop(e)
+				if err != nil {
+					slog.Error("failed to keep the mini widget on top", "err", err)
+				}
+			case system.StageEvent:
+				stage = e.Stage
+			case system.DestroyEvent:
+				return e.Err
+			case system.FrameEvent:
+				gtx := layout.NewContext(&ops, e)
+
+				if u.look != nil {
+					scale := u.look.Scale()
+					gtx.Metric.PxPerDp *= scale
+					gtx.Metric.PxPerSp *= scale
+				}
+
+				for _, e := range gtx.Events(&s) {
+					if e, ok := e.(key.Event); ok && e.State == key.Press {
+						w.Perform(system.ActionClose)
+					}
+				}
+
+				animate(gtx, s, stage)
+				layoutMini(gtx, th, &s)
+
+				e.Frame(gtx.Ops)
+			}
+		}
+	}
+}
+
+func layoutMini(gtx C, th *material.Theme, s *state.State) D {
+	defer clip.Rect(image.Rectangle{Max: gtx.Constraints.Max}).Push(gtx.Ops).Pop()
+	system.ActionInputOp(system.ActionMove).Add(gtx.Ops)
+	key.InputOp{Tag: s, Keys: key.NameEscape}.Add(gtx.Ops)
+
+	dot, label := red, i18n.T("Not participating")
+	switch {
+	case s.AccountsOnly:
+		dot, label = gray, i18n.T("Watching accounts through a public endpoint")
+	case !s.Running:
+		label = i18n.T("Not Running")
+	case s.Participation == state.ParticipationActive:
+		dot, label = green, i18n.T("Participating")
+	case s.Participation == state.ParticipationOffline:
+		dot, label = orange, i18n.T("Key active, account offline")
+	}
+
+	bar := material.ProgressBar(th, progress(*s, gtx.Now))
+
+	return layout.UniformInset(unit.Dp(6)).Layout(gtx, func(gtx C) D {
+		return layout.Flex{Axis: layout.Vertical}.Layout(
+			gtx,
+			layout.Rigid(func(gtx C) D {
+				return layout.Flex{Alignment: layout.Middle}.Layout(
+					gtx,
+					layout.Rigid(func(gtx C) D {
+						return described(gtx, label, func(gtx C) D {
+							return circle(gtx, unit.Dp(12), dot)
+						})
+					}),
+					layout.Rigid(layout.Spacer{Width: unit.Dp(6)}.Layout),
+					layout.Rigid(material.Body1(th, fmt.Sprintf("%d", s.Round)).Layout),
+				)
+			}),
+			layout.Rigid(layout.Spacer{Height: unit.Dp(4)}.Layout),
+			layout.Rigid(bar.Layout),
+		)
+	})
+}
+
+func circle(gtx C, size unit.Dp, c color.NRGBA) D {
+	d := gtx.Dp(size)
+	paint.FillShape(gtx.Ops, c, clip.Ellipse{Max: image.Pt(d, d)}.Op(gtx.Ops))
+	return D{Size: image.Pt(d, d)}
+}
@@ -0,0 +1,151 @@
+package ui
+
+import (
+	"gioui.org/layout"
+	"gioui.org/unit"
+	"gioui.org/widget/material"
+
+	"voiui/internal/i18n"
+)
+
+func (v *view) loadNodeConfig() {
+	v.nodeSettings, v.nodeCfgErr = v.nodeCfg.Settings()
+
+	t, err := v.nodeCfg.Telemetry()
+	if err != nil && v.nodeCfgErr == nil {
+		v.nodeCfgErr = err
+	}
+	v.telemetry = t
+	v.telemetryBox.Value = t.Enabled
+	v.telemetryName.SetText(t.Name)
+}
+
+func (v *view) layoutNodeConfig(gtx C) D {
+	if v.nodeCfg == nil {
+		return D{}
+	}
+
+	if v.nodeCfgBtn.Clicked() {
+		v.nodeCfgOpen = !v.nodeCfgOpen
+		v.nodeCfgNote, v.nodeCfgSaved = "", false
+		if v.nodeCfgOpen {
+			v.loadNodeConfig()
+		}
+	}
+
+	if v.nodeCfgSaveBtn.Clicked() {
+		note, err := v.nodeCfg.Set(v.nodeCfgName.Text(), v.nodeCfgValue.Text())
+		if err != nil {
+			v.nodeCfgNote, v.nodeCfgSaved = err.Error(), false
+		} else {
+			v.nodeCfgNote, v.nodeCfgSaved = note, true
+			v.nodeCfgValue.SetText("")
+		}
+		v.loadNodeConfig()
+	}
+
+	if v.telemetrySaveBtn.Clicked() {
+		note, err := v.nodeCfg.SetTelemetry(v.telemetryBox.Value, v.telemetryName.Text())
+		if err != nil {
+			v.nodeCfgNote, v.nodeCfgSaved = err.Error(), false
+		} else {
+			v.nodeCfgNote, v.nodeCfgSaved = note, true
+			v.loadNodeConfig()
+		}
+	}
+
+	if v.nodeCfgRestartBtn.Clicked() {
+		v.nodeCfgSaved = false
+		go v.action(func() (string, error) { return v.service.Control("restart") })
+	}
+
+	title := "Show node config"
+	if v.nodeCfgOpen {
+		title = "Hide node config"
+	}
+
+	children := []layout.FlexChild{
+		layout.Rigid(func(gtx C) D {
+			return layout.Flex{}.Layout(gtx, v.keyregButton(&v.nodeCfgBtn, title))
+		}),
+	}
+
+	if !v.nodeCfgOpen {
+		return layout.Flex{Axis: layout.Vertical}.Layout(gtx, children...)
+	}
+
+	if v.nodeCfgErr != nil {
+		children = append(children, layout.Rigid(func(gtx C) D {
+			l := material.Body2(v.th, v.nodeCfgErr.Error())
+			l.Color = red
+			return l.Layout(gtx)
+		}))
+	}
+
+	for _, s := range v.nodeSettings {
+		s := s
+		children = append(children, layout.Rigid(func(gtx C) D {
+			text := s.Name + ": " + s.Value
+			if s.Changed && s.Default != "" {
+				text += "  " + i18n.Tf("(default %s)", s.Default)
+			}
+
+			l := material.Body2(v.th, text)
+			l.Font.Variant = "Mono"
+			if s.Changed {
+				l.Color = blue
+			}
+			return l.Layout(gtx)
+		}))
+	}
+
+	children = append(children,
+		layout.Rigid(material.Caption(v.th, i18n.T("Set a setting, an empty value restores the default:")).Layout),
+		layout.Rigid(func(gtx C) D {
+			return layout.Flex{Alignment: layout.Middle}.Layout(
+				gtx,
+				layout.Flexed(1, func(gtx C) D {
+					return layout.Inset{Right: unit.Dp(8)}.Layout(gtx, material.Editor(v.th, &v.nodeCfgName, i18n.T("Setting")).Layout)
+				}),
+				layout.Flexed(1, func(gtx C) D {
+					return layout.Inset{Right: unit.Dp(8)}.Layout(gtx, material.Editor(v.th, &v.nodeCfgValue, i18n.T("Value")).Layout)
+				}),
+				layout.Rigid(material.Button(v.th, &v.nodeCfgSaveBtn, i18n.T("Save")).Layout),
+			)
+		}),
+	)
+
+	children = append(children,
+		layout.Rigid(func(gtx C) D {
+			return layout.Inset{Top: unit.Dp(8)}.Layout(gtx, material.CheckBox(v.th, &v.telemetryBox, i18n.T("Send telemetry")).Layout)
+		}),
+		layout.Rigid(func(gtx C) D {
+			return layout.Flex{Alignment: layout.Middle}.Layout(
+				gtx,
+				layout.Flexed(1, func(gtx C) D {
+					return layout.Inset{Right: unit.Dp(8)}.Layout(gtx, material.Editor(v.th, &v.telemetryName, i18n.T("Telemetry node name")).Layout)
+				}),
+				layout.Rigid(material.Button(v.th, &v.telemetrySaveBtn, i18n.T("Save telemetry")).Layout),
+			)
+		}),
+	)
+
+	if v.telemetry.GUID != "" {
+		children = append(children, layout.Rigid(material.Caption(v.th, i18n.Tf("Telemetry GUID: %s", v.telemetry.GUID)).Layout))
+	}
+
+	if v.nodeCfgNote != "" {
+		children = append(children, layout.Rigid(material.Caption(v.th, v.nodeCfgNote).Layout))
+	}
+
+	if v.nodeCfgSaved && v.s.Service.Restart {
+		children = append(children, layout.Rigid(func(gtx C) D {
+			return layout.Flex{}.Layout(gtx, v.keyregButton(&v.nodeCfgRestartBtn, "Restart node"))
+		}))
+	}
+
+	in := layout.UniformInset(unit.Dp(8))
+	return in.Layout(gtx, func(gtx C) D {
+		return layout.Flex{Axis: layout.Vertical}.Layout(gtx, children...)
+	})
+}
@@ -0,0 +1,29 @@
+package ui
+
+import (
+	"fmt"
+	"os/exec"
+
+	"gioui.org/app"
+	"github.com/pkg/errors"
+)
+
+// keepOnTop asks the X11 window manager through wmctrl, Wayland has no way
+// for a client to stay on top.
+func keepOnTop(e app.ViewEvent) error {
+	x, ok := e.(app.X11ViewEvent)
+	if !ok || x.Window == 0 {
+		return nil
+	}
+
+	if _, err := exec.LookPath("wmctrl"); err != nil {
+		return errors.New("install wmctrl to keep the mini widget on top")
+	}
+
+	err := exec.Command("wmctrl", "-i", "-r", fmt.Sprintf("0x%x", x.Window), "-b", "add,above").Run()
+	if err != nil {
+		return errors.Wrap(err, "failed to run wmctrl")
+	}
+
+	return nil
+}
@@ -0,0 +1,11 @@
+//go:build !windows && !linux
+
+package ui
+
+import (
+	"gioui.org/app"
+)
+
+func keepOnTop(e app.ViewEvent) error {
+	return nil
+}
@@ -0,0 +1,29 @@
+package ui
+
+import (
+	"gioui.org/app"
+	"github.com/pkg/errors"
+	"golang.org/x/sys/windows"
+)
+
+var procSetWindowPos = windows.NewLazySystemDLL("user32.dll").NewProc("SetWindowPos")
+
+func keepOnTop(e app.ViewEvent) error {
+	if e.HWND == 0 {
+		return nil
+	}
+
+	const (
+		hwndTopmost   = ^uintptr(0)
+		swpNoSize     = 0x0001
+		swpNoMove     = 0x0002
+		swpNoActivate = 0x0010
+	)
+
+	r, _, err := procSetWindowPos.Call(e.HWND, hwndTopmost, 0, 0, 0, 0, swpNoSize|swpNoMove|swpNoActivate)
+	if r == 0 {
+		return errors.Wrap(err, "failed to set the window topmost")
+	}
+
+	return nil
+}
@@ -0,0 +1,230 @@
+package ui
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"gioui.org/layout"
+	"gioui.org/unit"
+	"gioui.org/widget"
+	"gioui.org/widget/material"
+
+	"voiui/internal/i18n"
+)
+
+const paletteRows = 8
+
+type command struct {
+	title string
+	run   func()
+}
+
+// commands lists what the palette offers, mostly by clicking the button
+// that does the same so both go through handle.
+func (v *view) commands() []command {
+	var list []command
+
+	click := func(title string, btn *widget.Clickable) {
+		list = append(list, command{title: i18n.T(title), run: func() { btn.Click() }})
+	}
+
+	if v.profiles != nil {
+		for _, name := range v.profiles.Names() {
+			name := name
+			if name == v.s.Profile {
+				continue
+			}
+			list = append(list, command{title: i18n.Tf("Switch to profile %s", name), run: func() {
+				v.profile.Value = name
+				go v.action(func() (string, error) { return v.profiles.Switch(name) })
+			}})
+		}
+	}
+
+	click("Refresh now", &v.retryBtn)
+
+	if v.service != nil {
+		if v.s.Service.Start {
+			click("Start node", &v.startBtn)
+		}
+		if v.s.Service.Stop {
+			click("Stop node", &v.stopBtn)
+		}
+		if v.s.Service.Restart {
+			click("Restart node", &v.restartBtn)
+		}
+		if v.s.Service.Log {
+			click("Open node log", &v.logBtn)
+		}
+	}
+
+	if v.accounts != nil {
+		for _, a := range v.s.Watched {
+			address := a.Address
+			list = append(list, command{title: i18n.Tf("Go to account %s %s", a.Label, short(a.Address)), run: func() {
+				if v.detail != address {
+					v.showDetail(address)
+				}
+			}})
+		}
+	}
+
+	if v.explorer != nil && v.s.Round > 0 {
+		click("View last round on explorer", &v.roundBtn)
+	}
+
+	if v.exporter != nil {
+		click("Export history as CSV", &v.exportCSVBtn)
+		click("Export history as JSON", &v.exportJSONBtn)
+		click("Export weekly report", &v.reportBtn)
+	}
+
+	if v.keyreg != nil && !v.s.AccountsOnly {
+		click("Find kmd wallets", &v.walletsBtn)
+	}
+
+	if v.ctrl.DataDir() != "" {
+		click("Reload admin token", &v.reloadBtn)
+		if !v.s.AdminLocked {
+			click("Rotate admin token", &v.rotateBtn)
+		}
+	}
+
+	if v.lock.Enabled() {
+		click("Lock window", &v.lockAppBtn)
+	}
+
+	if v.look != nil {
+		if v.compactBox.Value {
+			click("Expanded layout", &v.expandBtn)
+		} else {
+			list = append(list, command{title: i18n.T("Compact layout"), run: func() { v.setCompact(true) }})
+		}
+	}
+
+	return list
+}
+
+// fuzzy matches query as a subsequence of text, ignoring case. Lower
+// scores are better: runs of adjacent letters and early starts win.
+func fuzzy(query string, text string) (int, bool) {
+	query = strings.ToLower(strings.TrimSpace(query))
+	text = strings.ToLower(text)
+
+	score, last := 0, -1
+
+	for _, r := range query {
+		if r == ' ' {
+			continue
+		}
+
+		i := strings.IndexRune(text[last+1:], r)
+		if i < 0 {
+			return 0, false
+		}
+		i += last + 1
+
+		if last >= 0 {
+			score += i - last - 1
+		} else {
+			score += i
+		}
+
+		last = i + utf8.RuneLen(r) - 1
+	}
+
+	return score, true
+}
+
+func (v *view) matches() []command {
+	type match struct {
+		command
+		score int
+	}
+
+	var found []match
+	for _, c := range v.commands() {
+		if score, ok := fuzzy(v.paletteQuery.Text(), c.title); ok {
+			found = append(found, match{c, score})
+		}
+	}
+
+	// stable insertion sort, the list is short and keeps its order on ties
+	for i := 1; i < len(found); i++ {
+		for j := i; j > 0 && found[j].score < found[j-1].score; j-- {
+			found[j], found[j-1] = found[j-1], found[j]
+		}
+	}
+
+	if len(found) > paletteRows {
+		found = found[:paletteRows]
+	}
+
+	list := make([]command, 0, len(found))
+	for _, m := range found {
+		list = append(list, m.command)
+	}
+
+	return list
+}
+
+func (v *view) openPalette(open bool) {
+	v.paletteOpen = open
+	v.paletteQuery.SetText("")
+	if open {
+		v.paletteQuery.Focus()
+	}
+}
+
+func (v *view) runCommand(c command) {
+	v.openPalette(false)
+	c.run()
+}
+
+// handlePalette runs what was picked. Like handleKeys it must come before
+// handle so that clicks it makes are seen this frame.
+func (v *view) handlePalette(gtx C) {
+	if !v.paletteOpen {
+		return
+	}
+
+	list := v.matches()
+
+	for _, e := range v.paletteQuery.Events() {
+		if _, ok := e.(widget.SubmitEvent); ok && len(list) > 0 {
+			v.runCommand(list[0])
+			return
+		}
+	}
+
+	for i := range list {
+		if v.paletteBtns[i].Clicked() {
+			v.runCommand(list[i])
+			return
+		}
+	}
+}
+
+func (v *view) layoutPalette(gtx C) D {
+	if !v.paletteOpen {
+		return D{}
+	}
+
+	children := []layout.FlexChild{
+		layout.Rigid(material.Editor(v.th, &v.paletteQuery, i18n.T("Type a command…")).Layout),
+	}
+
+	for i, c := range v.matches() {
+		i, title := i, c.title
+		children = append(children, layout.Rigid(func(gtx C) D {
+			return material.Clickable(gtx, &v.paletteBtns[i], func(gtx C) D {
+				return layout.Inset{Top: unit.Dp(4), Bottom: unit.Dp(4)}.Layout(gtx, material.Body1(v.th, title).Layout)
+			})
+		}))
+	}
+
+	in := layout.UniformInset(unit.Dp(8))
+	return in.Layout(gtx, func(gtx C) D {
+		return layout.Flex{Axis: layout.Vertical}.Layout(gtx, children...)
+	})
+}
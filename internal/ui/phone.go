@@ -0,0 +1,107 @@
+package ui
+
+import (
+	"log/slog"
+
+	"gioui.org/layout"
+	"gioui.org/unit"
+	"gioui.org/widget"
+	"gioui.org/widget/material"
+	qrcode "github.com/skip2/go-qrcode"
+
+	"voiui/internal/i18n"
+	"voiui/internal/state"
+)
+
+func (v *view) setSigning(signing state.Signing) {
+	v.send <- func(s *state.State) error {
+		s.Signing = signing
+		return nil
+	}
+}
+
+func (v *view) handlePhone() {
+	if id := v.keyregKey.Value; id != "" {
+		for online, btn := range map[bool]*widget.Clickable{true: &v.phoneOnlineBtn, false: &v.phoneOfflineBtn} {
+			if btn.Clicked() {
+				online := online
+				go v.action(func() (string, error) {
+					txn, txid, err := v.keyreg.Unsigned(id, online)
+					if err != nil {
+						return "", err
+					}
+
+					v.setSigning(state.Signing{Txn: txn, TxID: txid, Online: online})
+
+					return i18n.T("Sign the transaction with goal clerk sign or an offline signer, then paste the signed transaction"), nil
+				})
+			}
+		}
+	}
+
+	if v.phoneSubmitBtn.Clicked() && v.s.Signing.Txn != "" {
+		signed, txid := v.phoneSigned.Text(), v.s.Signing.TxID
+		go v.action(func() (string, error) {
+			note, err := v.keyreg.SubmitSigned(signed, txid)
+			if err == nil {
+				v.setSigning(state.Signing{})
+			}
+			return note, err
+		})
+	}
+
+	if v.phoneCancelBtn.Clicked() {
+		v.phoneSigned.SetText("")
+		go v.setSigning(state.Signing{})
+	}
+}
+
+// layoutPhone shows the unsigned transaction as a QR code to carry it to
+// an offline signer and takes the signed one back. Wallets do not scan it,
+// that needs a WalletConnect pairing.
+func (v *view) layoutPhone(gtx C) D {
+	txn := v.s.Signing.Txn
+	if txn == "" {
+		return D{}
+	}
+
+	if v.phoneQRFor != txn {
+		v.phoneQRFor = txn
+		v.phoneSigned.SetText("")
+
+		q, err := qrcode.New(txn, qrcode.Low)
+		if err != nil {
+			slog.Error("failed to encode QR code", "err", err)
+			v.phoneQR = nil
+		} else {
+			v.phoneQR = q.Bitmap()
+		}
+	}
+
+	title := i18n.T("Sign to go offline")
+	if v.s.Signing.Online {
+		title = i18n.T("Sign to go online")
+	}
+
+	return layout.Inset{Top: unit.Dp(8)}.Layout(gtx, func(gtx C) D {
+		return layout.Flex{Axis: layout.Vertical}.Layout(
+			gtx,
+			layout.Rigid(material.Body2(v.th, title).Layout),
+			layout.Rigid(func(gtx C) D {
+				return described(gtx, i18n.T("QR code of the unsigned transaction"), func(gtx C) D {
+					return qrCode(gtx, 280, v.phoneQR)
+				})
+			}),
+			layout.Rigid(material.Caption(v.th, i18n.Tf("Unsigned transaction %s, msgpack in base64", v.s.Signing.TxID)).Layout),
+			layout.Rigid(func(gtx C) D {
+				return layout.Inset{Top: unit.Dp(4)}.Layout(gtx, material.Editor(v.th, &v.phoneSigned, i18n.T("Signed transaction (base64)")).Layout)
+			}),
+			layout.Rigid(func(gtx C) D {
+				return layout.Flex{}.Layout(gtx,
+					v.keyregButton(&v.phoneSubmitBtn, "Submit"),
+					v.keyregButton(&v.phoneCancelBtn, "Cancel"),
+				)
+			}),
+		)
+	})
+}
@@ -0,0 +1,341 @@
+package ui
+
+import (
+	"context"
+
+	"gioui.org/app"
+	"gioui.org/font/gofont"
+	"gioui.org/io/system"
+	"gioui.org/layout"
+	"gioui.org/op"
+	"gioui.org/unit"
+	"gioui.org/widget"
+	"gioui.org/widget/material"
+
+	"voiui/internal/i18n"
+)
+
+// Choices are what the first-run setup collects.
+type Choices struct {
+	Remote bool
+	Path   string
+	Algod  string
+	Token  string
+	// Template is the hosting template of a node on this machine.
+	Template string
+
+	Sounds bool
+	// Ntfy is an ntfy topic URL, e.g. https://ntfy.sh/my-node.
+	Ntfy string
+
+	Autostart bool
+}
+
+// Template is a hosting setup the first-run setup offers to preconfigure.
+type Template struct {
+	Name        string
+	Title       string
+	Description string
+	Path        string
+}
+
+// Wizard tests and saves what the first-run setup collects.
+type Wizard interface {
+	// DataDirs are the data directories found on this machine.
+	DataDirs() []string
+	Templates() []Template
+	// Test connects to the node and tells which network it is on.
+	Test(c Choices) (string, error)
+	Save(c Choices) error
+}
+
+const (
+	stepConnect = iota
+	stepNotify
+	stepFinish
+)
+
+type setup struct {
+	th  *material.Theme
+	wiz Wizard
+
+	step int
+
+	source    widget.Enum
+	template  widget.Enum
+	templates []Template
+	path      widget.Editor
+	algod     widget.Editor
+	token     widget.Editor
+	testBtn   widget.Clickable
+	testing   bool
+	tested    string
+	testErr   string
+
+	sounds widget.Bool
+	ntfy   widget.Editor
+
+	autostart widget.Bool
+
+	backBtn widget.Clickable
+	nextBtn widget.Clickable
+	saveErr string
+
+	list widget.List
+}
+
+func (s *setup) choices() Choices {
+	return Choices{
+		Remote:    s.source.Value == "remote",
+		Path:      s.path.Text(),
+		Algod:     s.algod.Text(),
+		Token:     s.token.Text(),
+		Template:  s.template.Value,
+		Sounds:    s.sounds.Value,
+		Ntfy:      s.ntfy.Text(),
+		Autostart: s.autostart.Value,
+	}
+}
+
+func (s *setup) button(btn *widget.Clickable, text string) layout.FlexChild {
+	return layout.Rigid(func(gtx C) D {
+		return layout.Inset{Top: unit.Dp(8), Right: unit.Dp(4)}.Layout(gtx, material.Button(s.th, btn, i18n.T(text)).Layout)
+	})
+}
+
+func (s *setup) editor(e *widget.Editor, hint string) layout.FlexChild {
+	return layout.Rigid(func(gtx C) D {
+		return layout.Inset{Top: unit.Dp(4)}.Layout(gtx, material.Editor(s.th, e, i18n.T(hint)).Layout)
+	})
+}
+
+func (s *setup) connect() []layout.FlexChild {
+	children := []layout.FlexChild{
+		layout.Rigid(material.H6(s.th, i18n.T("1. Connect to your node")).Layout),
+		layout.Rigid(material.RadioButton(s.th, &s.source, "local", i18n.T("Node on this machine (data directory)")).Layout),
+		layout.Rigid(material.RadioButton(s.th, &s.source, "remote", i18n.T("Remote algod endpoint")).Layout),
+	}
+
+	if s.source.Value == "remote" {
+		children = append(children,
+			s.editor(&s.algod, "algod URL, e.g. http://192.168.1.10:8080"),
+			s.editor(&s.token, "API token"),
+		)
+	} else {
+		children = append(children, s.editor(&s.path, "Data directory"))
+
+		if len(s.templates) > 0 {
+			children = append(children,
+				layout.Rigid(material.Caption(s.th, i18n.T("Hosting template, presets the service commands and the log:")).Layout),
+				layout.Rigid(material.RadioButton(s.th, &s.template, "", i18n.T("None")).Layout),
+			)
+
+			for _, t := range s.templates {
+				children = append(children, layout.Rigid(material.RadioButton(s.th, &s.template, t.Name, t.Title+" - "+t.Description).Layout))
+			}
+		}
+	}
+
+	title := "Test connection"
+	if s.testing {
+		title = "Testing…"
+	}
+	children = append(children, layout.Rigid(func(gtx C) D {
+		return layout.Flex{}.Layout(gtx, s.button(&s.testBtn, title))
+	}))
+
+	if s.tested != "" {
+		children = append(children, layout.Rigid(func(gtx C) D {
+			l := material.Body2(s.th, s.tested)
+			l.Color = green
+			return l.Layout(gtx)
+		}))
+	}
+	if s.testErr != "" {
+		children = append(children, layout.Rigid(func(gtx C) D {
+			l := material.Body2(s.th, s.testErr)
+			l.Color = red
+			return l.Layout(gtx)
+		}))
+	}
+
+	return children
+}
+
+func (s *setup) notify() []layout.FlexChild {
+	return []layout.FlexChild{
+		layout.Rigid(material.H6(s.th, i18n.T("2. Notifications")).Layout),
+		layout.Rigid(material.Caption(s.th, i18n.T("Alerts always show in the window and the tray, pick more channels:")).Layout),
+		layout.Rigid(material.CheckBox(s.th, &s.sounds, i18n.T("Play sounds when the node goes down, stops participating or proposes")).Layout),
+		s.editor(&s.ntfy, "ntfy topic URL for phone notifications, e.g. https://ntfy.sh/my-node"),
+		layout.Rigid(material.Caption(s.th, i18n.T("Email, Gotify, MQTT and more can be set up in the config file later.")).Layout),
+	}
+}
+
+func (s *setup) finish() []layout.FlexChild {
+	c := s.choices()
+
+	node := c.Path
+	if c.Remote {
+		node = c.Algod
+	}
+
+	children := []layout.FlexChild{
+		layout.Rigid(material.H6(s.th, i18n.T("3. Finish")).Layout),
+		layout.Rigid(material.CheckBox(s.th, &s.autostart, i18n.T("Start voiui at login")).Layout),
+		layout.Rigid(material.Body2(s.th, i18n.Tf("Node: %s", node)).Layout),
+		layout.Rigid(material.Body2(s.th, s.tested).Layout),
+	}
+
+	if s.saveErr != "" {
+		children = append(children, layout.Rigid(func(gtx C) D {
+			l := material.Body2(s.th, s.saveErr)
+			l.Color = red
+			return l.Layout(gtx)
+		}))
+	}
+
+	return children
+}
+
+func (s *setup) layout(gtx C) D {
+	var children []layout.FlexChild
+
+	switch s.step {
+	case stepConnect:
+		children = s.connect()
+	case stepNotify:
+		children = s.notify()
+	default:
+		children = s.finish()
+	}
+
+	next := "Next"
+	if s.step == stepFinish {
+		next = "Save and start"
+	}
+
+	nav := []layout.FlexChild{}
+	if s.step > stepConnect {
+		nav = append(nav, s.button(&s.backBtn, "Back"))
+	}
+	if s.step > stepConnect || s.tested != "" {
+		nav = append(nav, s.button(&s.nextBtn, next))
+	}
+
+	children = append(children, layout.Rigid(func(gtx C) D {
+		return layout.Flex{}.Layout(gtx, nav...)
+	}))
+
+	return material.List(s.th, &s.list).Layout(gtx, 1, func(gtx C, _ int) D {
+		return layout.UniformInset(unit.Dp(16)).Layout(gtx, func(gtx C) D {
+			return layout.Flex{Axis: layout.Vertical}.Layout(gtx, children...)
+		})
+	})
+}
+
+// edited drains the events of e and tells whether its text changed.
+func edited(e *widget.Editor) bool {
+	changed := false
+	for _, ev := range e.Events() {
+		if _, ok := ev.(widget.ChangeEvent); ok {
+			changed = true
+		}
+	}
+	return changed
+}
+
+// RunSetup shows the first-run setup in w, it returns true once the
+// choices are saved and false when the window is closed before.
+func RunSetup(ctx context.Context, w *app.Window, wiz Wizard) (bool, error) {
+	s := &setup{
+		th:    material.NewTheme(gofont.Collection()),
+		wiz:   wiz,
+		path:  widget.Editor{SingleLine: true},
+		algod: widget.Editor{SingleLine: true},
+		token: widget.Editor{SingleLine: true, Mask: '•'},
+		ntfy:  widget.Editor{SingleLine: true},
+
+		templates: wiz.Templates(),
+	}
+	s.list.Axis = layout.Vertical
+	s.source.Value = "local"
+	s.sounds.Value = true
+
+	if dirs := wiz.DataDirs(); len(dirs) > 0 {
+		s.path.SetText(dirs[0])
+	} else {
+		s.source.Value = "remote"
+	}
+
+	results := make(chan func(), 1)
+
+	var ops op.Ops
+	for {
+		select {
+		case <-ctx.Done():
+			return false, ctx.Err()
+		case f := <-results:
+			f()
+			w.Invalidate()
+		case e := <-w.Events():
+			switch e := e.(type) {
+			case system.DestroyEvent:
+				return false, e.Err
+			case system.FrameEvent:
+				gtx := layout.NewContext(&ops, e)
+
+				if s.template.Changed() {
+					for _, t := range s.templates {
+						if t.Name == s.template.Value {
+							s.path.SetText(t.Path)
+						}
+					}
+				}
+
+				// an edit after a test means the endpoint was not tested
+				if s.source.Changed() || edited(&s.path) || edited(&s.algod) || edited(&s.token) {
+					s.tested, s.testErr = "", ""
+				}
+
+				if s.testBtn.Clicked() && !s.testing {
+					s.testing, s.tested, s.testErr = true, "", ""
+					c := s.choices()
+					go func() {
+						note, err := wiz.Test(c)
+						results <- func() {
+							s.testing = false
+							if s.choices() != c {
+								return
+							}
+							if err != nil {
+								s.testErr = err.Error()
+							} else {
+								s.tested = note
+							}
+						}
+					}()
+				}
+
+				if s.backBtn.Clicked() && s.step > stepConnect {
+					s.step--
+				}
+
+				if s.nextBtn.Clicked() {
+					if s.step < stepFinish {
+						s.step++
+					} else {
+						err := wiz.Save(s.choices())
+						if err == nil {
+							return true, nil
+						}
+						s.saveErr = err.Error()
+					}
+				}
+
+				s.layout(gtx)
+				e.Frame(gtx.Ops)
+			}
+		}
+	}
+}
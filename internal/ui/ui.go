@@ -0,0 +1,771 @@
+package ui
+
+import (
+	"context"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"gioui.org/app"
+	"gioui.org/font/gofont"
+	"gioui.org/io/system"
+	"gioui.org/layout"
+	"gioui.org/op"
+	"gioui.org/widget"
+	"gioui.org/widget/material"
+
+	"voiui/internal/i18n"
+	"voiui/internal/state"
+)
+
+type Controller interface {
+	URL() string
+	DataDir() string
+	Tokens() (string, string)
+
+	ReloadToken() (string, error)
+	RotateToken() (string, error)
+
+	SealAdmin(passphrase string) (string, error)
+	UnlockAdmin(passphrase string) (string, error)
+	LockAdmin() error
+
+	StoreTokens() (string, error)
+	ForgetTokens() (string, error)
+
+	RetryNow()
+}
+
+type Locker interface {
+	Enabled() bool
+	Verify(pin string) bool
+	SetPIN(pin string) error
+	Disable() error
+
+	OSAvailable() bool
+	OSAuthenticate(reason string) error
+}
+
+type TxnSubmitter interface {
+	Submit(path string) (string, error)
+}
+
+type Profiles interface {
+	Names() []string
+	Switch(name string) (string, error)
+}
+
+type Exporter interface {
+	Export(format string, days int) (string, error)
+}
+
+type Accounts interface {
+	Watch(addresses []string) (string, error)
+	Dismiss(addresses []string) (string, error)
+	Add(address string, label string) (string, error)
+	Remove(address string) (string, error)
+}
+
+type Explorer interface {
+	Block(round uint64) error
+	Account(address string) error
+}
+
+type Keyreg interface {
+	Export(keyID string, online bool) (string, error)
+	Submit(keyID string, online bool, wallet string, password string) (string, error)
+	Wallets() ([]string, error)
+	Unlock(wallet string, password string) ([]string, error)
+	// Unsigned returns the transaction for signing elsewhere, msgpack in
+	// base64, and its ID.
+	Unsigned(keyID string, online bool) (string, string, error)
+	// SubmitSigned submits the signed transaction if it is the one with
+	// the ID txid.
+	SubmitSigned(encoded string, txid string) (string, error)
+}
+
+type KeyBackup interface {
+	Backup(passphrase string) (string, error)
+	Restore(path string, passphrase string) (string, error)
+}
+
+type Service interface {
+	Control(action string) (string, error)
+	OpenLog() error
+}
+
+type Autostart interface {
+	Enabled() bool
+	Set(on bool) (string, error)
+}
+
+// Language is the saved language choice, empty to follow the system.
+type Language interface {
+	Get() string
+	Set(code string) (string, error)
+}
+
+// Diagnostics gives the recent log lines for bug reports, Report bundles
+// them with the sanitized config and node status.
+type Diagnostics interface {
+	Recent() []string
+	LogPath() string
+	Report() (string, error)
+}
+
+// Alerter acknowledges incidents and snoozes the notifications of an
+// alert kind.
+type Alerter interface {
+	Acknowledge(id int)
+	Snooze(kind string, until time.Time)
+}
+
+// Mailer sends a test email through the configured SMTP server.
+type Mailer interface {
+	Test() (string, error)
+}
+
+// NodeConfig reads and changes algod's config.json in the data directory.
+type NodeConfig interface {
+	Settings() ([]state.NodeSetting, error)
+	Set(name string, value string) (string, error)
+	Telemetry() (state.Telemetry, error)
+	SetTelemetry(enabled bool, name string) (string, error)
+}
+
+// DataDirs switches between the data directories found on this machine.
+type DataDirs interface {
+	Use(path string) (string, error)
+}
+
+// Appearance is the saved window scale and layout.
+type Appearance interface {
+	Scale() float32
+	Compact() bool
+	SetScale(scale float32) (string, error)
+	SetCompact(on bool) (string, error)
+}
+
+type Config struct {
+	Controller  Controller
+	Lock        Locker
+	Txns        TxnSubmitter
+	Profiles    Profiles
+	Exporter    Exporter
+	Accounts    Accounts
+	Explorer    Explorer
+	Keyreg      Keyreg
+	Backup      KeyBackup
+	Service     Service
+	Autostart   Autostart
+	Language    Language
+	Appearance  Appearance
+	Diagnostics Diagnostics
+	Alerts      Alerter
+	Email       Mailer
+	NodeConfig  NodeConfig
+	DataDirs    DataDirs
+	Driver      *Driver
+}
+
+type UI struct {
+	ctrl     Controller
+	lock     Locker
+	txns     TxnSubmitter
+	profiles Profiles
+	exporter Exporter
+	accounts Accounts
+	explorer Explorer
+	keyreg   Keyreg
+	backup   KeyBackup
+	service  Service
+	login    Autostart
+	lang     Language
+	look     Appearance
+	diag     Diagnostics
+	alerter  Alerter
+	mailer   Mailer
+	nodeCfg  NodeConfig
+	dataDirs DataDirs
+	driver   *Driver
+	store    Source
+	send     chan<- state.Update
+}
+
+// Source is where windows take the state from.
+type Source interface {
+	Snapshot() state.State
+	Subscribe() (<-chan struct{}, func())
+}
+
+// New creates the UI, it renders snapshots of store and sends its own
+// changes to send so that they pass through the same pipeline.
+func New(cfg Config, store Source, send chan<- state.Update) *UI {
+	return &UI{
+		ctrl:     cfg.Controller,
+		lock:     cfg.Lock,
+		txns:     cfg.Txns,
+		profiles: cfg.Profiles,
+		exporter: cfg.Exporter,
+		accounts: cfg.Accounts,
+		explorer: cfg.Explorer,
+		keyreg:   cfg.Keyreg,
+		backup:   cfg.Backup,
+		service:  cfg.Service,
+		login:    cfg.Autostart,
+		lang:     cfg.Language,
+		look:     cfg.Appearance,
+		diag:     cfg.Diagnostics,
+		alerter:  cfg.Alerts,
+		mailer:   cfg.Email,
+		nodeCfg:  cfg.NodeConfig,
+		dataDirs: cfg.DataDirs,
+		driver:   cfg.Driver,
+		store:    store,
+		send:     send,
+	}
+}
+
+type view struct {
+	*UI
+
+	th *material.Theme
+
+	// s is this window's snapshot of the state.
+	s state.State
+
+	reloadBtn widget.Clickable
+	rotateBtn widget.Clickable
+	storeBtn  widget.Clickable
+	forgetBtn widget.Clickable
+	retryBtn  widget.Clickable
+	unlockBtn widget.Clickable
+	lockBtn   widget.Clickable
+
+	passphrase widget.Editor
+
+	pin        widget.Editor
+	pinBtn     widget.Clickable
+	osAuthBtn  widget.Clickable
+	setPinBtn  widget.Clickable
+	noPinBtn   widget.Clickable
+	lockNote   string
+	newPin     widget.Editor
+	lockAppBtn widget.Clickable
+	// busy is set while a PIN is hashed or the OS authenticates, both run
+	// off the frame goroutine.
+	busy bool
+
+	// done runs the results of background work on the frame goroutine.
+	done chan func(v *view)
+
+	submitBtns map[string]*widget.Clickable
+
+	profile     widget.Enum
+	dataDir     widget.Enum
+	dataDirSeen string
+	profileSeen string
+
+	exportRange   widget.Enum
+	exportCSVBtn  widget.Clickable
+	reportBtn     widget.Clickable
+	exportJSONBtn widget.Clickable
+
+	watchBtn   widget.Clickable
+	dismissBtn widget.Clickable
+
+	watchAddress widget.Editor
+	watchLabel   widget.Editor
+	watchAddBtn  widget.Clickable
+	removeBtns   map[string]*widget.Clickable
+
+	detailBtns map[string]*widget.Clickable
+	detail     string
+	detailQR   [][]bool
+	detailNote string
+	copyBtn    widget.Clickable
+	closeBtn   widget.Clickable
+
+	roundBtn     widget.Clickable
+	accountBtn   widget.Clickable
+	proposalBtns map[string]*widget.Clickable
+
+	keyregKey        widget.Enum
+	keyregWallet     widget.Enum
+	keyregPassword   widget.Editor
+	walletsBtn       widget.Clickable
+	unlockWalletBtn  widget.Clickable
+	lockWalletBtn    widget.Clickable
+	exportOnlineBtn  widget.Clickable
+	exportOfflineBtn widget.Clickable
+	signOnlineBtn    widget.Clickable
+	signOfflineBtn   widget.Clickable
+
+	phoneOnlineBtn  widget.Clickable
+	phoneOfflineBtn widget.Clickable
+	phoneSigned     widget.Editor
+	phoneSubmitBtn  widget.Clickable
+	phoneCancelBtn  widget.Clickable
+	phoneQR         [][]bool
+	phoneQRFor      string
+
+	backupPassphrase widget.Editor
+	backupBtn        widget.Clickable
+	restorePath      widget.Editor
+	restoreBtn       widget.Clickable
+
+	startBtn   widget.Clickable
+	stopBtn    widget.Clickable
+	restartBtn widget.Clickable
+	logBtn     widget.Clickable
+
+	paletteOpen  bool
+	paletteQuery widget.Editor
+	paletteBtns  [paletteRows]widget.Clickable
+
+	loginBox widget.Bool
+	langOpt  widget.Enum
+
+	win        *app.Window
+	stage      system.Stage
+	scaleOpt   widget.Enum
+	compactBox widget.Bool
+	expandBtn  widget.Clickable
+
+	diagOpen      bool
+	diagBtn       widget.Clickable
+	diagCopyBtn   widget.Clickable
+	diagReportBtn widget.Clickable
+	diagNote      string
+
+	alertsOpen bool
+	alertsBtn  widget.Clickable
+	alertBtns  map[int]*alertButtons
+
+	testMailBtn widget.Clickable
+
+	nodeCfgOpen       bool
+	nodeCfgBtn        widget.Clickable
+	nodeCfgName       widget.Editor
+	nodeCfgValue      widget.Editor
+	nodeCfgSaveBtn    widget.Clickable
+	nodeCfgRestartBtn widget.Clickable
+	nodeCfgNote       string
+	nodeCfgSaved      bool
+	nodeSettings      []state.NodeSetting
+	nodeCfgErr        error
+
+	telemetry        state.Telemetry
+	telemetryBox     widget.Bool
+	telemetryName    widget.Editor
+	telemetrySaveBtn widget.Clickable
+}
+
+func (u *UI) action(action func() (string, error)) {
+	note, err := action()
+	if err != nil {
+		slog.Error("action failed", "err", err)
+		note = err.Error()
+	}
+
+	u.send <- func(s *state.State) error {
+		s.TokenNote = note
+		if err == nil {
+			s.Unauthorized = false
+		}
+		return nil
+	}
+}
+
+func (u *UI) Run(ctx context.Context, w *app.Window) error {
+	v := &view{
+		UI:            u,
+		th:            material.NewTheme(gofont.Collection()),
+		passphrase:    widget.Editor{SingleLine: true, Submit: true, Mask: '•'},
+		pin:           widget.Editor{SingleLine: true, Submit: true, Mask: '•'},
+		newPin:        widget.Editor{SingleLine: true, Mask: '•'},
+		nodeCfgName:   widget.Editor{SingleLine: true},
+		nodeCfgValue:  widget.Editor{SingleLine: true},
+		telemetryName: widget.Editor{SingleLine: true},
+		submitBtns:    map[string]*widget.Clickable{},
+		removeBtns:    map[string]*widget.Clickable{},
+		detailBtns:    map[string]*widget.Clickable{},
+
+		proposalBtns: map[string]*widget.Clickable{},
+
+		done: make(chan func(v *view), 1),
+	}
+
+	v.watchAddress.SingleLine = true
+	v.watchLabel.SingleLine = true
+	v.keyregPassword = widget.Editor{SingleLine: true, Mask: '•'}
+	v.phoneSigned.SingleLine = true
+	v.backupPassphrase = widget.Editor{SingleLine: true, Mask: '•'}
+	v.restorePath.SingleLine = true
+	v.paletteQuery = widget.Editor{SingleLine: true, Submit: true}
+
+	v.exportRange.Value = "7"
+
+	if u.login != nil {
+		v.loginBox.Value = u.login.Enabled()
+	}
+
+	v.win = w
+	v.stage = system.StageRunning
+	v.scaleOpt.Value = "1"
+
+	if u.look != nil {
+		v.scaleOpt.Value = strconv.FormatFloat(float64(u.look.Scale()), 'g', -1, 32)
+		v.compactBox.Value = u.look.Compact()
+		v.resize()
+	}
+
+	if u.lang != nil {
+		v.langOpt.Value = u.lang.Get()
+		if v.langOpt.Value == "" {
+			v.langOpt.Value = systemLanguage
+		}
+	}
+
+	changed, unsubscribe := u.store.Subscribe()
+	defer unsubscribe()
+
+	v.s = u.store.Snapshot()
+
+	// opening the window asks for the PIN again
+	if u.lock.Enabled() {
+		v.setLocked(true)
+	}
+
+	var cmds chan func(v *view)
+	if u.driver != nil {
+		cmds = u.driver.cmds
+	}
+
+	var ops op.Ops
+	for {
+		select {
+		case <-ctx.Done():
+			slog.Debug("window context done")
+			return ctx.Err()
+		case f := <-cmds:
+			f(v)
+			w.Invalidate()
+		case f := <-v.done:
+			f(v)
+			w.Invalidate()
+		case <-changed:
+			s := u.store.Snapshot()
+			if s.Version == v.s.Version {
+				continue
+			}
+			v.s = s
+			w.Invalidate()
+		case e := <-w.Events():
+			switch e := e.(type) {
+			case system.StageEvent:
+				v.stage = e.Stage
+			case system.DestroyEvent:
+				return e.Err
+			case system.FrameEvent:
+				gtx := layout.NewContext(&ops, e)
+
+				scale := v.scale()
+				gtx.Metric.PxPerDp *= scale
+				gtx.Metric.PxPerSp *= scale
+
+				if v.s.Locked {
+					v.handleLock()
+					v.layoutLock(gtx)
+				} else {
+					if v.handleKeys(gtx) {
+						w.Perform(system.ActionClose)
+					}
+					v.handlePalette(gtx)
+					v.handle()
+					if v.compactBox.Value {
+						v.layoutCompact(gtx)
+					} else {
+						v.layout(gtx)
+					}
+				}
+
+				e.Frame(gtx.Ops)
+			}
+		}
+	}
+}
+
+func (v *view) handle() {
+	if v.reloadBtn.Clicked() {
+		go v.action(v.ctrl.ReloadToken)
+	}
+
+	if v.rotateBtn.Clicked() {
+		go v.action(v.ctrl.RotateToken)
+	}
+
+	if v.storeBtn.Clicked() {
+		go v.action(v.ctrl.StoreTokens)
+	}
+
+	if v.forgetBtn.Clicked() {
+		go v.action(v.ctrl.ForgetTokens)
+	}
+
+	if v.retryBtn.Clicked() {
+		v.ctrl.RetryNow()
+	}
+
+	for action, btn := range map[string]*widget.Clickable{"start": &v.startBtn, "stop": &v.stopBtn, "restart": &v.restartBtn} {
+		if btn.Clicked() {
+			action := action
+			go v.action(func() (string, error) { return v.service.Control(action) })
+		}
+	}
+
+	if v.logBtn.Clicked() {
+		go v.action(func() (string, error) { return "", v.service.OpenLog() })
+	}
+
+	for format, btn := range map[string]*widget.Clickable{"csv": &v.exportCSVBtn, "json": &v.exportJSONBtn, "report": &v.reportBtn} {
+		if btn.Clicked() {
+			format := format
+			days, _ := strconv.Atoi(v.exportRange.Value)
+			go v.action(func() (string, error) { return v.exporter.Export(format, days) })
+		}
+	}
+
+	if found := v.discovered(); len(found) > 0 {
+		if v.watchBtn.Clicked() {
+			go v.action(func() (string, error) { return v.accounts.Watch(found) })
+		}
+
+		if v.dismissBtn.Clicked() {
+			go v.action(func() (string, error) { return v.accounts.Dismiss(found) })
+		}
+	}
+
+	if v.watchAddBtn.Clicked() {
+		address, label := v.watchAddress.Text(), v.watchLabel.Text()
+		v.watchAddress.SetText("")
+		v.watchLabel.SetText("")
+		go v.action(func() (string, error) { return v.accounts.Add(address, label) })
+	}
+
+	for address, btn := range v.removeBtns {
+		if btn.Clicked() {
+			address := address
+			go v.action(func() (string, error) { return v.accounts.Remove(address) })
+		}
+	}
+
+	for address, btn := range v.detailBtns {
+		if btn.Clicked() {
+			v.showDetail(address)
+		}
+	}
+
+	if v.closeBtn.Clicked() {
+		v.showDetail("")
+	}
+
+	if v.roundBtn.Clicked() {
+		round := v.s.Round
+		go v.action(func() (string, error) { return "", v.explorer.Block(round) })
+	}
+
+	if v.accountBtn.Clicked() {
+		address := v.detail
+		go v.action(func() (string, error) { return "", v.explorer.Account(address) })
+	}
+
+	for _, k := range v.s.Keys {
+		if btn, ok := v.proposalBtns[k.ID]; ok && btn.Clicked() {
+			round := k.LastProposal
+			go v.action(func() (string, error) { return "", v.explorer.Block(round) })
+		}
+	}
+
+	if id := v.keyregKey.Value; id != "" {
+		for online, btn := range map[bool]*widget.Clickable{true: &v.exportOnlineBtn, false: &v.exportOfflineBtn} {
+			if btn.Clicked() {
+				online := online
+				go v.action(func() (string, error) { return v.keyreg.Export(id, online) })
+			}
+		}
+
+		for online, btn := range map[bool]*widget.Clickable{true: &v.signOnlineBtn, false: &v.signOfflineBtn} {
+			if btn.Clicked() && v.s.KMD.Unlocked != "" {
+				online := online
+				wallet, password := v.s.KMD.Unlocked, v.keyregPassword.Text()
+				go v.action(func() (string, error) { return v.keyreg.Submit(id, online, wallet, password) })
+			}
+		}
+	}
+
+	v.handleWallet()
+	v.handlePhone()
+
+	if v.backupBtn.Clicked() {
+		passphrase := v.backupPassphrase.Text()
+		go v.action(func() (string, error) { return v.backup.Backup(passphrase) })
+	}
+
+	if v.restoreBtn.Clicked() {
+		path, passphrase := v.restorePath.Text(), v.backupPassphrase.Text()
+		go v.action(func() (string, error) { return v.backup.Restore(path, passphrase) })
+	}
+
+	if v.dataDir.Changed() {
+		path := v.dataDir.Value
+		go v.action(func() (string, error) { return v.dataDirs.Use(path) })
+	} else if v.dataDirSeen != v.s.DataDir {
+		v.dataDir.Value = v.s.DataDir
+		v.dataDirSeen = v.s.DataDir
+	}
+
+	if v.profile.Changed() {
+		name := v.profile.Value
+		go v.action(func() (string, error) { return v.profiles.Switch(name) })
+	} else if v.profileSeen != v.s.Profile {
+		v.profile.Value = v.s.Profile
+		v.profileSeen = v.s.Profile
+	}
+
+	submitted := false
+	for _, e := range v.passphrase.Events() {
+		if _, ok := e.(widget.SubmitEvent); ok {
+			submitted = true
+		}
+	}
+
+	if v.unlockBtn.Clicked() || submitted {
+		pass := v.passphrase.Text()
+		v.passphrase.SetText("")
+
+		if v.s.AdminSealed {
+			go v.action(func() (string, error) { return v.ctrl.UnlockAdmin(pass) })
+		} else {
+			go v.action(func() (string, error) { return v.ctrl.SealAdmin(pass) })
+		}
+	}
+
+	if v.lockBtn.Clicked() {
+		go v.action(func() (string, error) { return i18n.T("Admin token locked"), v.ctrl.LockAdmin() })
+	}
+
+	if v.loginBox.Changed() {
+		on := v.loginBox.Value
+		go v.action(func() (string, error) { return v.login.Set(on) })
+	}
+
+	if v.langOpt.Changed() {
+		v.setLanguage(v.langOpt.Value)
+	}
+
+	if v.scaleOpt.Changed() {
+		v.setScale(v.scaleOpt.Value)
+	}
+
+	if v.compactBox.Changed() {
+		v.setCompact(v.compactBox.Value)
+	}
+
+	if v.expandBtn.Clicked() {
+		v.setCompact(false)
+	}
+
+	if v.setPinBtn.Clicked() && !v.busy {
+		pin := v.newPin.Text()
+		v.newPin.SetText("")
+		v.busy = true
+
+		go func() {
+			err := v.lock.SetPIN(pin)
+			v.done <- func(v *view) {
+				v.busy = false
+				if err != nil {
+					v.lockNote = err.Error()
+				} else {
+					v.lockNote = i18n.T("App lock enabled")
+				}
+			}
+		}()
+	}
+
+	if v.noPinBtn.Clicked() {
+		err := v.lock.Disable()
+		if err != nil {
+			v.lockNote = err.Error()
+		} else {
+			v.lockNote = i18n.T("App lock disabled")
+		}
+	}
+
+	if v.lockAppBtn.Clicked() {
+		v.setLocked(true)
+	}
+
+	for path, btn := range v.submitBtns {
+		if btn.Clicked() {
+			path := path
+			go v.action(func() (string, error) { return v.txns.Submit(path) })
+		}
+	}
+}
+
+// setLocked shows the change right away and records it for other windows.
+func (v *view) setLocked(locked bool) {
+	v.s.Locked = locked
+	v.send <- func(s *state.State) error {
+		s.Locked = locked
+		return nil
+	}
+}
+
+func (v *view) handleLock() {
+	submitted := false
+	for _, e := range v.pin.Events() {
+		if _, ok := e.(widget.SubmitEvent); ok {
+			submitted = true
+		}
+	}
+
+	if (v.pinBtn.Clicked() || submitted) && !v.busy {
+		pin := v.pin.Text()
+		v.pin.SetText("")
+		v.busy = true
+
+		go func() {
+			ok := v.lock.Verify(pin)
+			v.done <- func(v *view) {
+				v.busy = false
+				if ok {
+					v.setLocked(false)
+					v.lockNote = ""
+				} else {
+					v.lockNote = i18n.T("Wrong PIN")
+				}
+			}
+		}()
+	}
+
+	if v.osAuthBtn.Clicked() && !v.busy {
+		v.busy = true
+
+		go func() {
+			err := v.lock.OSAuthenticate(i18n.T("Unlock Voi Node Monitor"))
+			v.done <- func(v *view) {
+				v.busy = false
+				if err != nil {
+					slog.Error("os authentication failed", "err", err)
+					v.lockNote = err.Error()
+					return
+				}
+				v.setLocked(false)
+				v.lockNote = ""
+			}
+		}()
+	}
+}
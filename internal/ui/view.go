@@ -0,0 +1,1660 @@
+package ui
+
+import (
+	"fmt"
+	"image/color"
+	"strings"
+	"time"
+
+	"gioui.org/layout"
+	"gioui.org/unit"
+	"gioui.org/widget"
+	"gioui.org/widget/material"
+
+	"voiui/internal/i18n"
+	"voiui/internal/state"
+)
+
+type (
+	C = layout.Context
+	D = layout.Dimensions
+)
+
+var (
+	green  = color.NRGBA{R: 0x00, G: 0xaa, B: 0x00, A: 0xff}
+	red    = color.NRGBA{R: 0xaa, G: 0x00, B: 0x00, A: 0xff}
+	orange = color.NRGBA{R: 0xaa, G: 0x66, B: 0x00, A: 0xff}
+	blue   = color.NRGBA{R: 0x00, G: 0x44, B: 0xaa, A: 0xff}
+	purple = color.NRGBA{R: 0x66, G: 0x00, B: 0xaa, A: 0xff}
+)
+
+func (v *view) field(gtx C, caption string, value string) D {
+	in := layout.UniformInset(unit.Dp(8))
+	return in.Layout(gtx, func(gtx C) D {
+		return layout.Flex{Axis: layout.Vertical}.Layout(
+			gtx,
+			layout.Rigid(material.Caption(v.th, caption).Layout),
+			layout.Rigid(material.Body1(v.th, value).Layout),
+		)
+	})
+}
+
+func (v *view) status(gtx C, ok bool, text string) D {
+	in := layout.UniformInset(unit.Dp(8))
+
+	title := material.Subtitle1(v.th, text)
+	if ok {
+		title.Color = green
+	} else {
+		title.Color = red
+	}
+
+	return in.Layout(gtx, title.Layout)
+}
+
+func (v *view) layout(gtx C) D {
+	return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+		layout.Rigid(v.layoutPalette),
+		layout.Rigid(v.layoutProfiles),
+		layout.Rigid(v.layoutDataDirs),
+		layout.Rigid(v.layoutDiscovered),
+		layout.Rigid(func(gtx C) D {
+			return v.field(gtx, i18n.T("Address:"), v.ctrl.URL())
+		}),
+		layout.Rigid(v.layoutNetwork),
+		layout.Rigid(v.layoutConsensus),
+		layout.Rigid(func(gtx C) D {
+			if v.s.Running {
+				return v.status(gtx, true, i18n.T("Running"))
+			}
+			return v.status(gtx, false, i18n.T("Not Running"))
+		}),
+		layout.Rigid(v.layoutRetry),
+		layout.Rigid(v.layoutService),
+		layout.Rigid(func(gtx C) D {
+			if v.explorer == nil || v.s.Round == 0 {
+				return v.field(gtx, i18n.T("Last round:"), fmt.Sprintf("%d", v.s.Round))
+			}
+
+			return layout.Flex{Alignment: layout.Middle}.Layout(
+				gtx,
+				layout.Rigid(func(gtx C) D {
+					return v.field(gtx, i18n.T("Last round:"), fmt.Sprintf("%d", v.s.Round))
+				}),
+				layout.Rigid(material.Button(v.th, &v.roundBtn, i18n.T("View on explorer")).Layout),
+			)
+		}),
+		layout.Rigid(v.layoutRTT),
+		layout.Rigid(v.layoutActivity),
+		layout.Rigid(v.layoutParticipation),
+		layout.Rigid(v.layoutProgress),
+		layout.Rigid(v.layoutETA),
+		layout.Rigid(v.layoutAlerts),
+		layout.Rigid(v.layoutAlertHistory),
+		layout.Rigid(v.layoutUPS),
+		layout.Rigid(v.layoutPanels),
+		layout.Rigid(v.layoutPlugins),
+		layout.Rigid(v.layoutResources),
+		layout.Rigid(v.layoutHardware),
+		layout.Rigid(v.layoutClock),
+		layout.Rigid(v.layoutPort),
+		layout.Rigid(v.layoutRelay),
+		layout.Rigid(v.layoutIndexer),
+		layout.Rigid(v.layoutFollower),
+		layout.Rigid(v.layoutConnectivity),
+		layout.Rigid(v.layoutEvents),
+		layout.Rigid(v.layoutWatchList),
+		layout.Rigid(v.layoutKeys),
+		layout.Rigid(v.layoutKeyreg),
+		layout.Rigid(v.layoutBackup),
+		layout.Rigid(v.layoutPerformance),
+		layout.Rigid(v.layoutRewards),
+		layout.Rigid(v.layoutUptime),
+		layout.Rigid(v.layoutHistory),
+		layout.Rigid(v.layoutWeekly),
+		layout.Rigid(v.layoutExport),
+		layout.Rigid(v.layoutIncidents),
+		layout.Rigid(v.layoutSignedTxns),
+		layout.Rigid(v.layoutPending),
+		layout.Rigid(v.layoutTokens),
+		layout.Rigid(v.layoutElevation),
+		layout.Rigid(v.layoutKeychain),
+		layout.Rigid(v.layoutLogin),
+		layout.Rigid(v.layoutLanguage),
+		layout.Rigid(v.layoutAppearance),
+		layout.Rigid(v.layoutAppLock),
+		layout.Rigid(v.layoutNodeConfig),
+		layout.Rigid(v.layoutEmail),
+		layout.Rigid(v.layoutDiagnostics),
+		layout.Rigid(func(gtx C) D {
+			if v.s.StagedUpdate != "" {
+				return layout.UniformInset(unit.Dp(8)).Layout(gtx, material.Caption(v.th, i18n.Tf("voiui %s will be installed on the next start", v.s.StagedUpdate)).Layout)
+			}
+
+			if v.s.AvailableUpdate != "" {
+				return layout.UniformInset(unit.Dp(8)).Layout(gtx, material.Caption(v.th, i18n.Tf("voiui %s is available", v.s.AvailableUpdate)).Layout)
+			}
+
+			return D{}
+		}),
+	)
+}
+
+func (v *view) layoutLock(gtx C) D {
+	in := layout.UniformInset(unit.Dp(8))
+	return in.Layout(gtx, func(gtx C) D {
+		return layout.Flex{Axis: layout.Vertical}.Layout(
+			gtx,
+			layout.Rigid(material.Subtitle1(v.th, i18n.T("Locked")).Layout),
+			layout.Rigid(func(gtx C) D {
+				return layout.Inset{Top: unit.Dp(8)}.Layout(gtx, func(gtx C) D {
+					return layout.Flex{Alignment: layout.Middle}.Layout(
+						gtx,
+						layout.Flexed(1, func(gtx C) D {
+							return layout.Inset{Right: unit.Dp(8)}.Layout(gtx, material.Editor(v.th, &v.pin, i18n.T("PIN")).Layout)
+						}),
+						layout.Rigid(material.Button(v.th, &v.pinBtn, i18n.T("Unlock")).Layout),
+					)
+				})
+			}),
+			layout.Rigid(func(gtx C) D {
+				if !v.lock.OSAvailable() {
+					return D{}
+				}
+
+				return layout.Inset{Top: unit.Dp(8)}.Layout(gtx, material.Button(v.th, &v.osAuthBtn, i18n.T("Use system authentication")).Layout)
+			}),
+			layout.Rigid(func(gtx C) D {
+				if v.lockNote == "" {
+					return D{}
+				}
+
+				title := material.Caption(v.th, v.lockNote)
+				title.Color = red
+				return layout.Inset{Top: unit.Dp(8)}.Layout(gtx, title.Layout)
+			}),
+		)
+	})
+}
+
+func (v *view) layoutLogin(gtx C) D {
+	if v.login == nil {
+		return D{}
+	}
+
+	return layout.UniformInset(unit.Dp(8)).Layout(gtx, material.CheckBox(v.th, &v.loginBox, i18n.T("Start voiui at login")).Layout)
+}
+
+// systemLanguage is the option for following the system language.
+const systemLanguage = "system"
+
+func (v *view) setLanguage(code string) {
+	if code == systemLanguage {
+		code = ""
+	}
+	go v.action(func() (string, error) { return v.lang.Set(code) })
+}
+
+func (v *view) layoutLanguage(gtx C) D {
+	if v.lang == nil {
+		return D{}
+	}
+
+	children := []layout.FlexChild{
+		layout.Rigid(material.Caption(v.th, i18n.T("Language:")).Layout),
+		layout.Rigid(material.RadioButton(v.th, &v.langOpt, systemLanguage, i18n.T("System")).Layout),
+	}
+	for _, l := range i18n.Languages() {
+		children = append(children, layout.Rigid(material.RadioButton(v.th, &v.langOpt, l.Code, l.Name).Layout))
+	}
+
+	in := layout.UniformInset(unit.Dp(8))
+	return in.Layout(gtx, func(gtx C) D {
+		return layout.Flex{Alignment: layout.Middle}.Layout(gtx, children...)
+	})
+}
+
+func (v *view) layoutAppLock(gtx C) D {
+	in := layout.UniformInset(unit.Dp(8))
+	return in.Layout(gtx, func(gtx C) D {
+		label := i18n.T("App lock is off, set a PIN to enable it:")
+		if v.lock.Enabled() {
+			label = i18n.T("App lock is on, set a new PIN or disable it:")
+		}
+
+		return layout.Flex{Axis: layout.Vertical}.Layout(
+			gtx,
+			layout.Rigid(material.Caption(v.th, label).Layout),
+			layout.Rigid(func(gtx C) D {
+				return layout.Flex{Alignment: layout.Middle}.Layout(
+					gtx,
+					layout.Flexed(1, func(gtx C) D {
+						return layout.Inset{Right: unit.Dp(8)}.Layout(gtx, material.Editor(v.th, &v.newPin, i18n.T("New PIN")).Layout)
+					}),
+					layout.Rigid(material.Button(v.th, &v.setPinBtn, i18n.T("Set PIN")).Layout),
+				)
+			}),
+			layout.Rigid(func(gtx C) D {
+				if !v.lock.Enabled() {
+					return D{}
+				}
+
+				return layout.Flex{}.Layout(
+					gtx,
+					layout.Rigid(func(gtx C) D {
+						return layout.Inset{Top: unit.Dp(4), Right: unit.Dp(4)}.Layout(gtx, material.Button(v.th, &v.lockAppBtn, i18n.T("Lock now")).Layout)
+					}),
+					layout.Rigid(func(gtx C) D {
+						return layout.Inset{Top: unit.Dp(4)}.Layout(gtx, material.Button(v.th, &v.noPinBtn, i18n.T("Disable")).Layout)
+					}),
+				)
+			}),
+			layout.Rigid(func(gtx C) D {
+				if v.lockNote == "" {
+					return D{}
+				}
+
+				return material.Caption(v.th, v.lockNote).Layout(gtx)
+			}),
+		)
+	})
+}
+
+func (v *view) layoutParticipation(gtx C) D {
+	switch {
+	case v.s.AccountsOnly:
+		return v.field(gtx, i18n.T("Mode:"), i18n.T("Watching accounts through a public endpoint"))
+	case v.s.Participation == state.ParticipationActive:
+		return v.status(gtx, true, i18n.T("Participating"))
+	case v.s.Participation == state.ParticipationOffline:
+		return v.status(gtx, false, i18n.T("Key active, account offline"))
+	case v.s.AdminLocked:
+		return v.status(gtx, false, i18n.T("Participation unknown (admin locked)"))
+	default:
+		return v.status(gtx, false, i18n.T("Not participating"))
+	}
+}
+
+func (v *view) layoutProgress(gtx C) D {
+	animate(gtx, v.s, v.stage)
+
+	left := progress(v.s, gtx.Now)
+	bar := material.ProgressBar(v.th, left)
+	if left < 0 {
+		left = 0
+	}
+	return described(gtx, i18n.Tf("Next block expected, %.0f%% of the time left", left*100), bar.Layout)
+}
+
+// layoutETA estimates the next block from the average block time and
+// warns once the wait is more than twice as long.
+func (v *view) layoutETA(gtx C) D {
+	avg := v.s.AvgBlockDuration
+	if avg == 0 || v.s.CurrBlockAt.IsZero() {
+		return D{}
+	}
+
+	wait := gtx.Now.Sub(v.s.CurrBlockAt)
+
+	var text string
+	switch {
+	case wait > 2*avg:
+		text = i18n.Tf("Waiting %s for the next block, more than twice the average", wait.Round(100*time.Millisecond))
+	case wait > avg:
+		text = i18n.Tf("Next block overdue by %s", (wait - avg).Round(100*time.Millisecond))
+	default:
+		text = i18n.Tf("Next block in about %s", (avg - wait).Round(100*time.Millisecond))
+	}
+
+	value := material.Body2(v.th, text)
+	if wait > 2*avg {
+		value.Color = red
+	}
+
+	in := layout.Inset{Left: unit.Dp(8), Right: unit.Dp(8), Top: unit.Dp(4)}
+	return in.Layout(gtx, func(gtx C) D {
+		return layout.Flex{Axis: layout.Vertical}.Layout(
+			gtx,
+			layout.Rigid(material.Caption(v.th, i18n.Tf("Average block time %s", avg.Round(10*time.Millisecond))).Layout),
+			layout.Rigid(value.Layout),
+		)
+	})
+}
+
+// pollErrors say what an error kind means for the operator.
+var pollErrors = map[state.ErrorKind]string{
+	state.ErrorUnauthorized: "Token rejected, check the algod token",
+	state.ErrorRefused:      "Connection refused, algod is not listening at the address",
+	state.ErrorTimeout:      "Timed out, the node or the network is too slow",
+	state.ErrorDNS:          "The algod host name does not resolve",
+	state.ErrorTLS:          "The algod certificate is not trusted",
+	state.ErrorOther:        "Cannot reach algod",
+}
+
+func (v *view) layoutActivity(gtx C) D {
+	a := v.s.Activity
+	if a.TxnsPerBlock == 0 && a.LastTxns == 0 {
+		return D{}
+	}
+
+	return v.field(gtx, i18n.T("Network activity:"), i18n.Tf("%.2f TPS, %.1f txns/block, %d in the last block", a.TPS, a.TxnsPerBlock, a.LastTxns))
+}
+
+func (v *view) layoutRTT(gtx C) D {
+	if v.s.RTT == 0 {
+		return D{}
+	}
+
+	value := material.Body1(v.th, v.s.RTT.Round(time.Millisecond).String())
+	if v.s.RTTSlow {
+		value.Color = red
+	}
+
+	in := layout.UniformInset(unit.Dp(8))
+	return in.Layout(gtx, func(gtx C) D {
+		return layout.Flex{Axis: layout.Vertical}.Layout(
+			gtx,
+			layout.Rigid(material.Caption(v.th, i18n.T("algod RTT:")).Layout),
+			layout.Rigid(value.Layout),
+		)
+	})
+}
+
+// layoutRetry is the banner shown while the node cannot be polled, with
+// the last error and when the next attempt is.
+func (v *view) layoutRetry(gtx C) D {
+	pe := v.s.PollError
+	if v.s.Running || (pe.At.IsZero() && v.s.RetryAt.IsZero() && !v.s.RetryStopped) {
+		return D{}
+	}
+
+	var children []layout.FlexChild
+
+	if !pe.At.IsZero() {
+		title := material.Body1(v.th, i18n.T(pollErrors[pe.Kind]))
+		title.Color = red
+		children = append(children,
+			layout.Rigid(title.Layout),
+			layout.Rigid(material.Caption(v.th, i18n.Tf("%s at %s", pe.Message, pe.At.Format("15:04:05"))).Layout),
+		)
+	}
+
+	var text string
+	if v.s.RetryStopped {
+		text = i18n.T("Gave up reconnecting")
+	} else if !v.s.RetryAt.IsZero() {
+		left := time.Until(v.s.RetryAt).Round(time.Second)
+		if left < 0 {
+			left = 0
+		}
+
+		text = i18n.Tf("Retrying in %s…", left)
+		if v.s.RetryMax > 0 {
+			text += fmt.Sprintf(" (%d/%d)", v.s.RetryAttempt, v.s.RetryMax)
+		} else {
+			text += i18n.Tf(" (attempt %d)", v.s.RetryAttempt)
+		}
+	}
+
+	children = append(children, layout.Rigid(func(gtx C) D {
+		return layout.Flex{Alignment: layout.Middle}.Layout(
+			gtx,
+			layout.Rigid(func(gtx C) D {
+				return layout.Inset{Right: unit.Dp(8)}.Layout(gtx, material.Caption(v.th, text).Layout)
+			}),
+			layout.Rigid(material.Button(v.th, &v.retryBtn, i18n.T("Retry now")).Layout),
+		)
+	}))
+
+	in := layout.Inset{Left: unit.Dp(8), Right: unit.Dp(8)}
+	return in.Layout(gtx, func(gtx C) D {
+		return layout.Flex{Axis: layout.Vertical}.Layout(gtx, children...)
+	})
+}
+
+// layoutService offers the service commands of the active profile.
+func (v *view) layoutService(gtx C) D {
+	if v.service == nil {
+		return D{}
+	}
+
+	var buttons []layout.FlexChild
+	add := func(ok bool, btn *widget.Clickable, text string) {
+		if ok {
+			buttons = append(buttons, v.keyregButton(btn, text))
+		}
+	}
+
+	add(v.s.Service.Start && !v.s.Running, &v.startBtn, "Start node")
+	add(v.s.Service.Stop && v.s.Running, &v.stopBtn, "Stop node")
+	add(v.s.Service.Restart, &v.restartBtn, "Restart node")
+	add(v.s.Service.Log, &v.logBtn, "Open log")
+
+	if len(buttons) == 0 {
+		return D{}
+	}
+
+	in := layout.Inset{Left: unit.Dp(8), Right: unit.Dp(8)}
+	return in.Layout(gtx, func(gtx C) D {
+		return layout.Flex{}.Layout(gtx, buttons...)
+	})
+}
+
+func (v *view) layoutTokens(gtx C) D {
+	return layout.Flex{Axis: layout.Vertical}.Layout(
+		gtx,
+		layout.Rigid(func(gtx C) D {
+			if apiToken, _ := v.ctrl.Tokens(); apiToken != "" || v.s.AccountsOnly {
+				return D{}
+			}
+
+			in := layout.Inset{Left: unit.Dp(8), Right: unit.Dp(8)}
+			return in.Layout(gtx, material.Caption(v.th, i18n.T("Polling with the admin token, configure a non-admin token (algod.token / -api-token) to limit its exposure")).Layout)
+		}),
+		layout.Rigid(func(gtx C) D {
+			if !v.s.Unauthorized && v.s.TokenNote == "" {
+				return D{}
+			}
+
+			in := layout.UniformInset(unit.Dp(8))
+			return in.Layout(gtx, func(gtx C) D {
+				return layout.Flex{Axis: layout.Vertical}.Layout(
+					gtx,
+					layout.Rigid(func(gtx C) D {
+						text := v.s.TokenNote
+						if v.s.Unauthorized {
+							text = i18n.T("Token rejected (401), it may have been regenerated")
+						}
+
+						title := material.Caption(v.th, text)
+						if v.s.Unauthorized {
+							title.Color = red
+						}
+						return title.Layout(gtx)
+					}),
+					layout.Rigid(func(gtx C) D {
+						if v.ctrl.DataDir() == "" {
+							return D{}
+						}
+
+						return layout.Flex{}.Layout(
+							gtx,
+							layout.Rigid(func(gtx C) D {
+								return layout.Inset{Top: unit.Dp(4), Right: unit.Dp(4)}.Layout(gtx, material.Button(v.th, &v.reloadBtn, i18n.T("Reload token")).Layout)
+							}),
+							layout.Rigid(func(gtx C) D {
+								if v.s.AdminLocked {
+									return D{}
+								}
+
+								return layout.Inset{Top: unit.Dp(4)}.Layout(gtx, material.Button(v.th, &v.rotateBtn, i18n.T("Rotate token")).Layout)
+							}),
+						)
+					}),
+				)
+			})
+		}),
+	)
+}
+
+func (v *view) layoutKeychain(gtx C) D {
+	if v.ctrl.DataDir() != "" || v.s.AccountsOnly {
+		return D{}
+	}
+
+	in := layout.UniformInset(unit.Dp(8))
+	return in.Layout(gtx, func(gtx C) D {
+		return layout.Flex{Axis: layout.Vertical}.Layout(
+			gtx,
+			layout.Rigid(material.Caption(v.th, i18n.T("OS keychain:")).Layout),
+			layout.Rigid(func(gtx C) D {
+				return layout.Flex{}.Layout(
+					gtx,
+					layout.Rigid(func(gtx C) D {
+						return layout.Inset{Top: unit.Dp(4), Right: unit.Dp(4)}.Layout(gtx, material.Button(v.th, &v.storeBtn, i18n.T("Save tokens")).Layout)
+					}),
+					layout.Rigid(func(gtx C) D {
+						return layout.Inset{Top: unit.Dp(4)}.Layout(gtx, material.Button(v.th, &v.forgetBtn, i18n.T("Forget")).Layout)
+					}),
+				)
+			}),
+		)
+	})
+}
+
+func (v *view) layoutElevation(gtx C) D {
+	if v.s.AccountsOnly {
+		return D{}
+	}
+
+	in := layout.UniformInset(unit.Dp(8))
+	return in.Layout(gtx, func(gtx C) D {
+		if v.s.AdminSealed && !v.s.AdminLocked {
+			left := time.Until(v.s.AdminUnlockedUntil).Round(time.Second)
+			if left < 0 {
+				left = 0
+			}
+
+			return layout.Flex{Alignment: layout.Middle}.Layout(
+				gtx,
+				layout.Rigid(func(gtx C) D {
+					title := material.Caption(v.th, i18n.Tf("Admin unlocked, locks in %s", left))
+					title.Color = orange
+					return layout.Inset{Right: unit.Dp(8)}.Layout(gtx, title.Layout)
+				}),
+				layout.Rigid(material.Button(v.th, &v.lockBtn, i18n.T("Lock now")).Layout),
+			)
+		}
+
+		label := i18n.T("Lock admin token with passphrase:")
+		action := i18n.T("Lock")
+		if v.s.AdminSealed {
+			label = i18n.T("Admin locked, enter passphrase to unlock:")
+			action = i18n.T("Unlock")
+		}
+
+		return layout.Flex{Axis: layout.Vertical}.Layout(
+			gtx,
+			layout.Rigid(material.Caption(v.th, label).Layout),
+			layout.Rigid(func(gtx C) D {
+				return layout.Flex{Alignment: layout.Middle}.Layout(
+					gtx,
+					layout.Flexed(1, func(gtx C) D {
+						return layout.Inset{Right: unit.Dp(8)}.Layout(gtx, material.Editor(v.th, &v.passphrase, i18n.T("Passphrase")).Layout)
+					}),
+					layout.Rigid(material.Button(v.th, &v.unlockBtn, action).Layout),
+				)
+			}),
+		)
+	})
+}
+
+func (v *view) layoutSignedTxns(gtx C) D {
+	if v.txns == nil || len(v.s.SignedTxns) == 0 {
+		return D{}
+	}
+
+	children := []layout.FlexChild{
+		layout.Rigid(material.Caption(v.th, i18n.T("Signed transactions:")).Layout),
+	}
+
+	for _, item := range v.s.SignedTxns {
+		item := item
+
+		btn, ok := v.submitBtns[item.Path]
+		if !ok {
+			btn = &widget.Clickable{}
+			v.submitBtns[item.Path] = btn
+		}
+
+		children = append(children, layout.Rigid(func(gtx C) D {
+			return layout.Inset{Top: unit.Dp(4)}.Layout(gtx, func(gtx C) D {
+				return layout.Flex{Alignment: layout.Middle}.Layout(
+					gtx,
+					layout.Flexed(1, func(gtx C) D {
+						text := item.Summary
+						if item.Err != "" {
+							text = item.Err
+						}
+
+						return layout.Flex{Axis: layout.Vertical}.Layout(
+							gtx,
+							layout.Rigid(material.Body2(v.th, item.Name).Layout),
+							layout.Rigid(func(gtx C) D {
+								label := material.Caption(v.th, text)
+								if item.Err != "" {
+									label.Color = red
+								}
+								return label.Layout(gtx)
+							}),
+						)
+					}),
+					layout.Rigid(func(gtx C) D {
+						if item.Err != "" {
+							return D{}
+						}
+						return layout.Inset{Left: unit.Dp(8)}.Layout(gtx, material.Button(v.th, btn, i18n.T("Submit")).Layout)
+					}),
+				)
+			})
+		}))
+	}
+
+	in := layout.UniformInset(unit.Dp(8))
+	return in.Layout(gtx, func(gtx C) D {
+		return layout.Flex{Axis: layout.Vertical}.Layout(gtx, children...)
+	})
+}
+
+func (v *view) layoutPending(gtx C) D {
+	p := v.s.Pending
+	if !v.s.Running {
+		return D{}
+	}
+
+	children := []layout.FlexChild{
+		layout.Rigid(func(gtx C) D {
+			return v.field(gtx, i18n.T("Transaction pool:"), i18n.Tf("%d pending", p.Total))
+		}),
+	}
+
+	for _, t := range p.Txns {
+		t := t
+		children = append(children, layout.Rigid(func(gtx C) D {
+			text := i18n.Tf("%s %s from %s: %s", short(t.ID), t.Type, short(t.Sender), t.Description)
+			if t.Ours {
+				text += i18n.T(" (yours)")
+			}
+
+			label := material.Caption(v.th, text)
+			if t.Ours {
+				label.Color = green
+			}
+			return label.Layout(gtx)
+		}))
+	}
+
+	if n := uint64(len(p.Txns)); p.Total > n {
+		children = append(children, layout.Rigid(material.Caption(v.th, i18n.Tf("and %d more", p.Total-n)).Layout))
+	}
+
+	in := layout.UniformInset(unit.Dp(8))
+	return in.Layout(gtx, func(gtx C) D {
+		return layout.Flex{Axis: layout.Vertical}.Layout(gtx, children...)
+	})
+}
+
+func (v *view) layoutIncidents(gtx C) D {
+	if len(v.s.Incidents) == 0 {
+		return D{}
+	}
+
+	children := []layout.FlexChild{
+		layout.Rigid(material.Caption(v.th, i18n.T("Missed proposals (estimated):")).Layout),
+	}
+
+	for i := len(v.s.Incidents) - 1; i >= 0; i-- {
+		inc := v.s.Incidents[i]
+
+		text := i18n.Tf("%s – %s, %d rounds: ~%.2f",
+			inc.Start.Format("Jan 2 15:04"),
+			inc.End.Format("15:04"),
+			inc.EndRound-inc.StartRound,
+			inc.MissedProposals,
+		)
+
+		children = append(children, layout.Rigid(material.Body2(v.th, text).Layout))
+	}
+
+	in := layout.UniformInset(unit.Dp(8))
+	return in.Layout(gtx, func(gtx C) D {
+		return layout.Flex{Axis: layout.Vertical}.Layout(gtx, children...)
+	})
+}
+
+func (v *view) layoutProfiles(gtx C) D {
+	if v.profiles == nil {
+		return D{}
+	}
+
+	names := v.profiles.Names()
+	if len(names) == 0 {
+		return D{}
+	}
+
+	children := []layout.FlexChild{
+		layout.Rigid(material.Caption(v.th, i18n.T("Profile:")).Layout),
+	}
+
+	for _, name := range names {
+		name := name
+		children = append(children, layout.Rigid(func(gtx C) D {
+			return material.RadioButton(v.th, &v.profile, name, name).Layout(gtx)
+		}))
+	}
+
+	if v.s.AlertsMuted {
+		children = append(children, layout.Rigid(material.Caption(v.th, i18n.T("Alerts muted for this profile")).Layout))
+	}
+
+	in := layout.UniformInset(unit.Dp(8))
+	return in.Layout(gtx, func(gtx C) D {
+		return layout.Flex{Axis: layout.Vertical}.Layout(gtx, children...)
+	})
+}
+
+func (v *view) layoutDataDirs(gtx C) D {
+	if v.dataDirs == nil || len(v.s.DataDirs) < 2 {
+		return D{}
+	}
+
+	children := []layout.FlexChild{
+		layout.Rigid(material.Caption(v.th, i18n.T("Data directories found:")).Layout),
+	}
+
+	for _, path := range v.s.DataDirs {
+		path := path
+		children = append(children, layout.Rigid(func(gtx C) D {
+			return material.RadioButton(v.th, &v.dataDir, path, path).Layout(gtx)
+		}))
+	}
+
+	in := layout.UniformInset(unit.Dp(8))
+	return in.Layout(gtx, func(gtx C) D {
+		return layout.Flex{Axis: layout.Vertical}.Layout(gtx, children...)
+	})
+}
+
+func (v *view) layoutPerformance(gtx C) D {
+	p := v.s.Performance
+	if p.Expected == 0 {
+		return D{}
+	}
+
+	children := []layout.FlexChild{
+		layout.Rigid(material.Caption(v.th, i18n.T("Performance (30 days):")).Layout),
+		layout.Rigid(func(gtx C) D {
+			text := i18n.Tf("%.2f – %d of %.1f expected proposals", p.Index, p.Actual, p.Expected)
+			return material.Body1(v.th, text).Layout(gtx)
+		}),
+	}
+
+	if p.RecentExpected > 0 {
+		children = append(children, layout.Rigid(func(gtx C) D {
+			trend := i18n.T("steady")
+			switch {
+			case p.RecentIndex > p.Index*1.1:
+				trend = i18n.T("improving")
+			case p.RecentIndex < p.Index*0.9:
+				trend = i18n.T("declining")
+			}
+
+			text := i18n.Tf("Last 7 days: %.2f (%s)", p.RecentIndex, trend)
+			return material.Body2(v.th, text).Layout(gtx)
+		}))
+	}
+
+	if p.PerDay > 0 {
+		children = append(children, layout.Rigid(func(gtx C) D {
+			text := i18n.Tf("Expected at current stake: %.2f per day", p.PerDay)
+			return material.Body2(v.th, text).Layout(gtx)
+		}))
+	}
+
+	if p.Underperforming {
+		children = append(children, layout.Rigid(func(gtx C) D {
+			title := material.Body2(v.th, i18n.Tf("Significantly below stake (%.2f%% chance of bad luck), check the participation key and clock", p.PValue*100))
+			title.Color = orange
+			return title.Layout(gtx)
+		}))
+	}
+
+	in := layout.UniformInset(unit.Dp(8))
+	return in.Layout(gtx, func(gtx C) D {
+		return layout.Flex{Axis: layout.Vertical}.Layout(gtx, children...)
+	})
+}
+
+// a key that voted this recently is the one in use
+const keyIdleRounds = 10
+
+func (v *view) layoutKeys(gtx C) D {
+	if len(v.s.Keys) == 0 {
+		return D{}
+	}
+
+	children := []layout.FlexChild{
+		layout.Rigid(material.Caption(v.th, i18n.T("Participation keys:")).Layout),
+	}
+
+	for _, k := range v.s.Keys {
+		k := k
+
+		vote := i18n.T("never voted")
+		if k.LastVote > 0 {
+			vote = i18n.Tf("last vote %d", k.LastVote)
+		}
+
+		proposal := i18n.T("never proposed")
+		if k.LastProposal > 0 {
+			proposal = i18n.Tf("last proposal %d", k.LastProposal)
+		}
+
+		inUse := k.LastVote > 0 && k.LastVote+keyIdleRounds >= v.s.Round
+
+		btn, ok := v.proposalBtns[k.ID]
+		if !ok {
+			btn = &widget.Clickable{}
+			v.proposalBtns[k.ID] = btn
+		}
+
+		children = append(children, layout.Rigid(func(gtx C) D {
+			return layout.Inset{Top: unit.Dp(4)}.Layout(gtx, func(gtx C) D {
+				title := i18n.Tf("%s for %s", short(k.ID), short(k.Address))
+				if inUse {
+					title += i18n.T(" – in use")
+				}
+
+				return layout.Flex{Alignment: layout.Middle}.Layout(
+					gtx,
+					layout.Flexed(1, func(gtx C) D {
+						return layout.Flex{Axis: layout.Vertical}.Layout(
+							gtx,
+							layout.Rigid(func(gtx C) D {
+								label := material.Body2(v.th, title)
+								if inUse {
+									label.Color = green
+								}
+								return label.Layout(gtx)
+							}),
+							layout.Rigid(material.Caption(v.th, i18n.Tf("rounds %d–%d, %s, %s", k.FirstValid, k.LastValid, vote, proposal)).Layout),
+						)
+					}),
+					layout.Rigid(func(gtx C) D {
+						if v.explorer == nil || k.LastProposal == 0 {
+							return D{}
+						}
+						return layout.Inset{Left: unit.Dp(8)}.Layout(gtx, material.Button(v.th, btn, i18n.T("View proposal")).Layout)
+					}),
+				)
+			})
+		}))
+	}
+
+	in := layout.UniformInset(unit.Dp(8))
+	return in.Layout(gtx, func(gtx C) D {
+		return layout.Flex{Axis: layout.Vertical}.Layout(gtx, children...)
+	})
+}
+
+func voi(micro uint64) string {
+	return i18n.Tf("%.2f VOI", float64(micro)/1e6)
+}
+
+func (v *view) layoutRewards(gtx C) D {
+	r := v.s.Rewards
+	if r.Month == 0 && r.Stake == 0 {
+		return D{}
+	}
+
+	children := []layout.FlexChild{
+		layout.Rigid(material.Caption(v.th, i18n.T("Rewards:")).Layout),
+		layout.Rigid(func(gtx C) D {
+			return v.field(gtx, i18n.T("Today:"), voi(r.Today))
+		}),
+		layout.Rigid(func(gtx C) D {
+			return v.field(gtx, i18n.T("7 days:"), voi(r.Week))
+		}),
+		layout.Rigid(func(gtx C) D {
+			return v.field(gtx, i18n.T("30 days:"), voi(r.Month))
+		}),
+	}
+
+	if r.APR > 0 {
+		children = append(children, layout.Rigid(func(gtx C) D {
+			text := i18n.Tf("Estimated APR: %.2f%% on %s online", r.APR, voi(r.Stake))
+			return material.Body2(v.th, text).Layout(gtx)
+		}))
+	}
+
+	in := layout.UniformInset(unit.Dp(8))
+	return in.Layout(gtx, func(gtx C) D {
+		return layout.Flex{Axis: layout.Vertical}.Layout(gtx, children...)
+	})
+}
+
+func (v *view) layoutUptime(gtx C) D {
+	u := v.s.Uptime
+	if u.Observed == 0 {
+		return D{}
+	}
+
+	children := []layout.FlexChild{
+		layout.Rigid(material.Caption(v.th, i18n.T("Availability (30 days):")).Layout),
+		layout.Rigid(func(gtx C) D {
+			text := i18n.Tf("%.1f%% uptime over %s monitored", u.Connection*100, u.Observed.Round(time.Hour))
+			return material.Body1(v.th, text).Layout(gtx)
+		}),
+	}
+
+	if u.Participation >= 0 {
+		children = append(children, layout.Rigid(func(gtx C) D {
+			text := i18n.Tf("%.1f%% participating", u.Participation*100)
+			return material.Body2(v.th, text).Layout(gtx)
+		}))
+	}
+
+	for i := len(u.Outages) - 1; i >= 0 && i >= len(u.Outages)-10; i-- {
+		o := u.Outages[i]
+
+		what := i18n.T("Node unreachable")
+		if o.Kind == state.OutageParticipation {
+			what = i18n.T("Not participating")
+		}
+
+		var text string
+		if o.End.IsZero() {
+			text = i18n.Tf("%s since %s (%s)", what, o.Start.Format("Jan 2 15:04"), time.Since(o.Start).Round(time.Second))
+		} else {
+			text = fmt.Sprintf("%s %s – %s (%s)", what, o.Start.Format("Jan 2 15:04"), o.End.Format("15:04"), o.End.Sub(o.Start).Round(time.Second))
+		}
+
+		children = append(children, layout.Rigid(func(gtx C) D {
+			l := material.Caption(v.th, text)
+			if o.End.IsZero() {
+				l.Color = red
+			}
+			return l.Layout(gtx)
+		}))
+	}
+
+	in := layout.UniformInset(unit.Dp(8))
+	return in.Layout(gtx, func(gtx C) D {
+		return layout.Flex{Axis: layout.Vertical}.Layout(gtx, children...)
+	})
+}
+
+func (v *view) layoutExport(gtx C) D {
+	if v.exporter == nil {
+		return D{}
+	}
+
+	in := layout.UniformInset(unit.Dp(8))
+	return in.Layout(gtx, func(gtx C) D {
+		return layout.Flex{Axis: layout.Vertical}.Layout(
+			gtx,
+			layout.Rigid(material.Caption(v.th, i18n.T("Export history:")).Layout),
+			layout.Rigid(func(gtx C) D {
+				return layout.Flex{}.Layout(
+					gtx,
+					layout.Rigid(material.RadioButton(v.th, &v.exportRange, "1", i18n.T("24 hours")).Layout),
+					layout.Rigid(material.RadioButton(v.th, &v.exportRange, "7", i18n.T("7 days")).Layout),
+					layout.Rigid(material.RadioButton(v.th, &v.exportRange, "30", i18n.T("30 days")).Layout),
+				)
+			}),
+			layout.Rigid(func(gtx C) D {
+				return layout.Flex{}.Layout(
+					gtx,
+					layout.Rigid(func(gtx C) D {
+						return layout.Inset{Top: unit.Dp(4), Right: unit.Dp(4)}.Layout(gtx, material.Button(v.th, &v.exportCSVBtn, i18n.T("CSV")).Layout)
+					}),
+					layout.Rigid(func(gtx C) D {
+						return layout.Inset{Top: unit.Dp(4), Right: unit.Dp(4)}.Layout(gtx, material.Button(v.th, &v.exportJSONBtn, i18n.T("JSON")).Layout)
+					}),
+					layout.Rigid(func(gtx C) D {
+						return layout.Inset{Top: unit.Dp(4)}.Layout(gtx, material.Button(v.th, &v.reportBtn, i18n.T("Weekly report")).Layout)
+					}),
+				)
+			}),
+		)
+	})
+}
+
+func (v *view) layoutHistory(gtx C) D {
+	h := v.s.History
+
+	var items []bar
+	var sum time.Duration
+	var count int
+
+	for _, d := range h.BlockTimes {
+		b := bar{value: d.Seconds(), color: gray}
+		if v.s.AvgBlockDuration > 0 && d > v.s.AvgBlockDuration*3/2 {
+			b.color = orange
+		}
+		items = append(items, b)
+
+		if d > 0 {
+			sum += d
+			count++
+		}
+	}
+
+	var txnItems []bar
+	var txns float64
+	for _, t := range h.TxnsPerBlock {
+		txnItems = append(txnItems, bar{value: t, color: gray})
+		txns += t
+	}
+
+	if count == 0 && h.Proposals == 0 {
+		return D{}
+	}
+
+	children := []layout.FlexChild{
+		layout.Rigid(material.Caption(v.th, i18n.T("History:")).Layout),
+	}
+
+	if count > 0 {
+		text := i18n.Tf("Block time %s average over 24 hours", (sum / time.Duration(count)).Round(10*time.Millisecond))
+		children = append(children,
+			layout.Rigid(material.Body2(v.th, text).Layout),
+			layout.Rigid(func(gtx C) D {
+				return layout.Inset{Top: unit.Dp(4)}.Layout(gtx, func(gtx C) D {
+					return described(gtx, i18n.T("Block times over 24 hours"), func(gtx C) D {
+						return bars(gtx, unit.Dp(40), items)
+					})
+				})
+			}),
+		)
+	}
+
+	if txns > 0 {
+		children = append(children,
+			layout.Rigid(material.Body2(v.th, i18n.T("Transactions per block over 24 hours")).Layout),
+			layout.Rigid(func(gtx C) D {
+				return layout.Inset{Top: unit.Dp(4)}.Layout(gtx, func(gtx C) D {
+					return described(gtx, i18n.T("Transactions per block over 24 hours"), func(gtx C) D {
+						return bars(gtx, unit.Dp(40), txnItems)
+					})
+				})
+			}),
+		)
+	}
+
+	if h.Proposals > 0 {
+		text := i18n.Tf("%d proposals recorded in 30 days, last %s", h.Proposals, h.LastProposal.Format("Jan 2 15:04"))
+		children = append(children, layout.Rigid(material.Body2(v.th, text).Layout))
+	}
+
+	in := layout.UniformInset(unit.Dp(8))
+	return in.Layout(gtx, func(gtx C) D {
+		return layout.Flex{Axis: layout.Vertical}.Layout(gtx, children...)
+	})
+}
+
+func (v *view) layoutAlerts(gtx C) D {
+	var children []layout.FlexChild
+
+	if v.s.AlertsSilenced.After(time.Now()) {
+		children = append(children, layout.Rigid(material.Caption(v.th, i18n.Tf("Notifications silenced until %s", v.s.AlertsSilenced.Format("Jan 2 15:04"))).Layout))
+	}
+
+	if len(v.s.Alerts) == 0 {
+		if len(children) == 0 {
+			return D{}
+		}
+
+		return layout.UniformInset(unit.Dp(8)).Layout(gtx, func(gtx C) D {
+			return layout.Flex{Axis: layout.Vertical}.Layout(gtx, children...)
+		})
+	}
+
+	inc := v.s.Alerts[len(v.s.Alerts)-1]
+
+	if inc.Resolved.IsZero() {
+		children = append(children, layout.Rigid(func(gtx C) D {
+			title := material.Subtitle1(v.th, inc.Title)
+			title.Color = red
+			return title.Layout(gtx)
+		}))
+	} else {
+		text := i18n.Tf("Resolved: %s (%s – %s)", inc.Title, inc.Opened.Format("Jan 2 15:04"), inc.Resolved.Format("15:04"))
+		children = append(children, layout.Rigid(material.Caption(v.th, text).Layout))
+	}
+
+	for _, e := range inc.Timeline {
+		e := e
+		children = append(children, layout.Rigid(func(gtx C) D {
+			text := e.At.Format("15:04:05") + " " + e.Message
+			if e.Cleared {
+				text += " ✓"
+			}
+			return material.Body2(v.th, text).Layout(gtx)
+		}))
+	}
+
+	in := layout.UniformInset(unit.Dp(8))
+	return in.Layout(gtx, func(gtx C) D {
+		return layout.Flex{Axis: layout.Vertical}.Layout(gtx, children...)
+	})
+}
+
+func networkColor(network string) color.NRGBA {
+	switch {
+	case strings.Contains(network, "main"):
+		return blue
+	case strings.Contains(network, "test"), strings.Contains(network, "beta"), strings.Contains(network, "dev"):
+		return orange
+	default:
+		return purple
+	}
+}
+
+func (v *view) layoutNetwork(gtx C) D {
+	if v.s.Network == "" {
+		return D{}
+	}
+
+	in := layout.UniformInset(unit.Dp(8))
+	return in.Layout(gtx, func(gtx C) D {
+		return layout.Flex{Axis: layout.Vertical}.Layout(
+			gtx,
+			layout.Rigid(material.Caption(v.th, i18n.T("Network:")).Layout),
+			layout.Rigid(func(gtx C) D {
+				title := material.Subtitle1(v.th, strings.ToUpper(v.s.Network)+"  "+v.s.GenesisID)
+				title.Color = networkColor(v.s.Network)
+				return title.Layout(gtx)
+			}),
+			layout.Rigid(func(gtx C) D {
+				if v.s.ExpectedNetwork == "" || v.s.ExpectedNetwork == v.s.Network {
+					return D{}
+				}
+
+				title := material.Body2(v.th, i18n.Tf("Expected %s, this node is on a different network", v.s.ExpectedNetwork))
+				title.Color = red
+				return title.Layout(gtx)
+			}),
+		)
+	})
+}
+
+func protocolName(version string) string {
+	name := version[strings.LastIndex(version, "/")+1:]
+	if len(name) > 12 {
+		name = name[:12]
+	}
+	return name
+}
+
+func (v *view) layoutConsensus(gtx C) D {
+	c := v.s.Consensus
+	if c.Current == "" {
+		return D{}
+	}
+
+	children := []layout.FlexChild{
+		layout.Rigid(material.Caption(v.th, i18n.T("Version:")).Layout),
+	}
+
+	if v.s.NodeVersion != "" {
+		children = append(children, layout.Rigid(material.Body1(v.th, "algod "+v.s.NodeVersion).Layout))
+	}
+
+	if u := v.s.NodeUpdate; u.Available {
+		children = append(children, layout.Rigid(func(gtx C) D {
+			title := material.Body2(v.th, i18n.Tf("Update available: algod %s", u.Latest))
+			title.Color = orange
+			return title.Layout(gtx)
+		}))
+	}
+
+	children = append(children, layout.Rigid(material.Body2(v.th, i18n.Tf("Protocol %s", protocolName(c.Current))).Layout))
+
+	if c.Next != c.Current {
+		children = append(children, layout.Rigid(func(gtx C) D {
+			text := i18n.Tf("Upgrade to %s at round %d", protocolName(c.Next), c.NextRound)
+			if v.s.Round > 0 && c.NextRound > v.s.Round {
+				text += i18n.Tf(" (in %d rounds)", c.NextRound-v.s.Round)
+			}
+			title := material.Body2(v.th, text)
+			title.Color = orange
+			return title.Layout(gtx)
+		}))
+	}
+
+	if c.VoteBefore > 0 {
+		children = append(children, layout.Rigid(func(gtx C) D {
+			vote := i18n.T("no")
+			if c.NodeVote {
+				vote = i18n.T("yes")
+			}
+
+			text := i18n.Tf("Upgrade vote: %d yes / %d no of %d rounds, %d needed, ends at round %d, this node votes %s",
+				c.YesVotes, c.NoVotes, c.VoteRounds, c.VotesRequired, c.VoteBefore, vote)
+			return material.Body2(v.th, text).Layout(gtx)
+		}))
+	}
+
+	if !c.NextSupported {
+		children = append(children, layout.Rigid(func(gtx C) D {
+			title := material.Body2(v.th, i18n.T("This node does not support the next protocol, update algod"))
+			title.Color = red
+			return title.Layout(gtx)
+		}))
+	}
+
+	in := layout.UniformInset(unit.Dp(8))
+	return in.Layout(gtx, func(gtx C) D {
+		return layout.Flex{Axis: layout.Vertical}.Layout(gtx, children...)
+	})
+}
+
+const shownEvents = 5
+
+func (v *view) layoutEvents(gtx C) D {
+	if len(v.s.Events) == 0 {
+		return D{}
+	}
+
+	children := []layout.FlexChild{
+		layout.Rigid(material.Caption(v.th, i18n.T("Events:")).Layout),
+	}
+
+	for i := len(v.s.Events) - 1; i >= 0 && i >= len(v.s.Events)-shownEvents; i-- {
+		e := v.s.Events[i]
+		children = append(children, layout.Rigid(func(gtx C) D {
+			text := fmt.Sprintf("%s [%s] %s", e.At.Format("15:04:05"), e.Source, e.Message)
+			return material.Body2(v.th, text).Layout(gtx)
+		}))
+	}
+
+	in := layout.UniformInset(unit.Dp(8))
+	return in.Layout(gtx, func(gtx C) D {
+		return layout.Flex{Axis: layout.Vertical}.Layout(gtx, children...)
+	})
+}
+
+func (v *view) layoutUPS(gtx C) D {
+	u := v.s.UPS
+	if u.UpdatedAt.IsZero() {
+		return D{}
+	}
+
+	var text string
+	var c color.NRGBA
+
+	switch {
+	case u.Err != "":
+		text, c = i18n.Tf("unreachable: %s", u.Err), orange
+	case u.LowBattery:
+		text, c = i18n.Tf("LOW BATTERY %.0f%%, %s left", u.Charge, u.Runtime.Round(time.Minute)), red
+	case u.OnBattery:
+		text, c = i18n.Tf("on battery %.0f%%, %s left", u.Charge, u.Runtime.Round(time.Minute)), red
+	default:
+		text, c = i18n.Tf("online, battery %.0f%%, load %.0f%%", u.Charge, u.Load), green
+	}
+
+	in := layout.UniformInset(unit.Dp(8))
+	return in.Layout(gtx, func(gtx C) D {
+		return layout.Flex{Axis: layout.Vertical}.Layout(
+			gtx,
+			layout.Rigid(material.Caption(v.th, "UPS "+u.Name+":").Layout),
+			layout.Rigid(func(gtx C) D {
+				title := material.Body1(v.th, text)
+				title.Color = c
+				return title.Layout(gtx)
+			}),
+		)
+	})
+}
+
+func (v *view) layoutClock(gtx C) D {
+	c := v.s.Clock
+	if c.CheckedAt.IsZero() {
+		return D{}
+	}
+
+	text := i18n.Tf("%s off %s", c.Offset.Round(time.Millisecond), c.Server)
+	if c.Off {
+		text += " - " + i18n.T("turn on time sync for the host")
+	}
+
+	value := material.Body1(v.th, text)
+	if c.Off {
+		value.Color = red
+	}
+
+	in := layout.UniformInset(unit.Dp(8))
+	return in.Layout(gtx, func(gtx C) D {
+		return layout.Flex{Axis: layout.Vertical}.Layout(
+			gtx,
+			layout.Rigid(material.Caption(v.th, i18n.T("Clock drift:")).Layout),
+			layout.Rigid(value.Layout),
+		)
+	})
+}
+
+func (v *view) layoutPort(gtx C) D {
+	p := v.s.Port
+	if p.CheckedAt.IsZero() {
+		return D{}
+	}
+
+	value := material.Body1(v.th, i18n.Tf("%d is open", p.Port))
+	if !p.Open {
+		value = material.Body1(v.th, i18n.Tf("%d is closed", p.Port))
+		value.Color = red
+	}
+
+	in := layout.UniformInset(unit.Dp(8))
+	return in.Layout(gtx, func(gtx C) D {
+		return layout.Flex{Axis: layout.Vertical}.Layout(
+			gtx,
+			layout.Rigid(material.Caption(v.th, i18n.Tf("Gossip port, checked at %s:", p.CheckedAt.Format("15:04"))).Layout),
+			layout.Rigid(value.Layout),
+		)
+	})
+}
+
+func (v *view) layoutRelay(gtx C) D {
+	r := v.s.Relay
+	if r.UpdatedAt.IsZero() {
+		return D{}
+	}
+
+	peers := material.Body2(v.th, i18n.Tf("Peers: %d in, %d out", r.Inbound, r.Outbound))
+	if r.Inbound == 0 {
+		peers.Color = red
+	}
+
+	children := []layout.FlexChild{
+		layout.Rigid(material.Caption(v.th, i18n.T("Relay:")).Layout),
+		layout.Rigid(peers.Layout),
+		layout.Rigid(material.Body2(v.th, i18n.Tf("Traffic: %s/s out, %s/s in", bytesize(uint64(r.Sent)), bytesize(uint64(r.Received)))).Layout),
+	}
+
+	if r.Checked {
+		listed := material.Body2(v.th, i18n.Tf("%s is in the bootstrap records", r.Name))
+		if !r.Listed {
+			listed = material.Body2(v.th, i18n.Tf("%s is missing from the bootstrap records", r.Name))
+			listed.Color = red
+		}
+		children = append(children, layout.Rigid(listed.Layout))
+	}
+
+	in := layout.UniformInset(unit.Dp(8))
+	return in.Layout(gtx, func(gtx C) D {
+		return layout.Flex{Axis: layout.Vertical}.Layout(gtx, children...)
+	})
+}
+
+func (v *view) layoutIndexer(gtx C) D {
+	ix := v.s.Indexer
+	if ix.UpdatedAt.IsZero() {
+		return D{}
+	}
+
+	var value material.LabelStyle
+	switch {
+	case ix.Err != "":
+		value = material.Body1(v.th, ix.Err)
+		value.Color = red
+	case ix.Behind > 0:
+		value = material.Body1(v.th, i18n.Tf("indexer behind node by %d rounds", ix.Behind))
+	default:
+		value = material.Body1(v.th, i18n.Tf("in sync at round %d", ix.Round))
+	}
+
+	in := layout.UniformInset(unit.Dp(8))
+	return in.Layout(gtx, func(gtx C) D {
+		return layout.Flex{Axis: layout.Vertical}.Layout(
+			gtx,
+			layout.Rigid(material.Caption(v.th, i18n.T("Indexer:")).Layout),
+			layout.Rigid(value.Layout),
+		)
+	})
+}
+
+func (v *view) layoutFollower(gtx C) D {
+	f := v.s.Follower
+	if f.UpdatedAt.IsZero() {
+		return D{}
+	}
+
+	delta := material.Body2(v.th, i18n.T("State delta available"))
+	if !f.Delta {
+		delta = material.Body2(v.th, i18n.T("State delta missing"))
+		delta.Color = red
+	}
+
+	in := layout.UniformInset(unit.Dp(8))
+	return in.Layout(gtx, func(gtx C) D {
+		return layout.Flex{Axis: layout.Vertical}.Layout(
+			gtx,
+			layout.Rigid(material.Caption(v.th, i18n.T("Follower:")).Layout),
+			layout.Rigid(material.Body1(v.th, i18n.Tf("Sync round %d, advanced at %s", f.SyncRound, f.AdvancedAt.Format("15:04:05"))).Layout),
+			layout.Rigid(delta.Layout),
+		)
+	})
+}
+
+func (v *view) layoutHardware(gtx C) D {
+	h := v.s.Hardware
+	if len(h.Temps) == 0 && len(h.Disks) == 0 {
+		return D{}
+	}
+
+	children := []layout.FlexChild{
+		layout.Rigid(material.Caption(v.th, i18n.T("Hardware:")).Layout),
+	}
+
+	hottest := -1
+	for i, t := range h.Temps {
+		if hottest < 0 || t.Celsius > h.Temps[hottest].Celsius {
+			hottest = i
+		}
+	}
+
+	if hottest >= 0 {
+		t := h.Temps[hottest]
+		children = append(children, layout.Rigid(func(gtx C) D {
+			title := material.Body2(v.th, i18n.Tf("Hottest sensor: %s %.0f°C", t.Name, t.Celsius))
+			if t.Critical > 0 && t.Celsius >= t.Critical-10 {
+				title.Color = red
+			}
+			return title.Layout(gtx)
+		}))
+	}
+
+	for _, d := range h.Disks {
+		d := d
+		children = append(children, layout.Rigid(func(gtx C) D {
+			text := d.Device + ": " + d.Status
+			if d.Celsius > 0 {
+				text += fmt.Sprintf(", %.0f°C", d.Celsius)
+			}
+			title := material.Body2(v.th, text)
+			if !d.Healthy {
+				title.Color = red
+			}
+			return title.Layout(gtx)
+		}))
+	}
+
+	in := layout.UniformInset(unit.Dp(8))
+	return in.Layout(gtx, func(gtx C) D {
+		return layout.Flex{Axis: layout.Vertical}.Layout(gtx, children...)
+	})
+}
+
+func (v *view) inIncident(t time.Time) bool {
+	for _, inc := range v.s.Incidents {
+		if !t.Before(inc.Start) && !t.After(inc.End) {
+			return true
+		}
+	}
+	return false
+}
+
+func (v *view) layoutConnectivity(gtx C) D {
+	history := v.s.Connectivity
+	if len(history) == 0 {
+		return D{}
+	}
+
+	last := history[len(history)-1]
+
+	items := make([]bar, len(history))
+	for i, c := range history {
+		b := bar{value: float64(c.Latency), color: gray}
+		switch {
+		case v.inIncident(c.At):
+			b.color = purple
+		case c.Loss >= 0.5:
+			b.color = red
+		case c.Loss > 0:
+			b.color = orange
+		}
+		items[i] = b
+	}
+
+	text := i18n.Tf("%d relays: %s latency, %s jitter, %.0f%% loss",
+		last.Relays,
+		last.Latency.Round(time.Millisecond),
+		last.Jitter.Round(time.Millisecond),
+		last.Loss*100,
+	)
+
+	in := layout.UniformInset(unit.Dp(8))
+	return in.Layout(gtx, func(gtx C) D {
+		return layout.Flex{Axis: layout.Vertical}.Layout(
+			gtx,
+			layout.Rigid(material.Caption(v.th, i18n.T("Connectivity (purple: missed proposals):")).Layout),
+			layout.Rigid(material.Body2(v.th, text).Layout),
+			layout.Rigid(func(gtx C) D {
+				return layout.Inset{Top: unit.Dp(4)}.Layout(gtx, func(gtx C) D {
+					return described(gtx, i18n.T("Relay latency, purple where proposals were missed"), func(gtx C) D {
+						return bars(gtx, unit.Dp(40), items)
+					})
+				})
+			}),
+		)
+	})
+}
+
+func bytesize(b uint64) string {
+	const unit = 1024
+	if b < unit {
+		return fmt.Sprintf("%d B", b)
+	}
+
+	div, exp := uint64(unit), 0
+	for n := b / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+
+	return fmt.Sprintf("%.1f %ciB", float64(b)/float64(div), "KMGTPE"[exp])
+}
+
+func (v *view) layoutResources(gtx C) D {
+	r := v.s.Resources
+	if r.UpdatedAt.IsZero() {
+		return D{}
+	}
+
+	children := []layout.FlexChild{
+		layout.Rigid(material.Caption(v.th, i18n.T("Host:")).Layout),
+	}
+
+	if r.MemTotal > 0 {
+		text := i18n.Tf("CPU %.0f%%, memory %s of %s", r.CPU, bytesize(r.MemUsed), bytesize(r.MemTotal))
+		children = append(children, layout.Rigid(material.Body2(v.th, text).Layout))
+	} else if r.HostErr != "" {
+		text := i18n.Tf("CPU and memory unavailable: %s", r.HostErr)
+		children = append(children, layout.Rigid(material.Caption(v.th, text).Layout))
+	}
+
+	if r.AlgodRunning {
+		text := i18n.Tf("algod: CPU %.0f%%, memory %s", r.AlgodCPU, bytesize(r.AlgodRSS))
+		children = append(children, layout.Rigid(material.Body2(v.th, text).Layout))
+	} else if r.AlgodErr != "" {
+		text := i18n.Tf("algod usage unavailable: %s", r.AlgodErr)
+		children = append(children, layout.Rigid(material.Caption(v.th, text).Layout))
+	}
+
+	if r.DiskTotal > 0 {
+		children = append(children, layout.Rigid(func(gtx C) D {
+			free := 100 * float64(r.DiskFree) / float64(r.DiskTotal)
+			text := i18n.Tf("Data %s, disk %s free (%.0f%%)", bytesize(r.DataDirSize), bytesize(r.DiskFree), free)
+			title := material.Body2(v.th, text)
+			if r.LowDisk {
+				title.Color = red
+			}
+			return title.Layout(gtx)
+		}))
+	}
+
+	if r.LedgerSize > 0 {
+		children = append(children, layout.Rigid(func(gtx C) D {
+			text := i18n.Tf("Ledger %s", bytesize(r.LedgerSize))
+			if r.LedgerGrowth > 0 {
+				text += i18n.Tf(", +%s/day", bytesize(uint64(r.LedgerGrowth)))
+			}
+			if r.DaysUntilFull > 0 {
+				text += i18n.Tf(", disk full in ~%.0f days", r.DaysUntilFull)
+			}
+			return material.Body2(v.th, text).Layout(gtx)
+		}))
+	}
+
+	in := layout.UniformInset(unit.Dp(8))
+	return in.Layout(gtx, func(gtx C) D {
+		return layout.Flex{Axis: layout.Vertical}.Layout(gtx, children...)
+	})
+}
+
+func (v *view) layoutPanels(gtx C) D {
+	children := make([]layout.FlexChild, 0, len(v.s.Panels))
+
+	for _, p := range v.s.Panels {
+		if p.Title == "" && p.UpdatedAt.IsZero() {
+			continue
+		}
+
+		p := p
+		children = append(children, layout.Rigid(func(gtx C) D {
+			rows := []layout.FlexChild{
+				layout.Rigid(material.Caption(v.th, p.Title+":").Layout),
+			}
+
+			if p.Err != "" {
+				rows = append(rows, layout.Rigid(func(gtx C) D {
+					title := material.Body2(v.th, p.Err)
+					title.Color = orange
+					return title.Layout(gtx)
+				}))
+			}
+
+			for _, val := range p.Values {
+				rows = append(rows, layout.Rigid(material.Body2(v.th, val.Label+": "+val.Value).Layout))
+			}
+
+			return layout.UniformInset(unit.Dp(8)).Layout(gtx, func(gtx C) D {
+				return layout.Flex{Axis: layout.Vertical}.Layout(gtx, rows...)
+			})
+		}))
+	}
+
+	return layout.Flex{Axis: layout.Vertical}.Layout(gtx, children...)
+}
+
+func (v *view) layoutPlugins(gtx C) D {
+	children := make([]layout.FlexChild, 0, len(v.s.Plugins))
+
+	for _, p := range v.s.Plugins {
+		p := p
+		children = append(children, layout.Rigid(func(gtx C) D {
+			status := i18n.T("running")
+			if !p.Running {
+				status = i18n.T("stopped")
+			}
+			if p.Restarts > 0 {
+				status += " · " + i18n.Tf("%d restarts", p.Restarts)
+			}
+
+			rows := []layout.FlexChild{
+				layout.Rigid(material.Caption(v.th, i18n.Tf("Plugin %s: %s", p.Name, status)).Layout),
+			}
+
+			if p.Err != "" && !p.Running {
+				rows = append(rows, layout.Rigid(func(gtx C) D {
+					l := material.Body2(v.th, p.Err)
+					l.Color = orange
+					return l.Layout(gtx)
+				}))
+			}
+
+			for _, val := range p.Values {
+				rows = append(rows, layout.Rigid(material.Body2(v.th, val.Label+": "+val.Value).Layout))
+			}
+
+			return layout.UniformInset(unit.Dp(8)).Layout(gtx, func(gtx C) D {
+				return layout.Flex{Axis: layout.Vertical}.Layout(gtx, rows...)
+			})
+		}))
+	}
+
+	return layout.Flex{Axis: layout.Vertical}.Layout(gtx, children...)
+}
@@ -0,0 +1,63 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	"gioui.org/layout"
+	"gioui.org/unit"
+	"gioui.org/widget/material"
+
+	"voiui/internal/i18n"
+)
+
+func change(this float64, last float64) string {
+	if last == 0 {
+		return "–"
+	}
+	return fmt.Sprintf("%+.1f%%", (this-last)/last*100)
+}
+
+// layoutWeekly compares the last seven days with the seven before.
+func (v *view) layoutWeekly(gtx C) D {
+	c := v.s.History.Weekly
+	if c.This.To.IsZero() {
+		return D{}
+	}
+
+	rows := [][3]string{
+		{"Uptime", i18n.Tf("%.2f%% (was %.2f%%)", c.This.Uptime*100, c.Last.Uptime*100), i18n.Tf("%+.2f pts", (c.This.Uptime-c.Last.Uptime)*100)},
+		{"Proposals", i18n.Tf("%d (was %d)", c.This.Proposals, c.Last.Proposals), fmt.Sprintf("%+d", c.This.Proposals-c.Last.Proposals)},
+	}
+
+	if c.This.HasRewards {
+		rows = append(rows, [3]string{"Rewards", i18n.Tf("%s (was %s)", voi(c.This.Rewards), voi(c.Last.Rewards)), change(float64(c.This.Rewards), float64(c.Last.Rewards))})
+	}
+
+	rows = append(rows, [3]string{"Avg block time", i18n.Tf("%s (was %s)", c.This.AvgBlock.Round(time.Millisecond), c.Last.AvgBlock.Round(time.Millisecond)), change(float64(c.This.AvgBlock), float64(c.Last.AvgBlock))})
+
+	children := []layout.FlexChild{
+		layout.Rigid(material.Caption(v.th, i18n.T("This week vs last week:")).Layout),
+	}
+
+	for _, r := range rows {
+		r := r
+		children = append(children, layout.Rigid(func(gtx C) D {
+			return layout.Flex{}.Layout(
+				gtx,
+				layout.Flexed(1, material.Body2(v.th, i18n.T(r[0])).Layout),
+				layout.Flexed(2, material.Body2(v.th, r[1]).Layout),
+				layout.Flexed(1, material.Body2(v.th, r[2]).Layout),
+			)
+		}))
+	}
+
+	for _, n := range c.Notes {
+		children = append(children, layout.Rigid(material.Caption(v.th, n).Layout))
+	}
+
+	in := layout.UniformInset(unit.Dp(8))
+	return in.Layout(gtx, func(gtx C) D {
+		return layout.Flex{Axis: layout.Vertical}.Layout(gtx, children...)
+	})
+}
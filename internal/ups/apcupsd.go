@@ -0,0 +1,112 @@
+package ups
+
+import (
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Apcupsd talks to the apcupsd network information server.
+type Apcupsd struct {
+	Addr string
+
+	name string
+}
+
+func (a *Apcupsd) Name() string {
+	if a.name == "" {
+		return a.Addr
+	}
+	return a.name
+}
+
+func (a *Apcupsd) records(ctx context.Context) (map[string]string, error) {
+	var d net.Dialer
+
+	conn, err := d.DialContext(ctx, "tcp", a.Addr)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to connect to apcupsd")
+	}
+	defer conn.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	cmd := []byte("status")
+
+	err = binary.Write(conn, binary.BigEndian, uint16(len(cmd)))
+	if err == nil {
+		_, err = conn.Write(cmd)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query apcupsd")
+	}
+
+	records := map[string]string{}
+
+	for {
+		var size uint16
+
+		err = binary.Read(conn, binary.BigEndian, &size)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read from apcupsd")
+		}
+
+		if size == 0 {
+			return records, nil
+		}
+
+		buf := make([]byte, size)
+
+		_, err = io.ReadFull(conn, buf)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read from apcupsd")
+		}
+
+		// KEY      : value
+		key, value, ok := strings.Cut(string(buf), ":")
+		if ok {
+			records[strings.TrimSpace(key)] = strings.TrimSpace(value)
+		}
+	}
+}
+
+// number parses values such as "100.0 Percent".
+func number(s string) float64 {
+	fields := strings.Fields(s)
+	if len(fields) == 0 {
+		return 0
+	}
+
+	f, _ := strconv.ParseFloat(fields[0], 64)
+	return f
+}
+
+func (a *Apcupsd) Status(ctx context.Context) (Status, error) {
+	r, err := a.records(ctx)
+	if err != nil {
+		return Status{}, err
+	}
+
+	if name := r["UPSNAME"]; name != "" {
+		a.name = name
+	}
+
+	st := Status{
+		Status:     r["STATUS"],
+		OnBattery:  strings.Contains(r["STATUS"], "ONBATT"),
+		LowBattery: strings.Contains(r["STATUS"], "LOWBATT"),
+		Charge:     number(r["BCHARGE"]),
+		Load:       number(r["LOADPCT"]),
+		Runtime:    time.Duration(number(r["TIMELEFT"]) * float64(time.Minute)),
+	}
+
+	return st, nil
+}
@@ -0,0 +1,100 @@
+package ups
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// NUT talks to upsd from Network UPS Tools.
+type NUT struct {
+	Addr string
+	UPS  string
+}
+
+func (n *NUT) Name() string {
+	return n.UPS
+}
+
+func (n *NUT) vars(ctx context.Context) (map[string]string, error) {
+	var d net.Dialer
+
+	conn, err := d.DialContext(ctx, "tcp", n.Addr)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to connect to upsd")
+	}
+	defer conn.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	_, err = fmt.Fprintf(conn, "LIST VAR %s\n", n.UPS)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query upsd")
+	}
+
+	vars := map[string]string{}
+
+	sc := bufio.NewScanner(conn)
+	for sc.Scan() {
+		line := sc.Text()
+
+		switch {
+		case strings.HasPrefix(line, "ERR "):
+			return nil, errors.Errorf("upsd: %s", strings.TrimPrefix(line, "ERR "))
+		case strings.HasPrefix(line, "END LIST VAR"):
+			fmt.Fprintf(conn, "LOGOUT\n")
+			return vars, nil
+		case strings.HasPrefix(line, "VAR "):
+			// VAR <ups> <name> "<value>"
+			parts := strings.SplitN(line, " ", 4)
+			if len(parts) == 4 {
+				vars[parts[2]] = strings.Trim(parts[3], `"`)
+			}
+		}
+	}
+
+	if err := sc.Err(); err != nil {
+		return nil, errors.Wrap(err, "failed to read from upsd")
+	}
+
+	return nil, errors.New("upsd closed the connection")
+}
+
+func (n *NUT) Status(ctx context.Context) (Status, error) {
+	vars, err := n.vars(ctx)
+	if err != nil {
+		return Status{}, err
+	}
+
+	flags := strings.Fields(vars["ups.status"])
+
+	st := Status{
+		Status: vars["ups.status"],
+	}
+
+	for _, f := range flags {
+		switch f {
+		case "OB":
+			st.OnBattery = true
+		case "LB":
+			st.LowBattery = true
+		}
+	}
+
+	st.Charge, _ = strconv.ParseFloat(vars["battery.charge"], 64)
+	st.Load, _ = strconv.ParseFloat(vars["ups.load"], 64)
+
+	if secs, err := strconv.ParseFloat(vars["battery.runtime"], 64); err == nil {
+		st.Runtime = time.Duration(secs * float64(time.Second))
+	}
+
+	return st, nil
+}
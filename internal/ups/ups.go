@@ -0,0 +1,124 @@
+package ups
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"voiui/internal/alert"
+	"voiui/internal/state"
+)
+
+type Status struct {
+	Status     string
+	OnBattery  bool
+	LowBattery bool
+	Charge     float64
+	Runtime    time.Duration
+	Load       float64
+}
+
+type Source interface {
+	Name() string
+	Status(ctx context.Context) (Status, error)
+}
+
+// Parse creates a source from nut://host[:3493]/ups or apcupsd://host[:3551].
+func Parse(addr string) (Source, error) {
+	u, err := url.Parse(addr)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid UPS address")
+	}
+
+	switch u.Scheme {
+	case "nut":
+		name := strings.Trim(u.Path, "/")
+		if name == "" {
+			return nil, errors.New("UPS name is required, e.g. nut://localhost/ups")
+		}
+		return &NUT{Addr: hostPort(u.Host, "3493"), UPS: name}, nil
+	case "apcupsd":
+		return &Apcupsd{Addr: hostPort(u.Host, "3551")}, nil
+	default:
+		return nil, errors.Errorf("unsupported UPS scheme: %s", u.Scheme)
+	}
+}
+
+func hostPort(host string, port string) string {
+	if host == "" {
+		host = "localhost"
+	}
+	if !strings.Contains(host, ":") {
+		host += ":" + port
+	}
+	return host
+}
+
+type Sink interface {
+	Set(kind alert.Kind, active bool, message string)
+}
+
+type Monitor struct {
+	src      Source
+	interval time.Duration
+	alerts   Sink
+	updates  chan<- state.Update
+}
+
+func NewMonitor(src Source, interval time.Duration, alerts Sink, updates chan<- state.Update) *Monitor {
+	return &Monitor{
+		src:      src,
+		interval: interval,
+		alerts:   alerts,
+		updates:  updates,
+	}
+}
+
+func (m *Monitor) check(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, m.interval)
+	defer cancel()
+
+	st, err := m.src.Status(ctx)
+
+	u := state.UPS{
+		Name:      m.src.Name(),
+		UpdatedAt: time.Now(),
+	}
+
+	if err != nil {
+		u.Err = err.Error()
+	} else {
+		u.Status = st.Status
+		u.OnBattery = st.OnBattery
+		u.LowBattery = st.LowBattery
+		u.Charge = st.Charge
+		u.Runtime = st.Runtime
+		u.Load = st.Load
+
+		m.alerts.Set(alert.OnBattery, st.OnBattery, "UPS "+u.Name+" is on battery")
+		m.alerts.Set(alert.LowBattery, st.LowBattery, "UPS "+u.Name+" battery is low")
+	}
+
+	m.updates <- func(s *state.State) error {
+		s.UPS = u
+		return nil
+	}
+}
+
+func (m *Monitor) Run(ctx context.Context) {
+	t := time.NewTicker(m.interval)
+	defer t.Stop()
+
+	for {
+		m.check(ctx)
+
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}